@@ -0,0 +1,184 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// accessTokenPrefix marks QR content that holds a signed access token rather
+// than item data, so decoders can route it before trying the payload codec
+const accessTokenPrefix = "iktok:"
+
+// defaultAccessTokenTTL is how long an access token stays valid when
+// ttl_seconds is not given
+const defaultAccessTokenTTL = 15 * time.Minute
+
+// errAccessTokenPayload is returned when item decoding is given an access token
+var errAccessTokenPayload = errors.New("payload is an access token, not item data")
+
+// accessTokenClaims is the signed body of an access token
+type accessTokenClaims struct {
+	Subject   string `json:"sub"` // Who the token grants access to (e.g. a worker id)
+	IssuedAt  int64  `json:"iat"` // Unix seconds
+	ExpiresAt int64  `json:"exp"` // Unix seconds
+}
+
+// accessTokenMAC signs the encoded claims. The type prefix is part of the
+// signed message so an item signature can never verify as a token.
+func accessTokenMAC(secret, body string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(accessTokenPrefix + body))
+	return mac.Sum(nil)
+}
+
+// encodeAccessToken returns "iktok:<claims>.<signature>", both base64url
+func encodeAccessToken(secret string, claims accessTokenClaims) (string, error) {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode access token: %w", err)
+	}
+	body := base64.RawURLEncoding.EncodeToString(raw)
+	sig := base64.RawURLEncoding.EncodeToString(accessTokenMAC(secret, body))
+	return accessTokenPrefix + body + "." + sig, nil
+}
+
+// decodeAccessToken checks the token's signature and returns its claims.
+// Expiry is left to the caller.
+func decodeAccessToken(secret, token string) (accessTokenClaims, error) {
+	var claims accessTokenClaims
+	if !strings.HasPrefix(token, accessTokenPrefix) {
+		return claims, errors.New("not an access token")
+	}
+	body, sig, ok := strings.Cut(strings.TrimPrefix(token, accessTokenPrefix), ".")
+	if !ok {
+		return claims, errors.New("malformed access token")
+	}
+	gotMAC, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotMAC, accessTokenMAC(secret, body)) {
+		return claims, errors.New("signature mismatch")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return claims, errors.New("malformed access token")
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return claims, fmt.Errorf("malformed access token: %w", err)
+	}
+	return claims, nil
+}
+
+// handleGenerateAccessToken issues a signed, time-limited access token for a
+// subject and renders it as a QR code, e.g. for a kiosk to print
+func (s *inventoryKeeperKeeper) handleGenerateAccessToken(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	secret := s.config().SigningSecret
+	if secret == "" {
+		return nil, errors.New("access tokens require signing_secret to be configured")
+	}
+	subject, err := optionalStringArg(cmd, "subject")
+	if err != nil {
+		return nil, err
+	}
+	if subject == "" {
+		return nil, errors.New("subject is required")
+	}
+	ttl := defaultAccessTokenTTL
+	if seconds, found, err := floatArg(cmd, "ttl_seconds"); err != nil {
+		return nil, err
+	} else if found {
+		if seconds <= 0 {
+			return nil, fmt.Errorf("ttl_seconds must be positive, got: %v", seconds)
+		}
+		ttl = time.Duration(seconds * float64(time.Second))
+	}
+
+	now := s.now()
+	expiresAt := now.Add(ttl)
+	claims := accessTokenClaims{Subject: subject, IssuedAt: now.Unix(), ExpiresAt: expiresAt.Unix()}
+	token, err := encodeAccessToken(secret, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	png, err := qrcode.Encode(token, qrcode.Medium, 256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+
+	s.logger.Infof("Issued access token for %s expiring at %s", subject, formatTimestamp(time.Unix(claims.ExpiresAt, 0)))
+	return map[string]interface{}{
+		"subject":    subject,
+		"expires_at": formatTimestampIn(time.Unix(claims.ExpiresAt, 0), s.location),
+		"qr_code":    base64.StdEncoding.EncodeToString(png),
+		"qr_data":    token,
+		"format":     "base64-png",
+		"size":       256,
+	}, nil
+}
+
+// handleRedeemAccessToken checks an access token given as qr_data, or as a
+// base64 image of its QR code, and reports whether it is currently valid.
+// Invalid tokens are a normal result with a reason, not an error.
+func (s *inventoryKeeperKeeper) handleRedeemAccessToken(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	secret := s.config().SigningSecret
+	if secret == "" {
+		return nil, errors.New("access tokens require signing_secret to be configured")
+	}
+
+	token, err := optionalStringArg(cmd, "qr_data")
+	if err != nil {
+		return nil, err
+	}
+	encodedImage, err := optionalStringArg(cmd, "image")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case token != "" && encodedImage != "":
+		return nil, errors.New("provide either qr_data or image, not both")
+	case encodedImage != "":
+		if err := s.config().checkImageArg("image", encodedImage); err != nil {
+			return nil, err
+		}
+		raw, err := base64.StdEncoding.DecodeString(encodedImage)
+		if err != nil {
+			return nil, fmt.Errorf("image is not valid base64: %w", err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("image is not a PNG or JPEG image: %w", err)
+		}
+		if token, _, err = decodeCodeImage(img); err != nil {
+			return map[string]interface{}{"valid": false, "reason": err.Error()}, nil
+		}
+	case token == "":
+		return nil, errors.New("qr_data or image is required")
+	}
+
+	claims, err := decodeAccessToken(secret, token)
+	if err != nil {
+		return map[string]interface{}{"valid": false, "reason": err.Error()}, nil
+	}
+
+	result := map[string]interface{}{
+		"valid":      true,
+		"subject":    claims.Subject,
+		"issued_at":  formatTimestampIn(time.Unix(claims.IssuedAt, 0), s.location),
+		"expires_at": formatTimestampIn(time.Unix(claims.ExpiresAt, 0), s.location),
+	}
+	if !s.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		result["valid"] = false
+		result["reason"] = "token expired"
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessTokens(t *testing.T) {
+	svc, _ := newTestKeeper(t, &Config{SigningSecret: "kiosk-secret"})
+
+	generated := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_access_token", "subject": "worker-17", "ttl_seconds": 60.0})
+	token := generated["qr_data"].(string)
+
+	t.Run("generated QR encodes the tagged token", func(t *testing.T) {
+		if !strings.HasPrefix(token, accessTokenPrefix) {
+			t.Errorf("expected token tagged %q, got: %s", accessTokenPrefix, token)
+		}
+		raw, err := base64.StdEncoding.DecodeString(generated["qr_code"].(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text, _, err := decodeCodeImage(img); err != nil || text != token {
+			t.Errorf("expected QR to decode to the token, got: %q (%v)", text, err)
+		}
+	})
+
+	t.Run("valid token redeems from data and image", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "redeem_access_token", "qr_data": token})
+		if result["valid"] != true || result["subject"] != "worker-17" {
+			t.Errorf("expected valid token for worker-17, got: %v", result)
+		}
+
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "redeem_access_token", "image": generated["qr_code"]})
+		if result["valid"] != true {
+			t.Errorf("expected valid token from image, got: %v", result)
+		}
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		expired, err := encodeAccessToken("kiosk-secret", accessTokenClaims{Subject: "worker-17", IssuedAt: past.Unix(), ExpiresAt: past.Add(time.Minute).Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "redeem_access_token", "qr_data": expired})
+		if result["valid"] != false || result["reason"] != "token expired" {
+			t.Errorf("expected expired token, got: %v", result)
+		}
+	})
+
+	t.Run("tampered token is rejected", func(t *testing.T) {
+		forged, err := encodeAccessToken("kiosk-secret", accessTokenClaims{Subject: "intruder", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Original signature on a different body
+		body, _, _ := strings.Cut(strings.TrimPrefix(forged, accessTokenPrefix), ".")
+		_, sig, _ := strings.Cut(token, ".")
+		tampered := accessTokenPrefix + body + "." + sig
+
+		otherSecret, err := encodeAccessToken("wrong-secret", accessTokenClaims{Subject: "worker-17", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, bad := range []string{tampered, otherSecret} {
+			result := mustDoCommand(t, svc, map[string]interface{}{"command": "redeem_access_token", "qr_data": bad})
+			if result["valid"] != false || result["reason"] != "signature mismatch" {
+				t.Errorf("expected signature mismatch, got: %v", result)
+			}
+		}
+	})
+
+	t.Run("tokens are not decoded as items", func(t *testing.T) {
+		if _, err := svc.decodeQRPayload(token); !errors.Is(err, errAccessTokenPayload) {
+			t.Errorf("expected access token error from item decoding, got: %v", err)
+		}
+	})
+
+	t.Run("signing_secret is required", func(t *testing.T) {
+		unsigned, _ := newTestKeeper(t, nil)
+		if _, err := unsigned.DoCommand(context.Background(), map[string]interface{}{"command": "generate_access_token", "subject": "worker-17"}); err == nil {
+			t.Error("expected error without signing_secret")
+		}
+	})
+}
@@ -0,0 +1,333 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxAlerts bounds the in-memory alert log. Oldest alerts are dropped first;
+// pollers that fall this far behind simply resume from the oldest retained alert.
+const maxAlerts = 1000
+
+// Alert severities, lowest first. The logic raising an alert picks one;
+// min_alert_severity decides which reach notifiers.
+const (
+	severityInfo     = "info"
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+// severityRank orders severities for at-or-above comparisons
+var severityRank = map[string]int{
+	severityInfo:     0,
+	severityWarning:  1,
+	severityCritical: 2,
+}
+
+// validateSeverity checks that a severity is one of info, warning, critical
+func validateSeverity(field, severity string) error {
+	if _, ok := severityRank[severity]; !ok {
+		return fmt.Errorf("%s must be %q, %q, or %q, got: %q", field, severityInfo, severityWarning, severityCritical, severity)
+	}
+	return nil
+}
+
+// minSeverityArg returns the command's optional min_severity filter,
+// defaulting to info (everything)
+func minSeverityArg(cmd map[string]interface{}) (string, error) {
+	severity, err := optionalStringArg(cmd, "min_severity")
+	if err != nil {
+		return "", err
+	}
+	if severity == "" {
+		return severityInfo, nil
+	}
+	if err := validateSeverity("min_severity", severity); err != nil {
+		return "", err
+	}
+	return severity, nil
+}
+
+// Alert is a notable event raised by the keeper (theft, discrepancy, etc.)
+type Alert struct {
+	ID        int64                  // Monotonically increasing sequence number (starts at 1)
+	Type      string                 // Alert type (e.g. "theft")
+	Severity  string                 // One of info, warning, critical
+	ItemID    string                 // Item the alert concerns (if any)
+	Message   string                 // Human-readable description
+	CreatedAt time.Time              // When the alert was raised
+	Details   map[string]interface{} // Alert-specific details
+	Muted     bool                   // Kept from notifiers by maintenance mode
+
+	AcknowledgedAt time.Time // When the alert was acknowledged (zero if not)
+	AckNote        string    // Optional note left when acknowledging
+}
+
+// toMap converts the alert into a DoCommand-friendly response map, with the
+// creation time shown in loc
+func (a Alert) toMap(loc *time.Location) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":         a.ID,
+		"type":       a.Type,
+		"severity":   a.Severity,
+		"message":    a.Message,
+		"created_at": formatTimestampIn(a.CreatedAt, loc),
+	}
+	if a.ItemID != "" {
+		m["item_id"] = a.ItemID
+	}
+	if len(a.Details) > 0 {
+		m["details"] = a.Details
+	}
+	if a.Muted {
+		m["muted"] = true
+	}
+	m["acknowledged"] = a.acknowledged()
+	if a.acknowledged() {
+		m["acknowledged_at"] = formatTimestampIn(a.AcknowledgedAt, loc)
+		if a.AckNote != "" {
+			m["ack_note"] = a.AckNote
+		}
+	}
+	return m
+}
+
+// acknowledged reports whether the alert has been marked as handled
+func (a Alert) acknowledged() bool {
+	return !a.AcknowledgedAt.IsZero()
+}
+
+// atLeast reports whether the alert's severity is at or above min
+func (a Alert) atLeast(min string) bool {
+	return severityRank[a.Severity] >= severityRank[min]
+}
+
+// raiseAlert records a new alert with the next sequence id. Every alert is
+// kept; only those at or above min_alert_severity are sent to notifiers, and
+// none while maintenance mode is on.
+// Under alert_cooldown_seconds, a repeat of an item's alert type within the
+// cooldown is dropped and the zero Alert returned.
+func (s *inventoryKeeperKeeper) raiseAlert(alertType, severity, itemID, message string, details map[string]interface{}) Alert {
+	s.alertsMu.Lock()
+	defer s.alertsMu.Unlock()
+
+	now := s.now().UTC()
+	if cooldown := s.config().alertCooldown(); cooldown > 0 && itemID != "" {
+		key := cooldownKey{Type: alertType, ItemID: itemID}
+		if last, ok := s.lastAlerted[key]; ok && now.Sub(last) < cooldown {
+			s.logger.Debugf("Suppressing %s alert for %s, last raised %v ago: %s", alertType, itemID, now.Sub(last).Round(time.Second), message)
+			return Alert{}
+		}
+		if s.lastAlerted == nil {
+			s.lastAlerted = make(map[cooldownKey]time.Time)
+		}
+		s.lastAlerted[key] = now
+	}
+
+	s.alertSeq++
+	s.alertStateDirty = true
+	alert := Alert{
+		ID:        s.alertSeq,
+		Type:      alertType,
+		Severity:  severity,
+		ItemID:    itemID,
+		Message:   message,
+		CreatedAt: now,
+		Details:   details,
+	}
+	deliver := alert.atLeast(s.config().minAlertSeverity())
+	if deliver && s.suppressNotification() {
+		alert.Muted = true
+		deliver = false
+	}
+
+	s.alerts = append(s.alerts, alert)
+	if len(s.alerts) > maxAlerts {
+		s.alerts = s.alerts[len(s.alerts)-maxAlerts:]
+	}
+
+	s.logger.Warnw(fmt.Sprintf("Alert %d (%s, %s): %s", alert.ID, alertType, severity, message),
+		"event", "alert", "alert_type", alertType, "severity", severity, "item_id", itemID)
+	if deliver {
+		s.dispatchAlert(alert)
+	}
+	return alert
+}
+
+// handleGetAlerts returns the active alerts at or above min_severity, oldest
+// first. Acknowledged alerts are left out unless include_acknowledged is set.
+func (s *inventoryKeeperKeeper) handleGetAlerts(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	minSeverity, err := minSeverityArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	includeAcknowledged, _ := cmd["include_acknowledged"].(bool)
+
+	s.alertsMu.Lock()
+	result := make([]interface{}, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		if alert.atLeast(minSeverity) && (includeAcknowledged || !alert.acknowledged()) {
+			result = append(result, alert.toMap(s.location))
+		}
+	}
+	s.alertsMu.Unlock()
+
+	return map[string]interface{}{
+		"alerts": result,
+		"count":  len(result),
+	}, nil
+}
+
+// handlePollAlerts returns only alerts newer than after_id (and at or above
+// min_severity), plus the new high-water mark to pass as after_id on the next
+// poll. The mark covers filtered-out alerts too, so they aren't revisited.
+func (s *inventoryKeeperKeeper) handlePollAlerts(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	afterID, _, err := intArg(cmd, "after_id")
+	if err != nil {
+		return nil, err
+	}
+	if afterID < 0 {
+		return nil, errors.New("after_id must be non-negative")
+	}
+	minSeverity, err := minSeverityArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.alertsMu.Lock()
+	result := []interface{}{}
+	for _, alert := range s.alerts {
+		if alert.ID > int64(afterID) && alert.atLeast(minSeverity) {
+			result = append(result, alert.toMap(s.location))
+		}
+	}
+	highWaterMark := s.alertSeq
+	s.alertsMu.Unlock()
+
+	// Never move the caller's cursor backwards
+	if int64(afterID) > highWaterMark {
+		highWaterMark = int64(afterID)
+	}
+
+	return map[string]interface{}{
+		"alerts":          result,
+		"count":           len(result),
+		"high_water_mark": highWaterMark,
+	}, nil
+}
+
+// handleAcknowledgeAlert marks an alert as handled, with an optional note.
+// Acknowledging an alert twice keeps the original time and note.
+func (s *inventoryKeeperKeeper) handleAcknowledgeAlert(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	id, found, err := intArg(cmd, "id")
+	if err != nil {
+		return nil, err
+	}
+	latest, _ := cmd["latest"].(bool)
+	if (!found || id <= 0) && !latest {
+		return nil, errors.New("id is required and must be a positive alert id (or set latest)")
+	}
+	note, err := optionalStringArg(cmd, "note")
+	if err != nil {
+		return nil, err
+	}
+
+	s.alertsMu.Lock()
+	index := -1
+	if latest && (!found || id <= 0) {
+		// latest: the newest alert still awaiting acknowledgement, as a
+		// button press has no id to send
+		for i := len(s.alerts) - 1; i >= 0; i-- {
+			if !s.alerts[i].acknowledged() {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			s.alertsMu.Unlock()
+			return nil, errors.New("no unacknowledged alerts")
+		}
+	} else {
+		for i := range s.alerts {
+			if s.alerts[i].ID == int64(id) {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 {
+		s.alertsMu.Unlock()
+		return nil, fmt.Errorf("alert %d not found (it may have been cleared or dropped)", id)
+	}
+	alreadyAcknowledged := s.alerts[index].acknowledged()
+	if !alreadyAcknowledged {
+		s.alerts[index].AcknowledgedAt = s.now().UTC()
+		s.alerts[index].AckNote = note
+	}
+	alert := s.alerts[index]
+	s.alertsMu.Unlock()
+
+	if !alreadyAcknowledged {
+		details := map[string]interface{}{"alert_id": alert.ID, "alert_type": alert.Type}
+		if note != "" {
+			details["note"] = note
+		}
+		s.inventoryMu.Lock()
+		s.recordHistoryLocked("alert_acknowledged", defaultNamespace, alert.ItemID, details)
+		s.inventoryMu.Unlock()
+		s.logger.Infof("Alert %d acknowledged", alert.ID)
+	}
+
+	return map[string]interface{}{
+		"alert":                alert.toMap(s.location),
+		"already_acknowledged": alreadyAcknowledged,
+	}, nil
+}
+
+// handleClearAcknowledged removes acknowledged alerts from the active list.
+// Each cleared alert is recorded in history so it can still be looked up.
+func (s *inventoryKeeperKeeper) handleClearAcknowledged(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	s.alertsMu.Lock()
+	var cleared []Alert
+	kept := s.alerts[:0]
+	for _, alert := range s.alerts {
+		if alert.acknowledged() {
+			cleared = append(cleared, alert)
+		} else {
+			kept = append(kept, alert)
+		}
+	}
+	s.alerts = kept
+	s.alertsMu.Unlock()
+
+	ids := make([]interface{}, 0, len(cleared))
+	s.inventoryMu.Lock()
+	for _, alert := range cleared {
+		details := map[string]interface{}{
+			"alert_id":        alert.ID,
+			"alert_type":      alert.Type,
+			"severity":        alert.Severity,
+			"message":         alert.Message,
+			"created_at":      formatTimestampIn(alert.CreatedAt, s.location),
+			"acknowledged_at": formatTimestampIn(alert.AcknowledgedAt, s.location),
+			"ack_note":        alert.AckNote,
+		}
+		// Keep the alert's own details so reports still see cleared alerts
+		if len(alert.Details) > 0 {
+			details["details"] = alert.Details
+		}
+		s.recordHistoryLocked("alert_cleared", defaultNamespace, alert.ItemID, details)
+		ids = append(ids, alert.ID)
+	}
+	s.inventoryMu.Unlock()
+
+	if len(cleared) > 0 {
+		s.logger.Infof("Cleared %d acknowledged alerts", len(cleared))
+	}
+	return map[string]interface{}{
+		"cleared":   len(cleared),
+		"alert_ids": ids,
+	}, nil
+}
@@ -0,0 +1,200 @@
+package inventorykeeper
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPollAlerts(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	t.Run("alert ids increase monotonically", func(t *testing.T) {
+		first := svc.raiseAlert("test", severityWarning, "apple-001", "first", nil)
+		second := svc.raiseAlert("test", severityWarning, "apple-001", "second", nil)
+		if first.ID != 1 || second.ID != 2 {
+			t.Errorf("expected ids 1 and 2, got: %d and %d", first.ID, second.ID)
+		}
+	})
+
+	t.Run("poll from zero returns everything", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "poll_alerts", "after_id": 0.0})
+		if result["count"] != 2 {
+			t.Errorf("expected 2 alerts, got: %v", result["count"])
+		}
+		if result["high_water_mark"] != int64(2) {
+			t.Errorf("expected high water mark 2, got: %v", result["high_water_mark"])
+		}
+	})
+
+	t.Run("polling with last id yields nothing until a new alert", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "poll_alerts", "after_id": 2.0})
+		if result["count"] != 0 {
+			t.Errorf("expected 0 new alerts, got: %v", result["count"])
+		}
+		if result["high_water_mark"] != int64(2) {
+			t.Errorf("expected high water mark to stay 2, got: %v", result["high_water_mark"])
+		}
+
+		svc.raiseAlert("test", severityWarning, "banana-042", "third", nil)
+
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "poll_alerts", "after_id": 2.0})
+		if result["count"] != 1 {
+			t.Fatalf("expected 1 new alert, got: %v", result["count"])
+		}
+		alert := result["alerts"].([]interface{})[0].(map[string]interface{})
+		if alert["id"] != int64(3) || alert["item_id"] != "banana-042" {
+			t.Errorf("unexpected alert: %v", alert)
+		}
+		if result["high_water_mark"] != int64(3) {
+			t.Errorf("expected high water mark 3, got: %v", result["high_water_mark"])
+		}
+	})
+
+	t.Run("negative after_id returns error", func(t *testing.T) {
+		if _, err := svc.DoCommand(t.Context(), map[string]interface{}{"command": "poll_alerts", "after_id": -1.0}); err == nil {
+			t.Error("expected error for negative after_id")
+		}
+	})
+
+	t.Run("get_alerts returns full history", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})
+		if result["count"] != 3 {
+			t.Errorf("expected 3 alerts, got: %v", result["count"])
+		}
+	})
+}
+
+func TestAlertSeverity(t *testing.T) {
+	t.Run("info alert is recorded but not sent below min severity", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{
+			SMTPHost:         "mail.example.com",
+			SMTPPort:         25,
+			AlertEmailTo:     "ops@example.com",
+			MinAlertSeverity: severityWarning,
+		})
+		sent := make(chan string, 2)
+		svc.notifiers[0].(*emailNotifier).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			sent <- string(msg)
+			return nil
+		}
+
+		svc.raiseAlert("audit_discrepancy", severityInfo, "apple-001", "info", nil)
+		svc.raiseAlert("theft", severityCritical, "banana-042", "critical", nil)
+
+		select {
+		case body := <-sent:
+			if !strings.Contains(body, "Severity: critical") {
+				t.Errorf("expected only the critical alert to be sent, got:\n%s", body)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected an email for the critical alert")
+		}
+		select {
+		case body := <-sent:
+			t.Errorf("expected info alert to be suppressed, got:\n%s", body)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]; count != 2 {
+			t.Errorf("expected both alerts recorded, got: %v", count)
+		}
+	})
+
+	t.Run("get_alerts and poll_alerts filter by min_severity", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		svc.raiseAlert("audit_discrepancy", severityInfo, "apple-001", "info", nil)
+		svc.raiseAlert("duplicate_item_id", severityWarning, "apple-001", "warning", nil)
+		svc.raiseAlert("theft", severityCritical, "apple-001", "critical", nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts", "min_severity": "warning"})
+		if result["count"] != 2 {
+			t.Errorf("expected 2 alerts at or above warning, got: %v", result["alerts"])
+		}
+
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "poll_alerts", "after_id": 0.0, "min_severity": "critical"})
+		alerts := result["alerts"].([]interface{})
+		if len(alerts) != 1 || alerts[0].(map[string]interface{})["severity"] != severityCritical {
+			t.Errorf("expected only the critical alert, got: %v", alerts)
+		}
+		if result["high_water_mark"] != int64(3) {
+			t.Errorf("expected high water mark past filtered alerts, got: %v", result["high_water_mark"])
+		}
+
+		if _, err := svc.DoCommand(t.Context(), map[string]interface{}{"command": "get_alerts", "min_severity": "urgent"}); err == nil {
+			t.Error("expected error for unknown min_severity")
+		}
+	})
+}
+
+func TestAcknowledgeAlerts(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+	first := svc.raiseAlert("theft", severityCritical, "apple-001", "first", nil)
+	second := svc.raiseAlert("theft", severityCritical, "banana-042", "second", nil)
+
+	t.Run("acknowledging records time and note", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "acknowledge_alert", "id": float64(first.ID), "note": "false alarm"})
+		alert := result["alert"].(map[string]interface{})
+		if alert["acknowledged"] != true || alert["ack_note"] != "false alarm" || alert["acknowledged_at"] == "" {
+			t.Errorf("expected acknowledged alert with note and time, got: %v", alert)
+		}
+		if result["already_acknowledged"] != false {
+			t.Errorf("expected first acknowledgement, got: %v", result["already_acknowledged"])
+		}
+
+		again := mustDoCommand(t, svc, map[string]interface{}{"command": "acknowledge_alert", "id": float64(first.ID), "note": "other"})
+		if again["already_acknowledged"] != true || again["alert"].(map[string]interface{})["ack_note"] != "false alarm" {
+			t.Errorf("expected repeat acknowledgement to keep the original note, got: %v", again)
+		}
+	})
+
+	t.Run("get_alerts hides acknowledged alerts by default", func(t *testing.T) {
+		active := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})
+		alerts := active["alerts"].([]interface{})
+		if len(alerts) != 1 || alerts[0].(map[string]interface{})["id"] != second.ID {
+			t.Errorf("expected only alert %d, got: %v", second.ID, alerts)
+		}
+
+		all := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts", "include_acknowledged": true})
+		if all["count"] != 2 {
+			t.Errorf("expected 2 alerts with include_acknowledged, got: %v", all["count"])
+		}
+	})
+
+	t.Run("clear_acknowledged removes acknowledged alerts and keeps history", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "clear_acknowledged"})
+		if result["cleared"] != 1 {
+			t.Errorf("expected 1 cleared alert, got: %v", result["cleared"])
+		}
+
+		all := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts", "include_acknowledged": true})
+		alerts := all["alerts"].([]interface{})
+		if len(alerts) != 1 || alerts[0].(map[string]interface{})["id"] != second.ID {
+			t.Errorf("expected only alert %d to remain, got: %v", second.ID, alerts)
+		}
+
+		history := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history"})
+		events := history["events"].([]interface{})
+		last := events[len(events)-1].(map[string]interface{})
+		details, _ := last["details"].(map[string]interface{})
+		if last["type"] != "alert_cleared" || details["alert_id"] != first.ID {
+			t.Errorf("expected alert_cleared history event for alert %d, got: %v", first.ID, last)
+		}
+	})
+
+	t.Run("unknown id is rejected", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "acknowledge_alert", "id": float64(first.ID)})
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("expected not found error for cleared alert, got: %v", err)
+		}
+	})
+
+	t.Run("latest applies when id is zero", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "acknowledge_alert", "id": 0.0, "latest": true})
+		if got := result["alert"].(map[string]interface{})["id"]; got != second.ID {
+			t.Errorf("expected the latest alert %d to be acknowledged, got: %v", second.ID, got)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package inventorykeeper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// alertStateVersion identifies the layout of the alert state file
+const alertStateVersion = 1
+
+// cooldownKey identifies what an alert cooldown applies to: one alert type
+// for one item
+type cooldownKey struct {
+	Type   string
+	ItemID string
+}
+
+// storedAlertState is the document written next to storage_path so alert
+// ids and cooldowns survive the keeper being rebuilt
+type storedAlertState struct {
+	Version     int              `json:"version"`
+	AlertSeq    int64            `json:"alert_seq"`
+	LastAlerted []storedCooldown `json:"last_alerted"`
+}
+
+// storedCooldown is one cooldown entry in the alert state file
+type storedCooldown struct {
+	Type   string    `json:"type"`
+	ItemID string    `json:"item_id"`
+	At     time.Time `json:"at"`
+}
+
+// alertCooldown returns how long repeats of an item's alert are suppressed,
+// or 0 when they never are
+func (cfg *Config) alertCooldown() time.Duration {
+	if cfg.AlertCooldownSeconds == nil {
+		return 0
+	}
+	return time.Duration(*cfg.AlertCooldownSeconds) * time.Second
+}
+
+// alertStatePath returns where alert state is kept for a storage_path
+func alertStatePath(storagePath string) string {
+	return storagePath + ".alerts"
+}
+
+// loadAlertState reads the alert state file. A missing file is a fresh
+// start, and so is a corrupt one, with a warning, since losing cooldowns
+// only risks a repeated alert.
+func loadAlertState(path string, logger logging.Logger) (int64, map[cooldownKey]time.Time) {
+	lastAlerted := make(map[cooldownKey]time.Time)
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, lastAlerted
+	}
+
+	var state storedAlertState
+	if err == nil {
+		if err = json.Unmarshal(raw, &state); err == nil && state.Version != alertStateVersion {
+			err = fmt.Errorf("unsupported alert state version %d", state.Version)
+		}
+	}
+	if err != nil {
+		logger.Warnf("Ignoring unreadable alert state %s, alert ids and cooldowns start fresh: %v", path, err)
+		return 0, lastAlerted
+	}
+
+	for _, entry := range state.LastAlerted {
+		lastAlerted[cooldownKey{entry.Type, entry.ItemID}] = entry.At
+	}
+	return state.AlertSeq, lastAlerted
+}
+
+// flushAlertState saves the alert sequence and unexpired cooldowns next to
+// storage_path if they changed. Failures are logged and retried on the next
+// flush.
+func (s *inventoryKeeperKeeper) flushAlertState(storagePath string) {
+	cooldown := s.config().alertCooldown()
+	now := s.now()
+
+	s.alertsMu.Lock()
+	if !s.alertStateDirty {
+		s.alertsMu.Unlock()
+		return
+	}
+	state := storedAlertState{Version: alertStateVersion, AlertSeq: s.alertSeq, LastAlerted: []storedCooldown{}}
+	for key, at := range s.lastAlerted {
+		if now.Sub(at) < cooldown {
+			state.LastAlerted = append(state.LastAlerted, storedCooldown{Type: key.Type, ItemID: key.ItemID, At: at})
+		}
+	}
+	s.alertStateDirty = false
+	s.alertsMu.Unlock()
+
+	sort.Slice(state.LastAlerted, func(i, j int) bool {
+		a, b := state.LastAlerted[i], state.LastAlerted[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.ItemID < b.ItemID
+	})
+
+	path := alertStatePath(storagePath)
+	err := s.saveAlertState(path, &state)
+	if err != nil {
+		s.logger.Errorf("Failed to save alert state to %s: %v", path, err)
+		s.alertsMu.Lock()
+		s.alertStateDirty = true
+		s.alertsMu.Unlock()
+	}
+}
+
+// saveAlertState writes the state to a temporary file and renames it into place
+func (s *inventoryKeeperKeeper) saveAlertState(path string, state *storedAlertState) error {
+	raw, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode alert state: %w", err)
+	}
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := s.writeStorageFile(tmp, raw, 0o644); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
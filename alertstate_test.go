@@ -0,0 +1,66 @@
+package inventorykeeper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAlertCooldown(t *testing.T) {
+	cooldown := 3600
+
+	t.Run("repeats within the cooldown are dropped", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{AlertCooldownSeconds: &cooldown})
+
+		if first := svc.raiseAlert("theft", severityCritical, "apple-001", "first", nil); first.ID != 1 {
+			t.Fatalf("expected first alert raised, got: %+v", first)
+		}
+		if repeat := svc.raiseAlert("theft", severityCritical, "apple-001", "repeat", nil); repeat.ID != 0 {
+			t.Errorf("expected repeat to be suppressed, got: %+v", repeat)
+		}
+		// Other items and other alert types have their own cooldowns
+		if other := svc.raiseAlert("theft", severityCritical, "banana-042", "other item", nil); other.ID != 2 {
+			t.Errorf("expected alert for another item, got: %+v", other)
+		}
+		if other := svc.raiseAlert("audit_discrepancy", severityWarning, "apple-001", "other type", nil); other.ID != 3 {
+			t.Errorf("expected alert of another type, got: %+v", other)
+		}
+	})
+
+	t.Run("cooldown and sequence survive a rebuild", func(t *testing.T) {
+		cfg := &Config{StoragePath: filepath.Join(t.TempDir(), "inventory.json"), AlertCooldownSeconds: &cooldown}
+		svc, _ := newTestKeeper(t, cfg)
+		svc.raiseAlert("theft", severityCritical, "apple-001", "before rebuild", nil)
+		svc.raiseAlert("theft", severityCritical, "banana-042", "before rebuild", nil)
+		svc.Close(context.Background())
+
+		rebuilt, _ := newTestKeeper(t, cfg)
+		if repeat := rebuilt.raiseAlert("theft", severityCritical, "apple-001", "after rebuild", nil); repeat.ID != 0 {
+			t.Errorf("expected apple-001 to stay in cooldown, got: %+v", repeat)
+		}
+		if next := rebuilt.raiseAlert("theft", severityCritical, "cheese-777", "after rebuild", nil); next.ID != 3 {
+			t.Errorf("expected alert ids to continue from 3, got: %d", next.ID)
+		}
+	})
+
+	t.Run("corrupt state starts fresh", func(t *testing.T) {
+		cfg := &Config{StoragePath: filepath.Join(t.TempDir(), "inventory.json"), AlertCooldownSeconds: &cooldown}
+		if err := os.WriteFile(alertStatePath(cfg.StoragePath), []byte("{not json"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		svc, _ := newTestKeeper(t, cfg)
+		if alert := svc.raiseAlert("theft", severityCritical, "apple-001", "fresh", nil); alert.ID != 1 {
+			t.Errorf("expected fresh alert ids, got: %+v", alert)
+		}
+	})
+
+	t.Run("no cooldown raises every alert", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		svc.raiseAlert("theft", severityCritical, "apple-001", "first", nil)
+		if repeat := svc.raiseAlert("theft", severityCritical, "apple-001", "repeat", nil); repeat.ID != 2 {
+			t.Errorf("expected repeat alert without a cooldown, got: %+v", repeat)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package inventorykeeper
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// intArg extracts an optional integer argument from a DoCommand payload.
+// JSON numbers arrive as float64, so whole-number floats are accepted.
+// Returns found=false when the key is absent.
+func intArg(cmd map[string]interface{}, key string) (value int, found bool, err error) {
+	raw, ok := cmd[key]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+
+	switch v := raw.(type) {
+	case int:
+		return v, true, nil
+	case int64:
+		return int(v), true, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, true, fmt.Errorf("%s must be a whole number, got: %v", key, v)
+		}
+		return int(v), true, nil
+	default:
+		return 0, true, fmt.Errorf("%s must be a number", key)
+	}
+}
+
+// optionalStringArg extracts an optional string argument, returning "" when absent
+func optionalStringArg(cmd map[string]interface{}, key string) (string, error) {
+	raw, ok := cmd[key]
+	if !ok || raw == nil {
+		return "", nil
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return value, nil
+}
+
+// timeArg extracts an optional RFC 3339 timestamp argument.
+// Returns found=false when the key is absent or empty.
+func timeArg(cmd map[string]interface{}, key string) (value time.Time, found bool, err error) {
+	raw, err := optionalStringArg(cmd, key)
+	if err != nil || raw == "" {
+		return time.Time{}, false, err
+	}
+	value, err = time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, true, fmt.Errorf("%s must be an RFC 3339 timestamp: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// floatArg extracts an optional numeric argument from a DoCommand payload.
+// Returns found=false when the key is absent.
+func floatArg(cmd map[string]interface{}, key string) (value float64, found bool, err error) {
+	raw, ok := cmd[key]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+
+	v, ok := toFloat(raw)
+	if !ok {
+		return 0, true, fmt.Errorf("%s must be a number", key)
+	}
+	return v, true, nil
+}
+
+// toFloat converts any Go numeric value to float64
+func toFloat(raw interface{}) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// stringSliceArg extracts an optional list of strings from a DoCommand payload.
+// Returns found=false when the key is absent.
+func stringSliceArg(cmd map[string]interface{}, key string) (values []string, found bool, err error) {
+	raw, ok := cmd[key]
+	if !ok || raw == nil {
+		return nil, false, nil
+	}
+
+	switch v := raw.(type) {
+	case []string:
+		return append([]string{}, v...), true, nil
+	case []interface{}:
+		values = make([]string, 0, len(v))
+		for _, entry := range v {
+			s, ok := entry.(string)
+			if !ok {
+				return nil, true, fmt.Errorf("%s must be a list of strings", key)
+			}
+			values = append(values, s)
+		}
+		return values, true, nil
+	default:
+		return nil, true, fmt.Errorf("%s must be a list of strings", key)
+	}
+}
+
+// toInterfaceSlice converts a string slice into the []interface{} form
+// DoCommand responses need to survive protobuf struct conversion
+func toInterfaceSlice(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,188 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// auditReport is the result of reconciling one namespace's recorded inventory
+// against its debounced present-set. Each bucket holds sorted item IDs.
+type auditReport struct {
+	Namespace            string    // Namespace that was reconciled
+	PresentAndRecorded   []string  // Recorded and currently seen on the shelf
+	RecordedButMissing   []string  // Recorded but not seen (theft or stale record)
+	PresentButUnrecorded []string  // Seen but never recorded (data-entry gap)
+	AuditedAt            time.Time // When the reconciliation ran
+}
+
+// toMap converts the report into a DoCommand-friendly response map
+func (r auditReport) toMap(loc *time.Location) map[string]interface{} {
+	return map[string]interface{}{
+		"namespace":              r.Namespace,
+		"present_and_recorded":   toInterfaceSlice(r.PresentAndRecorded),
+		"recorded_but_missing":   toInterfaceSlice(r.RecordedButMissing),
+		"present_but_unrecorded": toInterfaceSlice(r.PresentButUnrecorded),
+		"discrepancies":          len(r.RecordedButMissing) + len(r.PresentButUnrecorded),
+		"audited_at":             formatTimestampIn(r.AuditedAt, loc),
+	}
+}
+
+// reconcile compares a namespace's inventory with its present-set
+func (s *inventoryKeeperKeeper) reconcile(namespace string) auditReport {
+	present := make(map[string]bool)
+	s.monitorMu.Lock()
+	for key, entry := range s.presence {
+		if entry.Present && key.Namespace == namespace {
+			present[key.ItemID] = true
+		}
+	}
+	s.monitorMu.Unlock()
+
+	report := auditReport{
+		Namespace:            namespace,
+		PresentAndRecorded:   []string{},
+		RecordedButMissing:   []string{},
+		PresentButUnrecorded: []string{},
+		AuditedAt:            s.now(),
+	}
+
+	s.inventoryMu.RLock()
+	for key := range s.inventory {
+		if key.Namespace != namespace {
+			continue
+		}
+		if present[key.ItemID] {
+			report.PresentAndRecorded = append(report.PresentAndRecorded, key.ItemID)
+		} else {
+			report.RecordedButMissing = append(report.RecordedButMissing, key.ItemID)
+		}
+	}
+	for itemID := range present {
+		if _, recorded := s.inventory[keyFor(namespace, itemID)]; !recorded {
+			report.PresentButUnrecorded = append(report.PresentButUnrecorded, itemID)
+		}
+	}
+	s.inventoryMu.RUnlock()
+
+	sort.Strings(report.PresentAndRecorded)
+	sort.Strings(report.RecordedButMissing)
+	sort.Strings(report.PresentButUnrecorded)
+	return report
+}
+
+// runAudit reconciles a namespace and, when audit_alerts is enabled, raises an
+// alert for each discrepancy that was not already reported by its previous audit
+func (s *inventoryKeeperKeeper) runAudit(namespace string) auditReport {
+	report := s.reconcile(namespace)
+
+	current := make(map[itemKey]string)
+	for _, itemID := range report.RecordedButMissing {
+		current[keyFor(namespace, itemID)] = "recorded_but_missing"
+	}
+	for _, itemID := range report.PresentButUnrecorded {
+		current[keyFor(namespace, itemID)] = "present_but_unrecorded"
+	}
+
+	// Replace only this namespace's discrepancies
+	s.auditMu.Lock()
+	previous := make(map[itemKey]string)
+	next := make(map[itemKey]string, len(s.auditDiscrepancies)+len(current))
+	for key, bucket := range s.auditDiscrepancies {
+		if key.Namespace == namespace {
+			previous[key] = bucket
+		} else {
+			next[key] = bucket
+		}
+	}
+	for key, bucket := range current {
+		next[key] = bucket
+	}
+	s.auditDiscrepancies = next
+	s.auditMu.Unlock()
+
+	if !s.config().AuditAlerts {
+		return report
+	}
+
+	for _, itemID := range report.RecordedButMissing {
+		key := keyFor(namespace, itemID)
+		if previous[key] != current[key] {
+			s.raiseAlert("audit_discrepancy", severityWarning, itemID, fmt.Sprintf("Item %s is recorded in inventory but not on the shelf", itemID),
+				map[string]interface{}{"bucket": current[key], "namespace": namespace})
+		}
+	}
+	for _, itemID := range report.PresentButUnrecorded {
+		key := keyFor(namespace, itemID)
+		if previous[key] != current[key] {
+			s.raiseAlert("audit_discrepancy", severityInfo, itemID, fmt.Sprintf("Item %s is on the shelf but not recorded in inventory", itemID),
+				map[string]interface{}{"bucket": current[key], "namespace": namespace})
+		}
+	}
+	return report
+}
+
+// activeNamespaces lists, sorted, every namespace with recorded inventory or
+// tracked presence, plus any with discrepancies left from the last audit
+func (s *inventoryKeeperKeeper) activeNamespaces() []string {
+	seen := map[string]bool{}
+	s.inventoryMu.RLock()
+	for key := range s.inventory {
+		seen[key.Namespace] = true
+	}
+	s.inventoryMu.RUnlock()
+
+	s.monitorMu.Lock()
+	for key := range s.presence {
+		seen[key.Namespace] = true
+	}
+	s.monitorMu.Unlock()
+
+	s.auditMu.Lock()
+	for key := range s.auditDiscrepancies {
+		seen[key.Namespace] = true
+	}
+	s.auditMu.Unlock()
+
+	namespaces := make([]string, 0, len(seen))
+	for namespace := range seen {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// startAuditLoop runs runAudit every audit_interval_seconds until Close
+func (s *inventoryKeeperKeeper) startAuditLoop(interval time.Duration) {
+	s.logger.Infof("Starting inventory audit with interval: %v", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.cancelCtx.Done():
+				return
+			case <-ticker.C:
+				for _, namespace := range s.activeNamespaces() {
+					report := s.runAudit(namespace)
+					if n := len(report.RecordedButMissing) + len(report.PresentButUnrecorded); n > 0 {
+						s.logger.Infof("Inventory audit found %d discrepancies in namespace %s", n, namespace)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// handleAudit runs an on-demand reconciliation of one namespace and returns
+// the three buckets
+func (s *inventoryKeeperKeeper) handleAudit(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return s.runAudit(namespace).toMap(s.location), nil
+}
@@ -0,0 +1,79 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestAudit(t *testing.T) {
+	ctx := context.Background()
+
+	// Shelf shows apple (recorded) and cheese (never recorded); banana is recorded but gone
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+	cheese, _ := json.Marshal(ItemQRData{ItemID: "cheese-777", ItemName: "Aged Cheddar"})
+
+	setup := func(t *testing.T, cfg *Config) *inventoryKeeperKeeper {
+		svc, mockVision := newTestKeeper(t, cfg)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, string(apple)), testDetection(1, string(cheese))}, nil
+		}
+		for _, item := range []map[string]interface{}{
+			{"item_id": "apple-001", "item_name": "Honeycrisp Apple"},
+			{"item_id": "banana-042", "item_name": "Organic Banana"},
+		} {
+			item["command"] = "add_item"
+			mustDoCommand(t, svc, item)
+		}
+
+		// Two scans confirm presence with the default debounce
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		return svc
+	}
+
+	t.Run("audit reports the three buckets", func(t *testing.T) {
+		svc := setup(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "audit"})
+
+		expected := map[string][]interface{}{
+			"present_and_recorded":   {"apple-001"},
+			"recorded_but_missing":   {"banana-042"},
+			"present_but_unrecorded": {"cheese-777"},
+		}
+		for bucket, want := range expected {
+			if !reflect.DeepEqual(result[bucket], want) {
+				t.Errorf("%s: expected %v, got: %v", bucket, want, result[bucket])
+			}
+		}
+		if result["discrepancies"] != 2 {
+			t.Errorf("expected 2 discrepancies, got: %v", result["discrepancies"])
+		}
+
+		// Alerts are off by default
+		if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]; count != 0 {
+			t.Errorf("expected no alerts without audit_alerts, got: %v", count)
+		}
+	})
+
+	t.Run("audit_alerts raises each discrepancy once", func(t *testing.T) {
+		svc := setup(t, &Config{AuditAlerts: true})
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "audit"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "audit"})
+
+		alerts := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})
+		if alerts["count"] != 2 {
+			t.Fatalf("expected 2 alerts across repeated audits, got: %v", alerts["count"])
+		}
+		for _, raw := range alerts["alerts"].([]interface{}) {
+			if alert := raw.(map[string]interface{}); alert["type"] != "audit_discrepancy" {
+				t.Errorf("expected audit_discrepancy alert, got: %v", alert["type"])
+			}
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package inventorykeeper
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// errUnauthorized is returned for commands that need command_secret and
+// didn't carry it
+var errUnauthorized = errors.New("UNAUTHORIZED")
+
+// mutatingCommands change inventory, alerts, monitoring, or config, issue
+// credentials, or read files on the robot. With command_secret set they
+// require a matching auth field.
+var mutatingCommands = map[string]bool{
+	"create_item":             true,
+	"add_item":                true,
+	"update_item":             true,
+	"remove_item":             true,
+	"delete_item":             true,
+	"rename_item":             true,
+	"move_item":               true,
+	"merge_items":             true,
+	"repair_inventory":        true,
+	"adjust_quantity":         true,
+	"set_quantity":            true,
+	"set_item_status":         true,
+	"set_supplier_info":       true,
+	"fulfill_restock_request": true,
+	"check_in":                true,
+	"check_in_batch":          true,
+	"force_check_in":          true,
+	"replay_events":           true,
+	"test_notification":       true,
+	"acknowledge_alert":       true,
+	"clear_acknowledged":      true,
+	"restore_snapshot":        true,
+	"replicate_apply":         true,
+	"undo":                    true,
+	"set_config":              true,
+	"pause_monitoring":        true,
+	"resume_monitoring":       true,
+	"set_maintenance_mode":    true,
+	"generate_access_token":   true,
+	"enroll_person":           true,
+}
+
+// authorizeCommand checks the auth field of commands that need it. Mutating
+// commands need it whenever command_secret is set, and every command but
+// ping does with auth_all_commands.
+func (s *inventoryKeeperKeeper) authorizeCommand(cmdType string, cmd map[string]interface{}) error {
+	cfg := s.config()
+	if cfg.CommandSecret == "" || cmdType == "ping" {
+		return nil
+	}
+	if !mutatingCommands[cmdType] && !cfg.AuthAllCommands {
+		return nil
+	}
+
+	// Comparing digests keeps the comparison constant-time regardless of length
+	auth, _ := cmd["auth"].(string)
+	got := sha256.Sum256([]byte(auth))
+	want := sha256.Sum256([]byte(cfg.CommandSecret))
+	if auth == "" || subtle.ConstantTimeCompare(got[:], want[:]) != 1 {
+		return fmt.Errorf("%w: %s requires a valid auth field", errUnauthorized, cmdType)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCommandAuthorization(t *testing.T) {
+	ctx := context.Background()
+	addApple := func() map[string]interface{} {
+		return map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"}
+	}
+
+	t.Run("mutating command needs the secret", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CommandSecret: "s3cret"})
+
+		_, err := svc.DoCommand(ctx, addApple())
+		if !errors.Is(err, errUnauthorized) {
+			t.Fatalf("expected UNAUTHORIZED without auth, got: %v", err)
+		}
+
+		cmd := addApple()
+		cmd["auth"] = "wrong"
+		if _, err := svc.DoCommand(ctx, cmd); !errors.Is(err, errUnauthorized) {
+			t.Fatalf("expected UNAUTHORIZED with the wrong secret, got: %v", err)
+		}
+
+		cmd["auth"] = "s3cret"
+		if result := mustDoCommand(t, svc, cmd); result["item_id"] != "apple-001" {
+			t.Errorf("expected item added with the secret, got: %v", result)
+		}
+	})
+
+	t.Run("read-only commands stay open", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CommandSecret: "s3cret"})
+		for _, command := range []string{"ping", "list_items", "get_status"} {
+			mustDoCommand(t, svc, map[string]interface{}{"command": command})
+		}
+	})
+
+	t.Run("auth_all_commands protects everything but ping", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CommandSecret: "s3cret", AuthAllCommands: true})
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "ping"})
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "list_items"}); !errors.Is(err, errUnauthorized) {
+			t.Errorf("expected UNAUTHORIZED for list_items, got: %v", err)
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "list_items", "auth": "s3cret"})
+	})
+
+	t.Run("no secret leaves every command open", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, addApple())
+	})
+
+	t.Run("secret is redacted from snapshots", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CommandSecret: "s3cret"})
+		settings := mustDoCommand(t, svc, map[string]interface{}{"command": "snapshot"})["config"].(map[string]interface{})
+		if settings["command_secret"] != "[redacted]" {
+			t.Errorf("expected command_secret redacted, got: %v", settings["command_secret"])
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"slices"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// 1D barcode symbologies code_types can enable alongside QR. A barcode
+// carries only a SKU (digits for the retail codes), which resolveSKU maps to
+// an item.
+const (
+	codeTypeEAN13   = "ean13"
+	codeTypeUPCA    = "upca"
+	codeTypeCode128 = "code128"
+)
+
+// barcodeFormats maps each barcode code type to its decoder format
+var barcodeFormats = map[string]gozxing.BarcodeFormat{
+	codeTypeEAN13:   gozxing.BarcodeFormat_EAN_13,
+	codeTypeUPCA:    gozxing.BarcodeFormat_UPC_A,
+	codeTypeCode128: gozxing.BarcodeFormat_CODE_128,
+}
+
+// barcodePayloadPrefix marks a detection label produced by the barcode
+// decoder: "ikbar:<symbology>:<digits>". Keeping the symbology in the label
+// lets the scan pipeline tell a barcode from a QR payload holding digits.
+const barcodePayloadPrefix = "ikbar:"
+
+// codeTypes returns the configured symbologies, defaulting to QR only
+func (cfg *Config) codeTypes() []string {
+	if len(cfg.CodeTypes) == 0 {
+		return []string{codeTypeQR}
+	}
+	return cfg.CodeTypes
+}
+
+// scansQR reports whether QR codes are among the configured symbologies
+func (cfg *Config) scansQR() bool {
+	for _, codeType := range cfg.codeTypes() {
+		if codeType == codeTypeQR {
+			return true
+		}
+	}
+	return false
+}
+
+// barcodeFormats returns the decoder formats for the configured barcode
+// symbologies; empty when only QR is scanned
+func (cfg *Config) barcodeFormats() []gozxing.BarcodeFormat {
+	var formats []gozxing.BarcodeFormat
+	for _, codeType := range cfg.codeTypes() {
+		if format, ok := barcodeFormats[codeType]; ok {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// validateCodeTypes checks code_types names known, non-repeated symbologies
+func validateCodeTypes(codeTypes []string) error {
+	seen := make(map[string]bool, len(codeTypes))
+	for _, codeType := range codeTypes {
+		if _, ok := barcodeFormats[codeType]; !ok && codeType != codeTypeQR {
+			return fmt.Errorf("code_types entries must be %q, %q, %q, or %q, got: %q", codeTypeQR, codeTypeEAN13, codeTypeUPCA, codeTypeCode128, codeType)
+		}
+		if seen[codeType] {
+			return fmt.Errorf("code_types lists %q more than once", codeType)
+		}
+		seen[codeType] = true
+	}
+	return nil
+}
+
+// decodeBarcodes finds a barcode of the given formats in an image with the
+// pure-Go decoders: the retail (UPC/EAN) reader, then Code 128. Each 1D
+// reader returns at most one code per frame, and the first found is used. A
+// frame with no readable barcode is not an error.
+func decodeBarcodes(img image.Image, formats []gozxing.BarcodeFormat) ([]objectdetection.Detection, error) {
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to binarize image: %w", err)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_POSSIBLE_FORMATS: formats,
+		gozxing.DecodeHintType_TRY_HARDER:       true,
+	}
+	var readers []gozxing.Reader
+	if slices.ContainsFunc(formats, func(f gozxing.BarcodeFormat) bool { return f != gozxing.BarcodeFormat_CODE_128 }) {
+		readers = append(readers, oned.NewMultiFormatUPCEANReader(hints))
+	}
+	if slices.Contains(formats, gozxing.BarcodeFormat_CODE_128) {
+		readers = append(readers, oned.NewCode128Reader())
+	}
+
+	for _, reader := range readers {
+		result, err := reader.Decode(bmp, hints)
+		if err != nil {
+			// Not found, bad checksum, and malformed reads all mean no usable code
+			if _, unreadable := err.(gozxing.ReaderException); unreadable {
+				continue
+			}
+			return nil, fmt.Errorf("barcode decoding failed: %w", err)
+		}
+
+		symbology := codeTypeEAN13
+		for codeType, format := range barcodeFormats {
+			if format == result.GetBarcodeFormat() {
+				symbology = codeType
+			}
+		}
+		label := barcodePayloadPrefix + symbology + ":" + result.GetText()
+		return []objectdetection.Detection{
+			objectdetection.NewDetection(img.Bounds(), resultBounds(result, img.Bounds()), 1.0, label),
+		}, nil
+	}
+	return []objectdetection.Detection{}, nil
+}
+
+// parseBarcodeLabel splits a barcode detection label into its symbology and
+// value; ok is false for any other content
+func parseBarcodeLabel(content string) (symbology, digits string, ok bool) {
+	rest, found := strings.CutPrefix(content, barcodePayloadPrefix)
+	if !found {
+		return "", "", false
+	}
+	symbology, digits, found = strings.Cut(rest, ":")
+	if !found || digits == "" {
+		return "", "", false
+	}
+	return symbology, digits, true
+}
+
+// codeSymbology returns the symbology a detection label was decoded from
+func codeSymbology(content string) string {
+	if symbology, _, ok := parseBarcodeLabel(content); ok {
+		return symbology
+	}
+	return codeTypeQR
+}
+
+// codeDetector returns the frameDetector for the configured code_types: the
+// vision service for QR codes, followed by the local barcode decoder
+func (s *inventoryKeeperKeeper) codeDetector() frameDetector {
+	cfg := s.config()
+	formats := cfg.barcodeFormats()
+	if len(formats) == 0 {
+		return s.visionDetections
+	}
+	scanQR := cfg.scansQR()
+	return func(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+		var detections []objectdetection.Detection
+		if scanQR {
+			qr, err := s.visionDetections(ctx, img)
+			if err != nil {
+				return nil, err
+			}
+			detections = qr
+		}
+		barcodes, err := decodeBarcodes(img, formats)
+		if err != nil {
+			return nil, err
+		}
+		return append(detections, barcodes...), nil
+	}
+}
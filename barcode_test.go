@@ -0,0 +1,96 @@
+package inventorykeeper
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestBarcodeScanning(t *testing.T) {
+	// barcodeFrame places an EAN-13 barcode on a white background
+	barcodeFrame := func(t *testing.T, digits string) image.Image {
+		t.Helper()
+		bars, err := oned.NewEAN13Writer().Encode(digits, gozxing.BarcodeFormat_EAN_13, 300, 120, nil)
+		if err != nil {
+			t.Fatalf("failed to render EAN-13: %v", err)
+		}
+		frame := image.NewRGBA(image.Rect(0, 0, 400, 200))
+		draw.Draw(frame, frame.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(frame, image.Rect(50, 40, 350, 160), bars, image.Point{}, draw.Src)
+		return frame
+	}
+
+	t.Run("EAN-13 decodes to its digits as the item ID", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CodeTypes: []string{codeTypeEAN13}})
+		setCameraFrame(t, svc, barcodeFrame(t, "4006381333931"))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		codes := result["codes"].([]interface{})
+		if len(codes) != 1 {
+			t.Fatalf("expected 1 code, got: %v", codes)
+		}
+		code := codes[0].(map[string]interface{})
+		if code["item_id"] != "4006381333931" {
+			t.Errorf("expected item_id 4006381333931, got: %v", code["item_id"])
+		}
+		if code["item_name"] != "" {
+			t.Errorf("expected empty item_name, got: %v", code["item_name"])
+		}
+		if code["symbology"] != codeTypeEAN13 {
+			t.Errorf("expected symbology ean13, got: %v", code["symbology"])
+		}
+	})
+
+	t.Run("UPC-A is reported when enabled", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CodeTypes: []string{codeTypeEAN13, codeTypeUPCA}})
+		setCameraFrame(t, svc, barcodeFrame(t, "0036000291452"))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		code := result["codes"].([]interface{})[0].(map[string]interface{})
+		if code["item_id"] != "036000291452" || code["symbology"] != codeTypeUPCA {
+			t.Errorf("expected UPC-A 036000291452, got: %v", code)
+		}
+	})
+
+	t.Run("QR and barcodes are scanned together", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{CodeTypes: []string{codeTypeQR, codeTypeEAN13}})
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, `{"item_id":"apple-001","item_name":"Apple"}`)}, nil
+		}
+		setCameraFrame(t, svc, barcodeFrame(t, "4006381333931"))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		symbologies := map[interface{}]interface{}{}
+		for _, raw := range result["codes"].([]interface{}) {
+			code := raw.(map[string]interface{})
+			symbologies[code["item_id"]] = code["symbology"]
+		}
+		if symbologies["apple-001"] != codeTypeQR || symbologies["4006381333931"] != codeTypeEAN13 {
+			t.Errorf("expected a QR code and an EAN-13, got: %v", symbologies)
+		}
+	})
+
+	t.Run("barcodes are ignored by default", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{})
+		setCameraFrame(t, svc, barcodeFrame(t, "4006381333931"))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		if result["count"] != 0 {
+			t.Errorf("expected no codes with QR-only scanning, got: %v", result["codes"])
+		}
+	})
+
+	t.Run("invalid code_types are rejected", func(t *testing.T) {
+		for _, codeTypes := range [][]string{{"aztec"}, {codeTypeQR, codeTypeQR}} {
+			cfg := &Config{CameraName: "cam", QRVisionService: "qr", CodeTypes: codeTypes}
+			if _, _, err := cfg.Validate(""); err == nil {
+				t.Errorf("expected validation error for code_types %v", codeTypes)
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// benchmark_scan iteration limits
+const (
+	defaultBenchmarkIterations = 20
+	maxBenchmarkIterations     = 1000
+)
+
+// benchmarkPayload is the item encoded in the synthetic benchmark frame
+var benchmarkPayload = ItemQRData{ItemID: "benchmark-001", ItemName: "Benchmark Item"}
+
+// benchmarkFrame returns the encoded frame each benchmark iteration decodes:
+// the supplied base64 image, or a synthetic 640x480 PNG with one QR code
+func (s *inventoryKeeperKeeper) benchmarkFrame(cmd map[string]interface{}) ([]byte, string, error) {
+	encoded, err := optionalStringArg(cmd, "image")
+	if err != nil {
+		return nil, "", err
+	}
+	if encoded != "" {
+		if err := s.config().checkImageArg("image", encoded); err != nil {
+			return nil, "", err
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, "", fmt.Errorf("image is not valid base64: %w", err)
+		}
+		return raw, "supplied", nil
+	}
+
+	payload, err := s.encodeQRPayload(benchmarkPayload)
+	if err != nil {
+		return nil, "", err
+	}
+	frame := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	draw.Draw(frame, frame.Bounds(), image.White, image.Point{}, draw.Src)
+	if err := drawLabelCode(frame, payload, image.Pt(192, 112), 256); err != nil {
+		return nil, "", fmt.Errorf("failed to generate benchmark QR code: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, frame); err != nil {
+		return nil, "", fmt.Errorf("failed to encode benchmark frame: %w", err)
+	}
+	raw := buf.Bytes()
+	return raw, "synthetic", nil
+}
+
+// latencySummary reports min, max, mean, and p95 of the durations in
+// milliseconds. durations must be non-empty; it is sorted in place.
+func latencySummary(durations []time.Duration) map[string]interface{} {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	p95 := durations[int(math.Ceil(0.95*float64(len(durations))))-1]
+	return map[string]interface{}{
+		"min_ms":  ms(durations[0]),
+		"max_ms":  ms(durations[len(durations)-1]),
+		"mean_ms": ms(total / time.Duration(len(durations))),
+		"p95_ms":  ms(p95),
+	}
+}
+
+// handleBenchmarkScan times the decode side of the scan pipeline (image
+// decoding, preprocessing, ROI, code detection, and payload decoding) over
+// one frame for a number of iterations. It never touches monitoring state,
+// but shares vision call slots with the monitoring loop. Refused unless
+// enable_benchmark is set, so it can't load a production device by accident.
+func (s *inventoryKeeperKeeper) handleBenchmarkScan(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	cfg := s.config()
+	if !cfg.EnableBenchmark {
+		return nil, errors.New("benchmark_scan is disabled; set enable_benchmark to use it")
+	}
+	iterations, hasIterations, err := intArg(cmd, "iterations")
+	if err != nil {
+		return nil, err
+	}
+	if !hasIterations {
+		iterations = defaultBenchmarkIterations
+	}
+	if iterations < 1 || iterations > maxBenchmarkIterations {
+		return nil, fmt.Errorf("iterations must be between 1 and %d, got: %d", maxBenchmarkIterations, iterations)
+	}
+	raw, source, err := s.benchmarkFrame(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var latencies []time.Duration
+	var failures, codes, decoded int
+	var lastErr error
+	start := time.Now() // Throughput is wall-clock time, even under a test clock
+	for i := 0; i < iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("benchmark stopped after %d of %d iterations: %w", i, iterations, err)
+		}
+
+		began := time.Now()
+		detections, err := s.benchmarkIteration(ctx, raw)
+		latencies = append(latencies, time.Since(began))
+		if err != nil {
+			failures++
+			lastErr = err
+			continue
+		}
+		codes += len(detections)
+		decoded += s.countDecodable(detections)
+	}
+	elapsed := time.Since(start)
+
+	throughput := 0.0
+	if elapsed > 0 {
+		throughput = float64(iterations) / elapsed.Seconds()
+	}
+	result := map[string]interface{}{
+		"source":           source,
+		"iterations":       iterations,
+		"errors":           failures,
+		"error_rate":       float64(failures) / float64(iterations),
+		"elapsed_seconds":  elapsed.Seconds(),
+		"scans_per_second": throughput,
+		"latency":          latencySummary(latencies),
+		"codes_per_scan":   0.0,
+		"decoded_per_scan": 0.0,
+	}
+	if succeeded := iterations - failures; succeeded > 0 {
+		result["codes_per_scan"] = float64(codes) / float64(succeeded)
+		result["decoded_per_scan"] = float64(decoded) / float64(succeeded)
+	}
+	if lastErr != nil {
+		result["last_error"] = lastErr.Error()
+	}
+	s.logger.Infof("Benchmarked scan pipeline: %d iterations, %.1f scans/s, %d errors", iterations, throughput, failures)
+	return result, nil
+}
+
+// benchmarkIteration runs one pass of the decode pipeline over an encoded frame
+func (s *inventoryKeeperKeeper) benchmarkIteration(ctx context.Context, raw []byte) ([]objectdetection.Detection, error) {
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode frame: %w", err)
+	}
+	return s.detectInFrame(ctx, preprocessImage(img, s.config()))
+}
@@ -0,0 +1,88 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestBenchmarkScan(t *testing.T) {
+	t.Run("refused unless enable_benchmark is set", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "benchmark_scan"}); err == nil {
+			t.Error("expected benchmark_scan to be refused without enable_benchmark")
+		}
+	})
+
+	t.Run("synthetic frame returns sane statistics", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{EnableBenchmark: true})
+		// Decode the frame for real, so the synthetic QR code has to be readable
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return decodeQRCodesLocally(ctx, img)
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "benchmark_scan", "iterations": 5.0})
+
+		if result["source"] != "synthetic" || result["iterations"] != 5 {
+			t.Errorf("expected 5 iterations over the synthetic frame, got: %v", result)
+		}
+		if result["errors"] != 0 || result["error_rate"] != 0.0 {
+			t.Errorf("expected no errors, got: %v (%v)", result["errors"], result["last_error"])
+		}
+		if result["decoded_per_scan"] != 1.0 {
+			t.Errorf("expected the synthetic code decoded every scan, got: %v", result["decoded_per_scan"])
+		}
+		if rate := result["scans_per_second"].(float64); rate <= 0 {
+			t.Errorf("expected positive throughput, got: %v", rate)
+		}
+		latency := result["latency"].(map[string]interface{})
+		low, mean, p95, high := latency["min_ms"].(float64), latency["mean_ms"].(float64), latency["p95_ms"].(float64), latency["max_ms"].(float64)
+		if low <= 0 || low > mean || mean > high || p95 < low || p95 > high {
+			t.Errorf("expected 0 < min <= mean <= max and min <= p95 <= max, got: %v", latency)
+		}
+		if len(svc.presence) != 0 || !svc.lastScanAt.IsZero() {
+			t.Errorf("expected live monitoring state untouched, got: %v", svc.presence)
+		}
+	})
+
+	t.Run("failed iterations count toward the error rate", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{EnableBenchmark: true})
+		calls := 0
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			calls++
+			if calls%2 == 0 {
+				return nil, errors.New("vision unavailable")
+			}
+			return nil, nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "benchmark_scan", "iterations": 4.0})
+		if result["errors"] != 2 || result["error_rate"] != 0.5 {
+			t.Errorf("expected 2 of 4 iterations to fail, got: %v", result)
+		}
+		if result["last_error"] == nil {
+			t.Error("expected the last error to be reported")
+		}
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{EnableBenchmark: true})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := svc.handleBenchmarkScan(ctx, map[string]interface{}{"iterations": 3.0}); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected a cancelled benchmark, got: %v", err)
+		}
+	})
+
+	t.Run("rejects out of range iterations", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{EnableBenchmark: true})
+		for _, iterations := range []float64{0, maxBenchmarkIterations + 1} {
+			if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "benchmark_scan", "iterations": iterations}); err == nil {
+				t.Errorf("expected iterations %v to be rejected", iterations)
+			}
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltItemsBucket is the table holding items
+var boltItemsBucket = []byte("items")
+
+// boltOpenTimeout bounds waiting for another process to release db_path.
+// bbolt locks the file, so only one keeper can use a database at a time.
+const boltOpenTimeout = 5 * time.Second
+
+// boltStore keeps each item as JSON in an embedded bbolt database, one
+// record per item in the items bucket, keyed by item_id prefixed with its
+// namespace ("<namespace>/<item_id>") since an item_id can be reused across
+// namespaces. A save writes only the records of the items that changed.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// openBoltStore opens the database at path, creating it and its items
+// bucket if needed
+func openBoltStore(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open db_path %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltItemsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set up db_path %s: %w", path, err)
+	}
+	return &boltStore{db: db}, nil
+}
+
+// boltKey returns the record key for an item key
+func boltKey(key itemKey) []byte {
+	return []byte(key.Namespace + "/" + key.ItemID)
+}
+
+// decodeBoltItem parses a stored record. bbolt values are only valid inside
+// their transaction, so the item must not keep references to raw.
+func decodeBoltItem(key, raw []byte) (*InventoryItem, error) {
+	var item InventoryItem
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return nil, fmt.Errorf("failed to decode item %s: %w", key, err)
+	}
+	item.Namespace = normalizeNamespace(item.Namespace)
+	return &item, nil
+}
+
+// Get implements InventoryStore
+func (b *boltStore) Get(ctx context.Context, key itemKey) (*InventoryItem, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	var item *InventoryItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(boltItemsBucket).Get(boltKey(key))
+		if raw == nil {
+			return nil
+		}
+		var err error
+		item, err = decodeBoltItem(boltKey(key), raw)
+		return err
+	})
+	if err != nil || item == nil {
+		return nil, false, err
+	}
+	return item, true, nil
+}
+
+// Put implements InventoryStore
+func (b *boltStore) Put(ctx context.Context, item *InventoryItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltItemsBucket).Put(boltKey(item.key()), encoded)
+	})
+}
+
+// Delete implements InventoryStore
+func (b *boltStore) Delete(ctx context.Context, key itemKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltItemsBucket).Delete(boltKey(key))
+	})
+}
+
+// List implements InventoryStore
+func (b *boltStore) List(ctx context.Context) ([]*InventoryItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	byKey := map[itemKey]*InventoryItem{}
+	var keys []itemKey
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltItemsBucket).ForEach(func(k, v []byte) error {
+			item, err := decodeBoltItem(k, v)
+			if err != nil {
+				return err
+			}
+			byKey[item.key()] = item
+			keys = append(keys, item.key())
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys sort bytewise, which puts "a-b/..." before "a/..."
+	sortItemKeys(keys)
+	items := make([]*InventoryItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, byKey[key])
+	}
+	return items, nil
+}
+
+// Close implements InventoryStore
+func (b *boltStore) Close() error { return b.db.Close() }
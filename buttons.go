@@ -0,0 +1,98 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.viam.com/rdk/components/input"
+)
+
+// validateButtonCommands checks that button_controller and button_commands
+// are set together and that each button runs a named command
+func (cfg *Config) validateButtonCommands() error {
+	if cfg.ButtonController == "" {
+		if len(cfg.ButtonCommands) > 0 {
+			return errors.New("button_controller is required when button_commands is set")
+		}
+		return nil
+	}
+	if len(cfg.ButtonCommands) == 0 {
+		return errors.New("button_commands is required when button_controller is set")
+	}
+	for control, cmd := range cfg.ButtonCommands {
+		if control == "" {
+			return errors.New("button_commands keys must be non-empty control names")
+		}
+		if name, ok := cmd["command"].(string); !ok || name == "" {
+			return fmt.Errorf("button_commands[%s] must have a command string", control)
+		}
+		if _, ok := cmd["auth"]; ok {
+			return fmt.Errorf("button_commands[%s] must not set auth; button presses are authorized by the config", control)
+		}
+	}
+	return nil
+}
+
+// buttonControls returns the configured controls, sorted
+func (cfg *Config) buttonControls() []input.Control {
+	controls := make([]input.Control, 0, len(cfg.ButtonCommands))
+	for control := range cfg.ButtonCommands {
+		controls = append(controls, input.Control(control))
+	}
+	sort.Slice(controls, func(i, j int) bool { return controls[i] < controls[j] })
+	return controls
+}
+
+// startButtons subscribes to presses of each configured button. Each press
+// runs its command in its own goroutine so a slow scan never holds up the
+// controller's event delivery.
+func (s *inventoryKeeperKeeper) startButtons(ctx context.Context) error {
+	cfg := s.config()
+	for _, control := range cfg.buttonControls() {
+		err := s.buttonController.RegisterControlCallback(ctx, control, []input.EventType{input.ButtonPress},
+			func(ctx context.Context, event input.Event) {
+				go s.pressButton(event.Control)
+			}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s on button controller %s: %w", control, cfg.ButtonController, err)
+		}
+	}
+	s.logger.Infof("Listening for %d buttons on %s", len(cfg.ButtonCommands), cfg.ButtonController)
+	return nil
+}
+
+// stopButtons removes the button callbacks registered by startButtons
+func (s *inventoryKeeperKeeper) stopButtons(ctx context.Context) {
+	for _, control := range s.config().buttonControls() {
+		if err := s.buttonController.RegisterControlCallback(ctx, control, []input.EventType{input.ButtonPress}, nil, nil); err != nil {
+			s.logger.Debugf("Failed to unsubscribe from %s: %v", control, err)
+		}
+	}
+}
+
+// pressButton runs the command mapped to a pressed button. The config is
+// trusted, so the command is sent with command_secret when one is set.
+func (s *inventoryKeeperKeeper) pressButton(control input.Control) {
+	cfg := s.config()
+	mapped, ok := cfg.ButtonCommands[string(control)]
+	if !ok {
+		return
+	}
+	cmd := make(map[string]interface{}, len(mapped)+1)
+	for key, value := range mapped {
+		cmd[key] = value
+	}
+	if cfg.CommandSecret != "" {
+		cmd["auth"] = cfg.CommandSecret
+	}
+
+	if _, err := s.DoCommand(s.cancelCtx, cmd); err != nil {
+		s.logger.Warnw(fmt.Sprintf("Button %s failed to run %v: %v", control, cmd["command"], err),
+			"event", "button_press", "control", string(control), "command", cmd["command"])
+		return
+	}
+	s.logger.Infow(fmt.Sprintf("Button %s ran %v", control, cmd["command"]),
+		"event", "button_press", "control", string(control), "command", cmd["command"])
+}
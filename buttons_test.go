@@ -0,0 +1,115 @@
+package inventorykeeper
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/components/input"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// fakeButtons is an input controller whose registered callbacks can be
+// triggered by press
+type fakeButtons struct {
+	*inject.InputController
+	mu        sync.Mutex
+	callbacks map[input.Control]input.ControlFunction
+}
+
+func newFakeButtons(name string) *fakeButtons {
+	b := &fakeButtons{InputController: inject.NewInputController(name), callbacks: map[input.Control]input.ControlFunction{}}
+	b.RegisterControlCallbackFunc = func(ctx context.Context, control input.Control, triggers []input.EventType, ctrlFunc input.ControlFunction, extra map[string]interface{}) error {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if ctrlFunc == nil {
+			delete(b.callbacks, control)
+		} else {
+			b.callbacks[control] = ctrlFunc
+		}
+		return nil
+	}
+	return b
+}
+
+// press delivers a button press to control's callback, reporting whether one
+// was registered
+func (b *fakeButtons) press(control input.Control) bool {
+	b.mu.Lock()
+	callback, ok := b.callbacks[control]
+	b.mu.Unlock()
+	if ok {
+		callback(context.Background(), input.Event{Time: time.Now(), Event: input.ButtonPress, Control: control, Value: 1})
+	}
+	return ok
+}
+
+func TestButtonCommands(t *testing.T) {
+	t.Run("config is validated", func(t *testing.T) {
+		for _, cfg := range []*Config{
+			{ButtonController: "deck"},
+			{ButtonCommands: map[string]map[string]interface{}{"ButtonSouth": {"command": "scan_shelf"}}},
+			{ButtonController: "deck", ButtonCommands: map[string]map[string]interface{}{"ButtonSouth": {}}},
+			{ButtonController: "deck", ButtonCommands: map[string]map[string]interface{}{"ButtonSouth": {"command": "undo", "auth": "x"}}},
+		} {
+			cfg.CameraName, cfg.QRVisionService = "cam", "qr"
+			if _, _, err := cfg.Validate("test"); err == nil {
+				t.Errorf("expected error for %+v", cfg)
+			}
+		}
+
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", ButtonController: "deck",
+			ButtonCommands: map[string]map[string]interface{}{"ButtonSouth": {"command": "scan_shelf"}}}
+		deps, _, err := cfg.Validate("test")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deps[len(deps)-1] != "deck" {
+			t.Errorf("expected the button controller as a dependency, got: %v", deps)
+		}
+	})
+
+	t.Run("pressing a button runs its command with the command secret", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{
+			CommandSecret:    "shelf-secret",
+			ButtonController: "deck",
+			ButtonCommands: map[string]map[string]interface{}{
+				"ButtonEast": {"command": "acknowledge_alert", "latest": true, "note": "Checked at shelf"},
+			},
+		})
+		svc.raiseAlert("theft", severityCritical, "apple-001", "Item apple-001 removed without check-in", nil)
+
+		buttons := svc.buttonController.(*fakeButtons)
+		if buttons.press("ButtonWest") {
+			t.Error("expected no callback for an unmapped button")
+		}
+		if !buttons.press("ButtonEast") {
+			t.Fatal("expected a callback for ButtonEast")
+		}
+
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})
+			if result["count"] == 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the button to acknowledge the alert, got: %v", result)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("close removes the callbacks", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{
+			ButtonController: "deck",
+			ButtonCommands:   map[string]map[string]interface{}{"ButtonNorth": {"command": "scan_shelf"}},
+		})
+		buttons := svc.buttonController.(*fakeButtons)
+		svc.Close(context.Background())
+		if buttons.press("ButtonNorth") {
+			t.Error("expected no callback after close")
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package inventorykeeper
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policies for adding an item once max_inventory_items is reached
+const (
+	evictionReject = "reject"
+	evictionLRU    = "evict_lru"
+)
+
+// validEvictionPolicies are the accepted inventory_eviction values
+var validEvictionPolicies = map[string]bool{
+	evictionReject: true,
+	evictionLRU:    true,
+}
+
+// maxInventoryItems returns the inventory size cap, or 0 when unbounded
+func (cfg *Config) maxInventoryItems() int {
+	if cfg.MaxInventoryItems == nil {
+		return 0
+	}
+	return *cfg.MaxInventoryItems
+}
+
+// inventoryEviction returns the policy applied when the inventory is full,
+// defaulting to rejecting the add
+func (cfg *Config) inventoryEviction() string {
+	if cfg.InventoryEviction == "" {
+		return evictionReject
+	}
+	return cfg.InventoryEviction
+}
+
+// leastRecentlyUpdatedLocked returns the key of the item with the oldest
+// UpdatedAt, breaking ties by namespace and item_id so eviction is
+// deterministic. Caller must hold inventoryMu.
+func (s *inventoryKeeperKeeper) leastRecentlyUpdatedLocked() (itemKey, bool) {
+	var oldest itemKey
+	var oldestAt time.Time
+	found := false
+	for key, item := range s.inventory {
+		if found {
+			if item.UpdatedAt.After(oldestAt) {
+				continue
+			}
+			if item.UpdatedAt.Equal(oldestAt) && !itemKeyLess(key, oldest) {
+				continue
+			}
+		}
+		oldest, oldestAt, found = key, item.UpdatedAt, true
+	}
+	return oldest, found
+}
+
+// makeRoomLocked ensures one more item fits under max_inventory_items, either
+// by rejecting the add or evicting least-recently-updated items. Returns the
+// evicted items. Caller must hold inventoryMu for writing.
+func (s *inventoryKeeperKeeper) makeRoomLocked() ([]*InventoryItem, error) {
+	cfg := s.config()
+	limit := cfg.maxInventoryItems()
+	if limit == 0 || len(s.inventory) < limit {
+		return nil, nil
+	}
+	if cfg.inventoryEviction() == evictionReject {
+		return nil, fmt.Errorf("inventory is full (max_inventory_items is %d); remove an item first", limit)
+	}
+
+	// A lowered limit can leave more than one item over the cap
+	var evicted []*InventoryItem
+	for len(s.inventory) >= limit {
+		key, ok := s.leastRecentlyUpdatedLocked()
+		if !ok {
+			break
+		}
+		item := s.inventory[key]
+		delete(s.inventory, key)
+		s.pushUndoLocked("evict_item", key, item)
+		s.recordHistoryLocked("item_evicted", key.Namespace, key.ItemID, map[string]interface{}{
+			"item_name":  item.ItemName,
+			"quantity":   item.Quantity,
+			"updated_at": formatTimestampIn(item.UpdatedAt, s.location),
+		})
+		s.logger.Infof("Evicted least-recently-updated item %s (%s) from namespace %s to stay within %d items",
+			key.ItemID, item.ItemName, key.Namespace, limit)
+		evicted = append(evicted, item)
+	}
+	return evicted, nil
+}
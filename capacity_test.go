@@ -0,0 +1,83 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaxInventoryItems(t *testing.T) {
+	limit := 2
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	// fill adds apple and banana, with apple the least recently updated
+	fill := func(t *testing.T, svc *inventoryKeeperKeeper) {
+		t.Helper()
+		for i, id := range []string{"apple-001", "banana-001"} {
+			mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": id, "item_name": id})
+			svc.inventory[keyFor(defaultNamespace, id)].UpdatedAt = base.Add(time.Duration(i) * time.Minute)
+		}
+	}
+
+	t.Run("reject policy refuses adds when full", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{MaxInventoryItems: &limit})
+		fill(t, svc)
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "add_item", "item_id": "cherry-001", "item_name": "Cherry",
+		})
+		if err == nil {
+			t.Fatal("expected error adding past max_inventory_items")
+		}
+		if len(svc.inventory) != limit {
+			t.Errorf("expected inventory to stay at %d items, got: %d", limit, len(svc.inventory))
+		}
+	})
+
+	t.Run("evict_lru replaces the least recently updated item", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{MaxInventoryItems: &limit, InventoryEviction: evictionLRU})
+		fill(t, svc)
+
+		// Touching apple makes banana the oldest
+		svc.inventory[keyFor(defaultNamespace, "apple-001")].UpdatedAt = base.Add(time.Hour)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "cherry-001", "item_name": "Cherry",
+		})
+
+		if _, ok := svc.inventory[keyFor(defaultNamespace, "banana-001")]; ok {
+			t.Error("expected banana-001 to be evicted")
+		}
+		for _, id := range []string{"apple-001", "cherry-001"} {
+			if _, ok := svc.inventory[keyFor(defaultNamespace, id)]; !ok {
+				t.Errorf("expected %s to remain in inventory", id)
+			}
+		}
+		evicted := result["evicted"].([]interface{})
+		if len(evicted) != 1 || evicted[0].(map[string]interface{})["item_id"] != "banana-001" {
+			t.Errorf("expected banana-001 reported as evicted, got: %v", evicted)
+		}
+	})
+
+	t.Run("unbounded when unset", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		for _, id := range []string{"a", "b", "c"} {
+			mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": id, "item_name": id})
+		}
+		if len(svc.inventory) != 3 {
+			t.Errorf("expected 3 items, got: %d", len(svc.inventory))
+		}
+	})
+
+	t.Run("invalid settings are rejected", func(t *testing.T) {
+		zero := 0
+		for _, cfg := range []*Config{
+			{CameraName: "cam", QRVisionService: "qr", MaxInventoryItems: &zero},
+			{CameraName: "cam", QRVisionService: "qr", InventoryEviction: "fifo"},
+		} {
+			if _, _, err := cfg.Validate(""); err == nil {
+				t.Errorf("expected validation error for %+v", cfg)
+			}
+		}
+	})
+}
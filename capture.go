@@ -0,0 +1,281 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// captureRetryBackoff is the delay before the first capture retry; each
+// further retry waits one more multiple of it
+const captureRetryBackoff = 50 * time.Millisecond
+
+// captureFrame grabs a single decoded frame from the shelf camera, asking
+// for capture_mime_type and decoding whatever format the camera returns
+func (s *inventoryKeeperKeeper) captureFrame(ctx context.Context) (image.Image, error) {
+	if err := s.warmUpCamera(ctx); err != nil {
+		return nil, err
+	}
+	cfg := s.config()
+	requested := cfg.captureMimeType()
+	frame, meta, err := s.shelfCamera().Image(ctx, requested, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture from camera %s: %w", cfg.CameraName, err)
+	}
+	s.recordFrameTaken()
+	if len(frame) == 0 {
+		return nil, fmt.Errorf("failed to capture from camera %s: received empty frame", cfg.CameraName)
+	}
+	img, err := decodeFrame(ctx, frame, meta.MimeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode frame from camera %s: %w", cfg.CameraName, err)
+	}
+	s.recordFrameMimeType(requested, meta.MimeType)
+	return img, nil
+}
+
+// detectQRCodes runs the QR vision service against the shelf camera,
+// retrying failed attempts up to capture_retries times. The outcome feeds
+// camera health so persistent failures are reported as degraded.
+func (s *inventoryKeeperKeeper) detectQRCodes(ctx context.Context) ([]objectdetection.Detection, error) {
+	detections, _, err := s.detectQRCodesWithSource(ctx)
+	return detections, err
+}
+
+// detectQRCodesWithSource is detectQRCodes, also reporting whether the
+// detections came from the vision service or, with local_decode_fallback,
+// from the local decoder after the vision service failed
+func (s *inventoryKeeperKeeper) detectQRCodesWithSource(ctx context.Context) ([]objectdetection.Detection, string, error) {
+	retries := s.config().captureRetries()
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			s.logger.Debugf("Retrying QR scan (attempt %d of %d) after error: %v", attempt, retries, err)
+			select {
+			case <-ctx.Done():
+				return nil, "", ctx.Err()
+			case <-time.After(time.Duration(attempt) * captureRetryBackoff):
+			}
+		}
+
+		var detections []objectdetection.Detection
+		detections, err = s.detectQRCodesOnce(ctx)
+		if err == nil {
+			s.recordCaptureSuccess()
+			return detections, detectionSourceVision, nil
+		}
+		if errors.Is(err, errVisionBusy) {
+			// Contention says nothing about the camera, so don't retry or
+			// count it against camera health
+			return nil, "", err
+		}
+	}
+
+	if s.config().LocalDecodeFallback && ctx.Err() == nil {
+		detections, localErr := s.detectLocally(ctx)
+		if localErr == nil {
+			s.recordLocalFallback(err)
+			return detections, detectionSourceLocal, nil
+		}
+		s.logger.Debugf("Local QR decode fallback failed: %v", localErr)
+	}
+
+	s.recordCaptureFailure(err)
+	s.onDependencyFailure()
+	return nil, "", err
+}
+
+// detectQRCodesOnce makes a single detection attempt. When preprocessing, a
+// region of interest, duplicate frame skipping, or barcode code_types are
+// configured the frame is captured locally, preprocessed, and cropped first;
+// otherwise the vision service captures it directly.
+func (s *inventoryKeeperKeeper) detectQRCodesOnce(ctx context.Context) ([]objectdetection.Detection, error) {
+	cfg := s.config()
+	if !cfg.preprocessingEnabled() && cfg.ROI == nil && !cfg.SkipDuplicateFrames && len(cfg.barcodeFormats()) == 0 {
+		if err := s.warmUpCamera(ctx); err != nil {
+			return nil, err
+		}
+		detections, err := s.visionDetectionsFromCamera(ctx, cfg.CameraName)
+		if err == nil {
+			s.recordFrameTaken()
+		}
+		return detections, err
+	}
+
+	img, err := s.captureFrame(ctx)
+	if err != nil {
+		return nil, err
+	}
+	frame := preprocessImage(img, cfg)
+	if cfg.SkipDuplicateFrames {
+		return s.detectInFrameDeduped(ctx, cfg.CameraName, frame)
+	}
+	return s.detectInFrame(ctx, frame)
+}
+
+// scanFrame captures one frame and detects its codes, from sourceName when
+// set and otherwise from the configured camera (falling back to the local
+// decoder). Returns the detections and which decoder produced them.
+func (s *inventoryKeeperKeeper) scanFrame(ctx context.Context, sourceName string) ([]objectdetection.Detection, string, error) {
+	if sourceName == "" {
+		detections, source, err := s.detectQRCodesWithSource(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan QR codes: %w", err)
+		}
+		return detections, source, nil
+	}
+
+	img, err := s.captureFrameFromSource(ctx, sourceName)
+	if err != nil {
+		return nil, "", err
+	}
+	detections, err := s.detectInFrame(ctx, preprocessImage(img, s.config()))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan QR codes: %w", err)
+	}
+	return detections, detectionSourceVision, nil
+}
+
+// handleScanQR runs a single on-demand scan and returns the decoded codes in
+// view. Monitoring state is left untouched. With source_name the frame comes
+// from that camera image source, in a single attempt that doesn't count
+// toward camera health.
+func (s *inventoryKeeperKeeper) handleScanQR(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	sourceName, err := optionalStringArg(cmd, "source_name")
+	if err != nil {
+		return nil, err
+	}
+	pageSize, cursor, err := pageArgs(cmd, "scan_qr", "")
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil {
+		// Later pages come from the saved scan, not a new capture
+		return s.scanPageResult(*cursor, pageSize)
+	}
+
+	detections, source, err := s.scanFrame(ctx, sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	scannedAt := s.now()
+	codes := make([]interface{}, 0, len(detections))
+	for _, detection := range detections {
+		code := map[string]interface{}{
+			"content":    detection.Label(),
+			"confidence": detection.Score(),
+			"source":     source,
+			"symbology":  codeSymbology(detection.Label()),
+		}
+		data, err := s.decodeQRPayload(detection.Label())
+		s.recordDecode(detection.Label(), sourceName, data, err, scannedAt)
+		if err == nil && data.ItemID != "" {
+			code["namespace"] = normalizeNamespace(data.Namespace)
+			code["item_id"] = data.ItemID
+			code["item_name"] = data.ItemName
+		}
+		codes = append(codes, code)
+	}
+
+	result := map[string]interface{}{
+		"codes":              codes,
+		"count":              len(codes),
+		"source":             source,
+		"duplicate_item_ids": duplicatesToInterfaceSlice(s.findDuplicateItemIDs(detections)),
+		"scanned_at":         formatTimestampIn(scannedAt, s.location),
+	}
+	if sourceName != "" {
+		result["source_name"] = sourceName
+	}
+	if pageSize > 0 {
+		delete(result, "codes")
+		delete(result, "count")
+		page := pageCursor{Command: "scan_qr"}
+		if len(codes) > pageSize {
+			page.ScanID = s.saveScanPage(result, codes)
+		}
+		return pagedScanResult(result, codes, page, pageSize), nil
+	}
+	return result, nil
+}
+
+// handleGetImage returns the current camera frame as the QR detector sees it
+// (after preprocessing), optionally annotated with detection bounding boxes
+func (s *inventoryKeeperKeeper) handleGetImage(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	annotate, _ := cmd["annotate"].(bool)
+	raw, _ := cmd["raw"].(bool)
+	sourceName, err := optionalStringArg(cmd, "source_name")
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := s.captureFrameFromSource(ctx, sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := toRGBA(img)
+	if !raw {
+		frame = toRGBA(preprocessImage(img, s.config()))
+	}
+
+	// The region of interest applies to the preprocessed frame, so the raw
+	// frame is annotated without it
+	roi := s.config().ROI
+	if raw {
+		roi = nil
+	}
+
+	detectionCount := 0
+	if annotate {
+		var detections []objectdetection.Detection
+		if raw {
+			detections, err = s.visionDetections(ctx, frame)
+		} else {
+			detections, err = s.detectInFrame(ctx, frame)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect QR codes: %w", err)
+		}
+		if roi != nil {
+			rect, err := roi.rect(frame.Bounds())
+			if err != nil {
+				return nil, err
+			}
+			drawRectangle(frame, rect, color.RGBA{B: 255, A: 255})
+		}
+		for _, detection := range detections {
+			if box := detection.BoundingBox(); box != nil {
+				drawRectangle(frame, *box, color.RGBA{R: 255, A: 255})
+			}
+		}
+		detectionCount = len(detections)
+	}
+
+	encoded, err := encodePNGBase64(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"image":        encoded,
+		"format":       "base64-png",
+		"width":        frame.Bounds().Dx(),
+		"height":       frame.Bounds().Dy(),
+		"preprocessed": !raw && s.config().preprocessingEnabled(),
+		"annotated":    annotate,
+		"detections":   detectionCount,
+		"roi_applied":  roi != nil,
+	}
+	if sourceName != "" {
+		result["source_name"] = sourceName
+	}
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"testing"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestCaptureRetry(t *testing.T) {
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	t.Run("camera failing once then succeeding yields a successful scan", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{Grayscale: true})
+		setCameraFrame(t, svc, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+
+		// Wrap the working camera so the first capture fails
+		mockCam := svc.camera.(*inject.Camera)
+		working := mockCam.ImageFunc
+		captures := 0
+		mockCam.ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+			captures++
+			if captures == 1 {
+				return nil, camera.ImageMetadata{}, errors.New("transient capture error")
+			}
+			return working(ctx, mimeType, extra)
+		}
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, string(payload))}, nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		if result["count"] != 1 {
+			t.Fatalf("expected 1 code, got: %v", result["count"])
+		}
+		code := result["codes"].([]interface{})[0].(map[string]interface{})
+		if code["item_id"] != "apple-001" {
+			t.Errorf("expected item_id apple-001, got: %v", code["item_id"])
+		}
+		if captures != 2 {
+			t.Errorf("expected 2 capture attempts, got: %d", captures)
+		}
+
+		camStatus := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["camera"].(map[string]interface{})
+		if camStatus["status"] != "ok" || camStatus["consecutive_failures"] != 0 {
+			t.Errorf("expected healthy camera after recovered capture, got: %v", camStatus)
+		}
+	})
+
+	t.Run("retries are bounded by capture_retries", func(t *testing.T) {
+		retries := 1
+		svc, mockVision := newTestKeeper(t, &Config{CaptureRetries: &retries})
+
+		attempts := 0
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			attempts++
+			return nil, errors.New("camera unavailable")
+		}
+
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "scan_qr"}); err == nil {
+			t.Fatal("expected error when every attempt fails")
+		}
+		if attempts != 2 {
+			t.Errorf("expected 2 attempts (1 + 1 retry), got: %d", attempts)
+		}
+	})
+
+	t.Run("persistent failure marks camera degraded", func(t *testing.T) {
+		noRetries := 0
+		svc, mockVision := newTestKeeper(t, &Config{CaptureRetries: &noRetries})
+
+		failing := true
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			if failing {
+				return nil, errors.New("camera unavailable")
+			}
+			return []objectdetection.Detection{}, nil
+		}
+
+		cameraStatus := func() map[string]interface{} {
+			return mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["camera"].(map[string]interface{})
+		}
+
+		for i := 0; i < cameraDegradedThreshold-1; i++ {
+			svc.scanAndCompare(ctx)
+		}
+		if status := cameraStatus(); status["status"] != "ok" {
+			t.Errorf("expected ok below threshold, got: %v", status["status"])
+		}
+
+		svc.scanAndCompare(ctx)
+		status := cameraStatus()
+		if status["status"] != "degraded" {
+			t.Errorf("expected degraded at threshold, got: %v", status["status"])
+		}
+		if status["last_error"] != "camera unavailable" {
+			t.Errorf("expected last_error to be recorded, got: %v", status["last_error"])
+		}
+
+		// A single success clears the streak
+		failing = false
+		svc.scanAndCompare(ctx)
+		if status := cameraStatus(); status["status"] != "ok" {
+			t.Errorf("expected ok after recovery, got: %v", status["status"])
+		}
+	})
+}
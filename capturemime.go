@@ -0,0 +1,83 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+// captureMimeTypes lists the frame formats the keeper can decode, in the
+// order they're reported in errors
+var captureMimeTypes = []string{utils.MimeTypeJPEG, utils.MimeTypePNG, utils.MimeTypeRawRGBA}
+
+// supportedCaptureMimeType reports whether frames of mimeType can be decoded
+func supportedCaptureMimeType(mimeType string) bool {
+	for _, supported := range captureMimeTypes {
+		if mimeType == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// captureMimeType returns the format requested from the camera, defaulting
+// to JPEG
+func (cfg *Config) captureMimeType() string {
+	if cfg.CaptureMimeType == "" {
+		return utils.MimeTypeJPEG
+	}
+	return cfg.CaptureMimeType
+}
+
+// normalizeFrameMimeType strips the "+lazy" suffix and any parameters from a
+// camera's reported mime type
+func normalizeFrameMimeType(mimeType string) string {
+	mimeType, _ = utils.CheckLazyMIMEType(mimeType)
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	return strings.ToLower(strings.TrimSpace(mimeType))
+}
+
+// decodeFrame decodes a captured frame by the mime type the camera reported,
+// which may differ from the one requested. Cameras that report no type are
+// sniffed as JPEG or PNG.
+func decodeFrame(ctx context.Context, frame []byte, mimeType string) (image.Image, error) {
+	switch normalizeFrameMimeType(mimeType) {
+	case utils.MimeTypeJPEG:
+		return jpeg.Decode(bytes.NewReader(frame))
+	case utils.MimeTypePNG:
+		return png.Decode(bytes.NewReader(frame))
+	case utils.MimeTypeRawRGBA:
+		return rimage.DecodeImage(ctx, frame, utils.MimeTypeRawRGBA)
+	case "":
+		img, _, err := image.Decode(bytes.NewReader(frame))
+		if err != nil {
+			return nil, fmt.Errorf("camera reported no image type and %d bytes are neither JPEG nor PNG: %w", len(frame), err)
+		}
+		return img, nil
+	default:
+		return nil, fmt.Errorf("camera returned unsupported image type %q (%d bytes); supported: %s",
+			mimeType, len(frame), strings.Join(captureMimeTypes, ", "))
+	}
+}
+
+// recordFrameMimeType notes the format the camera actually sent, logging
+// when it is first seen or changes
+func (s *inventoryKeeperKeeper) recordFrameMimeType(requested, received string) {
+	received = normalizeFrameMimeType(received)
+
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if s.health.frameMimeType == received {
+		return
+	}
+	s.logger.Infof("Camera %s negotiated frame format %q (requested %q)", s.config().CameraName, received, requested)
+	s.health.frameMimeType = received
+}
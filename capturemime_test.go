@@ -0,0 +1,108 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+func TestCaptureMimeType(t *testing.T) {
+	ctx := context.Background()
+
+	frame := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 8; y++ {
+			frame.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var jpegFrame, pngFrame bytes.Buffer
+	if err := jpeg.Encode(&jpegFrame, frame, nil); err != nil {
+		t.Fatalf("failed to encode JPEG: %v", err)
+	}
+	if err := png.Encode(&pngFrame, frame); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+
+	// serve makes the mock camera return data labeled mimeType, recording
+	// the format the keeper asked for
+	serve := func(svc *inventoryKeeperKeeper, data []byte, mimeType string) *string {
+		var requested string
+		svc.camera.(*inject.Camera).ImageFunc = func(ctx context.Context, want string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+			requested = want
+			return data, camera.ImageMetadata{MimeType: mimeType}, nil
+		}
+		return &requested
+	}
+
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		mimeType string
+	}{
+		{"jpeg frame", jpegFrame.Bytes(), utils.MimeTypeJPEG},
+		{"png frame", pngFrame.Bytes(), utils.MimeTypePNG},
+		{"lazy png frame", pngFrame.Bytes(), utils.WithLazyMIMEType(utils.MimeTypePNG)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, _ := newTestKeeper(t, nil)
+			requested := serve(svc, tc.data, tc.mimeType)
+
+			img, err := svc.captureFrame(ctx)
+			if err != nil {
+				t.Fatalf("captureFrame: %v", err)
+			}
+			if img.Bounds().Dx() != 16 || img.Bounds().Dy() != 8 {
+				t.Errorf("expected a 16x8 frame, got: %v", img.Bounds())
+			}
+			if r, _, _, _ := img.At(4, 4).RGBA(); r>>8 < 200 {
+				t.Errorf("expected a red pixel, got: %v", img.At(4, 4))
+			}
+			if *requested != utils.MimeTypeJPEG {
+				t.Errorf("expected JPEG requested by default, got: %q", *requested)
+			}
+
+			camStatus := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["camera"].(map[string]interface{})
+			if camStatus["frame_mime_type"] != normalizeFrameMimeType(tc.mimeType) {
+				t.Errorf("expected negotiated %s, got: %v", tc.mimeType, camStatus["frame_mime_type"])
+			}
+		})
+	}
+
+	t.Run("capture_mime_type is sent as the hint", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CaptureMimeType: utils.MimeTypePNG})
+		requested := serve(svc, pngFrame.Bytes(), utils.MimeTypePNG)
+
+		if _, err := svc.captureFrame(ctx); err != nil {
+			t.Fatalf("captureFrame: %v", err)
+		}
+		if *requested != utils.MimeTypePNG {
+			t.Errorf("expected PNG requested, got: %q", *requested)
+		}
+	})
+
+	t.Run("unsupported type names what was received", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		serve(svc, []byte("GIF89a"), "image/gif")
+
+		_, err := svc.captureFrame(ctx)
+		if err == nil || !strings.Contains(err.Error(), `"image/gif"`) || !strings.Contains(err.Error(), utils.MimeTypePNG) {
+			t.Errorf("expected error naming image/gif and the supported types, got: %v", err)
+		}
+	})
+
+	t.Run("invalid capture_mime_type is rejected", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", CaptureMimeType: "image/gif"}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected validation error for capture_mime_type image/gif")
+		}
+	})
+}
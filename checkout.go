@@ -0,0 +1,215 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Item check-out states, driven by the present-set
+const (
+	checkoutPresent    = "present"     // In view since it was first seen
+	checkoutRemoved    = "removed"     // Left the present-set within check_in_delay_seconds
+	checkoutCheckedOut = "checked_out" // Gone for check_in_delay_seconds or longer
+	checkoutReturned   = "returned"    // Back in view (or force_check_in) after being checked out
+)
+
+// itemCheckout tracks where one item is in the present -> removed ->
+// checked_out -> returned cycle. Removal from returned starts a new cycle.
+// An item force_check_in returned while out of view goes back to present
+// when the camera next sees it.
+type itemCheckout struct {
+	ItemName     string    // Name from the item's label when last seen
+	State        string    // One of the checkout* states
+	Since        time.Time // When the item entered State
+	RemovedAt    time.Time // When the item last left the present-set (zero if never)
+	CheckedOutAt time.Time // When the item was last checked out (zero if never)
+	TakenBy      string    // Authorized person recognized at the last removal ("" if none)
+}
+
+// checkoutTransition is one item's change of check-out state
+type checkoutTransition struct {
+	Key      itemKey
+	ItemName string
+	From     string
+	To       string
+	TakenBy  string
+}
+
+// transitionCheckoutLocked moves an item to a new state and logs the change.
+// Caller must hold monitorMu.
+func (s *inventoryKeeperKeeper) transitionCheckoutLocked(key itemKey, entry *itemCheckout, state string, now time.Time) checkoutTransition {
+	from := entry.State
+	if from == "" {
+		from = "none"
+	}
+	entry.State = state
+	entry.Since = now
+	s.logger.Infow(fmt.Sprintf("Item %s/%s %s -> %s", key.Namespace, key.ItemID, from, state),
+		"event", "item_state", "namespace", key.Namespace, "item_id", key.ItemID, "from", from, "to", state)
+	return checkoutTransition{Key: key, ItemName: entry.ItemName, From: from, To: state, TakenBy: entry.TakenBy}
+}
+
+// alertCheckouts raises an info alert for each item checked out or returned
+// when checkout_alerts is set. Call without holding monitorMu.
+func (s *inventoryKeeperKeeper) alertCheckouts(transitions []checkoutTransition) {
+	if !s.config().CheckoutAlerts {
+		return
+	}
+	for _, tr := range transitions {
+		details := map[string]interface{}{
+			"namespace": tr.Key.Namespace,
+			"item_name": tr.ItemName,
+		}
+		if tr.TakenBy != "" {
+			details["taken_by"] = tr.TakenBy
+		}
+		switch tr.To {
+		case checkoutCheckedOut:
+			s.raiseAlert("checked_out", severityInfo, tr.Key.ItemID, fmt.Sprintf("Item %s (%s) checked out", tr.Key.ItemID, tr.ItemName), details)
+		case checkoutReturned:
+			s.raiseAlert("returned", severityInfo, tr.Key.ItemID, fmt.Sprintf("Item %s (%s) returned", tr.Key.ItemID, tr.ItemName), details)
+		}
+	}
+}
+
+// updateCheckoutsLocked applies one scan's present-set changes to the
+// check-out states, then checks out items gone for check_in_delay_seconds.
+// Returns the transitions made. Caller must hold monitorMu.
+func (s *inventoryKeeperKeeper) updateCheckoutsLocked(changes presenceChanges, now time.Time) []checkoutTransition {
+	var transitions []checkoutTransition
+	for _, key := range changes.Appeared {
+		entry, ok := s.checkouts[key]
+		if !ok {
+			entry = &itemCheckout{}
+			s.checkouts[key] = entry
+		}
+		if present := s.presence[key]; present != nil {
+			entry.ItemName = present.ItemName
+		}
+		switch entry.State {
+		case "":
+			transitions = append(transitions, s.transitionCheckoutLocked(key, entry, checkoutPresent, now))
+		case checkoutRemoved:
+			// Back before the delay ran out, so it never left
+			transitions = append(transitions, s.transitionCheckoutLocked(key, entry, checkoutPresent, now))
+		case checkoutCheckedOut:
+			transitions = append(transitions, s.transitionCheckoutLocked(key, entry, checkoutReturned, now))
+		case checkoutReturned:
+			// Returned by force_check_in while out of view, now seen again
+			transitions = append(transitions, s.transitionCheckoutLocked(key, entry, checkoutPresent, now))
+		}
+	}
+
+	for _, key := range changes.Removed {
+		entry, ok := s.checkouts[key]
+		if !ok || (entry.State != checkoutPresent && entry.State != checkoutReturned) {
+			continue
+		}
+		entry.RemovedAt = now
+		entry.TakenBy = ""
+		transitions = append(transitions, s.transitionCheckoutLocked(key, entry, checkoutRemoved, now))
+	}
+
+	delay := s.config().checkInDelay()
+	keys := make([]itemKey, 0, len(s.checkouts))
+	for key, entry := range s.checkouts {
+		if entry.State == checkoutRemoved && now.Sub(entry.RemovedAt) >= delay {
+			keys = append(keys, key)
+		}
+	}
+	sortItemKeys(keys)
+	for _, key := range keys {
+		entry := s.checkouts[key]
+		entry.CheckedOutAt = now
+		transitions = append(transitions, s.transitionCheckoutLocked(key, entry, checkoutCheckedOut, now))
+	}
+	return transitions
+}
+
+// handleListCheckedOut returns the namespace's checked-out items, longest
+// out first
+func (s *inventoryKeeperKeeper) handleListCheckedOut(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+
+	type checkedOut struct {
+		key   itemKey
+		entry itemCheckout
+	}
+	s.monitorMu.Lock()
+	var out []checkedOut
+	for key, entry := range s.checkouts {
+		if key.Namespace == namespace && entry.State == checkoutCheckedOut {
+			out = append(out, checkedOut{key: key, entry: *entry})
+		}
+	}
+	s.monitorMu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].entry.RemovedAt.Equal(out[j].entry.RemovedAt) {
+			return out[i].entry.RemovedAt.Before(out[j].entry.RemovedAt)
+		}
+		return out[i].key.ItemID < out[j].key.ItemID
+	})
+
+	items := make([]interface{}, 0, len(out))
+	for _, c := range out {
+		item := map[string]interface{}{
+			"item_id":         c.key.ItemID,
+			"item_name":       c.entry.ItemName,
+			"removed_at":      formatTimestampIn(c.entry.RemovedAt, s.location),
+			"checked_out_at":  formatTimestampIn(c.entry.CheckedOutAt, s.location),
+			"out_for_seconds": now.Sub(c.entry.RemovedAt).Seconds(),
+		}
+		if c.entry.TakenBy != "" {
+			item["taken_by"] = c.entry.TakenBy
+		}
+		items = append(items, item)
+	}
+	return map[string]interface{}{
+		"namespace": namespace,
+		"items":     items,
+		"count":     len(items),
+	}, nil
+}
+
+// handleForceCheckIn marks a removed or checked-out item returned without
+// waiting for the camera to see it, e.g. when it came back to another shelf
+func (s *inventoryKeeperKeeper) handleForceCheckIn(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+	now := s.now()
+
+	s.monitorMu.Lock()
+	entry, ok := s.checkouts[key]
+	if !ok || (entry.State != checkoutRemoved && entry.State != checkoutCheckedOut) {
+		s.monitorMu.Unlock()
+		return nil, fmt.Errorf("item %s is not checked out", itemID)
+	}
+	previous := entry.State
+	transition := s.transitionCheckoutLocked(key, entry, checkoutReturned, now)
+	s.monitorMu.Unlock()
+
+	s.alertCheckouts([]checkoutTransition{transition})
+
+	return map[string]interface{}{
+		"namespace":      namespace,
+		"item_id":        itemID,
+		"previous_state": previous,
+		"state":          checkoutReturned,
+		"returned_at":    formatTimestampIn(now, s.location),
+	}, nil
+}
@@ -0,0 +1,144 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestCheckoutStates(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	// setup returns a keeper whose camera shows apple-001 while visible is
+	// true, confirming presence changes in a single scan
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, *fakeClock, func(bool)) {
+		oneScan := 1
+		delay := 60
+		svc, mockVision, clock := newTestKeeperWithClock(t, &Config{PresenceDebounceScans: &oneScan, CheckInDelaySeconds: &delay}, start)
+
+		var mu sync.Mutex
+		visible := true
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !visible {
+				return []objectdetection.Detection{}, nil
+			}
+			return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+		}
+		setVisible := func(v bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			visible = v
+		}
+
+		svc.scanAndCompare(ctx)
+		return svc, clock, setVisible
+	}
+
+	state := func(svc *inventoryKeeperKeeper) string {
+		svc.monitorMu.Lock()
+		defer svc.monitorMu.Unlock()
+		if entry, ok := svc.checkouts[keyFor("", "apple-001")]; ok {
+			return entry.State
+		}
+		return ""
+	}
+
+	t.Run("item gone past the delay is checked out, then returned", func(t *testing.T) {
+		svc, clock, setVisible := setup(t)
+		if got := state(svc); got != checkoutPresent {
+			t.Fatalf("expected present, got: %q", got)
+		}
+
+		setVisible(false)
+		clock.Advance(time.Second)
+		svc.scanAndCompare(ctx)
+		if got := state(svc); got != checkoutRemoved {
+			t.Fatalf("expected removed, got: %q", got)
+		}
+
+		clock.Advance(60 * time.Second)
+		svc.scanAndCompare(ctx)
+		if got := state(svc); got != checkoutCheckedOut {
+			t.Fatalf("expected checked_out, got: %q", got)
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "list_checked_out"})
+		if result["count"] != 1 {
+			t.Fatalf("expected 1 checked-out item, got: %v", result["count"])
+		}
+		item := result["items"].([]interface{})[0].(map[string]interface{})
+		if item["item_id"] != "apple-001" || item["item_name"] != "Honeycrisp Apple" {
+			t.Errorf("unexpected checked-out item: %v", item)
+		}
+		if item["removed_at"] != "2026-01-01T08:00:01Z" || item["checked_out_at"] != "2026-01-01T08:01:01Z" {
+			t.Errorf("unexpected timestamps: %v", item)
+		}
+
+		setVisible(true)
+		clock.Advance(time.Second)
+		svc.scanAndCompare(ctx)
+		if got := state(svc); got != checkoutReturned {
+			t.Errorf("expected returned, got: %q", got)
+		}
+		if count := mustDoCommand(t, svc, map[string]interface{}{"command": "list_checked_out"})["count"]; count != 0 {
+			t.Errorf("expected no checked-out items after return, got: %v", count)
+		}
+	})
+
+	t.Run("item back before the delay stays present", func(t *testing.T) {
+		svc, clock, setVisible := setup(t)
+
+		setVisible(false)
+		svc.scanAndCompare(ctx)
+		setVisible(true)
+		clock.Advance(30 * time.Second)
+		svc.scanAndCompare(ctx)
+		clock.Advance(60 * time.Second)
+		svc.scanAndCompare(ctx)
+
+		if got := state(svc); got != checkoutPresent {
+			t.Errorf("expected present, got: %q", got)
+		}
+	})
+
+	t.Run("force_check_in returns a checked-out item", func(t *testing.T) {
+		svc, clock, setVisible := setup(t)
+
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "force_check_in", "item_id": "apple-001"}); err == nil {
+			t.Error("expected error for an item still present")
+		}
+
+		setVisible(false)
+		svc.scanAndCompare(ctx)
+		clock.Advance(61 * time.Second)
+		svc.scanAndCompare(ctx)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "force_check_in", "item_id": "apple-001"})
+		if result["previous_state"] != checkoutCheckedOut || result["state"] != checkoutReturned {
+			t.Errorf("unexpected force_check_in result: %v", result)
+		}
+		if got := state(svc); got != checkoutReturned {
+			t.Errorf("expected returned, got: %q", got)
+		}
+
+		// Seen again after the forced return, it is simply present
+		setVisible(true)
+		svc.scanAndCompare(ctx)
+		if got := state(svc); got != checkoutPresent {
+			t.Errorf("expected present once back in view, got: %q", got)
+		}
+		setVisible(false)
+		svc.scanAndCompare(ctx)
+		if got := state(svc); got != checkoutRemoved {
+			t.Errorf("expected a new cycle on the next removal, got: %q", got)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package inventorykeeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PayloadCodec converts item data to and from the bytes stored in a QR code.
+// Signing and encryption are applied around the codec, so a codec only deals
+// with the plain item fields.
+type PayloadCodec interface {
+	Encode(data ItemQRData) ([]byte, error)
+	Decode(payload []byte) (ItemQRData, error)
+}
+
+// JSONCodec is the default codec, storing ItemQRData as JSON
+type JSONCodec struct{}
+
+// Encode implements PayloadCodec
+func (JSONCodec) Encode(data ItemQRData) ([]byte, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR data: %w", err)
+	}
+	return encoded, nil
+}
+
+// Decode implements PayloadCodec
+func (JSONCodec) Decode(payload []byte) (ItemQRData, error) {
+	var data ItemQRData
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return data, fmt.Errorf("payload is not item JSON: %w", err)
+	}
+	return data, nil
+}
+
+// defaultPayloadCodec is used when payload_codec is not set
+const defaultPayloadCodec = "json"
+
+// payloadCodecs maps payload_codec config values to implementations
+var payloadCodecs = map[string]PayloadCodec{
+	"json": JSONCodec{},
+}
+
+// lookupPayloadCodec resolves a payload_codec name, defaulting to JSON when empty
+func lookupPayloadCodec(name string) (PayloadCodec, error) {
+	if name == "" {
+		name = defaultPayloadCodec
+	}
+	codec, ok := payloadCodecs[name]
+	if !ok {
+		names := make([]string, 0, len(payloadCodecs))
+		for n := range payloadCodecs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("payload_codec must be one of %s, got: %q", strings.Join(names, ", "), name)
+	}
+	return codec, nil
+}
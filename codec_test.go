@@ -0,0 +1,48 @@
+package inventorykeeper
+
+import (
+	"testing"
+)
+
+func TestPayloadCodec(t *testing.T) {
+	t.Run("json codec round-trips item data", func(t *testing.T) {
+		original := ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple", Sig: "abc123"}
+
+		var codec PayloadCodec = JSONCodec{}
+		encoded, err := codec.Encode(original)
+		if err != nil {
+			t.Fatalf("encode failed: %v", err)
+		}
+		decoded, err := codec.Decode(encoded)
+		if err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		if decoded != original {
+			t.Errorf("expected %+v after round trip, got: %+v", original, decoded)
+		}
+	})
+
+	t.Run("json codec rejects non-JSON payload", func(t *testing.T) {
+		if _, err := (JSONCodec{}).Decode([]byte("plain text label")); err == nil {
+			t.Error("expected error decoding non-JSON payload")
+		}
+	})
+
+	t.Run("empty payload_codec defaults to json", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr"}
+		if _, _, err := cfg.Validate(""); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+		svc, _ := newTestKeeper(t, nil)
+		if _, ok := svc.codec.(JSONCodec); !ok {
+			t.Errorf("expected JSONCodec by default, got: %T", svc.codec)
+		}
+	})
+
+	t.Run("unknown payload_codec is rejected", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", PayloadCodec: "msgpack"}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for unknown payload_codec")
+		}
+	})
+}
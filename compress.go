@@ -0,0 +1,67 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// compressedPayloadPrefix marks QR content holding a deflate-compressed,
+// base64-encoded payload
+const compressedPayloadPrefix = "ikz:"
+
+// maxInflatedPayload bounds how large a compressed payload may inflate, so a
+// crafted label can't exhaust memory
+const maxInflatedPayload = 64 << 10
+
+var errPayloadInflate = errors.New("failed to inflate compressed payload")
+
+// compressPayload deflates an encoded payload and returns the marked form,
+// or the payload unchanged when compression wouldn't make it shorter. Small
+// payloads usually lose to the base64 and marker overhead.
+func compressPayload(encoded []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(encoded); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+
+	compressed := compressedPayloadPrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes())
+	if len(compressed) >= len(encoded) {
+		return encoded, nil
+	}
+	return []byte(compressed), nil
+}
+
+// inflatePayload reverses compressPayload. Content without the marker is
+// returned as is.
+func inflatePayload(content []byte) ([]byte, error) {
+	if !bytes.HasPrefix(content, []byte(compressedPayloadPrefix)) {
+		return content, nil
+	}
+
+	compressed, err := base64.RawURLEncoding.DecodeString(string(content[len(compressedPayloadPrefix):]))
+	if err != nil {
+		return nil, errPayloadInflate
+	}
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close()
+
+	inflated, err := io.ReadAll(io.LimitReader(r, maxInflatedPayload+1))
+	if err != nil {
+		return nil, errPayloadInflate
+	}
+	if len(inflated) > maxInflatedPayload {
+		return nil, fmt.Errorf("compressed payload inflates past %d bytes", maxInflatedPayload)
+	}
+	return inflated, nil
+}
@@ -0,0 +1,101 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPayloadCompression(t *testing.T) {
+	large := ItemQRData{
+		V:         currentPayloadVersion,
+		Namespace: "cold-storage",
+		ItemID:    "yogurt-assorted-0042",
+		ItemName:  strings.Repeat("Greek Yogurt, Vanilla, 6 x 150g, Aisle 3 Fridge ", 8),
+	}
+
+	t.Run("large payload round-trips compressed", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CompressPayload: true})
+
+		payload, err := svc.encodeQRPayload(large)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(payload, compressedPayloadPrefix) {
+			t.Fatalf("expected compressed payload, got: %s", payload)
+		}
+		plain, _ := json.Marshal(large)
+		if len(payload) >= len(plain) {
+			t.Errorf("expected compression to shrink the payload: %d >= %d", len(payload), len(plain))
+		}
+
+		decoded, err := svc.decodeQRPayload(payload)
+		if err != nil {
+			t.Fatalf("unexpected decode error: %v", err)
+		}
+		if decoded != large {
+			t.Errorf("expected %+v, got: %+v", large, decoded)
+		}
+	})
+
+	t.Run("small payload stays uncompressed", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CompressPayload: true})
+
+		payload, err := svc.encodeQRPayload(ItemQRData{ItemID: "pen-1", ItemName: "Pen"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.HasPrefix(payload, compressedPayloadPrefix) {
+			t.Errorf("expected small payload to stay uncompressed, got: %s", payload)
+		}
+	})
+
+	t.Run("compressed payload decodes without compress_payload", func(t *testing.T) {
+		writer, _ := newTestKeeper(t, &Config{CompressPayload: true})
+		reader, _ := newTestKeeper(t, nil)
+
+		payload, _ := writer.encodeQRPayload(large)
+		decoded, err := reader.decodeQRPayload(payload)
+		if err != nil || decoded != large {
+			t.Errorf("expected %+v, got: %+v (err %v)", large, decoded, err)
+		}
+	})
+
+	t.Run("compressed and encrypted round-trip", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CompressPayload: true, EncryptionKey: testEncryptionKey('k')})
+
+		payload, err := svc.encodeQRPayload(large)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(payload, encryptedPayloadPrefix) {
+			t.Fatalf("expected encrypted payload, got: %s", payload)
+		}
+		decoded, err := svc.decodeQRPayload(payload)
+		if err != nil || decoded != large {
+			t.Errorf("expected %+v, got: %+v (err %v)", large, decoded, err)
+		}
+	})
+
+	t.Run("oversized inflation is rejected", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, _ := flate.NewWriter(&buf, flate.BestCompression)
+		w.Write(bytes.Repeat([]byte{'a'}, maxInflatedPayload+1))
+		w.Close()
+		bomb := compressedPayloadPrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes())
+
+		if _, err := inflatePayload([]byte(bomb)); err == nil {
+			t.Error("expected error for payload inflating past the limit")
+		}
+	})
+
+	t.Run("corrupt compressed payload fails", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		if _, err := svc.decodeQRPayload(compressedPayloadPrefix + "not deflate!"); err == nil {
+			t.Error("expected error for corrupt compressed payload")
+		}
+	})
+}
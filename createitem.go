@@ -0,0 +1,52 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+)
+
+// handleCreateItem adds an item and returns a code for it in one call. The
+// code is rendered before the inventory is touched, so a rendering failure
+// leaves the inventory unchanged. Accepts add_item's fields plus generate_qr's
+// rendering options.
+func (s *inventoryKeeperKeeper) handleCreateItem(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	item, err := s.parseNewItem(cmd)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := codeRenderArgs(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	qrData := ItemQRData{ItemID: item.ItemID, ItemName: item.ItemName}
+	if item.Namespace != defaultNamespace {
+		qrData.Namespace = item.Namespace
+	}
+	qrCode, payload, size, err := s.renderItemCode(qrData, opts)
+	if err != nil {
+		return nil, fmt.Errorf("item %s was not added: %w", item.ItemID, err)
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	if _, exists := s.inventory[item.key()]; exists {
+		return nil, fmt.Errorf("item %s already exists", item.ItemID)
+	}
+	evicted, err := s.insertItemLocked(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"item":      addedItemMap(item, evicted, s.location),
+		"qr_code":   base64.StdEncoding.EncodeToString(qrCode),
+		"qr_data":   payload,
+		"encrypted": s.encryptionKey != nil,
+		"code_type": opts.CodeType,
+		"format":    opts.responseFormat(),
+		"size":      size,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestCreateItem(t *testing.T) {
+	t.Run("records the item and returns a decodable code", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "create_item",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+			"quantity":  12.0,
+			"location":  "aisle-3",
+			"tags":      []interface{}{"fruit"},
+		})
+
+		item, ok := svc.inventory[keyFor(defaultNamespace, "apple-001")]
+		if !ok {
+			t.Fatal("expected apple-001 in inventory")
+		}
+		if item.Quantity != 12 || item.Location != "aisle-3" || len(item.Tags) != 1 {
+			t.Errorf("expected quantity, location, and tags recorded, got: %+v", item)
+		}
+		if result["item"].(map[string]interface{})["item_id"] != "apple-001" {
+			t.Errorf("expected item in response, got: %v", result["item"])
+		}
+
+		img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(result["qr_code"].(string))))
+		if err != nil {
+			t.Fatalf("qr_code is not a PNG: %v", err)
+		}
+		content, _, err := decodeCodeImage(img)
+		if err != nil {
+			t.Fatalf("failed to decode QR image: %v", err)
+		}
+		data, err := svc.decodeQRPayload(content)
+		if err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if data.ItemID != "apple-001" || data.ItemName != "Honeycrisp Apple" {
+			t.Errorf("expected QR for apple-001, got: %+v", data)
+		}
+	})
+
+	t.Run("generation failure leaves inventory unchanged", func(t *testing.T) {
+		// Names this long pass validation but don't fit in a QR code
+		maxName := 5000
+		svc, _ := newTestKeeper(t, &Config{MaxItemNameLength: &maxName})
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command":   "create_item",
+			"item_id":   "apple-001",
+			"item_name": strings.Repeat("Apple ", 700),
+		})
+		if err == nil {
+			t.Fatal("expected error when the QR code can't be generated")
+		}
+		if len(svc.inventory) != 0 || len(svc.history) != 0 {
+			t.Errorf("expected no inventory or history changes, got %d items and %d events", len(svc.inventory), len(svc.history))
+		}
+	})
+
+	t.Run("existing item is rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "create_item", "item_id": "apple-001", "item_name": "Apple",
+		}); err == nil {
+			t.Error("expected error creating a duplicate item")
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultRecentDecodes is how many raw payloads get_recent_decodes keeps by default
+const defaultRecentDecodes = 50
+
+// maxRecordedPayload bounds how much of each raw payload is kept, so an
+// oversized label can't grow the buffer
+const maxRecordedPayload = 2048
+
+// decodeRecord is one raw payload as the decoder extracted it
+type decodeRecord struct {
+	At         time.Time // When the payload was decoded
+	Content    string    // Raw content, cut to maxRecordedPayload bytes
+	Truncated  bool      // Content was cut
+	Camera     string    // Camera the frame came from
+	SourceName string    // Camera image source, when one was requested
+	Parsed     bool      // Content decoded into ItemQRData with an item ID
+	ParseError string    // Why decoding failed ("" when parsed)
+	ItemID     string    // Decoded item ID (parsed payloads only)
+	Signed     bool      // Decoded payload carries a signature
+}
+
+// toMap converts the record into a DoCommand-friendly response map, with the
+// decode time shown in loc
+func (r decodeRecord) toMap(loc *time.Location) map[string]interface{} {
+	m := map[string]interface{}{
+		"decoded_at": formatTimestampIn(r.At, loc),
+		"content":    r.Content,
+		"camera":     r.Camera,
+		"parsed":     r.Parsed,
+	}
+	if r.Truncated {
+		m["truncated"] = true
+	}
+	if r.SourceName != "" {
+		m["source_name"] = r.SourceName
+	}
+	if r.Parsed {
+		m["item_id"] = r.ItemID
+		m["signed"] = r.Signed
+	} else {
+		m["parse_error"] = r.ParseError
+	}
+	return m
+}
+
+// decodeLog is a fixed-size ring of recently decoded raw payloads. A nil
+// log, or one with no capacity, records nothing.
+type decodeLog struct {
+	mu      sync.Mutex
+	records []decodeRecord // Ring buffer, len == capacity
+	next    int            // Slot the next record is written to
+	count   int            // Filled slots, up to len(records)
+}
+
+func newDecodeLog(capacity int) *decodeLog {
+	return &decodeLog{records: make([]decodeRecord, capacity)}
+}
+
+// record adds a payload, overwriting the oldest once the ring is full
+func (l *decodeLog) record(r decodeRecord) {
+	if l == nil || len(l.records) == 0 {
+		return
+	}
+	if len(r.Content) > maxRecordedPayload {
+		r.Content = r.Content[:maxRecordedPayload]
+		r.Truncated = true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records[l.next] = r
+	l.next = (l.next + 1) % len(l.records)
+	if l.count < len(l.records) {
+		l.count++
+	}
+}
+
+// recent returns up to limit records, newest first
+func (l *decodeLog) recent(limit int) []decodeRecord {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.count
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	out := make([]decodeRecord, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, l.records[(l.next-i+len(l.records))%len(l.records)])
+	}
+	return out
+}
+
+// recentDecodes returns how many raw payloads get_recent_decodes keeps, defaulting to 50
+func (cfg *Config) recentDecodes() int {
+	if cfg.RecentDecodes == nil {
+		return defaultRecentDecodes
+	}
+	return *cfg.RecentDecodes
+}
+
+// recordDecode notes a raw payload and the outcome of decoding it
+func (s *inventoryKeeperKeeper) recordDecode(content, sourceName string, data ItemQRData, decodeErr error, at time.Time) {
+	r := decodeRecord{
+		At:         at,
+		Content:    content,
+		Camera:     s.config().CameraName,
+		SourceName: sourceName,
+	}
+	switch {
+	case decodeErr != nil:
+		r.ParseError = decodeErr.Error()
+	case data.ItemID == "":
+		r.ParseError = "payload has no item_id"
+	default:
+		r.Parsed = true
+		r.ItemID = data.ItemID
+		r.Signed = data.Sig != ""
+	}
+	s.decodeLog.record(r)
+}
+
+// handleGetRecentDecodes returns the most recently decoded raw payloads,
+// newest first, whether or not they parsed
+func (s *inventoryKeeperKeeper) handleGetRecentDecodes(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	limit, _, err := intArg(cmd, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must be non-negative, got: %d", limit)
+	}
+
+	records := s.decodeLog.recent(limit)
+	decodes := make([]interface{}, 0, len(records))
+	for _, r := range records {
+		decodes = append(decodes, r.toMap(s.location))
+	}
+	return map[string]interface{}{
+		"decodes":  decodes,
+		"count":    len(decodes),
+		"capacity": s.config().recentDecodes(),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package inventorykeeper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestRecentDecodes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("malformed payload is kept and marked parse-failed", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{
+				testDetection(0, `{"item_id":"apple-001"`),
+				testDetection(1, `{"item_id":"pear-001","item_name":"Pear"}`),
+			}, nil
+		}
+		svc.scanAndCompare(ctx)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_recent_decodes"})
+		if result["count"] != 2 || result["capacity"] != defaultRecentDecodes {
+			t.Fatalf("expected 2 decodes with default capacity, got: %v", result)
+		}
+		byContent := map[string]map[string]interface{}{}
+		for _, raw := range result["decodes"].([]interface{}) {
+			decode := raw.(map[string]interface{})
+			byContent[decode["content"].(string)] = decode
+		}
+
+		bad := byContent[`{"item_id":"apple-001"`]
+		if bad == nil || bad["parsed"] != false || bad["parse_error"] == "" {
+			t.Errorf("expected malformed payload marked parse-failed, got: %v", bad)
+		}
+		if bad != nil && bad["camera"] != "test-camera" {
+			t.Errorf("expected camera test-camera, got: %v", bad["camera"])
+		}
+		good := byContent[`{"item_id":"pear-001","item_name":"Pear"}`]
+		if good == nil || good["parsed"] != true || good["item_id"] != "pear-001" || good["signed"] != false {
+			t.Errorf("expected parsed unsigned payload, got: %v", good)
+		}
+	})
+
+	t.Run("buffer keeps only the newest payloads", func(t *testing.T) {
+		size := 2
+		svc, mockVision := newTestKeeper(t, &Config{RecentDecodes: &size})
+		label := ""
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, label)}, nil
+		}
+		for _, l := range []string{"first", "second", "third"} {
+			label = l
+			svc.scanAndCompare(ctx)
+		}
+
+		decodes := mustDoCommand(t, svc, map[string]interface{}{"command": "get_recent_decodes"})["decodes"].([]interface{})
+		if len(decodes) != 2 || decodes[0].(map[string]interface{})["content"] != "third" || decodes[1].(map[string]interface{})["content"] != "second" {
+			t.Errorf("expected third and second newest first, got: %v", decodes)
+		}
+
+		decodes = mustDoCommand(t, svc, map[string]interface{}{"command": "get_recent_decodes", "limit": 1})["decodes"].([]interface{})
+		if len(decodes) != 1 || decodes[0].(map[string]interface{})["content"] != "third" {
+			t.Errorf("expected limit to return the newest decode, got: %v", decodes)
+		}
+	})
+
+	t.Run("oversized payload is truncated", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, strings.Repeat("x", maxRecordedPayload+10))}, nil
+		}
+		svc.scanAndCompare(ctx)
+
+		decode := mustDoCommand(t, svc, map[string]interface{}{"command": "get_recent_decodes"})["decodes"].([]interface{})[0].(map[string]interface{})
+		if decode["truncated"] != true || len(decode["content"].(string)) != maxRecordedPayload {
+			t.Errorf("expected truncated content, got %d bytes", len(decode["content"].(string)))
+		}
+	})
+
+	t.Run("zero size keeps nothing", func(t *testing.T) {
+		size := 0
+		svc, mockVision := newTestKeeper(t, &Config{RecentDecodes: &size})
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, "anything")}, nil
+		}
+		svc.scanAndCompare(ctx)
+
+		if result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_recent_decodes"}); result["count"] != 0 {
+			t.Errorf("expected no decodes, got: %v", result)
+		}
+	})
+}
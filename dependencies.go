@@ -0,0 +1,134 @@
+package inventorykeeper
+
+import (
+	"errors"
+	"fmt"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/services/vision"
+)
+
+// Responses to the camera or vision service failing persistently
+const (
+	dependencyRetry   = "retry"   // Keep scanning, backing off the interval
+	dependencyDegrade = "degrade" // Report degraded and only probe occasionally
+	dependencyRefetch = "refetch" // Re-resolve the camera and vision service
+)
+
+// validDependencyPolicies are the accepted dependency_failure_policy values
+var validDependencyPolicies = map[string]bool{
+	dependencyRetry:   true,
+	dependencyDegrade: true,
+	dependencyRefetch: true,
+}
+
+// dependencyFailurePolicy returns the configured policy, defaulting to retry
+func (cfg *Config) dependencyFailurePolicy() string {
+	if cfg.DependencyFailurePolicy == "" {
+		return dependencyRetry
+	}
+	return cfg.DependencyFailurePolicy
+}
+
+// shelfCamera returns the current camera reference
+func (s *inventoryKeeperKeeper) shelfCamera() camera.Camera {
+	s.depsMu.RLock()
+	defer s.depsMu.RUnlock()
+	return s.camera
+}
+
+// qrVision returns the current QR vision service reference
+func (s *inventoryKeeperKeeper) qrVision() vision.Service {
+	s.depsMu.RLock()
+	defer s.depsMu.RUnlock()
+	return s.qrVisionService
+}
+
+// refetchDependencies resolves the camera and QR vision service again from
+// the dependencies from the latest build or reconfigure, replacing the held
+// references
+func (s *inventoryKeeperKeeper) refetchDependencies() error {
+	s.depsMu.RLock()
+	deps := s.deps
+	s.depsMu.RUnlock()
+	if deps == nil {
+		return errors.New("no dependencies to refetch from")
+	}
+	cfg := s.config()
+	cam, err := camera.FromDependencies(deps, cfg.CameraName)
+	if err != nil {
+		return fmt.Errorf("failed to get camera %s: %w", cfg.CameraName, err)
+	}
+	qrVis, err := vision.FromDependencies(deps, cfg.QRVisionService)
+	if err != nil {
+		return fmt.Errorf("failed to get QR vision service %s: %w", cfg.QRVisionService, err)
+	}
+
+	s.depsMu.Lock()
+	s.camera = cam
+	s.qrVisionService = qrVis
+	s.depsMu.Unlock()
+	return nil
+}
+
+// onDependencyFailure applies dependency_failure_policy after a failed scan.
+// Each time the failure streak reaches another multiple of the degraded
+// threshold, "refetch" re-resolves the dependencies and "degrade" notes
+// that background scans have dropped to probing.
+func (s *inventoryKeeperKeeper) onDependencyFailure() {
+	s.healthMu.Lock()
+	failures := s.health.consecutiveFailures
+	s.healthMu.Unlock()
+	if failures == 0 || failures%cameraDegradedThreshold != 0 {
+		return
+	}
+
+	switch s.config().dependencyFailurePolicy() {
+	case dependencyDegrade:
+		if failures == cameraDegradedThreshold {
+			s.logger.Warnf("Dependencies degraded, background scans will probe every %v until a scan succeeds", maxScanBackoff)
+		}
+	case dependencyRefetch:
+		err := s.refetchDependencies()
+		s.healthMu.Lock()
+		s.health.refetches++
+		s.health.lastRefetchAt = s.now()
+		s.health.lastRefetchError = ""
+		if err != nil {
+			s.health.lastRefetchError = err.Error()
+		}
+		s.healthMu.Unlock()
+
+		if err != nil {
+			s.logger.Errorf("Failed to refetch dependencies after %d failed scans: %v", failures, err)
+		} else {
+			s.logger.Infof("Refetched camera and vision service after %d failed scans", failures)
+		}
+	}
+}
+
+// dependencyStatus summarizes the failure policy and its state for status
+// responses. "degraded" means scans have failed past the threshold.
+func (s *inventoryKeeperKeeper) dependencyStatus() map[string]interface{} {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	state := "ok"
+	if s.health.degraded() {
+		state = "degraded"
+	}
+
+	result := map[string]interface{}{
+		"policy":               s.config().dependencyFailurePolicy(),
+		"state":                state,
+		"consecutive_failures": s.health.consecutiveFailures,
+		"refetches":            s.health.refetches,
+	}
+	if s.health.refetches > 0 {
+		result["last_refetch_at"] = formatTimestampIn(s.health.lastRefetchAt, s.location)
+		if s.health.lastRefetchError != "" {
+			result["last_refetch_error"] = s.health.lastRefetchError
+		}
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestDependencyFailurePolicy(t *testing.T) {
+	ctx := context.Background()
+	noRetries := 0
+
+	// failCamera makes every capture fail, as if the camera had been removed
+	failCamera := func(svc *inventoryKeeperKeeper) {
+		svc.camera.(*inject.Camera).ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+			return nil, camera.ImageMetadata{}, errors.New("camera not found")
+		}
+	}
+	dependencies := func(t *testing.T, svc *inventoryKeeperKeeper) map[string]interface{} {
+		t.Helper()
+		return mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["dependencies"].(map[string]interface{})
+	}
+
+	t.Run("degrade reports degraded and probes at the longest delay", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{Grayscale: true, CaptureRetries: &noRetries, DependencyFailurePolicy: dependencyDegrade})
+		failCamera(svc)
+
+		if status := dependencies(t, svc); status["state"] != "ok" || status["policy"] != dependencyDegrade {
+			t.Fatalf("expected ok state before failures, got: %v", status)
+		}
+
+		for i := 0; i < cameraDegradedThreshold; i++ {
+			if err := svc.scanAndCompare(ctx); err == nil {
+				t.Fatal("expected scan to fail")
+			}
+		}
+
+		status := dependencies(t, svc)
+		if status["state"] != "degraded" || status["consecutive_failures"] != cameraDegradedThreshold {
+			t.Errorf("expected degraded after %d failures, got: %v", cameraDegradedThreshold, status)
+		}
+		if delay := svc.scanDelay(cameraDegradedThreshold); delay != maxScanBackoff {
+			t.Errorf("expected probe delay %v, got: %v", maxScanBackoff, delay)
+		}
+
+		// A successful scan clears the degraded state
+		setCameraFrame(t, svc, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+		if err := svc.scanAndCompare(ctx); err != nil {
+			t.Fatalf("expected scan to succeed, got: %v", err)
+		}
+		if status := dependencies(t, svc); status["state"] != "ok" {
+			t.Errorf("expected ok after recovery, got: %v", status)
+		}
+	})
+
+	t.Run("retry keeps the ordinary backoff", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{Grayscale: true, CaptureRetries: &noRetries})
+		failCamera(svc)
+		for i := 0; i < cameraDegradedThreshold; i++ {
+			svc.scanAndCompare(ctx)
+		}
+
+		if status := dependencies(t, svc); status["state"] != "degraded" || status["policy"] != dependencyRetry {
+			t.Errorf("expected degraded under retry, got: %v", status)
+		}
+		want := nextScanDelay(svc.config().scanInterval(), 0, cameraDegradedThreshold, svc.scanRand)
+		if delay := svc.scanDelay(cameraDegradedThreshold); delay != want {
+			t.Errorf("expected backoff delay %v, got: %v", want, delay)
+		}
+	})
+
+	t.Run("refetch swaps in the restarted camera", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{Grayscale: true, CaptureRetries: &noRetries, DependencyFailurePolicy: dependencyRefetch})
+		failCamera(svc)
+		stale := svc.camera
+
+		// The camera restarts as a new resource the keeper hasn't seen
+		restarted := &inject.Camera{}
+		svc.deps[camera.Named(svc.config().CameraName)] = restarted
+
+		for i := 0; i < cameraDegradedThreshold; i++ {
+			svc.scanAndCompare(ctx)
+		}
+
+		status := dependencies(t, svc)
+		if status["refetches"] != 1 || status["last_refetch_error"] != nil {
+			t.Fatalf("expected one successful refetch, got: %v", status)
+		}
+		if svc.shelfCamera() == stale || svc.shelfCamera() != camera.Camera(restarted) {
+			t.Fatal("expected the restarted camera to replace the stale one")
+		}
+
+		setCameraFrame(t, svc, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+		if err := svc.scanAndCompare(ctx); err != nil {
+			t.Fatalf("expected scan with the refetched camera to succeed, got: %v", err)
+		}
+		if status := dependencies(t, svc); status["state"] != "ok" {
+			t.Errorf("expected ok after refetch, got: %v", status)
+		}
+	})
+
+	t.Run("refetch failure is reported", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{Grayscale: true, CaptureRetries: &noRetries, DependencyFailurePolicy: dependencyRefetch})
+		failCamera(svc)
+		delete(svc.deps, camera.Named(svc.config().CameraName))
+
+		for i := 0; i < cameraDegradedThreshold; i++ {
+			svc.scanAndCompare(ctx)
+		}
+
+		status := dependencies(t, svc)
+		if status["state"] != "degraded" || status["last_refetch_error"] == nil {
+			t.Errorf("expected degraded with a refetch error, got: %v", status)
+		}
+	})
+}
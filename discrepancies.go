@@ -0,0 +1,58 @@
+package inventorykeeper
+
+import (
+	"context"
+	"sort"
+)
+
+// handleQuantityDiscrepancies compares each present item's recorded quantity
+// with the units its labels stood for in the latest scan that saw it, and
+// returns the items where they differ. Recorded items that are not present
+// at all are left to audit's recorded_but_missing.
+func (s *inventoryKeeperKeeper) handleQuantityDiscrepancies(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]int)
+	s.monitorMu.Lock()
+	lastScan := s.lastScanAt
+	for key, entry := range s.presence {
+		if entry.Present && key.Namespace == namespace {
+			labels[key.ItemID] = entry.Units
+		}
+	}
+	s.monitorMu.Unlock()
+
+	ids := make([]string, 0, len(labels))
+	for itemID := range labels {
+		ids = append(ids, itemID)
+	}
+	sort.Strings(ids)
+
+	discrepancies := []interface{}{}
+	s.inventoryMu.RLock()
+	for _, itemID := range ids {
+		item, recorded := s.inventory[keyFor(namespace, itemID)]
+		if !recorded || item.Quantity == labels[itemID] {
+			continue
+		}
+		discrepancies = append(discrepancies, map[string]interface{}{
+			"item_id":           item.ItemID,
+			"item_name":         item.ItemName,
+			"recorded_quantity": item.Quantity,
+			"detected_count":    labels[itemID],
+			"difference":        item.Quantity - labels[itemID],
+		})
+	}
+	s.inventoryMu.RUnlock()
+
+	return map[string]interface{}{
+		"namespace":     namespace,
+		"discrepancies": discrepancies,
+		"count":         len(discrepancies),
+		"scanned":       !lastScan.IsZero(),
+		"last_scan_at":  formatTimestampIn(lastScan, s.location),
+	}, nil
+}
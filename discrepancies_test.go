@@ -0,0 +1,46 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestQuantityDiscrepancies(t *testing.T) {
+	ctx := context.Background()
+	svc, mockVision := newTestKeeper(t, nil)
+
+	// Three soup cans recorded, but only two labels on the shelf
+	soup, _ := json.Marshal(ItemQRData{ItemID: "soup-003", ItemName: "Tomato Soup"})
+	beans, _ := json.Marshal(ItemQRData{ItemID: "beans-010", ItemName: "Black Beans"})
+	mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{
+			testDetection(0, string(soup)),
+			testDetection(1, string(soup)),
+			testDetection(2, string(beans)),
+		}, nil
+	}
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "soup-003", "item_name": "Tomato Soup", "quantity": 3})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "beans-010", "item_name": "Black Beans"})
+
+	// Before any scan nothing is present, so there is nothing to compare
+	result := mustDoCommand(t, svc, map[string]interface{}{"command": "quantity_discrepancies"})
+	if result["count"] != 0 || result["scanned"] != false {
+		t.Fatalf("expected no discrepancies before scanning, got: %v", result)
+	}
+
+	// Two scans confirm presence with the default debounce
+	svc.scanAndCompare(ctx)
+	svc.scanAndCompare(ctx)
+
+	result = mustDoCommand(t, svc, map[string]interface{}{"command": "quantity_discrepancies"})
+	if result["count"] != 1 {
+		t.Fatalf("expected 1 discrepancy (beans match), got: %v", result)
+	}
+	entry := result["discrepancies"].([]interface{})[0].(map[string]interface{})
+	if entry["item_id"] != "soup-003" || entry["recorded_quantity"] != 3 || entry["detected_count"] != 2 || entry["difference"] != 1 {
+		t.Errorf("unexpected discrepancy: %v", entry)
+	}
+}
@@ -0,0 +1,107 @@
+package inventorykeeper
+
+import (
+	"fmt"
+	"image"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// duplicateItemID is an item_id decoded from more than one label in a single
+// frame. Unlike quantity, this means two labels claim to be the same item.
+type duplicateItemID struct {
+	Key   itemKey
+	Count int               // Labels in the frame decoding to this item
+	Boxes []image.Rectangle // Bounding boxes of those labels, where the detector reported one
+}
+
+// toMap converts the duplicate into a DoCommand-friendly response map
+func (d duplicateItemID) toMap() map[string]interface{} {
+	boxes := make([]interface{}, 0, len(d.Boxes))
+	for _, box := range d.Boxes {
+		boxes = append(boxes, map[string]interface{}{
+			"x_min": box.Min.X,
+			"y_min": box.Min.Y,
+			"x_max": box.Max.X,
+			"y_max": box.Max.Y,
+		})
+	}
+	return map[string]interface{}{
+		"namespace":      d.Key.Namespace,
+		"item_id":        d.Key.ItemID,
+		"count":          d.Count,
+		"bounding_boxes": boxes,
+	}
+}
+
+// findDuplicateItemIDs returns, sorted by namespace and item_id, every item
+// that more than one detection in the frame decodes to
+func (s *inventoryKeeperKeeper) findDuplicateItemIDs(detections []objectdetection.Detection) []duplicateItemID {
+	byKey := make(map[itemKey]*duplicateItemID)
+	for _, detection := range detections {
+		data, err := s.decodeQRPayload(detection.Label())
+		if err != nil || data.ItemID == "" {
+			continue
+		}
+		key := keyFor(data.Namespace, data.ItemID)
+		dup, ok := byKey[key]
+		if !ok {
+			dup = &duplicateItemID{Key: key}
+			byKey[key] = dup
+		}
+		dup.Count++
+		if box := detection.BoundingBox(); box != nil {
+			dup.Boxes = append(dup.Boxes, *box)
+		}
+	}
+
+	keys := make([]itemKey, 0, len(byKey))
+	for key, dup := range byKey {
+		if dup.Count > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sortItemKeys(keys)
+
+	duplicates := make([]duplicateItemID, 0, len(keys))
+	for _, key := range keys {
+		duplicates = append(duplicates, *byKey[key])
+	}
+	return duplicates
+}
+
+// duplicatesToInterfaceSlice converts duplicates for a DoCommand response
+func duplicatesToInterfaceSlice(duplicates []duplicateItemID) []interface{} {
+	result := make([]interface{}, 0, len(duplicates))
+	for _, dup := range duplicates {
+		result = append(result, dup.toMap())
+	}
+	return result
+}
+
+// reportDuplicates records the duplicates seen by a background scan and, when
+// duplicate_alerts is enabled, raises an alert for each item that was not
+// already duplicated in the previous scan
+func (s *inventoryKeeperKeeper) reportDuplicates(duplicates []duplicateItemID) {
+	current := make(map[itemKey]bool, len(duplicates))
+	for _, dup := range duplicates {
+		current[dup.Key] = true
+	}
+
+	s.monitorMu.Lock()
+	previous := s.duplicates
+	s.duplicates = current
+	s.monitorMu.Unlock()
+
+	for _, dup := range duplicates {
+		if previous[dup.Key] {
+			continue
+		}
+		s.logger.Warnf("Item %s in namespace %s appears on %d labels in one frame", dup.Key.ItemID, dup.Key.Namespace, dup.Count)
+		if s.config().DuplicateAlerts {
+			s.raiseAlert("duplicate_item_id", severityWarning, dup.Key.ItemID,
+				fmt.Sprintf("Item %s appears on %d labels in the same frame", dup.Key.ItemID, dup.Count),
+				dup.toMap())
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestDuplicateItemIDs(t *testing.T) {
+	apple := `{"item_id":"apple-001","item_name":"Apple"}`
+	banana := `{"item_id":"banana-001","item_name":"Banana"}`
+	frame := []objectdetection.Detection{
+		testDetection(0, apple),
+		testDetection(1, banana),
+		testDetection(2, apple),
+	}
+
+	t.Run("scan_qr flags labels sharing an item_id", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return frame, nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		duplicates := result["duplicate_item_ids"].([]interface{})
+		if len(duplicates) != 1 {
+			t.Fatalf("expected 1 duplicate item_id, got: %v", duplicates)
+		}
+
+		dup := duplicates[0].(map[string]interface{})
+		if dup["item_id"] != "apple-001" || dup["count"] != 2 {
+			t.Errorf("expected apple-001 seen twice, got: %v", dup)
+		}
+		boxes := dup["bounding_boxes"].([]interface{})
+		if len(boxes) != 2 {
+			t.Fatalf("expected 2 bounding boxes, got: %v", boxes)
+		}
+		if boxes[0].(map[string]interface{})["x_min"] == boxes[1].(map[string]interface{})["x_min"] {
+			t.Errorf("expected distinct boxes for each label, got: %v", boxes)
+		}
+	})
+
+	t.Run("no duplicates in a frame of distinct labels", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return frame[:2], nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		if duplicates := result["duplicate_item_ids"].([]interface{}); len(duplicates) != 0 {
+			t.Errorf("expected no duplicates, got: %v", duplicates)
+		}
+	})
+
+	t.Run("background scan alerts once per duplicate when enabled", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{DuplicateAlerts: true})
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return frame, nil
+		}
+
+		for i := 0; i < 3; i++ {
+			if err := svc.scanAndCompare(context.Background()); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+		}
+
+		if len(svc.alerts) != 1 {
+			t.Fatalf("expected 1 alert across repeated scans, got: %v", svc.alerts)
+		}
+		if svc.alerts[0].Type != "duplicate_item_id" || svc.alerts[0].ItemID != "apple-001" {
+			t.Errorf("expected duplicate_item_id alert for apple-001, got: %+v", svc.alerts[0])
+		}
+	})
+}
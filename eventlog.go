@@ -0,0 +1,161 @@
+package inventorykeeper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxEventLogLine bounds a single replayed line; longer lines are counted as malformed
+const maxEventLogLine = 1 << 20
+
+// openEventLog opens the event log for appending, creating it if needed
+func openEventLog(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event_log_file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// appendEventLocked writes one detector event as a JSON line. Failures are
+// logged rather than returned so a full disk never stops detection.
+// Caller must hold theftMu.
+func (s *inventoryKeeperKeeper) appendEventLocked(event detectorEvent) {
+	if s.eventLog == nil {
+		return
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Warnf("Failed to encode event for log: %v", err)
+		return
+	}
+	if _, err := s.eventLog.Write(append(line, '\n')); err != nil {
+		s.logger.Warnf("Failed to append to event log: %v", err)
+	}
+}
+
+// replayResult summarizes running a recorded event log through a detector
+type replayResult struct {
+	Findings       []theftFinding
+	EventsReplayed int
+	MalformedLines int
+}
+
+// replayEventLog feeds every well-formed event in the file through the
+// given detector, which should be freshly created. Removals still held for
+// the delay when the log ends count as thefts, since nothing in the log
+// covered them.
+func replayEventLog(path string, detector *theftDetector) (replayResult, error) {
+	var result replayResult
+
+	f, err := os.Open(path)
+	if err != nil {
+		return result, fmt.Errorf("failed to open event log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLogLine)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var event detectorEvent
+		if err := json.Unmarshal(line, &event); err != nil || event.Type == "" || event.Time.IsZero() {
+			result.MalformedLines++
+			continue
+		}
+
+		result.EventsReplayed++
+		result.Findings = append(result.Findings, detector.process(event)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("failed to read event log %s: %w", path, err)
+	}
+	result.Findings = append(result.Findings, detector.flushPending()...)
+
+	return result, nil
+}
+
+// replayPath resolves replay_events' path argument. Replays read only from
+// event_log_file's directory, so the command can't be used to probe other
+// files; a relative path is taken from that directory.
+func replayPath(eventLogFile, path string) (string, error) {
+	if eventLogFile == "" {
+		return "", errors.New("replay_events needs event_log_file to be configured")
+	}
+	if path == "" {
+		return eventLogFile, nil
+	}
+	dir, err := filepath.Abs(filepath.Dir(eventLogFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve event_log_file directory: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	rel, err := filepath.Rel(dir, filepath.Clean(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path must be in the event_log_file directory %s", dir)
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+// handleReplayEvents re-runs a recorded event log in simulation mode and
+// returns the theft alerts it would have produced. No alerts are raised.
+// Optional arguments: path (defaults to event_log_file, and must be in its
+// directory) and check_in_window_seconds (defaults to the configured window)
+// for tuning.
+func (s *inventoryKeeperKeeper) handleReplayEvents(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	path, err := optionalStringArg(cmd, "path")
+	if err != nil {
+		return nil, err
+	}
+	path, err = replayPath(s.config().EventLogFile, path)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.config().checkInWindow()
+	windowSeconds, hasWindow, err := intArg(cmd, "check_in_window_seconds")
+	if err != nil {
+		return nil, err
+	}
+	if hasWindow {
+		if windowSeconds < 1 {
+			return nil, fmt.Errorf("check_in_window_seconds must be at least 1, got: %d", windowSeconds)
+		}
+		window = time.Duration(windowSeconds) * time.Second
+	}
+
+	result, err := replayEventLog(path, newTheftDetector(window, s.config().graceAfterCheckIn(), s.config().theftAlertDelay()))
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]interface{}, 0, len(result.Findings))
+	for _, finding := range result.Findings {
+		alerts = append(alerts, map[string]interface{}{
+			"type":       "theft",
+			"namespace":  finding.Namespace,
+			"item_id":    finding.ItemID,
+			"removed_at": formatTimestampIn(finding.Time, s.location),
+		})
+	}
+
+	return map[string]interface{}{
+		"alerts":          alerts,
+		"alert_count":     len(alerts),
+		"events_replayed": result.EventsReplayed,
+		"malformed_lines": result.MalformedLines,
+	}, nil
+}
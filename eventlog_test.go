@@ -0,0 +1,119 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestEventLogReplay(t *testing.T) {
+	ctx := context.Background()
+	logDir := t.TempDir()
+	logPath := filepath.Join(logDir, "events.jsonl")
+	// Replayers log next to the recorded session, since replays only read
+	// from event_log_file's directory
+	replayerLog := filepath.Join(logDir, "replayer.jsonl")
+
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+	banana, _ := json.Marshal(ItemQRData{ItemID: "banana-042", ItemName: "Organic Banana"})
+
+	// Record a live session: apple is checked in, both items leave the shelf
+	svc, mockVision := newTestKeeper(t, &Config{EventLogFile: logPath})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "banana-042", "item_name": "Organic Banana"})
+
+	mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{testDetection(0, string(apple)), testDetection(1, string(banana))}, nil
+	}
+	svc.scanAndCompare(ctx)
+	svc.scanAndCompare(ctx)
+
+	mustDoCommand(t, svc, map[string]interface{}{"command": "check_in", "item_id": "apple-001"})
+
+	mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{}, nil
+	}
+	svc.scanAndCompare(ctx)
+	svc.scanAndCompare(ctx)
+
+	live := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})
+	if live["count"] != 1 {
+		t.Fatalf("expected 1 live theft alert, got: %v", live["count"])
+	}
+
+	// Corrupt the log with a partial line, as a crash mid-write would
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("failed to open event log: %v", err)
+	}
+	f.WriteString("{\"type\":\"item_rem\n")
+	f.Close()
+
+	t.Run("replay reconstructs the live alert outcome", func(t *testing.T) {
+		replayer, _ := newTestKeeper(t, &Config{EventLogFile: replayerLog})
+
+		result := mustDoCommand(t, replayer, map[string]interface{}{"command": "replay_events", "path": logPath})
+		if result["events_replayed"] != 2 {
+			t.Errorf("expected 2 events replayed, got: %v", result["events_replayed"])
+		}
+		if result["malformed_lines"] != 1 {
+			t.Errorf("expected 1 malformed line, got: %v", result["malformed_lines"])
+		}
+		alerts := result["alerts"].([]interface{})
+		if len(alerts) != 1 || alerts[0].(map[string]interface{})["item_id"] != "banana-042" {
+			t.Errorf("expected a single banana theft, got: %v", alerts)
+		}
+
+		// Simulation never raises real alerts
+		if count := mustDoCommand(t, replayer, map[string]interface{}{"command": "get_alerts"})["count"]; count != 0 {
+			t.Errorf("expected replay to raise no alerts, got: %v", count)
+		}
+	})
+
+	t.Run("replay defaults to event_log_file", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "replay_events"})
+		if result["alert_count"] != 1 {
+			t.Errorf("expected 1 replayed alert, got: %v", result["alert_count"])
+		}
+	})
+
+	t.Run("removals still pending when the log ends are thefts", func(t *testing.T) {
+		delay := 30
+		replayer, _ := newTestKeeper(t, &Config{EventLogFile: replayerLog, TheftAlertDelaySeconds: &delay})
+
+		result := mustDoCommand(t, replayer, map[string]interface{}{"command": "replay_events", "path": "events.jsonl"})
+		alerts := result["alerts"].([]interface{})
+		if len(alerts) != 1 || alerts[0].(map[string]interface{})["item_id"] != "banana-042" {
+			t.Errorf("expected the held banana removal to be reported, got: %v", alerts)
+		}
+	})
+
+	t.Run("replay without event_log_file returns error", func(t *testing.T) {
+		replayer, _ := newTestKeeper(t, nil)
+		if _, err := replayer.DoCommand(ctx, map[string]interface{}{"command": "replay_events", "path": logPath}); err == nil {
+			t.Error("expected error without event_log_file")
+		}
+	})
+
+	t.Run("replay refuses paths outside the event log directory", func(t *testing.T) {
+		outside := filepath.Join(t.TempDir(), "events.jsonl")
+		os.WriteFile(outside, nil, 0o644)
+		for _, path := range []string{outside, "../events.jsonl", filepath.Join(logDir, "..", "x", "events.jsonl")} {
+			if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "replay_events", "path": path}); err == nil {
+				t.Errorf("expected error replaying %s", path)
+			}
+		}
+	})
+
+	t.Run("replay needs the command secret", func(t *testing.T) {
+		replayer, _ := newTestKeeper(t, &Config{EventLogFile: replayerLog, CommandSecret: "s3cret"})
+		if _, err := replayer.DoCommand(ctx, map[string]interface{}{"command": "replay_events", "path": logPath}); !errors.Is(err, errUnauthorized) {
+			t.Errorf("expected unauthorized, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// expiryCheckInterval is how often expiry_alert_within is checked
+const expiryCheckInterval = time.Minute
+
+// expiryAlertWindow returns the expiry_alert_within window, or 0 when expiry
+// alerts are off
+func (cfg *Config) expiryAlertWindow() (time.Duration, error) {
+	if cfg.ExpiryAlertWithin == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(cfg.ExpiryAlertWithin)
+	if err != nil {
+		return 0, fmt.Errorf("expiry_alert_within must be a duration such as \"48h\": %w", err)
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("expiry_alert_within must be positive, got: %s", cfg.ExpiryAlertWithin)
+	}
+	return window, nil
+}
+
+// expiringWithin returns clones of the items that have not expired yet but
+// will within window of now, soonest first. An empty namespace matches all.
+func (s *inventoryKeeperKeeper) expiringWithin(namespace string, now time.Time, window time.Duration) []*InventoryItem {
+	deadline := now.Add(window)
+
+	var items []*InventoryItem
+	s.inventoryMu.RLock()
+	for key, item := range s.inventory {
+		if namespace != "" && key.Namespace != namespace {
+			continue
+		}
+		if item.ExpiresAt.IsZero() || item.ExpiresAt.Before(now) || item.ExpiresAt.After(deadline) {
+			continue
+		}
+		items = append(items, item.clone())
+	}
+	s.inventoryMu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].ExpiresAt.Equal(items[j].ExpiresAt) {
+			return items[i].ExpiresAt.Before(items[j].ExpiresAt)
+		}
+		return itemKeyLess(items[i].key(), items[j].key())
+	})
+	return items
+}
+
+// handleExpiringSoon lists a namespace's items expiring within the given
+// duration, soonest first. Items without an expiry and items that have
+// already expired are excluded.
+func (s *inventoryKeeperKeeper) handleExpiringSoon(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := optionalStringArg(cmd, "within")
+	if err != nil {
+		return nil, err
+	}
+	if raw == "" {
+		return nil, errors.New("within is required, e.g. \"48h\"")
+	}
+	window, err := time.ParseDuration(raw)
+	if err != nil {
+		return nil, fmt.Errorf("within must be a duration such as \"48h\": %w", err)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("within must be positive, got: %s", raw)
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	expiring := s.expiringWithin(namespace, now, window)
+	items := make([]interface{}, 0, len(expiring))
+	for _, item := range expiring {
+		entry := item.toMap(s.location)
+		entry["expires_in_seconds"] = item.ExpiresAt.Sub(now).Seconds()
+		items = append(items, entry)
+	}
+
+	return map[string]interface{}{
+		"namespace": namespace,
+		"within":    window.String(),
+		"items":     items,
+		"count":     len(items),
+	}, nil
+}
+
+// checkExpiring raises an alert for each item that has entered the
+// expiry_alert_within window since the last check. An item is alerted once
+// per expiry, so changing its expiry can alert it again.
+func (s *inventoryKeeperKeeper) checkExpiring() {
+	window, err := s.config().expiryAlertWindow()
+	if err != nil || window == 0 {
+		return
+	}
+
+	now := s.now()
+	for _, item := range s.expiringWithin("", now, window) {
+		key := item.key()
+		s.expiryMu.Lock()
+		alerted := s.expiryAlerted[key].Equal(item.ExpiresAt)
+		if !alerted {
+			if s.expiryAlerted == nil {
+				s.expiryAlerted = make(map[itemKey]time.Time)
+			}
+			s.expiryAlerted[key] = item.ExpiresAt
+		}
+		s.expiryMu.Unlock()
+		if alerted {
+			continue
+		}
+
+		s.raiseAlert("expiring_soon", severityWarning, item.ItemID,
+			fmt.Sprintf("Item %s (%s) expires in %v", item.ItemID, item.ItemName, item.ExpiresAt.Sub(now).Round(time.Minute)),
+			map[string]interface{}{
+				"namespace":  item.Namespace,
+				"expires_at": formatTimestampIn(item.ExpiresAt, s.location),
+			})
+	}
+}
+
+// startExpiryLoop runs checkExpiring every expiryCheckInterval until Close
+func (s *inventoryKeeperKeeper) startExpiryLoop() {
+	go func() {
+		ticker := time.NewTicker(expiryCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.cancelCtx.Done():
+				return
+			case <-ticker.C:
+				s.checkExpiring()
+			}
+		}
+	}()
+}
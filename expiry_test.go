@@ -0,0 +1,101 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExpiringSoon(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T, cfg *Config) *inventoryKeeperKeeper {
+		svc, _ := newTestKeeper(t, cfg)
+		svc.now = func() time.Time { return now }
+		for _, item := range []map[string]interface{}{
+			{"item_id": "milk-001", "item_name": "Milk", "expires_at": now.Add(24 * time.Hour).Format(time.RFC3339)},
+			{"item_id": "bread-002", "item_name": "Bread", "expires_at": now.Add(6 * time.Hour).Format(time.RFC3339)},
+			{"item_id": "yogurt-003", "item_name": "Yogurt", "expires_at": now.Add(-time.Hour).Format(time.RFC3339)},
+			{"item_id": "bolt-004", "item_name": "Bolt"},
+		} {
+			item["command"] = "add_item"
+			mustDoCommand(t, svc, item)
+		}
+		return svc
+	}
+	expiringIDs := func(t *testing.T, svc *inventoryKeeperKeeper, within string) []interface{} {
+		t.Helper()
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "expiring_soon", "within": within})
+		var ids []interface{}
+		for _, item := range result["items"].([]interface{}) {
+			ids = append(ids, item.(map[string]interface{})["item_id"])
+		}
+		return ids
+	}
+
+	t.Run("expiry follows the keeper clock", func(t *testing.T) {
+		svc, _, clock := newTestKeeperWithClock(t, nil, now)
+		item := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "milk-001", "item_name": "Milk",
+			"expires_at": now.Add(48 * time.Hour).Format(time.RFC3339),
+		})
+		if item["created_at"] != formatTimestamp(now) {
+			t.Errorf("expected created_at from the fake clock, got: %v", item["created_at"])
+		}
+
+		if ids := expiringIDs(t, svc, "24h"); len(ids) != 0 {
+			t.Fatalf("expected nothing expiring within a day, got: %v", ids)
+		}
+		clock.Advance(30 * time.Hour)
+		if ids := expiringIDs(t, svc, "24h"); len(ids) != 1 || ids[0] != "milk-001" {
+			t.Errorf("expected milk to come due after the clock advanced, got: %v", ids)
+		}
+	})
+
+	t.Run("window selects items soonest first", func(t *testing.T) {
+		svc := setup(t, nil)
+
+		ids := expiringIDs(t, svc, "48h")
+		if len(ids) != 2 || ids[0] != "bread-002" || ids[1] != "milk-001" {
+			t.Errorf("expected [bread-002 milk-001] within 48h, got: %v", ids)
+		}
+
+		// Milk expires in 24h, outside a 12h window
+		ids = expiringIDs(t, svc, "12h")
+		if len(ids) != 1 || ids[0] != "bread-002" {
+			t.Errorf("expected [bread-002] within 12h, got: %v", ids)
+		}
+	})
+
+	t.Run("within is validated", func(t *testing.T) {
+		svc := setup(t, nil)
+		for _, within := range []interface{}{nil, "soon", "-1h", 48} {
+			cmd := map[string]interface{}{"command": "expiring_soon"}
+			if within != nil {
+				cmd["within"] = within
+			}
+			if _, err := svc.DoCommand(context.Background(), cmd); err == nil {
+				t.Errorf("expected error for within=%v", within)
+			}
+		}
+	})
+
+	t.Run("alerts once per item entering the window", func(t *testing.T) {
+		svc := setup(t, &Config{ExpiryAlertWithin: "12h"})
+
+		svc.checkExpiring()
+		svc.checkExpiring()
+		alerts := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{})
+		if len(alerts) != 1 || alerts[0].(map[string]interface{})["item_id"] != "bread-002" {
+			t.Fatalf("expected one expiring_soon alert for bread-002, got: %v", alerts)
+		}
+
+		// A day later milk enters the window
+		now = now.Add(20 * time.Hour)
+		svc.checkExpiring()
+		alerts = mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{})
+		if len(alerts) != 2 || alerts[1].(map[string]interface{})["item_id"] != "milk-001" {
+			t.Errorf("expected a second alert for milk-001, got: %v", alerts)
+		}
+	})
+}
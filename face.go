@@ -0,0 +1,280 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// defaultFaceConfidence is the lowest detection score accepted as a
+// recognized person when face_confidence is not configured
+const defaultFaceConfidence = 0.8
+
+// removerLookupTimeout bounds the face check made as items leave the shelf,
+// so a slow face service holds up a scan by at most this long
+const removerLookupTimeout = 2 * time.Second
+
+// unrecognizedFaceLabels are labels face services give faces they couldn't
+// match to an enrolled person
+var unrecognizedFaceLabels = map[string]bool{"": true, "unknown": true, "unrecognized": true}
+
+// recognizedPerson is a face the face vision service matched to a person
+type recognizedPerson struct {
+	Name       string
+	Confidence float64
+	Authorized bool // In authorized_people, or any recognized person when that is empty
+}
+
+func (p recognizedPerson) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"name":       p.Name,
+		"confidence": p.Confidence,
+		"authorized": p.Authorized,
+	}
+}
+
+// faceConfidence returns the minimum score for a recognized face
+func (cfg *Config) faceConfidence() float64 {
+	if cfg.FaceConfidence == nil {
+		return defaultFaceConfidence
+	}
+	return *cfg.FaceConfidence
+}
+
+// faceCamera returns the camera faces are detected from, defaulting to the
+// shelf camera
+func (cfg *Config) faceCamera() string {
+	if cfg.FaceCameraName != "" {
+		return cfg.FaceCameraName
+	}
+	return cfg.CameraName
+}
+
+// personAuthorized reports whether name may authorize removals
+func (cfg *Config) personAuthorized(name string) bool {
+	if len(cfg.AuthorizedPeople) == 0 {
+		return true
+	}
+	for _, authorized := range cfg.AuthorizedPeople {
+		if strings.EqualFold(authorized, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayRemoveFrom reports whether name is listed as authorized to take items
+// from namespace: in its namespace_authorized_people entry if it has one,
+// else in authorized_people. Unlike personAuthorized, an empty list allows
+// no one, so an unlisted face never excuses a removal.
+func (cfg *Config) mayRemoveFrom(namespace, name string) bool {
+	names, ok := cfg.NamespaceAuthorizedPeople[normalizeNamespace(namespace)]
+	if !ok {
+		names = cfg.AuthorizedPeople
+	}
+	for _, authorized := range names {
+		if strings.EqualFold(authorized, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// recognizedPeople keeps the detections that name a person with at least
+// the configured confidence, best match first and one entry per person
+func recognizedPeople(cfg *Config, detections []objectdetection.Detection) []recognizedPerson {
+	threshold := cfg.faceConfidence()
+	best := map[string]recognizedPerson{}
+	for _, detection := range detections {
+		name := strings.TrimSpace(detection.Label())
+		if unrecognizedFaceLabels[strings.ToLower(name)] || detection.Score() < threshold {
+			continue
+		}
+		if seen, ok := best[name]; ok && seen.Confidence >= detection.Score() {
+			continue
+		}
+		best[name] = recognizedPerson{Name: name, Confidence: detection.Score(), Authorized: cfg.personAuthorized(name)}
+	}
+
+	people := make([]recognizedPerson, 0, len(best))
+	for _, person := range best {
+		people = append(people, person)
+	}
+	sort.Slice(people, func(i, j int) bool {
+		if people[i].Confidence != people[j].Confidence {
+			return people[i].Confidence > people[j].Confidence
+		}
+		return people[i].Name < people[j].Name
+	})
+	return people
+}
+
+// detectPeople runs the face vision service on the face camera, within the
+// command timeout and a vision call slot
+func (s *inventoryKeeperKeeper) detectPeople(ctx context.Context) ([]recognizedPerson, error) {
+	cfg := s.config()
+	if s.faceVisionService == nil {
+		return nil, fmt.Errorf("face recognition is not configured; set face_vision_service")
+	}
+	if timeout := cfg.commandTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	release, err := s.visionGate.acquire(ctx, cfg.VisionBusyFailFast)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	detections, err := s.faceVisionService.DetectionsFromCamera(ctx, cfg.faceCamera(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect faces from camera %s: %w", cfg.faceCamera(), err)
+	}
+	return recognizedPeople(cfg, detections), nil
+}
+
+// confirmRemovalByFace returns the authorized person in view when
+// require_face_for_removal is set, or "" when it isn't. If person is given,
+// that person must be the one recognized. Fails with UNAUTHORIZED when no
+// authorized person is in view.
+func (s *inventoryKeeperKeeper) confirmRemovalByFace(ctx context.Context, cmdType, person string) (recognizedPerson, error) {
+	if !s.config().RequireFaceForRemoval {
+		return recognizedPerson{}, nil
+	}
+	people, err := s.detectPeople(ctx)
+	if err != nil {
+		return recognizedPerson{}, fmt.Errorf("%w: %s requires face confirmation: %v", errUnauthorized, cmdType, err)
+	}
+	for _, candidate := range people {
+		if !candidate.Authorized {
+			continue
+		}
+		if person == "" || strings.EqualFold(candidate.Name, person) {
+			s.logger.Infof("%s confirmed by face: %s (%.2f)", cmdType, candidate.Name, candidate.Confidence)
+			return candidate, nil
+		}
+	}
+	if person != "" {
+		return recognizedPerson{}, fmt.Errorf("%w: %s requires %s to be recognized as an authorized person", errUnauthorized, cmdType, person)
+	}
+	return recognizedPerson{}, fmt.Errorf("%w: %s requires a recognized authorized person in view of camera %s", errUnauthorized, cmdType, s.config().faceCamera())
+}
+
+// recognizeRemover checks who is at the shelf as items leave the
+// present-set and returns, by namespace, the best-matched person allowed to
+// take items from it, recording them as the items' taker. Namespaces with
+// nobody allowed in view are left out, so their removals count toward theft
+// alerts, as are all removals when face recognition isn't configured, fails,
+// or takes longer than removerLookupTimeout. removed is sorted by namespace.
+func (s *inventoryKeeperKeeper) recognizeRemover(removed []itemKey) map[string]string {
+	if len(removed) == 0 || s.faceVisionService == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(s.cancelCtx, removerLookupTimeout)
+	defer cancel()
+	people, err := s.detectPeople(ctx)
+	if err != nil {
+		s.logger.Warnf("Could not check who removed %d items: %v", len(removed), err)
+		return nil
+	}
+
+	cfg := s.config()
+	takers := make(map[string]string)
+	for start := 0; start < len(removed); {
+		namespace := removed[start].Namespace
+		end := start
+		for end < len(removed) && removed[end].Namespace == namespace {
+			end++
+		}
+
+		var taker recognizedPerson
+		for _, person := range people {
+			if cfg.mayRemoveFrom(namespace, person.Name) {
+				taker = person
+				break
+			}
+		}
+		if taker.Name == "" {
+			s.logger.Infof("No authorized person recognized for removal of %d items from %s", end-start, namespace)
+			start = end
+			continue
+		}
+
+		takers[namespace] = taker.Name
+		s.monitorMu.Lock()
+		for _, key := range removed[start:end] {
+			if entry, ok := s.checkouts[key]; ok {
+				entry.TakenBy = taker.Name
+			}
+		}
+		s.monitorMu.Unlock()
+		s.logger.Infow(fmt.Sprintf("Removal of %d items from %s attributed to %s (%.2f)", end-start, namespace, taker.Name, taker.Confidence),
+			"event", "removal_attributed", "namespace", namespace, "person", taker.Name)
+		start = end
+	}
+	return takers
+}
+
+// handleListPeople returns the people the face vision service can recognize
+func (s *inventoryKeeperKeeper) handleListPeople(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return s.faceServiceCommand(ctx, "list_people", cmd)
+}
+
+// handleEnrollPerson adds a person to the face vision service. The name and
+// any images are passed through as given.
+func (s *inventoryKeeperKeeper) handleEnrollPerson(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if name, ok := cmd["name"].(string); !ok || strings.TrimSpace(name) == "" {
+		return nil, errors.New("name is required and must be a string")
+	}
+	return s.faceServiceCommand(ctx, "enroll_person", cmd)
+}
+
+// faceServiceCommand forwards a command to the face vision service's
+// DoCommand, leaving out the keeper's own auth field
+func (s *inventoryKeeperKeeper) faceServiceCommand(ctx context.Context, command string, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if s.faceVisionService == nil {
+		return nil, fmt.Errorf("face recognition is not configured; set face_vision_service")
+	}
+	forward := make(map[string]interface{}, len(cmd))
+	for key, value := range cmd {
+		if key != "auth" {
+			forward[key] = value
+		}
+	}
+	forward["command"] = command
+
+	result, err := s.faceVisionService.DoCommand(ctx, forward)
+	if err != nil {
+		return nil, fmt.Errorf("face vision service %s failed %s: %w", s.config().FaceVisionService, command, err)
+	}
+	return result, nil
+}
+
+// handleDetectPerson reports the people the face vision service recognizes
+// on the face camera and whether each may authorize removals
+func (s *inventoryKeeperKeeper) handleDetectPerson(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	people, err := s.detectPeople(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]interface{}, 0, len(people))
+	authorized := 0
+	for _, person := range people {
+		entries = append(entries, person.toMap())
+		if person.Authorized {
+			authorized++
+		}
+	}
+	return map[string]interface{}{
+		"camera":     s.config().faceCamera(),
+		"people":     entries,
+		"count":      len(people),
+		"authorized": authorized,
+	}, nil
+}
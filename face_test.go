@@ -0,0 +1,267 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// faceDetection is a recognized face labeled with the person's name
+func faceDetection(name string, score float64) objectdetection.Detection {
+	return objectdetection.NewDetection(
+		image.Rect(0, 0, 640, 480),
+		image.Rect(200, 100, 320, 240),
+		score,
+		name,
+	)
+}
+
+func TestRequireFaceForRemoval(t *testing.T) {
+	ctx := context.Background()
+
+	// setup stocks one item with removals gated on alice or bob
+	setup := func(t *testing.T, faces ...objectdetection.Detection) *inventoryKeeperKeeper {
+		svc, _ := newTestKeeper(t, &Config{
+			FaceVisionService:     "test-face-vision",
+			RequireFaceForRemoval: true,
+			AuthorizedPeople:      []string{"alice", "bob"},
+		})
+		faceVision := svc.faceVisionService.(*inject.VisionService)
+		faceVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return faces, nil
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+		return svc
+	}
+
+	t.Run("removal is blocked when no authorized face is recognized", func(t *testing.T) {
+		for name, faces := range map[string][]objectdetection.Detection{
+			"nobody":         nil,
+			"unknown face":   {faceDetection("unknown", 0.99)},
+			"low confidence": {faceDetection("alice", 0.5)},
+			"not authorized": {faceDetection("mallory", 0.95)},
+		} {
+			svc := setup(t, faces...)
+			_, err := svc.DoCommand(ctx, map[string]interface{}{"command": "remove_item", "item_id": "apple-001"})
+			if !errors.Is(err, errUnauthorized) {
+				t.Errorf("%s: expected UNAUTHORIZED, got: %v", name, err)
+			}
+			if _, exists := svc.inventory[keyFor("", "apple-001")]; !exists {
+				t.Errorf("%s: item should not have been removed", name)
+			}
+		}
+	})
+
+	t.Run("removal is allowed and attributed when an authorized person is recognized", func(t *testing.T) {
+		svc := setup(t, faceDetection("mallory", 0.99), faceDetection("alice", 0.91))
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "remove_item", "item_id": "apple-001"})
+		if result["authorized_by"] != "alice" {
+			t.Errorf("expected removal authorized by alice, got: %v", result["authorized_by"])
+		}
+		last := svc.history[len(svc.history)-1]
+		if last.Type != "item_removed" || last.Details["authorized_by"] != "alice" {
+			t.Errorf("expected item_removed history authorized by alice, got: %+v", last)
+		}
+	})
+
+	t.Run("check-in records the recognized person and rejects a mismatched one", func(t *testing.T) {
+		svc := setup(t, faceDetection("bob", 0.9))
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "check_in", "item_id": "apple-001"})
+		if result["authorized_by"] != "bob" {
+			t.Errorf("expected check-in by bob, got: %v", result["authorized_by"])
+		}
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "check_in", "item_id": "apple-001", "person": "alice"}); !errors.Is(err, errUnauthorized) {
+			t.Errorf("expected UNAUTHORIZED when alice isn't the one in view, got: %v", err)
+		}
+	})
+
+	t.Run("detect_person lists recognized people", func(t *testing.T) {
+		svc := setup(t, faceDetection("mallory", 0.99), faceDetection("alice", 0.91), faceDetection("unknown", 0.99))
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "detect_person"})
+		if result["count"] != 2 || result["authorized"] != 1 {
+			t.Errorf("expected 2 recognized and 1 authorized, got: %v", result)
+		}
+	})
+
+	t.Run("namespace_authorized_people is validated", func(t *testing.T) {
+		for _, people := range []map[string][]string{{"bad namespace": {"alice"}}, {"cold-storage": {" "}}} {
+			cfg := &Config{CameraName: "cam", QRVisionService: "qr", NamespaceAuthorizedPeople: people}
+			if _, _, err := cfg.Validate(""); err == nil {
+				t.Errorf("expected validation error for %v", people)
+			}
+		}
+	})
+
+	t.Run("flag without face recognition fails validation", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", RequireFaceForRemoval: true}
+		if _, _, err := cfg.Validate("test"); err == nil {
+			t.Error("expected require_face_for_removal without face_vision_service to fail validation")
+		}
+	})
+}
+
+func TestFaceAttributedRemoval(t *testing.T) {
+	ctx := context.Background()
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	// setup confirms apple-001 present, then takes it off the shelf while
+	// the face camera sees the given faces
+	setup := func(t *testing.T, faces ...objectdetection.Detection) *inventoryKeeperKeeper {
+		noDelay := 0
+		svc, mockVision := newTestKeeper(t, &Config{
+			FaceVisionService:   "test-face-vision",
+			AuthorizedPeople:    []string{"alice"},
+			CheckInDelaySeconds: &noDelay,
+		})
+		faceVision := svc.faceVisionService.(*inject.VisionService)
+		faceVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return faces, nil
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+		}
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{}, nil
+		}
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		return svc
+	}
+
+	t.Run("removal by an authorized person is attributed, not a theft", func(t *testing.T) {
+		svc := setup(t, faceDetection("alice", 0.93))
+
+		if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]; count != 0 {
+			t.Errorf("expected no theft alert, got: %v", count)
+		}
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "list_checked_out"})
+		if result["count"] != 1 {
+			t.Fatalf("expected apple-001 checked out, got: %v", result["count"])
+		}
+		if item := result["items"].([]interface{})[0].(map[string]interface{}); item["taken_by"] != "alice" {
+			t.Errorf("expected removal attributed to alice, got: %v", item["taken_by"])
+		}
+	})
+
+	t.Run("unrecognized removal counts toward theft", func(t *testing.T) {
+		for name, faces := range map[string][]objectdetection.Detection{
+			"nobody":         nil,
+			"not authorized": {faceDetection("mallory", 0.95)},
+		} {
+			svc := setup(t, faces...)
+			if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]; count != 1 {
+				t.Errorf("%s: expected a theft alert, got: %v", name, count)
+			}
+		}
+	})
+
+	t.Run("a face only excuses removals from namespaces it may take from", func(t *testing.T) {
+		for name, cfg := range map[string]*Config{
+			"listed elsewhere": {AuthorizedPeople: []string{"alice"}, NamespaceAuthorizedPeople: map[string][]string{"cold-storage": {"bob"}}},
+			"no list":          {},
+		} {
+			cfg.FaceVisionService = "test-face-vision"
+			svc, mockVision := newTestKeeper(t, cfg)
+			svc.faceVisionService.(*inject.VisionService).DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+				return []objectdetection.Detection{faceDetection("alice", 0.95)}, nil
+			}
+			mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "namespace": "cold-storage", "item_id": "milk-001", "item_name": "Milk"})
+			milk, _ := json.Marshal(ItemQRData{Namespace: "cold-storage", ItemID: "milk-001", ItemName: "Milk"})
+
+			mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+				return []objectdetection.Detection{testDetection(0, string(milk))}, nil
+			}
+			svc.scanAndCompare(ctx)
+			svc.scanAndCompare(ctx)
+			mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+				return []objectdetection.Detection{}, nil
+			}
+			svc.scanAndCompare(ctx)
+			svc.scanAndCompare(ctx)
+
+			if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]; count != 1 {
+				t.Errorf("%s: expected alice's removal from cold-storage to alert, got: %v", name, count)
+			}
+		}
+	})
+
+	t.Run("a slow face service doesn't hold up the scan", func(t *testing.T) {
+		svc := setup(t)
+		svc.faceVisionService.(*inject.VisionService).DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		started := time.Now()
+		svc.recognizeRemover([]itemKey{keyFor(defaultNamespace, "apple-001")})
+		if elapsed := time.Since(started); elapsed > removerLookupTimeout+time.Second {
+			t.Errorf("expected the face check to give up after %v, took %v", removerLookupTimeout, elapsed)
+		}
+	})
+
+	t.Run("simulate_scan attributes removals like a live scan", func(t *testing.T) {
+		svc := setup(t, faceDetection("alice", 0.93))
+		mockVision := svc.qrVisionService.(*inject.VisionService)
+		calls := 0
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			calls++
+			if calls <= 2 {
+				return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+			}
+			return []objectdetection.Detection{}, nil
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 48))); err != nil {
+			t.Fatalf("failed to encode frame: %v", err)
+		}
+		frame := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "simulate_scan", "images": []interface{}{frame, frame}})
+		if alerts := result["alerts"].([]interface{}); len(alerts) != 0 {
+			t.Errorf("expected alice's removal not to alert, got: %v", alerts)
+		}
+	})
+
+	t.Run("people commands are forwarded to the face service", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{FaceVisionService: "test-face-vision"})
+		var forwarded []map[string]interface{}
+		svc.faceVisionService.(*inject.VisionService).DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			forwarded = append(forwarded, cmd)
+			return map[string]interface{}{"people": []interface{}{"alice"}}, nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "list_people"})
+		if len(result["people"].([]interface{})) != 1 {
+			t.Errorf("expected the face service's people, got: %v", result)
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "enroll_person", "name": "bob", "images": []interface{}{"<base64>"}})
+		if len(forwarded) != 2 || forwarded[1]["command"] != "enroll_person" || forwarded[1]["name"] != "bob" || forwarded[1]["images"] == nil {
+			t.Errorf("unexpected forwarded commands: %v", forwarded)
+		}
+
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "enroll_person"}); err == nil {
+			t.Error("expected error without name")
+		}
+	})
+
+	t.Run("people commands need a face service", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "list_people"}); err == nil {
+			t.Error("expected error without face_vision_service")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"image"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// frameMemo remembers the last frame decoded from a camera
+type frameMemo struct {
+	hash       uint64
+	detections []objectdetection.Detection
+}
+
+// frameHash returns a 64-bit FNV-1a hash of a frame's pixels and size. It
+// is a content hash rather than a perceptual one: frames match only if every
+// pixel is identical, so any change on the shelf gets decoded.
+func frameHash(img image.Image) uint64 {
+	rgba := toRGBA(img)
+	bounds := rgba.Bounds()
+
+	h := fnv.New64a()
+	var size [8]byte
+	binary.BigEndian.PutUint32(size[:4], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(size[4:], uint32(bounds.Dy()))
+	h.Write(size[:])
+	rowBytes := bounds.Dx() * 4
+	for y := 0; y < bounds.Dy(); y++ {
+		start := y * rgba.Stride
+		h.Write(rgba.Pix[start : start+rowBytes])
+	}
+	return h.Sum64()
+}
+
+// detectInFrameDeduped is detectInFrame, except that a frame identical to
+// the previous one from the same camera reuses its detections instead of
+// calling the vision service again
+func (s *inventoryKeeperKeeper) detectInFrameDeduped(ctx context.Context, cameraName string, frame image.Image) ([]objectdetection.Detection, error) {
+	hash := frameHash(frame)
+
+	s.framesMu.Lock()
+	memo, ok := s.lastFrames[cameraName]
+	s.framesMu.Unlock()
+	if ok && memo.hash == hash {
+		s.scanStats.recordSkippedFrame()
+		return append([]objectdetection.Detection(nil), memo.detections...), nil
+	}
+
+	detections, err := s.detectInFrame(ctx, frame)
+	if err != nil {
+		return nil, err
+	}
+
+	s.framesMu.Lock()
+	if s.lastFrames == nil {
+		s.lastFrames = make(map[string]frameMemo)
+	}
+	s.lastFrames[cameraName] = frameMemo{hash: hash, detections: detections}
+	s.framesMu.Unlock()
+	return append([]objectdetection.Detection(nil), detections...), nil
+}
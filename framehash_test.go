@@ -0,0 +1,69 @@
+package inventorykeeper
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"sync/atomic"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestFrameHash(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	b := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	if frameHash(a) != frameHash(b) {
+		t.Error("expected identical frames to hash the same")
+	}
+
+	b.Set(10, 10, color.White)
+	if frameHash(a) == frameHash(b) {
+		t.Error("expected a one-pixel change to change the hash")
+	}
+
+	// Same pixels, different shape
+	if frameHash(image.NewRGBA(image.Rect(0, 0, 48, 64))) == frameHash(a) {
+		t.Error("expected frame size to be part of the hash")
+	}
+}
+
+func TestSkipDuplicateFrames(t *testing.T) {
+	svc, mockVision := newTestKeeper(t, &Config{SkipDuplicateFrames: true})
+
+	var calls int32
+	mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		atomic.AddInt32(&calls, 1)
+		return []objectdetection.Detection{testDetection(0, `{"item_id":"apple-001","item_name":"Apple"}`)}, nil
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	setCameraFrame(t, svc, frame)
+	for i := 0; i < 2; i++ {
+		if err := svc.scanAndCompare(context.Background()); err != nil {
+			t.Fatalf("scan %d failed: %v", i, err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected identical frames to be decoded once, got: %d decodes", got)
+	}
+	stats := mustDoCommand(t, svc, map[string]interface{}{"command": "get_scan_stats"})
+	if stats["duplicate_frames_skipped"] != 1 {
+		t.Errorf("expected 1 skipped frame, got: %v", stats["duplicate_frames_skipped"])
+	}
+	// The skipped scan still saw the apple
+	if stats["scans"] != 2 || stats["successful"] != 2 {
+		t.Errorf("expected both scans to succeed, got: %v", stats)
+	}
+
+	changed := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	changed.Set(1, 1, color.White)
+	setCameraFrame(t, svc, changed)
+	if err := svc.scanAndCompare(context.Background()); err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected a changed frame to be decoded, got: %d decodes", got)
+	}
+}
@@ -0,0 +1,135 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sort"
+
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Methods shelf_fullness can report
+const (
+	fullnessMethodItemArea      = "item_area"      // Area of QR label boxes
+	fullnessMethodVisionService = "vision_service" // Area of fullness_vision_service detections
+)
+
+// unionArea returns the area of the union of rects within clip. Boxes are
+// few, so coordinate compression over their edges is plenty fast.
+func unionArea(rects []image.Rectangle, clip image.Rectangle) int {
+	var clipped []image.Rectangle
+	xs := map[int]bool{}
+	ys := map[int]bool{}
+	for _, r := range rects {
+		r = r.Intersect(clip)
+		if r.Empty() {
+			continue
+		}
+		clipped = append(clipped, r)
+		xs[r.Min.X], xs[r.Max.X] = true, true
+		ys[r.Min.Y], ys[r.Max.Y] = true, true
+	}
+
+	sortedKeys := func(m map[int]bool) []int {
+		keys := make([]int, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		return keys
+	}
+	xEdges, yEdges := sortedKeys(xs), sortedKeys(ys)
+
+	area := 0
+	for i := 0; i+1 < len(xEdges); i++ {
+		for j := 0; j+1 < len(yEdges); j++ {
+			cell := image.Pt(xEdges[i], yEdges[j])
+			for _, r := range clipped {
+				if cell.In(r) {
+					area += (xEdges[i+1] - xEdges[i]) * (yEdges[j+1] - yEdges[j])
+					break
+				}
+			}
+		}
+	}
+	return area
+}
+
+// gatedDetector returns a frameDetector for svc that shares the vision call limit
+func (s *inventoryKeeperKeeper) gatedDetector(svc vision.Service) frameDetector {
+	return func(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+		release, err := s.visionGate.acquire(ctx, s.config().VisionBusyFailFast)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return svc.Detections(ctx, img, nil)
+	}
+}
+
+// handleShelfFullness estimates how full the shelf is as the fraction of the
+// scanned region (the ROI, or the whole frame) covered by detection boxes.
+// With fullness_vision_service the boxes are stocked items; otherwise they
+// are QR labels, a much rougher proxy. The estimate is "unknown" when there
+// is nothing to measure, e.g. no labels in view.
+func (s *inventoryKeeperKeeper) handleShelfFullness(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	cfg := s.config()
+	img, err := s.captureFrame(ctx)
+	if err != nil {
+		return nil, err
+	}
+	frame := preprocessImage(img, cfg)
+
+	region := frame.Bounds()
+	if cfg.ROI != nil {
+		if region, err = cfg.ROI.rect(frame.Bounds()); err != nil {
+			return nil, err
+		}
+	}
+
+	method := fullnessMethodItemArea
+	detect := frameDetector(s.visionDetections)
+	if s.fullnessVisionService != nil {
+		method = fullnessMethodVisionService
+		detect = s.gatedDetector(s.fullnessVisionService)
+	}
+	detections, err := s.detectInFrameWith(ctx, frame, detect)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect items: %w", err)
+	}
+
+	boxes := make([]image.Rectangle, 0, len(detections))
+	for _, detection := range detections {
+		if box := detection.BoundingBox(); box != nil {
+			boxes = append(boxes, *box)
+		}
+	}
+
+	result := map[string]interface{}{
+		"method":      method,
+		"approximate": true,
+		"detections":  len(detections),
+		"region": map[string]interface{}{
+			"x_min": region.Min.X,
+			"y_min": region.Min.Y,
+			"x_max": region.Max.X,
+			"y_max": region.Max.Y,
+		},
+	}
+
+	// An empty item detector is a real "empty" reading; no QR labels is not
+	switch {
+	case len(boxes) == 0 && method == fullnessMethodItemArea:
+		result["status"] = "unknown"
+		result["reason"] = "no QR labels with bounding boxes in view"
+	case len(boxes) < len(detections):
+		result["status"] = "unknown"
+		result["reason"] = "detector did not report bounding boxes"
+	default:
+		result["status"] = "ok"
+		result["fullness"] = float64(unionArea(boxes, region)) / float64(region.Dx()*region.Dy())
+	}
+	return result, nil
+}
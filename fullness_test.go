@@ -0,0 +1,83 @@
+package inventorykeeper
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestShelfFullness(t *testing.T) {
+	// boxesVision makes the mock vision service report the given boxes for any image
+	boxesVision := func(boxes ...image.Rectangle) func(context.Context, image.Image, map[string]interface{}) ([]objectdetection.Detection, error) {
+		return func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			detections := make([]objectdetection.Detection, 0, len(boxes))
+			for _, box := range boxes {
+				detections = append(detections, objectdetection.NewDetection(img.Bounds(), box, 1.0, `{"item_id":"apple-001","item_name":"Apple"}`))
+			}
+			return detections, nil
+		}
+	}
+	frame := image.NewRGBA(image.Rect(0, 0, 100, 100))
+
+	t.Run("union of overlapping label boxes over the frame", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		setCameraFrame(t, svc, frame)
+		mockVision.DetectionsFunc = boxesVision(image.Rect(0, 0, 50, 50), image.Rect(25, 0, 75, 50))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "shelf_fullness"})
+		if result["status"] != "ok" || result["method"] != fullnessMethodItemArea {
+			t.Fatalf("expected ok item_area estimate, got: %v", result)
+		}
+		// 75x50 covered of 100x100
+		if result["fullness"] != 0.375 {
+			t.Errorf("expected fullness 0.375, got: %v", result["fullness"])
+		}
+	})
+
+	t.Run("estimate is relative to the region of interest", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{ROI: &RegionOfInterest{X: 0, Y: 0, Width: 50, Height: 100, Units: roiUnitsPixels}})
+		setCameraFrame(t, svc, frame)
+		mockVision.DetectionsFunc = boxesVision(image.Rect(0, 0, 50, 50))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "shelf_fullness"})
+		if result["fullness"] != 0.5 {
+			t.Errorf("expected half the ROI covered, got: %v", result)
+		}
+	})
+
+	t.Run("no labels in view is unknown", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		setCameraFrame(t, svc, frame)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "shelf_fullness"})
+		if result["status"] != "unknown" {
+			t.Errorf("expected unknown, got: %v", result)
+		}
+		if _, ok := result["fullness"]; ok {
+			t.Errorf("expected no fullness number when unknown, got: %v", result["fullness"])
+		}
+	})
+}
+
+func TestUnionArea(t *testing.T) {
+	clip := image.Rect(0, 0, 100, 100)
+	for _, tc := range []struct {
+		name  string
+		rects []image.Rectangle
+		want  int
+	}{
+		{"empty", nil, 0},
+		{"single", []image.Rectangle{image.Rect(10, 10, 20, 30)}, 200},
+		{"nested counts once", []image.Rectangle{image.Rect(0, 0, 50, 50), image.Rect(10, 10, 20, 20)}, 2500},
+		{"clipped to region", []image.Rectangle{image.Rect(-50, -50, 10, 10)}, 100},
+		{"disjoint", []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(90, 90, 100, 100)}, 200},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := unionArea(tc.rects, clip); got != tc.want {
+				t.Errorf("expected %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
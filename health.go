@@ -0,0 +1,187 @@
+package inventorykeeper
+
+import (
+	"context"
+	"time"
+)
+
+// cameraDegradedThreshold is the number of consecutive failed scan iterations
+// (after retries are exhausted) before camera health is reported as degraded
+const cameraDegradedThreshold = 3
+
+// captureHealth tracks the outcome of recent capture attempts
+type captureHealth struct {
+	consecutiveFailures int       // Failed iterations since the last success
+	lastError           string    // Most recent capture error
+	lastErrorAt         time.Time // When the most recent error occurred
+	lastSuccessAt       time.Time // When a capture last succeeded
+
+	// Local decode fallback, used while the vision service is failing
+	usingFallback   bool      // The latest successful scan was decoded locally
+	localFallbacks  int       // Scans decoded locally since startup
+	lastFallbackAt  time.Time // When a scan was last decoded locally
+	lastVisionError string    // Vision error that caused the latest fallback
+
+	// Dependency refetches under dependency_failure_policy "refetch"
+	refetches        int       // Refetch attempts since startup
+	lastRefetchAt    time.Time // When dependencies were last refetched
+	lastRefetchError string    // Error from the latest refetch ("" if it succeeded)
+
+	frameMimeType string    // Format of the latest decoded camera frame
+	lastFrameAt   time.Time // When the camera last produced a frame, for camera_warmup_frames
+}
+
+// resetCameraState forgets the previous camera's failure streak, frame
+// timing, and duplicate-frame memos, so a swapped-in camera starts cold
+func (s *inventoryKeeperKeeper) resetCameraState() {
+	s.healthMu.Lock()
+	s.health.consecutiveFailures = 0
+	s.health.lastError = ""
+	s.health.lastErrorAt = time.Time{}
+	s.health.lastSuccessAt = time.Time{}
+	s.health.frameMimeType = ""
+	s.health.lastFrameAt = time.Time{}
+	s.healthMu.Unlock()
+
+	s.framesMu.Lock()
+	s.lastFrames = nil
+	s.framesMu.Unlock()
+}
+
+// degraded reports whether failures have persisted past the threshold
+func (h captureHealth) degraded() bool {
+	return h.consecutiveFailures >= cameraDegradedThreshold
+}
+
+// recordCaptureSuccess resets the failure streak
+func (s *inventoryKeeperKeeper) recordCaptureSuccess() {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if s.health.degraded() {
+		s.logger.Infof("Camera %s recovered after %d failed scans", s.config().CameraName, s.health.consecutiveFailures)
+	}
+	if s.health.usingFallback {
+		s.logger.Infof("Vision service %s recovered after %d locally decoded scans", s.config().QRVisionService, s.health.localFallbacks)
+	}
+	s.health.consecutiveFailures = 0
+	s.health.lastSuccessAt = s.now()
+	s.health.usingFallback = false
+}
+
+// recordLocalFallback notes a scan that succeeded only through the local
+// decoder, logging a warning when scanning first falls back
+func (s *inventoryKeeperKeeper) recordLocalFallback(visionErr error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if !s.health.usingFallback {
+		s.logger.Warnf("Vision service %s failed, decoding QR codes locally until it recovers: %v", s.config().QRVisionService, visionErr)
+	}
+	now := s.now()
+	s.health.consecutiveFailures = 0
+	s.health.lastSuccessAt = now
+	s.health.usingFallback = true
+	s.health.localFallbacks++
+	s.health.lastFallbackAt = now
+	s.health.lastVisionError = visionErr.Error()
+}
+
+// recordCaptureFailure extends the failure streak, logging once when the
+// camera crosses into degraded health
+func (s *inventoryKeeperKeeper) recordCaptureFailure(err error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	s.health.consecutiveFailures++
+	s.health.lastError = err.Error()
+	s.health.lastErrorAt = s.now()
+
+	if s.health.consecutiveFailures == cameraDegradedThreshold {
+		s.logger.Errorf("Camera %s degraded after %d consecutive failed scans: %v", s.config().CameraName, cameraDegradedThreshold, err)
+	}
+}
+
+// cameraStatus summarizes capture health for status responses
+func (s *inventoryKeeperKeeper) cameraStatus() map[string]interface{} {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	status := "ok"
+	if s.health.degraded() {
+		status = "degraded"
+	}
+
+	result := map[string]interface{}{
+		"name":                 s.config().CameraName,
+		"status":               status,
+		"consecutive_failures": s.health.consecutiveFailures,
+		"last_success_at":      formatTimestampIn(s.health.lastSuccessAt, s.location),
+	}
+	if s.health.lastError != "" {
+		result["last_error"] = s.health.lastError
+		result["last_error_at"] = formatTimestampIn(s.health.lastErrorAt, s.location)
+	}
+	if s.health.frameMimeType != "" {
+		result["frame_mime_type"] = s.health.frameMimeType
+	}
+	return result
+}
+
+// visionStatus summarizes vision service health for status responses.
+// "fallback" means scans are currently being decoded locally.
+func (s *inventoryKeeperKeeper) visionStatus() map[string]interface{} {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	status := "ok"
+	if s.health.usingFallback {
+		status = "fallback"
+	}
+
+	result := map[string]interface{}{
+		"name":                  s.config().QRVisionService,
+		"status":                status,
+		"local_decode_fallback": s.config().LocalDecodeFallback,
+		"local_fallbacks":       s.health.localFallbacks,
+	}
+	if s.health.localFallbacks > 0 {
+		result["last_fallback_at"] = formatTimestampIn(s.health.lastFallbackAt, s.location)
+		result["last_vision_error"] = s.health.lastVisionError
+	}
+	return result
+}
+
+// handleGetStatus reports keeper health and a summary of tracked state
+func (s *inventoryKeeperKeeper) handleGetStatus(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	s.monitorMu.Lock()
+	visibleCount := len(s.visibleCodes)
+	presentCount := 0
+	for _, entry := range s.presence {
+		if entry.Present {
+			presentCount++
+		}
+	}
+	s.monitorMu.Unlock()
+
+	s.inventoryMu.RLock()
+	inventoryCount := len(s.inventory)
+	s.inventoryMu.RUnlock()
+
+	status := map[string]interface{}{
+		"camera":             s.cameraStatus(),
+		"vision":             s.visionStatus(),
+		"dependencies":       s.dependencyStatus(),
+		"monitoring_enabled": s.config().monitoringEnabled(),
+		"monitoring_paused":  s.pauseStatus(),
+		"maintenance_mode":   s.maintenanceStatus(),
+		"visible_codes":      visibleCount,
+		"present_items":      presentCount,
+		"inventory_items":    inventoryCount,
+		"dropped_alerts":     s.droppedAlerts(),
+	}
+	if grace := s.startupGraceStatus(); grace != nil {
+		status["startup_grace"] = grace
+	}
+	return status, nil
+}
@@ -0,0 +1,75 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// testDetection builds a full-confidence detection carrying the given QR content.
+// Each detection gets its own bounding box based on index so multiple codes don't overlap.
+func testDetection(index int, content string) objectdetection.Detection {
+	x := 10 + index*110
+	return objectdetection.NewDetection(
+		image.Rectangle{Min: image.Point{X: 0, Y: 0}, Max: image.Point{X: 640, Y: 480}},     // Image bounds
+		image.Rectangle{Min: image.Point{X: x, Y: 10}, Max: image.Point{X: x + 90, Y: 100}}, // Bounding box
+		1.0, // Confidence
+		content,
+	)
+}
+
+// fakeClock is a clock that only moves when advanced
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the clock's current time
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestKeeperWithClock is newTestKeeper with the clock frozen at start and
+// scan jitter drawn from a fixed seed, so time-dependent behavior is deterministic
+func newTestKeeperWithClock(t *testing.T, cfg *Config, start time.Time) (*inventoryKeeperKeeper, *inject.VisionService, *fakeClock) {
+	t.Helper()
+	svc, mockVision := newTestKeeper(t, cfg)
+	clock := &fakeClock{now: start}
+	svc.now = clock.Now
+	svc.scanRand = rand.New(rand.NewPCG(1, 2)).Float64
+	return svc, mockVision, clock
+}
+
+// setCameraFrame makes the keeper's mock camera return the given image as PNG
+func setCameraFrame(t *testing.T, svc *inventoryKeeperKeeper, img image.Image) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test frame: %v", err)
+	}
+	frame := buf.Bytes()
+
+	svc.camera.(*inject.Camera).ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+		return frame, camera.ImageMetadata{MimeType: utils.MimeTypePNG}, nil
+	}
+}
@@ -0,0 +1,149 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// maxHistoryEvents bounds the in-memory history log so a long-running keeper
+// doesn't grow without limit. Oldest events are dropped first.
+const maxHistoryEvents = 1000
+
+// HistoryEvent records a single change to inventory state
+type HistoryEvent struct {
+	Type      string                 `json:"type"`                // Event type (e.g. "item_added", "undo")
+	Namespace string                 `json:"namespace,omitempty"` // Namespace of the item(s) the event applies to
+	ItemID    string                 `json:"item_id,omitempty"`   // Item the event applies to (if any)
+	Timestamp time.Time              `json:"timestamp"`           // When the event happened
+	Details   map[string]interface{} `json:"details,omitempty"`   // Event-specific details
+	Seq       int64                  `json:"seq,omitempty"`       // Position in the log, increasing; get_history cursors resume after it
+}
+
+// toMap converts the event into a DoCommand-friendly response map, with the
+// timestamp shown in loc
+func (e HistoryEvent) toMap(loc *time.Location) map[string]interface{} {
+	m := map[string]interface{}{
+		"type":      e.Type,
+		"namespace": normalizeNamespace(e.Namespace),
+		"timestamp": formatTimestampIn(e.Timestamp, loc),
+	}
+	if e.ItemID != "" {
+		m["item_id"] = e.ItemID
+	}
+	if len(e.Details) > 0 {
+		m["details"] = e.Details
+	}
+	if e.Seq > 0 {
+		m["seq"] = e.Seq
+	}
+	return m
+}
+
+// recordHistoryLocked appends an event to the history log.
+// Caller must hold inventoryMu for writing.
+func (s *inventoryKeeperKeeper) recordHistoryLocked(eventType, namespace, itemID string, details map[string]interface{}) HistoryEvent {
+	s.historySeq++
+	event := HistoryEvent{
+		Type:      eventType,
+		Namespace: namespace,
+		ItemID:    itemID,
+		Timestamp: s.now().UTC(),
+		Details:   details,
+		Seq:       s.historySeq,
+	}
+
+	s.history = append(s.history, event)
+	if len(s.history) > maxHistoryEvents {
+		s.history = s.history[len(s.history)-maxHistoryEvents:]
+	}
+	s.markStorageDirtyLocked()
+	s.logger.Debugw("History event recorded", "event", eventType, "namespace", namespace, "item_id", itemID)
+	return event
+}
+
+// numberHistory gives loaded events without an increasing seq (saved before
+// seqs existed, or hand-edited) one following the previous event's, and
+// returns the last seq
+func numberHistory(history []HistoryEvent) int64 {
+	var last int64
+	for i := range history {
+		if history[i].Seq <= last {
+			history[i].Seq = last + 1
+		}
+		last = history[i].Seq
+	}
+	return last
+}
+
+// handleGetHistory returns the namespace's recorded inventory events, oldest first
+func (s *inventoryKeeperKeeper) handleGetHistory(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, hasLimit, err := intArg(cmd, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if hasLimit && limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	pageSize, cursor, err := pageArgs(cmd, "get_history", namespace)
+	if err != nil {
+		return nil, err
+	}
+	if hasLimit && (pageSize > 0 || cursor != nil) {
+		return nil, errors.New("limit can't be combined with page_size or cursor")
+	}
+
+	s.inventoryMu.RLock()
+	events := make([]HistoryEvent, 0, len(s.history))
+	for _, event := range s.history {
+		if normalizeNamespace(event.Namespace) != namespace {
+			continue
+		}
+		if cursor != nil && event.Seq <= cursor.AfterSeq {
+			continue
+		}
+		events = append(events, event)
+	}
+	if hasLimit && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	nextCursor := ""
+	if pageSize > 0 && len(events) > pageSize {
+		events = events[:pageSize]
+		nextCursor = pageCursor{Command: "get_history", Namespace: namespace, AfterSeq: events[pageSize-1].Seq}.encode()
+	}
+	result := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		result = append(result, event.toMap(s.location))
+	}
+	s.inventoryMu.RUnlock()
+
+	response := map[string]interface{}{
+		"events": result,
+		"count":  len(result),
+	}
+	if nextCursor != "" {
+		response["next_cursor"] = nextCursor
+	}
+	return response, nil
+}
+
+// formatTimestamp renders a timestamp for DoCommand responses.
+// Zero timestamps are rendered as an empty string.
+func formatTimestamp(t time.Time) string {
+	return formatTimestampIn(t, time.UTC)
+}
+
+// formatTimestampIn renders a timestamp in the given zone, with its offset,
+// for responses shown to people (see the timezone config)
+func formatTimestampIn(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(time.RFC3339Nano)
+}
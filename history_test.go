@@ -0,0 +1,86 @@
+package inventorykeeper
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	t.Run("timestamps are shown in the configured zone", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{Timezone: "America/New_York"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+		svc.raiseAlert("test", severityInfo, "apple-001", "hello", nil)
+
+		history := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history"})
+		event := history["events"].([]interface{})[0].(map[string]interface{})
+		alerts := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})
+		alert := alerts["alerts"].([]interface{})[0].(map[string]interface{})
+		items := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})
+		item := items["items"].([]interface{})[0].(map[string]interface{})
+
+		for name, raw := range map[string]interface{}{
+			"history timestamp": event["timestamp"],
+			"alert created_at":  alert["created_at"],
+			"item created_at":   item["created_at"],
+		} {
+			shown, err := time.Parse(time.RFC3339Nano, raw.(string))
+			if err != nil {
+				t.Fatalf("%s %v is not RFC3339: %v", name, raw, err)
+			}
+			_, wantOffset := shown.In(newYork).Zone()
+			if _, offset := shown.Zone(); offset != wantOffset {
+				t.Errorf("expected %s in New York offset %ds, got: %s", name, wantOffset, raw)
+			}
+			if strings.HasSuffix(raw.(string), "Z") {
+				t.Errorf("expected %s with an offset rather than Z, got: %s", name, raw)
+			}
+		}
+
+		// Storage stays in UTC, and the displayed time is the same instant
+		svc.inventoryMu.RLock()
+		stored := svc.history[0].Timestamp
+		svc.inventoryMu.RUnlock()
+		if stored.Location() != time.UTC {
+			t.Errorf("expected history stored in UTC, got: %v", stored.Location())
+		}
+		shown, _ := time.Parse(time.RFC3339Nano, event["timestamp"].(string))
+		if !shown.Equal(stored) {
+			t.Errorf("expected displayed time %v to equal stored %v", shown, stored)
+		}
+	})
+
+	t.Run("alert notifications use the configured zone", func(t *testing.T) {
+		alert := Alert{ID: 1, Type: "theft", Severity: severityCritical, Message: "gone", CreatedAt: time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)}
+		const want = "2026-01-02T10:00:00-05:00"
+		if email := string(formatAlertEmail("keeper@example.com", []string{"ops@example.com"}, alert, newYork)); !strings.Contains(email, "Time: "+want) {
+			t.Errorf("expected email time %s, got:\n%s", want, email)
+		}
+		if slack := formatSlackMessage(alert, newYork); !strings.Contains(slack, want) {
+			t.Errorf("expected slack time %s, got: %s", want, slack)
+		}
+	})
+
+	t.Run("default is UTC", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+
+		history := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history"})
+		event := history["events"].([]interface{})[0].(map[string]interface{})
+		if ts := event["timestamp"].(string); !strings.HasSuffix(ts, "Z") {
+			t.Errorf("expected UTC timestamp, got: %s", ts)
+		}
+	})
+
+	t.Run("unknown zone is rejected", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", Timezone: "Mars/Olympus_Mons"}
+		if _, _, err := cfg.Validate(""); err == nil || !strings.Contains(err.Error(), "timezone") {
+			t.Errorf("expected timezone error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+)
+
+// imageSources lists the names of the camera's image sources, sorted
+func (s *inventoryKeeperKeeper) imageSources(ctx context.Context) ([]string, error) {
+	images, _, err := s.shelfCamera().Images(ctx, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list image sources of camera %s: %w", s.config().CameraName, err)
+	}
+	names := make([]string, 0, len(images))
+	for _, img := range images {
+		names = append(names, img.SourceName)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// captureFrameFromSource grabs a frame from one of the camera's named image
+// sources, or its default image when sourceName is empty. An unknown source
+// is an error listing the ones the camera has.
+func (s *inventoryKeeperKeeper) captureFrameFromSource(ctx context.Context, sourceName string) (image.Image, error) {
+	if sourceName == "" {
+		return s.captureFrame(ctx)
+	}
+
+	images, _, err := s.shelfCamera().Images(ctx, []string{sourceName}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture source %s from camera %s: %w", sourceName, s.config().CameraName, err)
+	}
+	// Cameras may ignore the filter, so match the name ourselves
+	for i := range images {
+		if images[i].SourceName != sourceName {
+			continue
+		}
+		img, err := images[i].Image(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode source %s from camera %s: %w", sourceName, s.config().CameraName, err)
+		}
+		return img, nil
+	}
+
+	available, err := s.imageSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("camera %s has no image source %q, available: %s", s.config().CameraName, sourceName, strings.Join(available, ", "))
+}
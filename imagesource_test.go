@@ -0,0 +1,89 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestImageSourceSelection(t *testing.T) {
+	ctx := context.Background()
+	payload, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	// The camera has a 16x8 color stream and an 8x8 depth stream
+	sources := map[string]image.Image{
+		"color": image.NewRGBA(image.Rect(0, 0, 16, 8)),
+		"depth": image.NewGray(image.Rect(0, 0, 8, 8)),
+	}
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, *inject.VisionService) {
+		svc, mockVision := newTestKeeper(t, nil)
+		svc.camera.(*inject.Camera).ImagesFunc = func(ctx context.Context, filterSourceNames []string, extra map[string]interface{}) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+			var out []camera.NamedImage
+			for _, name := range []string{"color", "depth"} {
+				if len(filterSourceNames) > 0 && filterSourceNames[0] != name {
+					continue
+				}
+				named, err := camera.NamedImageFromImage(sources[name], name, utils.MimeTypePNG, data.Annotations{})
+				if err != nil {
+					return nil, resource.ResponseMetadata{}, err
+				}
+				out = append(out, named)
+			}
+			return out, resource.ResponseMetadata{}, nil
+		}
+		return svc, mockVision
+	}
+
+	t.Run("get_image uses the named source", func(t *testing.T) {
+		svc, _ := setup(t)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_image", "source_name": "color"})
+		if result["width"] != 16 || result["source_name"] != "color" {
+			t.Errorf("expected the 16px color frame, got width %v source %v", result["width"], result["source_name"])
+		}
+	})
+
+	t.Run("scan_qr decodes the named source", func(t *testing.T) {
+		svc, mockVision := setup(t)
+		var scanned image.Rectangle
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			scanned = img.Bounds()
+			return []objectdetection.Detection{testDetection(0, string(payload))}, nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr", "source_name": "depth"})
+		if result["count"] != 1 || result["source_name"] != "depth" {
+			t.Errorf("expected one code from depth, got: %v", result)
+		}
+		if scanned.Dx() != 8 {
+			t.Errorf("expected the 8px depth frame to be scanned, got: %v", scanned)
+		}
+	})
+
+	t.Run("unknown source lists the available ones", func(t *testing.T) {
+		svc, _ := setup(t)
+		for _, command := range []string{"get_image", "scan_qr"} {
+			_, err := svc.DoCommand(ctx, map[string]interface{}{"command": command, "source_name": "infrared"})
+			if err == nil || !strings.Contains(err.Error(), "available: color, depth") {
+				t.Errorf("%s: expected error listing sources, got: %v", command, err)
+			}
+		}
+	})
+
+	t.Run("default source without source_name", func(t *testing.T) {
+		svc, _ := setup(t)
+		setCameraFrame(t, svc, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_image"})
+		if result["width"] != 4 || result["source_name"] != nil {
+			t.Errorf("expected the default 4px frame, got width %v source %v", result["width"], result["source_name"])
+		}
+	})
+}
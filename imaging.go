@@ -0,0 +1,141 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// defaultQRBorder is the quiet zone (in modules) go-qrcode draws by default
+const defaultQRBorder = 4
+
+// validRotations are the frame rotations (clockwise degrees) supported by preprocessing
+var validRotations = map[int]bool{0: true, 90: true, 180: true, 270: true}
+
+// preprocessImage applies the configured rotation, grayscale conversion, and
+// contrast stretch to a captured frame before QR decoding
+func preprocessImage(img image.Image, cfg *Config) image.Image {
+	out := toRGBA(img)
+
+	if cfg.RotateDegrees != 0 {
+		out = rotateClockwise(out, cfg.RotateDegrees)
+	}
+	if cfg.Grayscale {
+		out = grayscale(out)
+	}
+	if cfg.ContrastStretch {
+		out = contrastStretch(out)
+	}
+	return out
+}
+
+// toRGBA copies an image into a zero-origin RGBA buffer
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+	return out
+}
+
+// rotateClockwise rotates an image by 90, 180, or 270 degrees clockwise
+func rotateClockwise(img *image.RGBA, degrees int) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+
+	var out *image.RGBA
+	switch degrees {
+	case 90, 270:
+		out = image.NewRGBA(image.Rect(0, 0, h, w))
+	case 180:
+		out = image.NewRGBA(image.Rect(0, 0, w, h))
+	default:
+		return img
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			switch degrees {
+			case 90:
+				out.SetRGBA(h-1-y, x, c)
+			case 180:
+				out.SetRGBA(w-1-x, h-1-y, c)
+			case 270:
+				out.SetRGBA(y, w-1-x, c)
+			}
+		}
+	}
+	return out
+}
+
+// grayscale converts an image to luminance while keeping the RGBA layout
+func grayscale(img *image.RGBA) *image.RGBA {
+	out := image.NewRGBA(img.Bounds())
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			g := color.GrayModel.Convert(img.RGBAAt(x, y)).(color.Gray)
+			out.SetRGBA(x, y, color.RGBA{R: g.Y, G: g.Y, B: g.Y, A: 255})
+		}
+	}
+	return out
+}
+
+// contrastStretch linearly rescales each color channel so the darkest value
+// maps to 0 and the brightest to 255. Flat images are returned unchanged.
+func contrastStretch(img *image.RGBA) *image.RGBA {
+	minV, maxV := uint8(255), uint8(0)
+	for i := 0; i < len(img.Pix); i += 4 {
+		for _, v := range img.Pix[i : i+3] {
+			if v < minV {
+				minV = v
+			}
+			if v > maxV {
+				maxV = v
+			}
+		}
+	}
+	if maxV <= minV {
+		return img
+	}
+
+	scale := 255.0 / float64(maxV-minV)
+	out := image.NewRGBA(img.Bounds())
+	for i := 0; i < len(img.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			out.Pix[i+c] = uint8(float64(img.Pix[i+c]-minV)*scale + 0.5)
+		}
+		out.Pix[i+3] = img.Pix[i+3]
+	}
+	return out
+}
+
+// drawRectangle outlines a rectangle on the image with a 2px border
+func drawRectangle(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	const thickness = 2
+	rect = rect.Intersect(img.Bounds())
+	if rect.Empty() {
+		return
+	}
+	for t := 0; t < thickness; t++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, rect.Min.Y+t, c)
+			img.SetRGBA(x, rect.Max.Y-1-t, c)
+		}
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			img.SetRGBA(rect.Min.X+t, y, c)
+			img.SetRGBA(rect.Max.X-1-t, y, c)
+		}
+	}
+}
+
+// encodePNGBase64 encodes an image as base64 PNG for DoCommand responses
+func encodePNGBase64(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("failed to encode image: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
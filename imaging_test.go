@@ -0,0 +1,197 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// orientedTestImage returns a 40x20 image whose top-left quadrant is black,
+// standing in for a QR code that only decodes in its upright orientation
+func orientedTestImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			c := color.RGBA{R: 200, G: 200, B: 200, A: 255}
+			if x < 20 && y < 10 {
+				c = color.RGBA{A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// isUpright reports whether an image matches orientedTestImage's orientation
+func isUpright(img image.Image) bool {
+	b := img.Bounds()
+	if b.Dx() <= b.Dy() {
+		return false
+	}
+	r, _, _, _ := img.At(b.Min.X, b.Min.Y).RGBA()
+	return r < 0x4000
+}
+
+func TestPreprocessImage(t *testing.T) {
+	t.Run("rotation returns to upright", func(t *testing.T) {
+		upright := orientedTestImage()
+		for _, degrees := range []int{90, 180, 270} {
+			rotated := rotateClockwise(upright, degrees)
+			restored := rotateClockwise(rotated, 360-degrees)
+			if !isUpright(restored) {
+				t.Errorf("rotating by %d then %d should restore orientation", degrees, 360-degrees)
+			}
+		}
+		if isUpright(rotateClockwise(upright, 90)) {
+			t.Error("90-degree rotation should not be upright")
+		}
+	})
+
+	t.Run("grayscale equalizes channels", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		img.SetRGBA(0, 0, color.RGBA{R: 255, G: 0, B: 0, A: 255})
+		c := grayscale(img).RGBAAt(0, 0)
+		if c.R != c.G || c.G != c.B {
+			t.Errorf("expected equal channels, got: %+v", c)
+		}
+	})
+
+	t.Run("contrast stretch expands range", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+		img.SetRGBA(0, 0, color.RGBA{R: 100, G: 100, B: 100, A: 255})
+		img.SetRGBA(1, 0, color.RGBA{R: 150, G: 150, B: 150, A: 255})
+		out := contrastStretch(img)
+		if out.RGBAAt(0, 0).R != 0 || out.RGBAAt(1, 0).R != 255 {
+			t.Errorf("expected 0 and 255, got: %d and %d", out.RGBAAt(0, 0).R, out.RGBAAt(1, 0).R)
+		}
+	})
+
+	t.Run("invalid rotate_degrees rejected", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", RotateDegrees: 45}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for rotate_degrees=45")
+		}
+	})
+}
+
+func TestPreprocessingDecodePath(t *testing.T) {
+	content := `{"item_id":"coffee-888","item_name":"Dark Roast Coffee"}`
+
+	// Camera is mounted so that frames arrive rotated 90 degrees counter-clockwise
+	frame := rotateClockwise(orientedTestImage(), 270)
+
+	// The mock detector only "decodes" frames in upright orientation
+	uprightOnly := func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		if isUpright(img) {
+			return []objectdetection.Detection{testDetection(0, content)}, nil
+		}
+		return []objectdetection.Detection{}, nil
+	}
+
+	t.Run("rotated frame does not decode without rotation", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{Grayscale: true})
+		mockVision.DetectionsFunc = uprightOnly
+		setCameraFrame(t, svc, frame)
+
+		svc.scanAndCompare(context.Background())
+
+		svc.monitorMu.Lock()
+		defer svc.monitorMu.Unlock()
+		if len(svc.visibleCodes) != 0 {
+			t.Errorf("expected no codes from rotated frame, got: %d", len(svc.visibleCodes))
+		}
+	})
+
+	t.Run("rotated frame decodes after preprocessing", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{RotateDegrees: 90, Grayscale: true})
+		mockVision.DetectionsFunc = uprightOnly
+		setCameraFrame(t, svc, frame)
+
+		svc.scanAndCompare(context.Background())
+
+		svc.monitorMu.Lock()
+		defer svc.monitorMu.Unlock()
+		code, ok := svc.visibleCodes[content]
+		if !ok {
+			t.Fatal("expected code to decode after rotation")
+		}
+		if code.ItemID != "coffee-888" {
+			t.Errorf("expected item coffee-888, got: %s", code.ItemID)
+		}
+	})
+
+	t.Run("get_image returns preprocessed annotated frame", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{RotateDegrees: 90})
+		mockVision.DetectionsFunc = uprightOnly
+		setCameraFrame(t, svc, frame)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_image", "annotate": true})
+		if result["width"] != 40 || result["height"] != 20 {
+			t.Errorf("expected rotated 40x20 frame, got: %vx%v", result["width"], result["height"])
+		}
+		if result["preprocessed"] != true {
+			t.Error("expected preprocessed=true")
+		}
+		if result["detections"] != 1 {
+			t.Errorf("expected 1 annotated detection, got: %v", result["detections"])
+		}
+		if _, err := base64.StdEncoding.DecodeString(result["image"].(string)); err != nil {
+			t.Errorf("image is not valid base64: %v", err)
+		}
+	})
+}
+
+func TestGenerateQRBorder(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	generate := func(border float64) image.Image {
+		t.Helper()
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+			"border":    border,
+		})
+		img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(result["qr_code"].(string))))
+		if err != nil {
+			t.Fatalf("qr_code is not a PNG: %v", err)
+		}
+		if result["size"] != img.Bounds().Dx() {
+			t.Errorf("expected size %d to match image width, got: %v", img.Bounds().Dx(), result["size"])
+		}
+		return img
+	}
+
+	noBorder := generate(0)
+	wideBorder := generate(10)
+
+	if wideBorder.Bounds().Dx() <= noBorder.Bounds().Dx() {
+		t.Errorf("expected border 10 to be wider than border 0, got %d and %d", wideBorder.Bounds().Dx(), noBorder.Bounds().Dx())
+	}
+
+	// Without a border the finder pattern starts at the very corner
+	if gray := color.GrayModel.Convert(noBorder.At(0, 0)).(color.Gray); gray.Y != 0 {
+		t.Errorf("expected dark corner module with border 0, got luminance %d", gray.Y)
+	}
+	if gray := color.GrayModel.Convert(wideBorder.At(0, 0)).(color.Gray); gray.Y != 255 {
+		t.Errorf("expected white quiet zone with border 10, got luminance %d", gray.Y)
+	}
+
+	t.Run("negative border returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+			"border":    -1.0,
+		})
+		if err == nil {
+			t.Error("expected error for negative border")
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// knownIntegrations lists every integration integrations_status reports,
+// configured or not
+var knownIntegrations = []string{"email", "slack"}
+
+// deliveryRecord tracks the outcomes of one integration's alert deliveries
+type deliveryRecord struct {
+	sent          int       // Successful deliveries since startup
+	failed        int       // Failed deliveries since startup
+	dropped       int       // Alerts dropped from a full queue since startup
+	lastAttemptAt time.Time // When a delivery was last attempted
+	lastError     string    // Error from the latest attempt ("" if it succeeded)
+}
+
+// recordDelivery notes the outcome of handing an alert to a notifier
+func (s *inventoryKeeperKeeper) recordDelivery(name string, err error) {
+	s.deliveriesMu.Lock()
+	defer s.deliveriesMu.Unlock()
+
+	if s.deliveries == nil {
+		s.deliveries = make(map[string]*deliveryRecord)
+	}
+	record, ok := s.deliveries[name]
+	if !ok {
+		record = &deliveryRecord{}
+		s.deliveries[name] = record
+	}
+
+	record.lastAttemptAt = s.now()
+	record.lastError = ""
+	if err != nil {
+		record.failed++
+		record.lastError = err.Error()
+	} else {
+		record.sent++
+	}
+}
+
+// recordDrop notes an alert dropped from a notifier's full queue
+func (s *inventoryKeeperKeeper) recordDrop(name string) {
+	s.deliveriesMu.Lock()
+	defer s.deliveriesMu.Unlock()
+
+	if s.deliveries == nil {
+		s.deliveries = make(map[string]*deliveryRecord)
+	}
+	record, ok := s.deliveries[name]
+	if !ok {
+		record = &deliveryRecord{}
+		s.deliveries[name] = record
+	}
+	record.dropped++
+}
+
+// droppedAlerts returns the alerts dropped from full queues across notifiers
+func (s *inventoryKeeperKeeper) droppedAlerts() int {
+	s.deliveriesMu.Lock()
+	defer s.deliveriesMu.Unlock()
+	total := 0
+	for _, record := range s.deliveries {
+		total += record.dropped
+	}
+	return total
+}
+
+// redactEmail hides all but the first character of an address's local part
+func redactEmail(addr string) string {
+	local, domain, ok := strings.Cut(addr, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
+
+// emailTarget describes where alert email goes without exposing full addresses
+func emailTarget(cfg *Config) string {
+	to := parseEmailList(cfg.AlertEmailTo)
+	redacted := make([]string, 0, len(to))
+	for _, addr := range to {
+		redacted = append(redacted, redactEmail(addr))
+	}
+	return fmt.Sprintf("%s -> %s", net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)), strings.Join(redacted, ", "))
+}
+
+// handleIntegrationsStatus reports each alert integration's configured state,
+// redacted target, and the result of its latest delivery. Secrets are never
+// included.
+func (s *inventoryKeeperKeeper) handleIntegrationsStatus(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	cfg := s.config()
+	active := make(map[string]alertNotifier, len(s.notifiers))
+	names := append([]string(nil), knownIntegrations...)
+	for _, n := range s.notifiers {
+		if _, known := active[n.Name()]; !known && !slices.Contains(knownIntegrations, n.Name()) {
+			names = append(names, n.Name())
+		}
+		active[n.Name()] = n
+	}
+
+	s.deliveriesMu.Lock()
+	defer s.deliveriesMu.Unlock()
+
+	integrations := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		n, configured := active[name]
+		entry := map[string]interface{}{
+			"name":        name,
+			"configured":  configured,
+			"enabled":     configured,
+			"last_result": "none",
+			"sent":        0,
+			"failed":      0,
+			"dropped":     0,
+		}
+		switch n := n.(type) {
+		case *configuredNotifier:
+			entry["target"] = n.target
+			entry["type"] = n.alertNotifier.Name()
+		case *emailNotifier:
+			entry["target"] = emailTarget(cfg)
+		case *slackNotifier:
+			entry["target"] = slackTarget(cfg)
+		}
+
+		if record, ok := s.deliveries[name]; ok {
+			entry["sent"] = record.sent
+			entry["failed"] = record.failed
+			entry["dropped"] = record.dropped
+		}
+		if record, ok := s.deliveries[name]; ok && !record.lastAttemptAt.IsZero() {
+			entry["last_attempt_at"] = formatTimestampIn(record.lastAttemptAt, s.location)
+			if record.lastError != "" {
+				entry["last_result"] = "failure"
+				entry["last_error"] = record.lastError
+			} else {
+				entry["last_result"] = "success"
+			}
+		}
+		integrations = append(integrations, entry)
+	}
+
+	return map[string]interface{}{
+		"integrations": integrations,
+		"count":        len(integrations),
+	}, nil
+}
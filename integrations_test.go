@@ -0,0 +1,84 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntegrationsStatus(t *testing.T) {
+	// emailStatus polls integrations_status until the email entry satisfies done
+	emailStatus := func(t *testing.T, svc *inventoryKeeperKeeper, done func(map[string]interface{}) bool) map[string]interface{} {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			result := mustDoCommand(t, svc, map[string]interface{}{"command": "integrations_status"})
+			entry := result["integrations"].([]interface{})[0].(map[string]interface{})
+			if done(entry) || time.Now().After(deadline) {
+				return entry
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	t.Run("unconfigured email is reported as such", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		entry := emailStatus(t, svc, func(map[string]interface{}) bool { return true })
+		if entry["name"] != "email" || entry["configured"] != false || entry["last_result"] != "none" {
+			t.Errorf("expected unconfigured email, got: %v", entry)
+		}
+	})
+
+	t.Run("failed delivery is reported with timestamp and redacted target", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{
+			SMTPHost:     "mail.example.com",
+			SMTPPort:     587,
+			SMTPUsername: "keeper@example.com",
+			SMTPPassword: "hunter2",
+			AlertEmailTo: "ops@example.com",
+		})
+		svc.notifiers[0].(*emailNotifier).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			return errors.New("connection refused")
+		}
+
+		before := time.Now()
+		svc.raiseAlert("theft", severityCritical, "apple-001", "Item apple-001 removed without check-in", nil)
+
+		entry := emailStatus(t, svc, func(e map[string]interface{}) bool { return e["last_result"] != "none" })
+		if entry["configured"] != true || entry["last_result"] != "failure" || entry["failed"] != 1 || entry["sent"] != 0 {
+			t.Fatalf("expected one failed delivery, got: %v", entry)
+		}
+		if !strings.Contains(entry["last_error"].(string), "connection refused") {
+			t.Errorf("expected last_error from the failed send, got: %v", entry["last_error"])
+		}
+		at, err := time.Parse(time.RFC3339Nano, entry["last_attempt_at"].(string))
+		if err != nil || at.Before(before.Add(-time.Second)) {
+			t.Errorf("expected a recent last_attempt_at, got: %v (%v)", entry["last_attempt_at"], err)
+		}
+
+		target := entry["target"].(string)
+		if target != "mail.example.com:587 -> o***@example.com" {
+			t.Errorf("expected redacted target, got: %s", target)
+		}
+		for _, secret := range []string{"hunter2", "ops@example.com"} {
+			for key, value := range entry {
+				if s, ok := value.(string); ok && strings.Contains(s, secret) {
+					t.Errorf("expected %q redacted from %s, got: %s", secret, key, s)
+				}
+			}
+		}
+
+		// A later success replaces the failure as the latest result
+		svc.notifiers[0].(*emailNotifier).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			return nil
+		}
+		svc.raiseAlert("theft", severityCritical, "apple-001", "again", nil)
+		entry = emailStatus(t, svc, func(e map[string]interface{}) bool { return e["last_result"] == "success" })
+		if entry["last_result"] != "success" || entry["sent"] != 1 || entry["failed"] != 1 {
+			t.Errorf("expected success after failure, got: %v", entry)
+		}
+	})
+}
@@ -0,0 +1,873 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// InventoryItem represents an item tracked in the keeper's inventory
+type InventoryItem struct {
+	Namespace  string    `json:"namespace"`             // Shelf zone the item belongs to; item_id is unique within it
+	ItemID     string    `json:"item_id"`               // Unique item identifier (matches ItemQRData.ItemID)
+	ItemName   string    `json:"item_name"`             // Human-readable item name
+	Quantity   int       `json:"quantity"`              // Number of units on hand
+	Location   string    `json:"location"`              // Where the item is stored (e.g. "aisle-3")
+	Tags       []string  `json:"tags"`                  // Free-form labels for grouping and filtering
+	UnitWeight float64   `json:"unit_weight,omitempty"` // Weight of one unit, for scale-based estimates (0 if unknown)
+	Value      float64   `json:"value,omitempty"`       // Estimated value of one unit, for shrinkage_report (0 if unknown)
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`   // When a perishable item expires (zero if it doesn't)
+	CreatedAt  time.Time `json:"created_at"`            // When the item was added to inventory
+	UpdatedAt  time.Time `json:"updated_at"`            // When the item was last changed
+
+	// Purchasing details, for low-stock alerts and get_reorder_list
+	Supplier         string `json:"supplier,omitempty"`          // Who the item is ordered from
+	SupplierSKU      string `json:"supplier_sku,omitempty"`      // The supplier's code for the item
+	ReorderQuantity  int    `json:"reorder_quantity,omitempty"`  // Units to order at a time (0 if unset)
+	ReorderThreshold int    `json:"reorder_threshold,omitempty"` // Quantity at or below which stock is low (0 if unset)
+	MinQuantity      int    `json:"min_quantity,omitempty"`      // Units seen on the shelf below which stock is low (0 if unset)
+
+	// Quality and hold workflow, set with set_item_status
+	Status          string    `json:"status,omitempty"`           // One of allowed_statuses ("" means active)
+	Note            string    `json:"note,omitempty"`             // Why the item has its status
+	StatusChangedAt time.Time `json:"status_changed_at,omitzero"` // When the status was last set (zero if never)
+}
+
+// clone returns a deep copy so snapshots aren't affected by later mutations
+func (item *InventoryItem) clone() *InventoryItem {
+	if item == nil {
+		return nil
+	}
+	c := *item
+	if item.Tags != nil {
+		c.Tags = append([]string{}, item.Tags...)
+	}
+	return &c
+}
+
+// key returns the item's inventory key
+func (item *InventoryItem) key() itemKey {
+	return keyFor(item.Namespace, item.ItemID)
+}
+
+// toMap converts the item into a DoCommand-friendly response map
+func (item *InventoryItem) toMap(loc *time.Location) map[string]interface{} {
+	tags := item.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	m := map[string]interface{}{
+		"namespace":  item.Namespace,
+		"item_id":    item.ItemID,
+		"item_name":  item.ItemName,
+		"quantity":   item.Quantity,
+		"location":   item.Location,
+		"tags":       toInterfaceSlice(tags),
+		"created_at": formatTimestampIn(item.CreatedAt, loc),
+		"updated_at": formatTimestampIn(item.UpdatedAt, loc),
+		"status":     item.status(),
+	}
+	if item.Note != "" {
+		m["note"] = item.Note
+	}
+	if !item.StatusChangedAt.IsZero() {
+		m["status_changed_at"] = formatTimestampIn(item.StatusChangedAt, loc)
+	}
+	if item.UnitWeight > 0 {
+		m["unit_weight"] = item.UnitWeight
+	}
+	if item.Value > 0 {
+		m["value"] = item.Value
+	}
+	if !item.ExpiresAt.IsZero() {
+		m["expires_at"] = formatTimestampIn(item.ExpiresAt, loc)
+	}
+	if item.Supplier != "" {
+		m["supplier"] = item.Supplier
+	}
+	if item.SupplierSKU != "" {
+		m["supplier_sku"] = item.SupplierSKU
+	}
+	if item.ReorderQuantity > 0 {
+		m["reorder_quantity"] = item.ReorderQuantity
+	}
+	if item.ReorderThreshold > 0 {
+		m["reorder_threshold"] = item.ReorderThreshold
+	}
+	if item.MinQuantity > 0 {
+		m["min_quantity"] = item.MinQuantity
+	}
+	return m
+}
+
+// undoEntry captures the state of an item before a reversible mutation.
+// A nil Before means the item did not exist (the operation was an add).
+type undoEntry struct {
+	Operation string
+	Key       itemKey
+	Before    *InventoryItem
+}
+
+// pushUndoLocked records a reversible operation, discarding the oldest entry
+// once the configured depth is exceeded. Caller must hold inventoryMu for writing.
+func (s *inventoryKeeperKeeper) pushUndoLocked(operation string, key itemKey, before *InventoryItem) {
+	maxUndo := s.config().maxUndo()
+	if maxUndo == 0 {
+		return
+	}
+
+	s.undoStack = append(s.undoStack, undoEntry{
+		Operation: operation,
+		Key:       key,
+		Before:    before.clone(),
+	})
+	if len(s.undoStack) > maxUndo {
+		s.undoStack = s.undoStack[len(s.undoStack)-maxUndo:]
+	}
+}
+
+// parseNewItem validates add_item's fields and builds the item to insert.
+// Timestamps are set on insert.
+func (s *inventoryKeeperKeeper) parseNewItem(cmd map[string]interface{}) (*InventoryItem, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	itemName, ok := cmd["item_name"].(string)
+	if !ok || itemName == "" {
+		return nil, errors.New("item_name is required and must be a string")
+	}
+	if err := s.fieldRules.validateItemID(itemID); err != nil {
+		return nil, err
+	}
+	if err := s.fieldRules.validateItemName(itemName); err != nil {
+		return nil, err
+	}
+
+	// Quantity defaults to a single unit
+	quantity, hasQuantity, err := intArg(cmd, "quantity")
+	if err != nil {
+		return nil, err
+	}
+	if !hasQuantity {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return nil, fmt.Errorf("quantity must be non-negative, got: %d", quantity)
+	}
+
+	location := ""
+	if raw, ok := cmd["location"]; ok {
+		location, ok = raw.(string)
+		if !ok {
+			return nil, errors.New("location must be a string")
+		}
+	}
+
+	tags, _, err := stringSliceArg(cmd, "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	// Unit weight is only needed for items tracked by a shelf scale
+	unitWeight, _, err := floatArg(cmd, "unit_weight")
+	if err != nil {
+		return nil, err
+	}
+	if unitWeight < 0 {
+		return nil, fmt.Errorf("unit_weight must be non-negative, got: %v", unitWeight)
+	}
+
+	// Value per unit is only needed for loss estimates
+	value, _, err := floatArg(cmd, "value")
+	if err != nil {
+		return nil, err
+	}
+	if value < 0 {
+		return nil, fmt.Errorf("value must be non-negative, got: %v", value)
+	}
+
+	// Only perishable items have an expiry
+	expiresAt, _, err := timeArg(cmd, "expires_at")
+	if err != nil {
+		return nil, err
+	}
+
+	item := &InventoryItem{
+		Namespace:  namespace,
+		ItemID:     itemID,
+		ItemName:   itemName,
+		Quantity:   quantity,
+		Location:   location,
+		Tags:       tags,
+		UnitWeight: unitWeight,
+		Value:      value,
+		ExpiresAt:  expiresAt,
+	}
+
+	// Supplier and reorder details are optional
+	if _, err := applySupplierArgs(cmd, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// insertItemLocked adds a new item, first making room under
+// max_inventory_items, and records it for undo and history. Returns any items
+// evicted to make room. Caller must hold inventoryMu for writing and have
+// checked the item doesn't already exist.
+func (s *inventoryKeeperKeeper) insertItemLocked(item *InventoryItem) ([]*InventoryItem, error) {
+	evicted, err := s.makeRoomLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	s.inventory[item.key()] = item
+
+	s.pushUndoLocked("add_item", item.key(), nil)
+	s.recordHistoryLocked("item_added", item.Namespace, item.ItemID, map[string]interface{}{
+		"item_name": item.ItemName,
+		"quantity":  item.Quantity,
+	})
+
+	s.logger.Infof("Added item %s (%s) with quantity %d", item.ItemID, item.ItemName, item.Quantity)
+	return evicted, nil
+}
+
+// addedItemMap is the response for a newly added item, listing any evictions
+func addedItemMap(item *InventoryItem, evicted []*InventoryItem, loc *time.Location) map[string]interface{} {
+	result := item.toMap(loc)
+	if len(evicted) > 0 {
+		evictedIDs := make([]interface{}, 0, len(evicted))
+		for _, e := range evicted {
+			evictedIDs = append(evictedIDs, map[string]interface{}{
+				"namespace": e.Namespace,
+				"item_id":   e.ItemID,
+			})
+		}
+		result["evicted"] = evictedIDs
+	}
+	return result
+}
+
+// handleAddItem adds a new item to the inventory
+func (s *inventoryKeeperKeeper) handleAddItem(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	item, err := s.parseNewItem(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	if _, exists := s.inventory[item.key()]; exists {
+		return nil, fmt.Errorf("item %s already exists", item.ItemID)
+	}
+	evicted, err := s.insertItemLocked(item)
+	if err != nil {
+		return nil, err
+	}
+	return addedItemMap(item, evicted, s.location), nil
+}
+
+// handleRemoveItem removes an item from the inventory entirely
+func (s *inventoryKeeperKeeper) handleRemoveItem(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+
+	// With require_face_for_removal, someone authorized must be in view
+	confirmedBy, err := s.confirmRemovalByFace(ctx, "remove_item", "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	delete(s.inventory, key)
+
+	details := map[string]interface{}{
+		"item_name": item.ItemName,
+		"quantity":  item.Quantity,
+	}
+	result := map[string]interface{}{
+		"namespace": namespace,
+		"item_id":   itemID,
+		"removed":   true,
+		"message":   "Item removed from inventory",
+	}
+	if confirmedBy.Name != "" {
+		details["authorized_by"] = confirmedBy.Name
+		details["face_confidence"] = confirmedBy.Confidence
+		result["authorized_by"] = confirmedBy.Name
+	}
+
+	s.pushUndoLocked("remove_item", key, item)
+	s.recordHistoryLocked("item_removed", namespace, itemID, details)
+
+	s.logger.Infof("Removed item %s (%s)", itemID, item.ItemName)
+	return result, nil
+}
+
+// handleRenameItem changes an item's display name
+func (s *inventoryKeeperKeeper) handleRenameItem(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+
+	itemName, ok := cmd["item_name"].(string)
+	if !ok || itemName == "" {
+		return nil, errors.New("item_name is required and must be a string")
+	}
+	if err := s.fieldRules.validateItemName(itemName); err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	s.pushUndoLocked("rename_item", key, item)
+
+	previousName := item.ItemName
+	item.ItemName = itemName
+	item.UpdatedAt = s.now()
+
+	s.recordHistoryLocked("item_renamed", namespace, itemID, map[string]interface{}{
+		"previous_name": previousName,
+		"item_name":     itemName,
+	})
+
+	s.logger.Infof("Renamed item %s from %s to %s", itemID, previousName, itemName)
+	return item.toMap(s.location), nil
+}
+
+// handleGetItem returns one item by namespace and item_id
+func (s *inventoryKeeperKeeper) handleGetItem(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.RLock()
+	defer s.inventoryMu.RUnlock()
+
+	item, exists := s.inventory[keyFor(namespace, itemID)]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+	return item.toMap(s.location), nil
+}
+
+// applyItemUpdates sets the fields present in cmd on item, returning the
+// names of the fields set. Fields absent from cmd are left unchanged.
+func (s *inventoryKeeperKeeper) applyItemUpdates(cmd map[string]interface{}, item *InventoryItem) ([]string, error) {
+	var set []string
+
+	if raw, ok := cmd["item_name"]; ok {
+		itemName, ok := raw.(string)
+		if !ok || itemName == "" {
+			return nil, errors.New("item_name must be a non-empty string")
+		}
+		if err := s.fieldRules.validateItemName(itemName); err != nil {
+			return nil, err
+		}
+		item.ItemName = itemName
+		set = append(set, "item_name")
+	}
+
+	quantity, hasQuantity, err := intArg(cmd, "quantity")
+	if err != nil {
+		return nil, err
+	}
+	if hasQuantity {
+		if quantity < 0 {
+			return nil, fmt.Errorf("quantity must be non-negative, got: %d", quantity)
+		}
+		item.Quantity = quantity
+		set = append(set, "quantity")
+	}
+
+	if raw, ok := cmd["location"]; ok {
+		location, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("location must be a string")
+		}
+		item.Location = location
+		set = append(set, "location")
+	}
+
+	tags, hasTags, err := stringSliceArg(cmd, "tags")
+	if err != nil {
+		return nil, err
+	}
+	if hasTags {
+		item.Tags = tags
+		set = append(set, "tags")
+	}
+
+	for _, field := range []struct {
+		name   string
+		target *float64
+	}{
+		{"unit_weight", &item.UnitWeight},
+		{"value", &item.Value},
+	} {
+		value, ok, err := floatArg(cmd, field.name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if value < 0 {
+			return nil, fmt.Errorf("%s must be non-negative, got: %v", field.name, value)
+		}
+		*field.target = value
+		set = append(set, field.name)
+	}
+
+	expiresAt, hasExpiry, err := timeArg(cmd, "expires_at")
+	if err != nil {
+		return nil, err
+	}
+	if hasExpiry {
+		item.ExpiresAt = expiresAt
+		set = append(set, "expires_at")
+	}
+
+	supplierFields, err := applySupplierArgs(cmd, item)
+	if err != nil {
+		return nil, err
+	}
+	return append(set, supplierFields...), nil
+}
+
+// handleUpdateItem changes any of an item's editable fields in one step, so
+// a correction is a single history event and a single undo
+func (s *inventoryKeeperKeeper) handleUpdateItem(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+
+	// Deferred before the unlock so the alert is raised after it
+	var lowStock *InventoryItem
+	defer func() { s.alertLowStock(lowStock) }()
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	// Updates are applied to a copy so a bad field leaves the item untouched
+	updated := item.clone()
+	fields, err := s.applyItemUpdates(cmd, updated)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("update_item requires at least one field to change")
+	}
+
+	s.pushUndoLocked("update_item", key, item)
+
+	updated.UpdatedAt = s.now()
+	s.inventory[key] = updated
+
+	s.recordHistoryLocked("item_updated", namespace, itemID, map[string]interface{}{
+		"fields":            toInterfaceSlice(fields),
+		"previous_quantity": item.Quantity,
+		"quantity":          updated.Quantity,
+	})
+
+	lowStock = lowStockCopy(updated, item.Quantity)
+	s.openRestockRequestLocked(lowStock)
+
+	s.logger.Infof("Updated item %s: %v", itemID, fields)
+	result := updated.toMap(s.location)
+	result["updated_fields"] = toInterfaceSlice(fields)
+	return result, nil
+}
+
+// handleMoveItem relocates an item and records the transfer with both locations
+func (s *inventoryKeeperKeeper) handleMoveItem(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+
+	newLocation, ok := cmd["new_location"].(string)
+	if !ok || newLocation == "" {
+		return nil, errors.New("new_location is required and must be a string")
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	s.pushUndoLocked("move_item", key, item)
+
+	previousLocation := item.Location
+	item.Location = newLocation
+	item.UpdatedAt = s.now()
+
+	s.recordHistoryLocked("item_transferred", namespace, itemID, map[string]interface{}{
+		"from_location": previousLocation,
+		"to_location":   newLocation,
+	})
+
+	s.logger.Infof("Moved item %s from %q to %q", itemID, previousLocation, newLocation)
+	return item.toMap(s.location), nil
+}
+
+// handleAdjustQuantity changes an item's quantity by a relative amount
+func (s *inventoryKeeperKeeper) handleAdjustQuantity(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+
+	delta, hasDelta, err := intArg(cmd, "delta")
+	if err != nil {
+		return nil, err
+	}
+	if !hasDelta || delta == 0 {
+		return nil, errors.New("delta is required and must be a non-zero number")
+	}
+
+	// Deferred before the unlock so the alert is raised after it
+	var lowStock *InventoryItem
+	defer func() { s.alertLowStock(lowStock) }()
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	newQuantity := item.Quantity + delta
+	if newQuantity < 0 {
+		return nil, fmt.Errorf("cannot adjust %s by %d: only %d on hand", itemID, delta, item.Quantity)
+	}
+
+	s.pushUndoLocked("adjust_quantity", key, item)
+
+	previousQuantity := item.Quantity
+	item.Quantity = newQuantity
+	item.UpdatedAt = s.now()
+
+	s.recordHistoryLocked("quantity_adjusted", namespace, itemID, map[string]interface{}{
+		"previous_quantity": previousQuantity,
+		"quantity":          newQuantity,
+		"delta":             delta,
+	})
+
+	lowStock = lowStockCopy(item, previousQuantity)
+	s.openRestockRequestLocked(lowStock)
+	return item.toMap(s.location), nil
+}
+
+// handleSetQuantity overwrites an item's quantity with an absolute value
+// (e.g. after a physical recount). A quantity of 0 keeps the item in
+// inventory unless remove_if_zero is set.
+func (s *inventoryKeeperKeeper) handleSetQuantity(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+
+	quantity, hasQuantity, err := intArg(cmd, "quantity")
+	if err != nil {
+		return nil, err
+	}
+	if !hasQuantity {
+		return nil, errors.New("quantity is required")
+	}
+	if quantity < 0 {
+		return nil, fmt.Errorf("quantity must be non-negative, got: %d", quantity)
+	}
+
+	removeIfZero, _ := cmd["remove_if_zero"].(bool)
+
+	// Deferred before the unlock so the alert is raised after it
+	var lowStock *InventoryItem
+	defer func() { s.alertLowStock(lowStock) }()
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	previousQuantity := item.Quantity
+	delta := quantity - previousQuantity
+
+	if quantity == 0 && removeIfZero {
+		delete(s.inventory, key)
+		s.pushUndoLocked("set_quantity", key, item)
+		s.recordHistoryLocked("item_removed", namespace, itemID, map[string]interface{}{
+			"item_name":         item.ItemName,
+			"previous_quantity": previousQuantity,
+			"quantity":          0,
+			"delta":             delta,
+			"reason":            "set_quantity remove_if_zero",
+		})
+
+		s.logger.Infof("Set quantity of %s to 0 and removed it", itemID)
+		return map[string]interface{}{
+			"namespace":         namespace,
+			"item_id":           itemID,
+			"previous_quantity": previousQuantity,
+			"quantity":          0,
+			"delta":             delta,
+			"removed":           true,
+		}, nil
+	}
+
+	s.pushUndoLocked("set_quantity", key, item)
+
+	item.Quantity = quantity
+	item.UpdatedAt = s.now()
+
+	s.recordHistoryLocked("quantity_set", namespace, itemID, map[string]interface{}{
+		"previous_quantity": previousQuantity,
+		"quantity":          quantity,
+		"delta":             delta,
+	})
+
+	lowStock = lowStockCopy(item, previousQuantity)
+	s.openRestockRequestLocked(lowStock)
+	result := item.toMap(s.location)
+	result["previous_quantity"] = previousQuantity
+	result["delta"] = delta
+	result["removed"] = false
+	return result, nil
+}
+
+// handleListItems returns every item in the namespace sorted by item_id
+func (s *inventoryKeeperKeeper) handleListItems(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	pageSize, cursor, err := pageArgs(cmd, "list_items", namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.RLock()
+	items := make([]*InventoryItem, 0, len(s.inventory))
+	for key, item := range s.inventory {
+		if key.Namespace == namespace {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ItemID < items[j].ItemID })
+	total := len(items)
+
+	// Pages resume after the last item_id returned, so items added or removed
+	// meanwhile don't shift the rest
+	if cursor != nil {
+		items = items[sort.Search(len(items), func(i int) bool { return items[i].ItemID > cursor.AfterID }):]
+	}
+	nextCursor := ""
+	if pageSize > 0 && len(items) > pageSize {
+		items = items[:pageSize]
+		nextCursor = pageCursor{Command: "list_items", Namespace: namespace, AfterID: items[pageSize-1].ItemID}.encode()
+	}
+
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.toMap(s.location))
+	}
+	s.inventoryMu.RUnlock()
+
+	response := map[string]interface{}{
+		"namespace":   namespace,
+		"items":       result,
+		"total_count": total,
+	}
+	if pageSize > 0 || cursor != nil {
+		response["count"] = len(result)
+	}
+	if nextCursor != "" {
+		response["next_cursor"] = nextCursor
+	}
+	return response, nil
+}
+
+// handleCountItems returns the number of distinct items in the namespace and
+// their summed quantity, optionally restricted to a location and/or tag
+func (s *inventoryKeeperKeeper) handleCountItems(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	location := ""
+	if raw, ok := cmd["location"]; ok {
+		location, ok = raw.(string)
+		if !ok {
+			return nil, errors.New("location must be a string")
+		}
+	}
+
+	tag := ""
+	if raw, ok := cmd["tag"]; ok {
+		tag, ok = raw.(string)
+		if !ok {
+			return nil, errors.New("tag must be a string")
+		}
+	}
+
+	distinct := 0
+	totalQuantity := 0
+
+	// Single pass over the inventory regardless of filters
+	s.inventoryMu.RLock()
+	for key, item := range s.inventory {
+		if key.Namespace != namespace {
+			continue
+		}
+		if location != "" && item.Location != location {
+			continue
+		}
+		if tag != "" && !hasTag(item, tag) {
+			continue
+		}
+		distinct++
+		totalQuantity += item.Quantity
+	}
+	s.inventoryMu.RUnlock()
+
+	result := map[string]interface{}{
+		"namespace":      namespace,
+		"distinct_items": distinct,
+		"total_quantity": totalQuantity,
+	}
+	if location != "" {
+		result["location"] = location
+	}
+	if tag != "" {
+		result["tag"] = tag
+	}
+	return result, nil
+}
+
+// hasTag reports whether the item carries the given tag
+func hasTag(item *InventoryItem, tag string) bool {
+	for _, t := range item.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// handleUndo reverts the most recent reversible inventory mutation
+func (s *inventoryKeeperKeeper) handleUndo(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	if len(s.undoStack) == 0 {
+		return map[string]interface{}{
+			"undone":  false,
+			"message": "nothing to undo",
+		}, nil
+	}
+
+	entry := s.undoStack[len(s.undoStack)-1]
+	s.undoStack = s.undoStack[:len(s.undoStack)-1]
+
+	result := map[string]interface{}{
+		"undone":    true,
+		"operation": entry.Operation,
+		"namespace": entry.Key.Namespace,
+		"item_id":   entry.Key.ItemID,
+	}
+
+	if entry.Before == nil {
+		// Undoing an add removes the item again
+		delete(s.inventory, entry.Key)
+	} else {
+		// Undoing anything else restores the exact prior state
+		restored := entry.Before.clone()
+		s.inventory[entry.Key] = restored
+		result["item"] = restored.toMap(s.location)
+	}
+
+	s.recordHistoryLocked("undo", entry.Key.Namespace, entry.Key.ItemID, map[string]interface{}{
+		"operation": entry.Operation,
+	})
+
+	s.logger.Infof("Undid %s for item %s", entry.Operation, entry.Key.ItemID)
+	return result, nil
+}
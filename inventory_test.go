@@ -0,0 +1,505 @@
+package inventorykeeper
+
+import (
+	"context"
+	"image"
+	"reflect"
+	"testing"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/input"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/generic"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// newTestKeeper creates a keeper with background monitoring disabled and
+// mock dependencies. Camera and vision names are filled in if cfg omits them;
+// a mock scale or face service is added when cfg names one.
+func newTestKeeper(t *testing.T, cfg *Config) (*inventoryKeeperKeeper, *inject.VisionService) {
+	t.Helper()
+
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	if cfg.CameraName == "" {
+		cfg.CameraName = "test-camera"
+	}
+	if cfg.QRVisionService == "" {
+		cfg.QRVisionService = "test-qr-vision"
+	}
+	if cfg.ScanIntervalMs == nil {
+		disabledInterval := 0
+		cfg.ScanIntervalMs = &disabledInterval
+	}
+
+	mockCam := &inject.Camera{}
+	mockVision := inject.NewVisionService(cfg.QRVisionService)
+	mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{}, nil
+	}
+	mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{}, nil
+	}
+
+	deps := resource.Dependencies{
+		camera.Named(cfg.CameraName):      mockCam,
+		vision.Named(cfg.QRVisionService): mockVision,
+	}
+	if cfg.ScaleSensor != "" {
+		deps[sensor.Named(cfg.ScaleSensor)] = inject.NewSensor(cfg.ScaleSensor)
+	}
+	if cfg.ButtonController != "" {
+		deps[input.Named(cfg.ButtonController)] = newFakeButtons(cfg.ButtonController)
+	}
+	if cfg.FaceVisionService != "" {
+		// inject.VisionService only dispatches to DetectionsFromCameraFunc
+		// when DetectionsFunc is also set
+		faceVision := inject.NewVisionService(cfg.FaceVisionService)
+		faceVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{}, nil
+		}
+		deps[vision.Named(cfg.FaceVisionService)] = faceVision
+	}
+
+	keeper, err := NewKeeper(context.Background(), deps, resource.NewName(generic.API, "test"), cfg, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatalf("failed to create keeper: %v", err)
+	}
+	t.Cleanup(func() { keeper.Close(context.Background()) })
+
+	return keeper.(*inventoryKeeperKeeper), mockVision
+}
+
+// mustDoCommand runs a command and fails the test on error
+func mustDoCommand(t *testing.T, svc *inventoryKeeperKeeper, cmd map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	result, err := svc.DoCommand(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("command %v failed: %v", cmd["command"], err)
+	}
+	return result
+}
+
+func TestInventoryCommands(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	t.Run("add_item with defaults", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "add_item",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+		})
+		if result["quantity"] != 1 {
+			t.Errorf("expected default quantity 1, got: %v", result["quantity"])
+		}
+	})
+
+	t.Run("add_item duplicate returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command":   "add_item",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+		})
+		if err == nil {
+			t.Error("expected error for duplicate item_id")
+		}
+	})
+
+	t.Run("add_item negative quantity returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command":   "add_item",
+			"item_id":   "banana-042",
+			"item_name": "Organic Banana",
+			"quantity":  -1.0,
+		})
+		if err == nil {
+			t.Error("expected error for negative quantity")
+		}
+	})
+
+	t.Run("rename_item and adjust_quantity update the item", func(t *testing.T) {
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "rename_item",
+			"item_id":   "apple-001",
+			"item_name": "Gala Apple",
+		})
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "adjust_quantity",
+			"item_id": "apple-001",
+			"delta":   4.0,
+		})
+		if result["item_name"] != "Gala Apple" {
+			t.Errorf("expected renamed item, got: %v", result["item_name"])
+		}
+		if result["quantity"] != 5 {
+			t.Errorf("expected quantity 5, got: %v", result["quantity"])
+		}
+	})
+
+	t.Run("update_item changes several fields as one undo", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":  "update_item",
+			"item_id":  "apple-001",
+			"quantity": 7.0,
+			"location": "aisle-4",
+			"tags":     []interface{}{"fruit"},
+		})
+		if result["quantity"] != 7 || result["location"] != "aisle-4" {
+			t.Errorf("expected quantity 7 in aisle-4, got: %v", result)
+		}
+		if !reflect.DeepEqual(result["updated_fields"], []interface{}{"quantity", "location", "tags"}) {
+			t.Errorf("unexpected updated_fields: %v", result["updated_fields"])
+		}
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+		item := mustDoCommand(t, svc, map[string]interface{}{"command": "get_item", "item_id": "apple-001"})
+		if item["quantity"] != 5 || item["location"] != "" || item["item_name"] != "Gala Apple" {
+			t.Errorf("expected undo to restore the whole item, got: %v", item)
+		}
+	})
+
+	t.Run("update_item rejects bad fields without changing the item", func(t *testing.T) {
+		for _, cmd := range []map[string]interface{}{
+			{"command": "update_item", "item_id": "apple-001"},
+			{"command": "update_item", "item_id": "apple-001", "location": "aisle-9", "quantity": -1.0},
+			{"command": "update_item", "item_id": "missing", "quantity": 1.0},
+		} {
+			if _, err := svc.DoCommand(context.Background(), cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+		item := mustDoCommand(t, svc, map[string]interface{}{"command": "get_item", "item_id": "apple-001"})
+		if item["location"] != "" {
+			t.Errorf("expected location unchanged, got: %v", item["location"])
+		}
+	})
+
+	t.Run("get_item unknown item returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "get_item", "item_id": "missing"})
+		if err == nil {
+			t.Error("expected error for unknown item")
+		}
+	})
+
+	t.Run("adjust_quantity below zero returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "adjust_quantity",
+			"item_id": "apple-001",
+			"delta":   -10.0,
+		})
+		if err == nil {
+			t.Error("expected error when quantity would go negative")
+		}
+	})
+
+	t.Run("list_items returns all items", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})
+		if result["total_count"] != 1 {
+			t.Errorf("expected 1 item, got: %v", result["total_count"])
+		}
+	})
+
+	t.Run("delete_item removes the item", func(t *testing.T) {
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "pear-001", "item_name": "Pear"})
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "delete_item", "item_id": "pear-001"})
+		if result["removed"] != true {
+			t.Errorf("expected removed=true, got: %v", result["removed"])
+		}
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "get_item", "item_id": "pear-001"}); err == nil {
+			t.Error("expected deleted item to be gone")
+		}
+	})
+
+	t.Run("remove_item unknown item returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "remove_item",
+			"item_id": "missing",
+		})
+		if err == nil {
+			t.Error("expected error for unknown item")
+		}
+	})
+}
+
+func TestUndo(t *testing.T) {
+	t.Run("undo with empty stack is a no-op", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+		if result["undone"] != false {
+			t.Errorf("expected undone=false, got: %v", result["undone"])
+		}
+		if result["message"] != "nothing to undo" {
+			t.Errorf("expected no-op message, got: %v", result["message"])
+		}
+	})
+
+	t.Run("undo remove restores exact prior state", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "add_item",
+			"item_id":   "milk-128",
+			"item_name": "Whole Milk 1gal",
+			"quantity":  3.0,
+			"location":  "aisle-3",
+			"tags":      []interface{}{"dairy", "cold"},
+		})
+
+		svc.inventoryMu.RLock()
+		before := svc.inventory[keyFor(defaultNamespace, "milk-128")].clone()
+		svc.inventoryMu.RUnlock()
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "remove_item", "item_id": "milk-128"})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+		if result["undone"] != true {
+			t.Fatalf("expected undone=true, got: %v", result["undone"])
+		}
+		if result["operation"] != "remove_item" {
+			t.Errorf("expected operation remove_item, got: %v", result["operation"])
+		}
+
+		svc.inventoryMu.RLock()
+		after, ok := svc.inventory[keyFor(defaultNamespace, "milk-128")]
+		svc.inventoryMu.RUnlock()
+		if !ok {
+			t.Fatal("expected item to be restored")
+		}
+		if !reflect.DeepEqual(before, after) {
+			t.Errorf("restored item differs from original:\nbefore: %+v\nafter:  %+v", before, after)
+		}
+
+		// The undo itself is recorded in history
+		history := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history"})
+		events := history["events"].([]interface{})
+		last := events[len(events)-1].(map[string]interface{})
+		if last["type"] != "undo" {
+			t.Errorf("expected last history event to be undo, got: %v", last["type"])
+		}
+	})
+
+	t.Run("undo add removes the item", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "add_item",
+			"item_id":   "eggs-217",
+			"item_name": "Free Range Eggs",
+		})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+
+		svc.inventoryMu.RLock()
+		_, exists := svc.inventory[keyFor(defaultNamespace, "eggs-217")]
+		svc.inventoryMu.RUnlock()
+		if exists {
+			t.Error("expected added item to be gone after undo")
+		}
+	})
+
+	t.Run("undo depth is bounded by max_undo", func(t *testing.T) {
+		maxUndo := 1
+		svc, _ := newTestKeeper(t, &Config{MaxUndo: &maxUndo})
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "a", "item_name": "A"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "b", "item_name": "B"})
+
+		first := mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+		if first["item_id"] != "b" {
+			t.Errorf("expected to undo item b, got: %v", first["item_id"])
+		}
+		second := mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+		if second["undone"] != false {
+			t.Error("expected undo stack to be exhausted after max_undo entries")
+		}
+	})
+}
+
+func TestCountItems(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	fixture := []map[string]interface{}{
+		{"item_id": "apple-001", "item_name": "Honeycrisp Apple", "quantity": 4.0, "location": "aisle-3", "tags": []interface{}{"fruit"}},
+		{"item_id": "banana-042", "item_name": "Organic Banana", "quantity": 6.0, "location": "aisle-3", "tags": []interface{}{"fruit", "organic"}},
+		{"item_id": "milk-128", "item_name": "Whole Milk 1gal", "quantity": 2.0, "location": "cooler-1", "tags": []interface{}{"dairy"}},
+		{"item_id": "bread-305", "item_name": "Sourdough Loaf", "quantity": 0.0, "location": "aisle-1"},
+	}
+	for _, item := range fixture {
+		item["command"] = "add_item"
+		mustDoCommand(t, svc, item)
+	}
+
+	t.Run("no filters returns global totals", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "count_items"})
+		if result["distinct_items"] != 4 {
+			t.Errorf("expected 4 distinct items, got: %v", result["distinct_items"])
+		}
+		if result["total_quantity"] != 12 {
+			t.Errorf("expected total quantity 12, got: %v", result["total_quantity"])
+		}
+	})
+
+	t.Run("location filter", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "count_items", "location": "aisle-3"})
+		if result["distinct_items"] != 2 {
+			t.Errorf("expected 2 distinct items in aisle-3, got: %v", result["distinct_items"])
+		}
+		if result["total_quantity"] != 10 {
+			t.Errorf("expected total quantity 10 in aisle-3, got: %v", result["total_quantity"])
+		}
+	})
+
+	t.Run("location and tag filters combine", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "count_items", "location": "aisle-3", "tag": "organic"})
+		if result["distinct_items"] != 1 || result["total_quantity"] != 6 {
+			t.Errorf("expected 1 item totalling 6, got: %v items totalling %v", result["distinct_items"], result["total_quantity"])
+		}
+	})
+
+	t.Run("unknown location counts nothing", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "count_items", "location": "aisle-9"})
+		if result["distinct_items"] != 0 || result["total_quantity"] != 0 {
+			t.Errorf("expected zero counts, got: %v", result)
+		}
+	})
+}
+
+func TestSetQuantity(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	mustDoCommand(t, svc, map[string]interface{}{
+		"command":   "add_item",
+		"item_id":   "yogurt-555",
+		"item_name": "Greek Yogurt",
+		"quantity":  5.0,
+		"location":  "cooler-1",
+	})
+
+	t.Run("set quantity up", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "set_quantity", "item_id": "yogurt-555", "quantity": 9.0})
+		if result["quantity"] != 9 || result["delta"] != 4 {
+			t.Errorf("expected quantity 9 with delta 4, got: %v and %v", result["quantity"], result["delta"])
+		}
+	})
+
+	t.Run("set quantity down records delta in history", func(t *testing.T) {
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_quantity", "item_id": "yogurt-555", "quantity": 2.0})
+
+		history := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history", "limit": 1.0})
+		event := history["events"].([]interface{})[0].(map[string]interface{})
+		if event["type"] != "quantity_set" {
+			t.Fatalf("expected quantity_set event, got: %v", event["type"])
+		}
+		details := event["details"].(map[string]interface{})
+		if details["delta"] != -7 || details["previous_quantity"] != 9 {
+			t.Errorf("expected delta -7 from 9, got: %v", details)
+		}
+	})
+
+	t.Run("zero keeps item and metadata", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "set_quantity", "item_id": "yogurt-555", "quantity": 0.0})
+		if result["removed"] != false {
+			t.Error("expected item to remain in inventory")
+		}
+
+		svc.inventoryMu.RLock()
+		item, ok := svc.inventory[keyFor(defaultNamespace, "yogurt-555")]
+		svc.inventoryMu.RUnlock()
+		if !ok || item.Location != "cooler-1" || item.Quantity != 0 {
+			t.Errorf("expected item kept at quantity 0 with location, got: %+v", item)
+		}
+	})
+
+	t.Run("zero with remove_if_zero removes item", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":        "set_quantity",
+			"item_id":        "yogurt-555",
+			"quantity":       0.0,
+			"remove_if_zero": true,
+		})
+		if result["removed"] != true {
+			t.Error("expected removed=true")
+		}
+
+		svc.inventoryMu.RLock()
+		_, ok := svc.inventory[keyFor(defaultNamespace, "yogurt-555")]
+		svc.inventoryMu.RUnlock()
+		if ok {
+			t.Error("expected item to be removed")
+		}
+	})
+
+	t.Run("negative quantity returns error", func(t *testing.T) {
+		mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "set_quantity", "item_id": "yogurt-555", "quantity": -1.0})
+		if err == nil {
+			t.Error("expected error for negative quantity")
+		}
+	})
+
+	t.Run("unknown item returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "set_quantity", "item_id": "missing", "quantity": 1.0})
+		if err == nil {
+			t.Error("expected error for unknown item")
+		}
+	})
+}
+
+func TestMoveItem(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	mustDoCommand(t, svc, map[string]interface{}{
+		"command":   "add_item",
+		"item_id":   "flour-210",
+		"item_name": "Bread Flour",
+		"location":  "aisle-2",
+	})
+
+	t.Run("move updates the record and logs the transfer", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":      "move_item",
+			"item_id":      "flour-210",
+			"new_location": "backroom",
+		})
+		if result["location"] != "backroom" {
+			t.Errorf("expected location backroom, got: %v", result["location"])
+		}
+
+		history := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history", "limit": 1.0})
+		event := history["events"].([]interface{})[0].(map[string]interface{})
+		if event["type"] != "item_transferred" {
+			t.Fatalf("expected item_transferred event, got: %v", event["type"])
+		}
+		details := event["details"].(map[string]interface{})
+		if details["from_location"] != "aisle-2" || details["to_location"] != "backroom" {
+			t.Errorf("expected transfer aisle-2 -> backroom, got: %v", details)
+		}
+	})
+
+	t.Run("unknown item returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command":      "move_item",
+			"item_id":      "missing-999",
+			"new_location": "backroom",
+		})
+		if err == nil {
+			t.Error("expected error for unknown item")
+		}
+	})
+
+	t.Run("empty new_location returns error", func(t *testing.T) {
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command":      "move_item",
+			"item_id":      "flour-210",
+			"new_location": "",
+		})
+		if err == nil {
+			t.Error("expected error for empty new_location")
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// statusActive is the status of every item that hasn't been given one
+const statusActive = "active"
+
+// defaultAllowedStatuses are the statuses set_item_status accepts when
+// allowed_statuses isn't configured
+var defaultAllowedStatuses = []string{statusActive, "damaged", "quarantined", "on_hold"}
+
+// maxStatusNoteLength bounds the note attached with a status, in characters
+const maxStatusNoteLength = 500
+
+// allowedStatuses returns the statuses items may be given. active is always
+// allowed, since it is the default.
+func (cfg *Config) allowedStatuses() map[string]bool {
+	statuses := cfg.AllowedStatuses
+	if len(statuses) == 0 {
+		statuses = defaultAllowedStatuses
+	}
+	allowed := map[string]bool{statusActive: true}
+	for _, status := range statuses {
+		allowed[status] = true
+	}
+	return allowed
+}
+
+// validateAllowedStatuses checks allowed_statuses has no blank or repeated entries
+func validateAllowedStatuses(statuses []string) error {
+	seen := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		if strings.TrimSpace(status) == "" {
+			return errors.New("allowed_statuses must not contain empty statuses")
+		}
+		if seen[status] {
+			return fmt.Errorf("allowed_statuses lists %q more than once", status)
+		}
+		seen[status] = true
+	}
+	return nil
+}
+
+// status returns the item's status, active unless one was set
+func (item *InventoryItem) status() string {
+	if item.Status == "" {
+		return statusActive
+	}
+	return item.Status
+}
+
+// statusArg extracts the required "status" argument and checks it against
+// the configured allowed statuses
+func statusArg(cmd map[string]interface{}, cfg *Config) (string, error) {
+	status, ok := cmd["status"].(string)
+	if !ok || status == "" {
+		return "", errors.New("status is required and must be a string")
+	}
+	allowed := cfg.allowedStatuses()
+	if !allowed[status] {
+		names := make([]string, 0, len(allowed))
+		for name := range allowed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("status must be one of %s, got: %q", strings.Join(names, ", "), status)
+	}
+	return status, nil
+}
+
+// handleSetItemStatus sets an item's status and note, replacing any previous
+// note (omitting note clears it). The change is timestamped, recorded in
+// history, and can be undone.
+func (s *inventoryKeeperKeeper) handleSetItemStatus(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	status, err := statusArg(cmd, s.config())
+	if err != nil {
+		return nil, err
+	}
+	note, err := optionalStringArg(cmd, "note")
+	if err != nil {
+		return nil, err
+	}
+	if length := utf8.RuneCountInString(note); length > maxStatusNoteLength {
+		return nil, fmt.Errorf("note must be at most %d characters, got: %d", maxStatusNoteLength, length)
+	}
+	key := keyFor(namespace, itemID)
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	s.pushUndoLocked("set_item_status", key, item)
+	updated := item.clone()
+	updated.Status = status
+	if status == statusActive {
+		updated.Status = ""
+	}
+	updated.Note = note
+	updated.StatusChangedAt = s.now()
+	updated.UpdatedAt = updated.StatusChangedAt
+	s.inventory[key] = updated
+
+	details := map[string]interface{}{
+		"status":          status,
+		"previous_status": item.status(),
+	}
+	if note != "" {
+		details["note"] = note
+	}
+	s.recordHistoryLocked("status_changed", namespace, itemID, details)
+
+	s.logger.Infof("Set status of %s from %s to %s", itemID, item.status(), status)
+	return updated.toMap(s.location), nil
+}
+
+// handleQueryByStatus returns the namespace's items in the given status,
+// sorted by item_id
+func (s *inventoryKeeperKeeper) handleQueryByStatus(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	status, err := statusArg(cmd, s.config())
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.RLock()
+	var items []*InventoryItem
+	for key, item := range s.inventory {
+		if key.Namespace == namespace && item.status() == status {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ItemID < items[j].ItemID })
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.toMap(s.location))
+	}
+	s.inventoryMu.RUnlock()
+
+	return map[string]interface{}{
+		"namespace": namespace,
+		"status":    status,
+		"items":     result,
+		"count":     len(result),
+	}, nil
+}
@@ -0,0 +1,97 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestItemStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("status is set with a note, timestamped, and recorded in history", func(t *testing.T) {
+		start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		svc, _, clock := newTestKeeperWithClock(t, nil, start)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "beans-12", "item_name": "Black Beans"})
+
+		items := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})["items"].([]interface{})
+		if status := items[0].(map[string]interface{})["status"]; status != "active" {
+			t.Errorf("expected new items to be active, got: %v", status)
+		}
+
+		clock.Advance(time.Hour)
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "set_item_status", "item_id": "beans-12", "status": "quarantined", "note": "Dented cans, awaiting QA",
+		})
+		if result["status"] != "quarantined" || result["note"] != "Dented cans, awaiting QA" {
+			t.Errorf("expected quarantined with note, got: %v", result)
+		}
+		if result["status_changed_at"] != formatTimestamp(start.Add(time.Hour)) {
+			t.Errorf("expected status timestamped at the change, got: %v", result["status_changed_at"])
+		}
+
+		last := svc.history[len(svc.history)-1]
+		if last.Type != "status_changed" || last.Details["status"] != "quarantined" || last.Details["previous_status"] != "active" {
+			t.Errorf("expected status_changed history from active, got: %+v", last)
+		}
+
+		// Back to active clears the note
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "set_item_status", "item_id": "beans-12", "status": "active"})
+		if result["status"] != "active" || result["note"] != nil {
+			t.Errorf("expected active without a note, got: %v", result)
+		}
+	})
+
+	t.Run("query_by_status returns the namespace's items in that status", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		for _, itemID := range []string{"beans-12", "rice-3", "oats-7"} {
+			mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": itemID, "item_name": itemID})
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "beans-12", "item_name": "Beans", "namespace": "backroom"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_item_status", "item_id": "rice-3", "status": "damaged"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_item_status", "item_id": "beans-12", "status": "damaged"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_item_status", "item_id": "beans-12", "status": "damaged", "namespace": "backroom"})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "query_by_status", "status": "damaged"})
+		items := result["items"].([]interface{})
+		if result["count"] != 2 || items[0].(map[string]interface{})["item_id"] != "beans-12" || items[1].(map[string]interface{})["item_id"] != "rice-3" {
+			t.Errorf("expected beans-12 and rice-3 damaged, got: %v", items)
+		}
+		if active := mustDoCommand(t, svc, map[string]interface{}{"command": "query_by_status", "status": "active"}); active["count"] != 1 {
+			t.Errorf("expected only oats-7 active, got: %v", active["items"])
+		}
+	})
+
+	t.Run("statuses outside the allowed set are rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{AllowedStatuses: []string{"recalled"}})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "beans-12", "item_name": "Black Beans"})
+
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "set_item_status", "item_id": "beans-12", "status": "damaged"}); err == nil {
+			t.Error("expected damaged to be rejected when only recalled is allowed")
+		}
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "query_by_status", "status": "lost"}); err == nil {
+			t.Error("expected query_by_status to reject an unknown status")
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_item_status", "item_id": "beans-12", "status": "recalled"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_item_status", "item_id": "beans-12", "status": "active"})
+	})
+
+	t.Run("undo restores the previous status", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "beans-12", "item_name": "Black Beans"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_item_status", "item_id": "beans-12", "status": "on_hold", "note": "Pending count"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+		if item := svc.inventory[keyFor("", "beans-12")]; item.status() != "active" || item.Note != "" {
+			t.Errorf("expected undo to restore active without a note, got: %+v", item)
+		}
+	})
+
+	t.Run("blank or repeated allowed_statuses rejected", func(t *testing.T) {
+		for _, statuses := range [][]string{{"damaged", " "}, {"damaged", "damaged"}} {
+			cfg := &Config{CameraName: "cam", QRVisionService: "qr", AllowedStatuses: statuses}
+			if _, _, err := cfg.Validate("test"); err == nil {
+				t.Errorf("expected allowed_statuses %q to be rejected", statuses)
+			}
+		}
+	})
+}
@@ -0,0 +1,393 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Label layouts: the code on the left with text to its right, or the code on
+// top with text beneath
+const (
+	labelLayoutBeside = "beside"
+	labelLayoutBelow  = "below"
+)
+
+// Label sizing. Dimensions are in pixels; font sizes are pixel heights.
+const (
+	defaultLabelWidth  = 600
+	defaultLabelHeight = 300
+	maxLabelSide       = 4096
+	minLabelTextSpan   = 96 // Narrowest text area beside, or shortest text area below, the code
+	minLabelModulePx   = 2  // Smallest QR module that still scans reliably from print
+	labelPadding       = 12 // Margin around the text area
+	maxLabelNameLines  = 2  // Longer names are truncated with an ellipsis
+	minLabelFontSize   = 10
+	maxLabelFontSize   = 160
+)
+
+// labelEllipsis marks text cut short to fit the label
+const labelEllipsis = "…"
+
+// labelFonts parses the bundled Go fonts once: bold for the item name,
+// regular for the id and location lines
+var labelFonts = sync.OnceValues(func() ([2]*opentype.Font, error) {
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return [2]*opentype.Font{}, err
+	}
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return [2]*opentype.Font{}, err
+	}
+	return [2]*opentype.Font{bold, regular}, nil
+})
+
+// labelFace returns a face for f at a pixel height of size
+func labelFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// truncateText shortens s with an ellipsis until it is at most width pixels
+// wide in face. Returns s unchanged when it already fits.
+func truncateText(face font.Face, s string, width int) (string, bool) {
+	if font.MeasureString(face, s).Ceil() <= width {
+		return s, false
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimRight(string(runes), " ") + labelEllipsis
+		if font.MeasureString(face, candidate).Ceil() <= width {
+			return candidate, true
+		}
+	}
+	return labelEllipsis, true
+}
+
+// wrapText breaks s into lines at most width pixels wide in face, using at
+// most maxLines lines. Words too wide for a line, and text past the last
+// line, are truncated with an ellipsis; truncated reports whether that
+// happened.
+func wrapText(face font.Face, s string, width, maxLines int) (lines []string, truncated bool) {
+	fits := func(line string) bool { return font.MeasureString(face, line).Ceil() <= width }
+
+	words := strings.Fields(s)
+	var current string
+	for i, word := range words {
+		if current == "" || fits(current+" "+word) {
+			current = strings.TrimPrefix(current+" "+word, " ")
+			continue
+		}
+		lines = append(lines, current)
+		current = word
+		if len(lines) == maxLines {
+			// Out of lines: the rest goes on the last one, to be cut below
+			lines[maxLines-1] += " " + strings.Join(words[i:], " ")
+			current = ""
+			truncated = true
+			break
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	for i, line := range lines {
+		var cut bool
+		lines[i], cut = truncateText(face, line, width)
+		truncated = truncated || cut
+	}
+	return lines, truncated
+}
+
+// labelTextBlock is the text laid out for a label: the wrapped item name in
+// bold, then smaller detail lines (id, location)
+type labelTextBlock struct {
+	nameFace   font.Face
+	nameLines  []string
+	detailFace font.Face
+	details    []string
+	truncated  bool
+}
+
+// height returns the block's height in pixels
+func (b labelTextBlock) height() int {
+	h := len(b.nameLines) * b.nameFace.Metrics().Height.Ceil()
+	if len(b.details) > 0 {
+		h += len(b.details) * b.detailFace.Metrics().Height.Ceil()
+	}
+	return h
+}
+
+// layoutLabelText picks the largest font size at which the name wraps into
+// maxLabelNameLines without truncation and everything fits the area,
+// falling back to the smallest size with truncation
+func layoutLabelText(name string, details []string, area image.Point) (labelTextBlock, error) {
+	fonts, err := labelFonts()
+	if err != nil {
+		return labelTextBlock{}, fmt.Errorf("failed to load label font: %w", err)
+	}
+
+	build := func(size float64) (labelTextBlock, error) {
+		nameFace, err := labelFace(fonts[0], size)
+		if err != nil {
+			return labelTextBlock{}, err
+		}
+		detailFace, err := labelFace(fonts[1], max(minLabelFontSize, size*0.55))
+		if err != nil {
+			return labelTextBlock{}, err
+		}
+		block := labelTextBlock{nameFace: nameFace, detailFace: detailFace}
+		block.nameLines, block.truncated = wrapText(nameFace, name, area.X, maxLabelNameLines)
+		for _, detail := range details {
+			line, cut := truncateText(detailFace, detail, area.X)
+			block.details = append(block.details, line)
+			block.truncated = block.truncated || cut
+		}
+		return block, nil
+	}
+
+	for size := min(float64(area.Y)/2, maxLabelFontSize); size > minLabelFontSize; size *= 0.9 {
+		block, err := build(size)
+		if err != nil {
+			return labelTextBlock{}, err
+		}
+		if !block.truncated && block.height() <= area.Y {
+			return block, nil
+		}
+	}
+	block, err := build(minLabelFontSize)
+	if err != nil {
+		return labelTextBlock{}, err
+	}
+	// Drop detail lines that don't fit even at the smallest size
+	for len(block.details) > 0 && block.height() > area.Y {
+		block.details = block.details[:len(block.details)-1]
+		block.truncated = true
+	}
+	return block, nil
+}
+
+// drawLabelText draws the block into dst, vertically centered in area
+func drawLabelText(dst draw.Image, block labelTextBlock, area image.Rectangle) {
+	y := area.Min.Y + (area.Dy()-block.height())/2
+	drawLines := func(face font.Face, lines []string) {
+		metrics := face.Metrics()
+		for _, line := range lines {
+			d := font.Drawer{
+				Dst:  dst,
+				Src:  image.Black,
+				Face: face,
+				Dot:  fixed.P(area.Min.X, y+metrics.Ascent.Ceil()),
+			}
+			d.DrawString(line)
+			y += metrics.Height.Ceil()
+		}
+	}
+	drawLines(block.nameFace, block.nameLines)
+	drawLines(block.detailFace, block.details)
+}
+
+// drawLabelCode draws payload as a QR code filling a side x side square at
+// origin, with the standard quiet zone and whole-pixel modules
+func drawLabelCode(dst draw.Image, payload string, origin image.Point, side int) error {
+	q, err := qrcode.New(payload, qrcode.Medium)
+	if err != nil {
+		return fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	q.DisableBorder = true
+	bitmap := q.Bitmap()
+
+	modules := len(bitmap) + 2*defaultQRBorder
+	moduleSize := side / modules
+	if moduleSize < minLabelModulePx {
+		return fmt.Errorf("code needs at least %dpx for its %d modules, label leaves %dpx", modules*minLabelModulePx, modules, side)
+	}
+	// Center the code in its square
+	offset := origin.Add(image.Pt(1, 1).Mul((side - modules*moduleSize) / 2))
+	for y, row := range bitmap {
+		for x, set := range row {
+			if !set {
+				continue
+			}
+			corner := offset.Add(image.Pt(x+defaultQRBorder, y+defaultQRBorder).Mul(moduleSize))
+			module := image.Rectangle{Min: corner, Max: corner.Add(image.Pt(moduleSize, moduleSize))}
+			draw.Draw(dst, module, image.Black, image.Point{}, draw.Src)
+		}
+	}
+	return nil
+}
+
+// labelRegions splits a width x height label into the code square and the
+// padded text area for layout, checking both have room
+func labelRegions(layout string, width, height int) (codeSide int, text image.Rectangle, err error) {
+	switch layout {
+	case labelLayoutBeside:
+		if width-height < minLabelTextSpan {
+			return 0, image.Rectangle{}, fmt.Errorf("layout %q needs width at least height + %d to leave room for text, got %dx%d", layout, minLabelTextSpan, width, height)
+		}
+		text = image.Rect(height, 0, width, height)
+		codeSide = height
+	case labelLayoutBelow:
+		if height-width < minLabelTextSpan {
+			return 0, image.Rectangle{}, fmt.Errorf("layout %q needs height at least width + %d to leave room for text, got %dx%d", layout, minLabelTextSpan, width, height)
+		}
+		text = image.Rect(0, width, width, height)
+		codeSide = width
+	default:
+		return 0, image.Rectangle{}, fmt.Errorf("layout must be %q or %q, got: %q", labelLayoutBeside, labelLayoutBelow, layout)
+	}
+	return codeSide, text.Inset(labelPadding), nil
+}
+
+// renderLabel draws a width x height label: the code in a codeSide square at
+// the top left and the name and detail lines in textArea
+func renderLabel(payload, itemName string, details []string, width, height, codeSide int, textArea image.Rectangle) (*image.Gray, labelTextBlock, error) {
+	block, err := layoutLabelText(itemName, details, textArea.Size())
+	if err != nil {
+		return nil, labelTextBlock{}, err
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	if err := drawLabelCode(img, payload, image.Point{}, codeSide); err != nil {
+		return nil, labelTextBlock{}, fmt.Errorf("label is too small: %w", err)
+	}
+	drawLabelText(img, block, textArea)
+	return img, block, nil
+}
+
+// handleGenerateLabel renders a printable label: the item's QR code with its
+// name (and optionally id and location) in large text beside or below it,
+// as one PNG. Name and location default to the inventory item's; item_name
+// is required for items not in inventory.
+func (s *inventoryKeeperKeeper) handleGenerateLabel(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	if err := s.fieldRules.validateItemID(itemID); err != nil {
+		return nil, err
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	itemName, err := optionalStringArg(cmd, "item_name")
+	if err != nil {
+		return nil, err
+	}
+	location, err := optionalStringArg(cmd, "location")
+	if err != nil {
+		return nil, err
+	}
+
+	width, hasWidth, err := intArg(cmd, "width")
+	if err != nil {
+		return nil, err
+	}
+	if !hasWidth {
+		width = defaultLabelWidth
+	}
+	height, hasHeight, err := intArg(cmd, "height")
+	if err != nil {
+		return nil, err
+	}
+	if !hasHeight {
+		height = defaultLabelHeight
+	}
+	if width < 1 || height < 1 || width > maxLabelSide || height > maxLabelSide {
+		return nil, fmt.Errorf("width and height must be between 1 and %d, got %dx%d", maxLabelSide, width, height)
+	}
+	layout, err := optionalStringArg(cmd, "layout")
+	if err != nil {
+		return nil, err
+	}
+	if layout == "" {
+		layout = labelLayoutBeside
+	}
+	codeSide, textArea, err := labelRegions(layout, width, height)
+	if err != nil {
+		return nil, err
+	}
+	showID := true
+	if v, ok := cmd["show_id"].(bool); ok {
+		showID = v
+	}
+	showLocation := true
+	if v, ok := cmd["show_location"].(bool); ok {
+		showLocation = v
+	}
+
+	s.inventoryMu.RLock()
+	if item, exists := s.inventory[keyFor(namespace, itemID)]; exists {
+		if itemName == "" {
+			itemName = item.ItemName
+		}
+		if location == "" {
+			location = item.Location
+		}
+	}
+	s.inventoryMu.RUnlock()
+	if itemName == "" {
+		return nil, fmt.Errorf("item %s not found; item_name is required to label it", itemID)
+	}
+	if err := s.fieldRules.validateItemName(itemName); err != nil {
+		return nil, err
+	}
+
+	qrData := ItemQRData{ItemID: itemID, ItemName: itemName}
+	if namespace != defaultNamespace {
+		qrData.Namespace = namespace
+	}
+	payload, err := s.encodeQRPayload(qrData)
+	if err != nil {
+		return nil, err
+	}
+
+	var details []string
+	if showID {
+		details = append(details, itemID)
+	}
+	if showLocation && location != "" {
+		details = append(details, location)
+	}
+	img, block, err := renderLabel(payload, itemName, details, width, height, codeSide, textArea)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode label: %w", err)
+	}
+
+	s.logger.Infof("Generated %dx%d label for item: %s", width, height, itemID)
+	return map[string]interface{}{
+		"namespace":  namespace,
+		"item_id":    itemID,
+		"item_name":  itemName,
+		"label":      base64.StdEncoding.EncodeToString(buf.Bytes()),
+		"qr_data":    payload,
+		"layout":     layout,
+		"width":      width,
+		"height":     height,
+		"name_lines": len(block.nameLines),
+		"truncated":  block.truncated,
+		"format":     "base64-png",
+	}, nil
+}
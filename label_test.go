@@ -0,0 +1,97 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestGenerateLabel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("label is a PNG of the requested size that scans back to the payload", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple", "location": "aisle-3"})
+
+		for _, tc := range []struct {
+			layout        string
+			width, height int
+		}{
+			{"beside", 600, 300},
+			{"below", 300, 450},
+		} {
+			result := mustDoCommand(t, svc, map[string]interface{}{
+				"command": "generate_label", "item_id": "apple-001",
+				"layout": tc.layout, "width": tc.width, "height": tc.height,
+			})
+			if result["item_name"] != "Honeycrisp Apple" {
+				t.Errorf("%s: expected the inventory name, got %v", tc.layout, result["item_name"])
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(result["label"].(string))
+			if err != nil {
+				t.Fatalf("%s: label is not base64: %v", tc.layout, err)
+			}
+			img, err := png.Decode(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("%s: label is not a PNG: %v", tc.layout, err)
+			}
+			if b := img.Bounds(); b.Dx() != tc.width || b.Dy() != tc.height {
+				t.Errorf("%s: expected %dx%d, got %dx%d", tc.layout, tc.width, tc.height, b.Dx(), b.Dy())
+			}
+
+			content, _, err := decodeCodeImage(img)
+			if err != nil {
+				t.Fatalf("%s: label QR did not decode: %v", tc.layout, err)
+			}
+			if content != result["qr_data"] {
+				t.Errorf("%s: decoded %q, want %q", tc.layout, content, result["qr_data"])
+			}
+			data, err := svc.decodeQRPayload(content)
+			if err != nil || data.ItemID != "apple-001" {
+				t.Errorf("%s: payload should identify apple-001, got %+v (%v)", tc.layout, data, err)
+			}
+		}
+	})
+
+	t.Run("long names are truncated with an ellipsis", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "generate_label", "item_id": "widget-9", "width": 400, "height": 200,
+			"item_name": "Supercalifragilisticexpialidocious-Widget-Assembly-Unit-Extended",
+		})
+		if result["truncated"] != true {
+			t.Errorf("expected an unbreakable long name to be truncated, got %v", result)
+		}
+	})
+
+	t.Run("dimensions without room for text are rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		for _, dims := range [][2]int{{300, 300}, {0, 200}, {5000, 300}} {
+			_, err := svc.DoCommand(ctx, map[string]interface{}{
+				"command": "generate_label", "item_id": "apple-001", "item_name": "Apple",
+				"width": dims[0], "height": dims[1],
+			})
+			if err == nil {
+				t.Errorf("expected %dx%d to be rejected", dims[0], dims[1])
+			}
+		}
+
+		_, err := svc.DoCommand(ctx, map[string]interface{}{
+			"command": "generate_label", "item_id": "apple-001", "item_name": "Apple", "width": 140, "height": 40,
+		})
+		if err == nil || !strings.Contains(err.Error(), "too small") {
+			t.Errorf("expected a code too small to scan to be rejected, got: %v", err)
+		}
+	})
+
+	t.Run("unknown item needs item_name", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "generate_label", "item_id": "ghost"}); err == nil {
+			t.Error("expected an error for an unknown item without item_name")
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Label sheet defaults describe Avery 5160 / 8160 address labels on US
+// letter: 3 columns of 10 labels, 2-5/8" x 1". Lengths are in millimeters.
+const (
+	defaultSheetPageWidth   = 215.9
+	defaultSheetPageHeight  = 279.4
+	defaultSheetLabelWidth  = 66.675
+	defaultSheetLabelHeight = 25.4
+	defaultSheetMarginTop   = 12.7
+	defaultSheetMarginLeft  = 4.7625
+	defaultSheetGapX        = 3.175
+	defaultSheetGapY        = 0
+	defaultSheetColumns     = 3
+	defaultSheetRows        = 10
+	defaultSheetDPI         = 300
+	minSheetDPI             = 72
+	maxSheetDPI             = 600
+	pointsPerMM             = 72 / 25.4
+)
+
+// labelSheet is the grid labels are laid out on
+type labelSheet struct {
+	pageWidth, pageHeight   float64 // mm
+	labelWidth, labelHeight float64 // mm
+	marginTop, marginLeft   float64 // mm from the page's top left to the first label
+	gapX, gapY              float64 // mm between columns and between rows
+	columns, rows           int
+	dpi                     int // Resolution labels are rendered at
+}
+
+// labelSheetArgs reads the sheet geometry from cmd, defaulting to Avery 5160,
+// and checks the grid fits on the page
+func labelSheetArgs(cmd map[string]interface{}) (labelSheet, error) {
+	sheet := labelSheet{columns: defaultSheetColumns, rows: defaultSheetRows, dpi: defaultSheetDPI}
+	for _, field := range []struct {
+		name   string
+		target *float64
+		value  float64
+	}{
+		{"page_width_mm", &sheet.pageWidth, defaultSheetPageWidth},
+		{"page_height_mm", &sheet.pageHeight, defaultSheetPageHeight},
+		{"label_width_mm", &sheet.labelWidth, defaultSheetLabelWidth},
+		{"label_height_mm", &sheet.labelHeight, defaultSheetLabelHeight},
+		{"margin_top_mm", &sheet.marginTop, defaultSheetMarginTop},
+		{"margin_left_mm", &sheet.marginLeft, defaultSheetMarginLeft},
+		{"gap_x_mm", &sheet.gapX, defaultSheetGapX},
+		{"gap_y_mm", &sheet.gapY, defaultSheetGapY},
+	} {
+		value, found, err := floatArg(cmd, field.name)
+		if err != nil {
+			return labelSheet{}, err
+		}
+		if !found {
+			value = field.value
+		}
+		if value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+			return labelSheet{}, fmt.Errorf("%s must be a non-negative number, got: %v", field.name, value)
+		}
+		*field.target = value
+	}
+	for _, field := range []struct {
+		name   string
+		target *int
+	}{
+		{"columns", &sheet.columns},
+		{"rows", &sheet.rows},
+		{"dpi", &sheet.dpi},
+	} {
+		value, found, err := intArg(cmd, field.name)
+		if err != nil {
+			return labelSheet{}, err
+		}
+		if found {
+			*field.target = value
+		}
+	}
+
+	if sheet.columns < 1 || sheet.rows < 1 {
+		return labelSheet{}, fmt.Errorf("columns and rows must be at least 1, got %dx%d", sheet.columns, sheet.rows)
+	}
+	if sheet.dpi < minSheetDPI || sheet.dpi > maxSheetDPI {
+		return labelSheet{}, fmt.Errorf("dpi must be between %d and %d, got: %d", minSheetDPI, maxSheetDPI, sheet.dpi)
+	}
+	if sheet.labelWidth == 0 || sheet.labelHeight == 0 {
+		return labelSheet{}, errors.New("label_width_mm and label_height_mm must be positive")
+	}
+	gridWidth := sheet.marginLeft + float64(sheet.columns)*sheet.labelWidth + float64(sheet.columns-1)*sheet.gapX
+	gridHeight := sheet.marginTop + float64(sheet.rows)*sheet.labelHeight + float64(sheet.rows-1)*sheet.gapY
+	if gridWidth > sheet.pageWidth+0.01 || gridHeight > sheet.pageHeight+0.01 {
+		return labelSheet{}, fmt.Errorf("a %dx%d grid of %vx%vmm labels needs %.1fx%.1fmm from the top left margin, page is %vx%vmm",
+			sheet.columns, sheet.rows, sheet.labelWidth, sheet.labelHeight, gridWidth, gridHeight, sheet.pageWidth, sheet.pageHeight)
+	}
+	return sheet, nil
+}
+
+// pixels converts a length in mm to pixels at the sheet's resolution
+func (sheet labelSheet) pixels(mm float64) int {
+	return int(math.Round(mm / 25.4 * float64(sheet.dpi)))
+}
+
+// sheetLabel is one label to print
+type sheetLabel struct {
+	key      itemKey
+	itemName string
+	location string
+}
+
+// labelSheetItems resolves the command's items (item_id strings or objects
+// with item_id and optional item_name, namespace, location) against
+// inventory. Without items, every item in the namespace is labeled.
+func (s *inventoryKeeperKeeper) labelSheetItems(cmd map[string]interface{}) ([]sheetLabel, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, given := cmd["items"]
+	if !given {
+		s.inventoryMu.RLock()
+		var labels []sheetLabel
+		for key, item := range s.inventory {
+			if key.Namespace == namespace {
+				labels = append(labels, sheetLabel{key: key, itemName: item.ItemName, location: item.Location})
+			}
+		}
+		s.inventoryMu.RUnlock()
+		if len(labels) == 0 {
+			return nil, fmt.Errorf("namespace %s has no items to label", namespace)
+		}
+		sort.Slice(labels, func(i, j int) bool { return labels[i].key.ItemID < labels[j].key.ItemID })
+		return labels, nil
+	}
+
+	entries, ok := raw.([]interface{})
+	if !ok || len(entries) == 0 {
+		return nil, errors.New("items must be a non-empty list of item_ids or objects")
+	}
+	labels := make([]sheetLabel, 0, len(entries))
+	for i, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			itemID, isString := entry.(string)
+			if !isString {
+				return nil, fmt.Errorf("items[%d] must be an item_id or an object", i)
+			}
+			fields = map[string]interface{}{"item_id": itemID}
+		}
+
+		itemID, _ := fields["item_id"].(string)
+		if itemID == "" {
+			return nil, fmt.Errorf("items[%d]: item_id is required and must be a string", i)
+		}
+		if err := s.fieldRules.validateItemID(itemID); err != nil {
+			return nil, fmt.Errorf("items[%d]: %w", i, err)
+		}
+		itemNamespace := namespace
+		if _, set := fields["namespace"]; set {
+			if itemNamespace, err = namespaceArg(fields); err != nil {
+				return nil, fmt.Errorf("items[%d]: %w", i, err)
+			}
+		}
+		label := sheetLabel{key: keyFor(itemNamespace, itemID)}
+		label.itemName, _ = fields["item_name"].(string)
+		label.location, _ = fields["location"].(string)
+
+		s.inventoryMu.RLock()
+		if item, exists := s.inventory[label.key]; exists {
+			if label.itemName == "" {
+				label.itemName = item.ItemName
+			}
+			if label.location == "" {
+				label.location = item.Location
+			}
+		}
+		s.inventoryMu.RUnlock()
+		if label.itemName == "" {
+			return nil, fmt.Errorf("items[%d]: item %s not found; item_name is required to label it", i, itemID)
+		}
+		if err := s.fieldRules.validateItemName(label.itemName); err != nil {
+			return nil, fmt.Errorf("items[%d]: %w", i, err)
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// handleGenerateLabelSheet lays out labels (code plus name, as
+// generate_label draws them) on a grid of label stock and returns a PDF
+// ready to print at 100% scale. The grid defaults to Avery 5160; page,
+// label, margin, and gap sizes are in mm.
+func (s *inventoryKeeperKeeper) handleGenerateLabelSheet(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	sheet, err := labelSheetArgs(cmd)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := optionalStringArg(cmd, "layout")
+	if err != nil {
+		return nil, err
+	}
+	if layout == "" {
+		layout = labelLayoutBeside
+	}
+	width, height := sheet.pixels(sheet.labelWidth), sheet.pixels(sheet.labelHeight)
+	if width > maxLabelSide || height > maxLabelSide {
+		return nil, fmt.Errorf("labels render at %dx%d pixels, over the %d limit; lower dpi", width, height, maxLabelSide)
+	}
+	codeSide, textArea, err := labelRegions(layout, width, height)
+	if err != nil {
+		return nil, fmt.Errorf("labels render at %dx%d pixels at %d dpi: %w", width, height, sheet.dpi, err)
+	}
+	showID := true
+	if v, ok := cmd["show_id"].(bool); ok {
+		showID = v
+	}
+	showLocation := true
+	if v, ok := cmd["show_location"].(bool); ok {
+		showLocation = v
+	}
+
+	labels, err := s.labelSheetItems(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.config().checkBatchArg("items", len(labels)); err != nil {
+		return nil, err
+	}
+
+	perPage := sheet.columns * sheet.rows
+	doc := &pdfWriter{}
+	catalog := doc.reserve()
+	pages := doc.reserve()
+	var kids []string
+	for start := 0; start < len(labels); start += perPage {
+		end := min(start+perPage, len(labels))
+
+		var content strings.Builder
+		var resources strings.Builder
+		for i, label := range labels[start:end] {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			qrData := ItemQRData{ItemID: label.key.ItemID, ItemName: label.itemName}
+			if label.key.Namespace != defaultNamespace {
+				qrData.Namespace = label.key.Namespace
+			}
+			payload, err := s.encodeQRPayload(qrData)
+			if err != nil {
+				return nil, fmt.Errorf("item %s: %w", label.key.ItemID, err)
+			}
+			var details []string
+			if showID {
+				details = append(details, label.key.ItemID)
+			}
+			if showLocation && label.location != "" {
+				details = append(details, label.location)
+			}
+			img, _, err := renderLabel(payload, label.itemName, details, width, height, codeSide, textArea)
+			if err != nil {
+				return nil, fmt.Errorf("item %s: %w", label.key.ItemID, err)
+			}
+			image, err := doc.addGrayImage(img)
+			if err != nil {
+				return nil, fmt.Errorf("item %s: failed to encode label: %w", label.key.ItemID, err)
+			}
+
+			// PDF coordinates are points from the bottom left
+			column, row := i%sheet.columns, i/sheet.columns
+			x := sheet.marginLeft + float64(column)*(sheet.labelWidth+sheet.gapX)
+			y := sheet.pageHeight - sheet.marginTop - float64(row)*(sheet.labelHeight+sheet.gapY) - sheet.labelHeight
+			fmt.Fprintf(&content, "q %.3f 0 0 %.3f %.3f %.3f cm /L%d Do Q\n",
+				sheet.labelWidth*pointsPerMM, sheet.labelHeight*pointsPerMM, x*pointsPerMM, y*pointsPerMM, i)
+			fmt.Fprintf(&resources, "/L%d %d 0 R ", i, image)
+		}
+
+		stream := doc.addStream("", []byte(content.String()))
+		page := doc.add([]byte(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.3f %.3f] /Resources << /XObject << %s>> >> /Contents %d 0 R >>",
+			pages, sheet.pageWidth*pointsPerMM, sheet.pageHeight*pointsPerMM, resources.String(), stream)))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	doc.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	doc.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	pdf := doc.bytes(catalog)
+
+	s.logger.Infof("Generated label sheet: %d labels on %d pages", len(labels), len(kids))
+	return map[string]interface{}{
+		"pdf":             base64.StdEncoding.EncodeToString(pdf),
+		"format":          "base64-pdf",
+		"labels":          len(labels),
+		"pages":           len(kids),
+		"labels_per_page": perPage,
+		"label_pixels":    map[string]interface{}{"width": width, "height": height},
+		"layout":          layout,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGenerateLabelSheet(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("inventory spills onto a second page of Avery 5160", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		for i := 1; i <= 35; i++ {
+			mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": fmt.Sprintf("bin-%03d", i), "item_name": fmt.Sprintf("Bin %d", i)})
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_label_sheet", "dpi": 150})
+		if result["labels"] != 35 || result["pages"] != 2 || result["labels_per_page"] != 30 {
+			t.Fatalf("expected 35 labels on 2 pages of 30, got: %v", result)
+		}
+		pdf, err := base64.StdEncoding.DecodeString(result["pdf"].(string))
+		if err != nil {
+			t.Fatalf("pdf is not base64: %v", err)
+		}
+		if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+			t.Fatalf("expected a PDF header, got: %q", pdf[:min(len(pdf), 16)])
+		}
+		if !bytes.Contains(pdf, []byte("/Count 2")) {
+			t.Error("expected the page tree to count 2 pages")
+		}
+		if images := bytes.Count(pdf, []byte("/Subtype /Image")); images != 35 {
+			t.Errorf("expected 35 label images, got %d", images)
+		}
+
+		// startxref must point at the cross-reference table
+		trailer := pdf[bytes.LastIndex(pdf, []byte("startxref\n"))+len("startxref\n"):]
+		offset, err := strconv.Atoi(strings.TrimSpace(string(trailer[:bytes.IndexByte(trailer, '\n')])))
+		if err != nil || !bytes.HasPrefix(pdf[offset:], []byte("xref\n")) {
+			t.Errorf("startxref does not point at the xref table (offset %d, %v)", offset, err)
+		}
+	})
+
+	t.Run("listed items may be unrecorded if named", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "generate_label_sheet", "dpi": 100, "columns": 2, "rows": 5,
+			"label_width_mm": 101.6, "label_height_mm": 50.8,
+			"items": []interface{}{"apple-001", map[string]interface{}{"item_id": "fuse-010", "item_name": "Spare Fuses"}},
+		})
+		if result["labels"] != 2 || result["pages"] != 1 || result["labels_per_page"] != 10 {
+			t.Errorf("expected 2 labels on one page of 10, got: %v", result)
+		}
+
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "generate_label_sheet", "items": []interface{}{"ghost-404"}}); err == nil {
+			t.Error("expected error labeling an unknown item without a name")
+		}
+	})
+
+	t.Run("geometry is checked against the page", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+
+		for _, cmd := range []map[string]interface{}{
+			{"columns": 4},                 // 4 x 66.675mm is wider than letter
+			{"rows": 11},                   // Runs off the bottom
+			{"label_width_mm": 0},          // Nothing to draw
+			{"margin_top_mm": -1},          // Negative lengths
+			{"dpi": 1200},                  // Over the render limit
+			{"columns": 0},                 // Empty grid
+			{"label_height_mm": 200},       // Too tall for letter
+			{"layout": "sideways"},         // Unknown layout
+			{"label_width_mm": "wide"},     // Not a number
+			{"items": []interface{}{42.0}}, // Not an item_id
+		} {
+			cmd["command"] = "generate_label_sheet"
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+	})
+}
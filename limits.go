@@ -0,0 +1,74 @@
+package inventorykeeper
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// errPayloadTooLarge is returned when a command argument exceeds one of the
+// configured input limits
+var errPayloadTooLarge = errors.New("PAYLOAD_TOO_LARGE")
+
+// Input limits used when the corresponding config field is not set
+const (
+	defaultMaxImageBytes  = 10 << 20 // Decoded size of one base64 image argument
+	defaultMaxBatchItems  = 500      // Entries in a list argument such as item_ids or images
+	defaultMaxImportItems = 10000    // Inventory items or history events in an imported snapshot
+)
+
+// maxImageBytes returns the largest decoded image a command accepts
+func (cfg *Config) maxImageBytes() int {
+	if cfg.MaxImageBytes == nil {
+		return defaultMaxImageBytes
+	}
+	return *cfg.MaxImageBytes
+}
+
+// maxBatchItems returns the longest list a batch command accepts
+func (cfg *Config) maxBatchItems() int {
+	if cfg.MaxBatchItems == nil {
+		return defaultMaxBatchItems
+	}
+	return *cfg.MaxBatchItems
+}
+
+// maxImportItems returns the most entries a snapshot may carry in either its
+// inventory or its history
+func (cfg *Config) maxImportItems() int {
+	if cfg.MaxImportItems == nil {
+		return defaultMaxImportItems
+	}
+	return *cfg.MaxImportItems
+}
+
+// checkImageArg rejects a base64 image whose decoded size would exceed
+// max_image_bytes. The size is worked out from the encoded length, so
+// nothing is allocated for an oversized image.
+func (cfg *Config) checkImageArg(field, encoded string) error {
+	size := base64.StdEncoding.DecodedLen(len(encoded))
+	if limit := cfg.maxImageBytes(); size > limit {
+		return fmt.Errorf("%w: %s is about %d bytes, over max_image_bytes %d", errPayloadTooLarge, field, size, limit)
+	}
+	return nil
+}
+
+// checkBatchArg rejects a list argument longer than max_batch_items
+func (cfg *Config) checkBatchArg(field string, count int) error {
+	if limit := cfg.maxBatchItems(); count > limit {
+		return fmt.Errorf("%w: %s has %d entries, over max_batch_items %d", errPayloadTooLarge, field, count, limit)
+	}
+	return nil
+}
+
+// checkImportArg rejects a snapshot whose inventory or history list is
+// longer than max_import_items, before it is parsed
+func (cfg *Config) checkImportArg(dump map[string]interface{}) error {
+	limit := cfg.maxImportItems()
+	for _, field := range []string{"inventory", "history"} {
+		if entries, ok := dump[field].([]interface{}); ok && len(entries) > limit {
+			return fmt.Errorf("%w: snapshot %s has %d entries, over max_import_items %d", errPayloadTooLarge, field, len(entries), limit)
+		}
+	}
+	return nil
+}
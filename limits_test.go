@@ -0,0 +1,66 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestInputSizeLimits(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 32, 32))); err != nil {
+		t.Fatalf("failed to encode frame: %v", err)
+	}
+	frame := base64.StdEncoding.EncodeToString(buf.Bytes())
+	limit := buf.Len() + 64
+
+	t.Run("image over max_image_bytes is rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{MaxImageBytes: &limit})
+		oversized := base64.StdEncoding.EncodeToString(make([]byte, limit+1))
+
+		_, err := svc.DoCommand(ctx, map[string]interface{}{"command": "simulate_scan", "images": []interface{}{frame, oversized}})
+		if !errors.Is(err, errPayloadTooLarge) || !strings.Contains(err.Error(), "images[1]") {
+			t.Errorf("expected PAYLOAD_TOO_LARGE for images[1], got: %v", err)
+		}
+	})
+
+	t.Run("image under max_image_bytes is processed", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{MaxImageBytes: &limit})
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "simulate_scan", "images": []interface{}{frame}})
+		if scans := result["scans"].([]interface{}); len(scans) != 1 {
+			t.Errorf("expected 1 simulated scan, got: %v", scans)
+		}
+	})
+
+	t.Run("batch over max_batch_items is rejected", func(t *testing.T) {
+		maxBatch := 2
+		svc, _ := newTestKeeper(t, &Config{MaxBatchItems: &maxBatch})
+		_, err := svc.DoCommand(ctx, map[string]interface{}{"command": "check_in_batch", "item_ids": []interface{}{"a", "b", "c"}})
+		if !errors.Is(err, errPayloadTooLarge) {
+			t.Errorf("expected PAYLOAD_TOO_LARGE, got: %v", err)
+		}
+	})
+
+	t.Run("snapshot over max_import_items is rejected", func(t *testing.T) {
+		maxImport := 1
+		svc, _ := newTestKeeper(t, &Config{MaxImportItems: &maxImport})
+		snapshot := map[string]interface{}{"inventory": []interface{}{
+			map[string]interface{}{"item_id": "a", "item_name": "A"},
+			map[string]interface{}{"item_id": "b", "item_name": "B"},
+		}}
+		_, err := svc.DoCommand(ctx, map[string]interface{}{"command": "restore_snapshot", "snapshot": snapshot})
+		if !errors.Is(err, errPayloadTooLarge) {
+			t.Errorf("expected PAYLOAD_TOO_LARGE, got: %v", err)
+		}
+		if len(svc.inventory) != 0 {
+			t.Error("rejected snapshot must not change inventory")
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	"github.com/makiuchi-d/gozxing"
+	multiqr "github.com/makiuchi-d/gozxing/multi/qrcode"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Where a scan's detections came from
+const (
+	detectionSourceVision = "vision"
+	detectionSourceLocal  = "local"
+)
+
+// decodeQRCodesLocally finds every QR code in an image with the pure-Go
+// decoder, returning them as detections so they flow through the same
+// pipeline as vision service results. A frame with no codes is not an error.
+func decodeQRCodesLocally(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to binarize image: %w", err)
+	}
+
+	results, err := multiqr.NewQRCodeMultiReader().DecodeMultiple(bmp, nil)
+	if err != nil {
+		if _, notFound := err.(gozxing.NotFoundException); notFound {
+			return []objectdetection.Detection{}, nil
+		}
+		return nil, fmt.Errorf("local QR decoding failed: %w", err)
+	}
+
+	detections := make([]objectdetection.Detection, 0, len(results))
+	for _, result := range results {
+		detections = append(detections, objectdetection.NewDetection(img.Bounds(), resultBounds(result, img.Bounds()), 1.0, result.GetText()))
+	}
+	return detections, nil
+}
+
+// resultBounds returns the box around a decoded code's finder points,
+// clipped to the image
+func resultBounds(result *gozxing.Result, bounds image.Rectangle) image.Rectangle {
+	points := result.GetResultPoints()
+	if len(points) == 0 {
+		return bounds
+	}
+	box := image.Rect(int(points[0].GetX()), int(points[0].GetY()), int(points[0].GetX())+1, int(points[0].GetY())+1)
+	for _, p := range points[1:] {
+		box = box.Union(image.Rect(int(p.GetX()), int(p.GetY()), int(p.GetX())+1, int(p.GetY())+1))
+	}
+	return box.Intersect(bounds)
+}
+
+// detectLocally captures a frame and decodes it with the local decoder,
+// applying preprocessing and the region of interest like a vision scan
+func (s *inventoryKeeperKeeper) detectLocally(ctx context.Context) ([]objectdetection.Detection, error) {
+	img, err := s.captureFrame(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.detectInFrameWith(ctx, preprocessImage(img, s.config()), decodeQRCodesLocally)
+}
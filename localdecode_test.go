@@ -0,0 +1,103 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/draw"
+	"image/png"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestLocalDecodeFallback(t *testing.T) {
+	// shelfFrame places two item codes side by side on a white background
+	shelfFrame := func(t *testing.T) image.Image {
+		t.Helper()
+		frame := image.NewRGBA(image.Rect(0, 0, 600, 300))
+		draw.Draw(frame, frame.Bounds(), image.White, image.Point{}, draw.Src)
+		for i, content := range []string{`{"item_id":"apple-001","item_name":"Apple"}`, `{"item_id":"banana-001","item_name":"Banana"}`} {
+			pngBytes, err := qrcode.Encode(content, qrcode.Medium, 256)
+			if err != nil {
+				t.Fatalf("failed to render QR: %v", err)
+			}
+			code, err := png.Decode(bytes.NewReader(pngBytes))
+			if err != nil {
+				t.Fatalf("failed to read QR: %v", err)
+			}
+			draw.Draw(frame, image.Rect(i*300+20, 20, i*300+276, 276), code, image.Point{}, draw.Src)
+		}
+		return frame
+	}
+
+	// failingVisionKeeper returns a keeper whose vision service is down
+	failingVisionKeeper := func(t *testing.T, fallback bool) (*inventoryKeeperKeeper, *inject.VisionService) {
+		t.Helper()
+		noRetries := 0
+		svc, mockVision := newTestKeeper(t, &Config{LocalDecodeFallback: fallback, CaptureRetries: &noRetries})
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return nil, errors.New("vision service unavailable")
+		}
+		setCameraFrame(t, svc, shelfFrame(t))
+		return svc, mockVision
+	}
+
+	t.Run("vision failure decodes locally when enabled", func(t *testing.T) {
+		svc, _ := failingVisionKeeper(t, true)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		if result["source"] != detectionSourceLocal {
+			t.Errorf("expected source local, got: %v", result["source"])
+		}
+		codes := result["codes"].([]interface{})
+		found := map[string]bool{}
+		for _, raw := range codes {
+			code := raw.(map[string]interface{})
+			if code["source"] != detectionSourceLocal {
+				t.Errorf("expected each code marked local, got: %v", code)
+			}
+			if id, ok := code["item_id"].(string); ok {
+				found[id] = true
+			}
+		}
+		if !found["apple-001"] || !found["banana-001"] {
+			t.Errorf("expected both items decoded locally, got: %v", codes)
+		}
+
+		vision := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["vision"].(map[string]interface{})
+		if vision["status"] != "fallback" || vision["local_fallbacks"] != 1 || vision["last_vision_error"] == nil {
+			t.Errorf("expected fallback reported in health, got: %v", vision)
+		}
+		camera := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["camera"].(map[string]interface{})
+		if camera["consecutive_failures"] != 0 {
+			t.Errorf("expected locally decoded scan to count as a success, got: %v", camera)
+		}
+	})
+
+	t.Run("vision failure fails the scan when disabled", func(t *testing.T) {
+		svc, _ := failingVisionKeeper(t, false)
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "scan_qr"}); err == nil {
+			t.Error("expected scan to fail without local fallback")
+		}
+	})
+
+	t.Run("vision recovery clears fallback status", func(t *testing.T) {
+		svc, mockVision := failingVisionKeeper(t, true)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{}, nil
+		}
+		if result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"}); result["source"] != detectionSourceVision {
+			t.Errorf("expected source vision after recovery, got: %v", result["source"])
+		}
+		vision := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["vision"].(map[string]interface{})
+		if vision["status"] != "ok" || vision["local_fallbacks"] != 1 {
+			t.Errorf("expected ok status that remembers the fallback, got: %v", vision)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package inventorykeeper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.viam.com/rdk/logging"
+)
+
+// Log line formats
+const (
+	logFormatText = "text" // Human-readable messages (default)
+	logFormatJSON = "json" // Each message is a JSON object of msg plus its fields
+)
+
+// validLogFormats lists the accepted log_format values
+var validLogFormats = map[string]bool{
+	logFormatText: true,
+	logFormatJSON: true,
+}
+
+// redactedLogKey reports whether a structured log field holds a secret:
+// a command's auth field or a secret config field
+func redactedLogKey(key string) bool {
+	if key == "auth" {
+		return true
+	}
+	for _, secret := range secretConfigFields {
+		if key == secret {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonLogger renders each message as a JSON object carrying the message as
+// "msg" alongside its structured fields, for pipelines that parse log lines.
+// Level and time stay on the underlying log entry. Methods not overridden
+// here pass through unchanged.
+type jsonLogger struct {
+	logging.Logger
+	fields []interface{} // Key/value pairs added by WithFields
+}
+
+// newJSONLogger wraps logger so its messages are JSON objects
+func newJSONLogger(logger logging.Logger) *jsonLogger {
+	return &jsonLogger{Logger: logger}
+}
+
+// encode builds the JSON line for a message and its key/value pairs. Secret
+// keys are redacted; values JSON can't represent are written as strings.
+func (l *jsonLogger) encode(msg string, keysAndValues []interface{}) string {
+	entry := map[string]interface{}{}
+	for _, pairs := range [][]interface{}{l.fields, keysAndValues} {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			key := fmt.Sprint(pairs[i])
+			value := pairs[i+1]
+			switch v := value.(type) {
+			case error:
+				value = v.Error()
+			case fmt.Stringer:
+				value = v.String()
+			}
+			if redactedLogKey(key) {
+				value = "[redacted]"
+			}
+			entry[key] = value
+		}
+	}
+	entry["msg"] = msg
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		for key, value := range entry {
+			entry[key] = fmt.Sprint(value)
+		}
+		line, _ = json.Marshal(entry)
+	}
+	return string(line)
+}
+
+// Debug implements logging.Logger
+func (l *jsonLogger) Debug(args ...interface{}) { l.Logger.Debug(l.encode(fmt.Sprint(args...), nil)) }
+
+// Debugf implements logging.Logger
+func (l *jsonLogger) Debugf(template string, args ...interface{}) {
+	l.Logger.Debug(l.encode(fmt.Sprintf(template, args...), nil))
+}
+
+// Debugw implements logging.Logger
+func (l *jsonLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.Logger.Debug(l.encode(msg, keysAndValues))
+}
+
+// Info implements logging.Logger
+func (l *jsonLogger) Info(args ...interface{}) { l.Logger.Info(l.encode(fmt.Sprint(args...), nil)) }
+
+// Infof implements logging.Logger
+func (l *jsonLogger) Infof(template string, args ...interface{}) {
+	l.Logger.Info(l.encode(fmt.Sprintf(template, args...), nil))
+}
+
+// Infow implements logging.Logger
+func (l *jsonLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.Logger.Info(l.encode(msg, keysAndValues))
+}
+
+// Warn implements logging.Logger
+func (l *jsonLogger) Warn(args ...interface{}) { l.Logger.Warn(l.encode(fmt.Sprint(args...), nil)) }
+
+// Warnf implements logging.Logger
+func (l *jsonLogger) Warnf(template string, args ...interface{}) {
+	l.Logger.Warn(l.encode(fmt.Sprintf(template, args...), nil))
+}
+
+// Warnw implements logging.Logger
+func (l *jsonLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.Logger.Warn(l.encode(msg, keysAndValues))
+}
+
+// Error implements logging.Logger
+func (l *jsonLogger) Error(args ...interface{}) { l.Logger.Error(l.encode(fmt.Sprint(args...), nil)) }
+
+// Errorf implements logging.Logger
+func (l *jsonLogger) Errorf(template string, args ...interface{}) {
+	l.Logger.Error(l.encode(fmt.Sprintf(template, args...), nil))
+}
+
+// Errorw implements logging.Logger
+func (l *jsonLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.Logger.Error(l.encode(msg, keysAndValues))
+}
+
+// Sublogger implements logging.Logger, keeping the JSON format
+func (l *jsonLogger) Sublogger(subname string) logging.Logger {
+	return &jsonLogger{Logger: l.Logger.Sublogger(subname), fields: l.fields}
+}
+
+// WithFields implements logging.Logger, adding the fields to every message
+func (l *jsonLogger) WithFields(args ...interface{}) logging.Logger {
+	fields := append(append([]interface{}{}, l.fields...), args...)
+	return &jsonLogger{Logger: l.Logger, fields: fields}
+}
@@ -0,0 +1,76 @@
+package inventorykeeper
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestJSONLogFormat(t *testing.T) {
+	// parsedLines decodes every observed message, failing on any that isn't JSON
+	parsedLines := func(t *testing.T, messages []string) []map[string]interface{} {
+		t.Helper()
+		lines := make([]map[string]interface{}, 0, len(messages))
+		for _, msg := range messages {
+			var line map[string]interface{}
+			if err := json.Unmarshal([]byte(msg), &line); err != nil {
+				t.Fatalf("log message is not JSON: %q", msg)
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	t.Run("keeper events are JSON with command, item, and event fields", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{LogFormat: logFormatJSON})
+		observed, logs := logging.NewObservedTestLogger(t)
+		svc.logger.(*jsonLogger).Logger = observed
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+
+		var messages []string
+		for _, entry := range logs.All() {
+			messages = append(messages, entry.Message)
+		}
+		var sawCommand, sawEvent bool
+		for _, line := range parsedLines(t, messages) {
+			if _, ok := line["msg"].(string); !ok {
+				t.Errorf("expected a msg field, got: %v", line)
+			}
+			if line["command"] == "add_item" {
+				sawCommand = true
+			}
+			if line["event"] == "item_added" && line["item_id"] == "apple-001" {
+				sawEvent = true
+			}
+		}
+		if !sawCommand || !sawEvent {
+			t.Errorf("expected command and item_added event lines, got: %v", messages)
+		}
+	})
+
+	t.Run("secrets are redacted", func(t *testing.T) {
+		observed, logs := logging.NewObservedTestLogger(t)
+		logger := newJSONLogger(observed).WithFields("command", "set_config")
+		logger.Infow("Command received", "auth", "hunter2", "smtp_password", "swordfish", "error", errors.New("boom"))
+
+		entry := logs.All()[0]
+		if strings.Contains(entry.Message, "hunter2") || strings.Contains(entry.Message, "swordfish") {
+			t.Fatalf("expected secrets redacted, got: %s", entry.Message)
+		}
+		line := parsedLines(t, []string{entry.Message})[0]
+		if line["auth"] != "[redacted]" || line["command"] != "set_config" || line["error"] != "boom" {
+			t.Errorf("unexpected fields: %v", line)
+		}
+	})
+
+	t.Run("invalid log_format is rejected", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", LogFormat: "xml"}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected validation error for log_format xml")
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maintenanceMode mutes alert delivery while scanning carries on. Unlike a
+// monitoring pause, presence tracking, history, and alerts stay accurate;
+// alerts are only kept from notifiers.
+type maintenanceMode struct {
+	active          bool      // Alert delivery is muted
+	startedAt       time.Time // When maintenance mode began
+	reason          string    // Why maintenance mode was entered ("" if not given)
+	endsAt          time.Time // Automatic end time (zero when on until turned off)
+	suppressedCount int       // Alerts kept from notifiers during the current window
+}
+
+// endMaintenanceLocked leaves maintenance mode and records the window in
+// history. Caller must hold maintenanceMu.
+func (s *inventoryKeeperKeeper) endMaintenanceLocked(auto bool) map[string]interface{} {
+	details := map[string]interface{}{
+		"started_at":        formatTimestampIn(s.maintenance.startedAt, s.location),
+		"suppressed_alerts": s.maintenance.suppressedCount,
+		"auto":              auto,
+	}
+	if s.maintenance.reason != "" {
+		details["reason"] = s.maintenance.reason
+	}
+	s.maintenance = maintenanceMode{}
+
+	s.inventoryMu.Lock()
+	s.recordHistoryLocked("maintenance_ended", defaultNamespace, "", details)
+	s.inventoryMu.Unlock()
+	s.logger.Infof("Maintenance mode ended (%v alerts not delivered)", details["suppressed_alerts"])
+	return details
+}
+
+// activeMaintenanceLocked reports whether maintenance mode is on, ending it
+// first if a timed window has run out. Caller must hold maintenanceMu.
+func (s *inventoryKeeperKeeper) activeMaintenanceLocked() bool {
+	if !s.maintenance.active {
+		return false
+	}
+	if !s.maintenance.endsAt.IsZero() && !s.now().Before(s.maintenance.endsAt) {
+		s.endMaintenanceLocked(true)
+		return false
+	}
+	return true
+}
+
+// suppressNotification reports whether an alert about to be raised should be
+// kept from notifiers, counting it against the maintenance window if so
+func (s *inventoryKeeperKeeper) suppressNotification() bool {
+	s.maintenanceMu.Lock()
+	defer s.maintenanceMu.Unlock()
+
+	if !s.activeMaintenanceLocked() {
+		return false
+	}
+	s.maintenance.suppressedCount++
+	return true
+}
+
+// maintenanceStatus summarizes maintenance mode for status responses
+func (s *inventoryKeeperKeeper) maintenanceStatus() map[string]interface{} {
+	s.maintenanceMu.Lock()
+	defer s.maintenanceMu.Unlock()
+
+	result := map[string]interface{}{"enabled": s.activeMaintenanceLocked()}
+	if !s.maintenance.active {
+		return result
+	}
+	result["started_at"] = formatTimestampIn(s.maintenance.startedAt, s.location)
+	result["suppressed_alerts"] = s.maintenance.suppressedCount
+	if s.maintenance.reason != "" {
+		result["reason"] = s.maintenance.reason
+	}
+	if !s.maintenance.endsAt.IsZero() {
+		result["ends_at"] = formatTimestampIn(s.maintenance.endsAt, s.location)
+	}
+	return result
+}
+
+// handleSetMaintenanceMode turns maintenance mode on, optionally ending
+// automatically after duration, or off. Turning it on again replaces the
+// duration and reason without starting a new window.
+func (s *inventoryKeeperKeeper) handleSetMaintenanceMode(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	enabled, ok := cmd["enabled"].(bool)
+	if !ok {
+		return nil, errors.New("enabled is required and must be a boolean")
+	}
+	rawDuration, err := optionalStringArg(cmd, "duration")
+	if err != nil {
+		return nil, err
+	}
+	var duration time.Duration
+	if rawDuration != "" {
+		if !enabled {
+			return nil, errors.New("duration only applies when enabling maintenance mode")
+		}
+		if duration, err = time.ParseDuration(rawDuration); err != nil {
+			return nil, fmt.Errorf("duration must be a duration such as \"2h\": %w", err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("duration must be positive, got: %s", rawDuration)
+		}
+	}
+	reason, err := optionalStringArg(cmd, "reason")
+	if err != nil {
+		return nil, err
+	}
+
+	s.maintenanceMu.Lock()
+	if !enabled {
+		if !s.activeMaintenanceLocked() {
+			s.maintenanceMu.Unlock()
+			return map[string]interface{}{"enabled": false, "ended": false}, nil
+		}
+		details := s.endMaintenanceLocked(false)
+		s.maintenanceMu.Unlock()
+		return map[string]interface{}{
+			"enabled":           false,
+			"ended":             true,
+			"suppressed_alerts": details["suppressed_alerts"],
+		}, nil
+	}
+
+	now := s.now()
+	if !s.activeMaintenanceLocked() {
+		s.maintenance = maintenanceMode{active: true, startedAt: now}
+	}
+	s.maintenance.reason = reason
+	s.maintenance.endsAt = time.Time{}
+	if duration > 0 {
+		s.maintenance.endsAt = now.Add(duration)
+	}
+
+	details := map[string]interface{}{}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	if duration > 0 {
+		details["ends_at"] = formatTimestampIn(s.maintenance.endsAt, s.location)
+	}
+	s.inventoryMu.Lock()
+	s.recordHistoryLocked("maintenance_started", defaultNamespace, "", details)
+	s.inventoryMu.Unlock()
+	s.maintenanceMu.Unlock()
+
+	s.logger.Infof("Maintenance mode on, alert delivery muted: %s", reason)
+	return s.maintenanceStatus(), nil
+}
@@ -0,0 +1,156 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// recordingNotifier counts the alerts handed to it
+type recordingNotifier struct {
+	mu        sync.Mutex
+	delivered []int64
+	notified  chan struct{}
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.mu.Lock()
+	n.delivered = append(n.delivered, alert.ID)
+	n.mu.Unlock()
+	n.notified <- struct{}{}
+	return nil
+}
+
+func (n *recordingNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.delivered)
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	ctx := context.Background()
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	// setup confirms apple present with a recording notifier attached; the
+	// returned func switches the shelf between stocked and empty
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, *recordingNotifier, *int, func(stocked bool)) {
+		svc, mockVision := newTestKeeper(t, nil)
+		notifier := &recordingNotifier{notified: make(chan struct{}, 10)}
+		svc.notifiers = []alertNotifier{notifier}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+
+		calls := 0
+		stocked := true
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			calls++
+			if !stocked {
+				return []objectdetection.Detection{}, nil
+			}
+			return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+		}
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		return svc, notifier, &calls, func(s bool) { stocked = s }
+	}
+
+	t.Run("alerts are recorded but not delivered while scanning continues", func(t *testing.T) {
+		svc, notifier, calls, setStocked := setup(t)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "set_maintenance_mode", "enabled": true, "reason": "shelf repair"})
+		if result["enabled"] != true || result["reason"] != "shelf repair" {
+			t.Fatalf("expected maintenance mode on, got: %v", result)
+		}
+
+		before := *calls
+		setStocked(false)
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		if *calls != before+2 {
+			t.Errorf("expected scanning to continue, got %d vision calls", *calls-before)
+		}
+
+		alerts := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{})
+		if len(alerts) != 1 {
+			t.Fatalf("expected the theft alert to be recorded, got: %v", alerts)
+		}
+		alert := alerts[0].(map[string]interface{})
+		if alert["type"] != "theft" || alert["muted"] != true {
+			t.Errorf("expected a muted theft alert, got: %v", alert)
+		}
+		if n := notifier.count(); n != 0 {
+			t.Errorf("expected no deliveries in maintenance mode, got: %d", n)
+		}
+
+		status := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})
+		maintenance := status["maintenance_mode"].(map[string]interface{})
+		if maintenance["enabled"] != true || maintenance["suppressed_alerts"] != 1 {
+			t.Errorf("expected get_status to report maintenance mode, got: %v", maintenance)
+		}
+		if status["monitoring_paused"].(map[string]interface{})["paused"] != false {
+			t.Errorf("expected monitoring not to be paused, got: %v", status["monitoring_paused"])
+		}
+
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "set_maintenance_mode", "enabled": false})
+		if result["ended"] != true || result["suppressed_alerts"] != 1 {
+			t.Fatalf("expected maintenance mode to end, got: %v", result)
+		}
+
+		// Delivery resumes once maintenance mode is off
+		setStocked(true)
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		setStocked(false)
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		select {
+		case <-notifier.notified:
+		case <-time.After(time.Second):
+			t.Fatal("expected the next alert to be delivered")
+		}
+
+		types := map[string]int{}
+		for _, event := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_history"})["events"].([]interface{}) {
+			types[event.(map[string]interface{})["type"].(string)]++
+		}
+		if types["maintenance_started"] != 1 || types["maintenance_ended"] != 1 {
+			t.Errorf("expected the maintenance window in history, got: %v", types)
+		}
+	})
+
+	t.Run("timed maintenance ends on its own", func(t *testing.T) {
+		svc, _, _, _ := setup(t)
+		now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		svc.now = func() time.Time { return now }
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "set_maintenance_mode", "enabled": true, "duration": "2h"})
+		if result["ends_at"] != "2026-03-01T11:00:00Z" {
+			t.Errorf("expected ends_at 11:00, got: %v", result["ends_at"])
+		}
+
+		now = now.Add(2 * time.Hour)
+		status := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["maintenance_mode"].(map[string]interface{})
+		if status["enabled"] != false {
+			t.Errorf("expected maintenance mode to have ended, got: %v", status)
+		}
+	})
+
+	t.Run("invalid arguments are rejected", func(t *testing.T) {
+		svc, _, _, _ := setup(t)
+		for _, cmd := range []map[string]interface{}{
+			{"command": "set_maintenance_mode"},
+			{"command": "set_maintenance_mode", "enabled": true, "duration": "soon"},
+			{"command": "set_maintenance_mode", "enabled": true, "duration": "-5m"},
+			{"command": "set_maintenance_mode", "enabled": false, "duration": "1h"},
+		} {
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// mergeItemFields folds source into a copy of target. Quantities are summed
+// and tags combined; for every other field the target's value wins unless it
+// is empty, in which case the source's is taken. Returns the merged item and
+// the fields filled in from the source.
+func mergeItemFields(target, source *InventoryItem) (*InventoryItem, []string) {
+	merged := target.clone()
+	merged.Quantity += source.Quantity
+
+	var filled []string
+	fillString := func(field string, dst *string, src string) {
+		if *dst == "" && src != "" {
+			*dst = src
+			filled = append(filled, field)
+		}
+	}
+	fillFloat := func(field string, dst *float64, src float64) {
+		if *dst == 0 && src != 0 {
+			*dst = src
+			filled = append(filled, field)
+		}
+	}
+	fillInt := func(field string, dst *int, src int) {
+		if *dst == 0 && src != 0 {
+			*dst = src
+			filled = append(filled, field)
+		}
+	}
+
+	fillString("location", &merged.Location, source.Location)
+	fillFloat("unit_weight", &merged.UnitWeight, source.UnitWeight)
+	fillFloat("value", &merged.Value, source.Value)
+	if merged.ExpiresAt.IsZero() && !source.ExpiresAt.IsZero() {
+		merged.ExpiresAt = source.ExpiresAt
+		filled = append(filled, "expires_at")
+	}
+	fillString("supplier", &merged.Supplier, source.Supplier)
+	fillString("supplier_sku", &merged.SupplierSKU, source.SupplierSKU)
+	fillInt("reorder_quantity", &merged.ReorderQuantity, source.ReorderQuantity)
+	fillInt("reorder_threshold", &merged.ReorderThreshold, source.ReorderThreshold)
+
+	have := make(map[string]bool, len(merged.Tags))
+	for _, tag := range merged.Tags {
+		have[tag] = true
+	}
+	for _, tag := range source.Tags {
+		if !have[tag] {
+			have[tag] = true
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+	return merged, filled
+}
+
+// handleMergeItems folds a duplicate item (source_id) into another
+// (target_id) in the same namespace and removes the duplicate. See
+// mergeItemFields for how fields combine. A merge touches two items, so it
+// isn't undoable. With regenerate_qr, a fresh code for the target is
+// returned, rendered before anything changes.
+func (s *inventoryKeeperKeeper) handleMergeItems(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	sourceID, ok := cmd["source_id"].(string)
+	if !ok || sourceID == "" {
+		return nil, errors.New("source_id is required and must be a string")
+	}
+	targetID, ok := cmd["target_id"].(string)
+	if !ok || targetID == "" {
+		return nil, errors.New("target_id is required and must be a string")
+	}
+	if sourceID == targetID {
+		return nil, fmt.Errorf("source_id and target_id must differ, got %s for both", sourceID)
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	regenerate, _ := cmd["regenerate_qr"].(bool)
+	opts, err := codeRenderArgs(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	source, exists := s.inventory[keyFor(namespace, sourceID)]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", sourceID)
+	}
+	target, exists := s.inventory[keyFor(namespace, targetID)]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", targetID)
+	}
+
+	merged, filled := mergeItemFields(target, source)
+	merged.UpdatedAt = s.now()
+
+	result := map[string]interface{}{
+		"namespace":       namespace,
+		"source_id":       sourceID,
+		"target_id":       targetID,
+		"source_quantity": source.Quantity,
+		"filled_fields":   toInterfaceSlice(filled),
+	}
+	if regenerate {
+		qrData := ItemQRData{ItemID: merged.ItemID, ItemName: merged.ItemName}
+		if namespace != defaultNamespace {
+			qrData.Namespace = namespace
+		}
+		qrCode, payload, size, err := s.renderItemCode(qrData, opts)
+		if err != nil {
+			return nil, fmt.Errorf("items were not merged: %w", err)
+		}
+		result["qr_code"] = base64.StdEncoding.EncodeToString(qrCode)
+		result["qr_data"] = payload
+		result["code_type"] = opts.CodeType
+		result["format"] = opts.responseFormat()
+		result["size"] = size
+	}
+
+	s.inventory[merged.key()] = merged
+	delete(s.inventory, source.key())
+
+	s.recordHistoryLocked("items_merged", namespace, targetID, map[string]interface{}{
+		"source_id":       sourceID,
+		"source_name":     source.ItemName,
+		"source_quantity": source.Quantity,
+		"target_quantity": target.Quantity,
+		"merged_quantity": merged.Quantity,
+		"filled_fields":   toInterfaceSlice(filled),
+	})
+
+	s.logger.Infof("Merged item %s into %s (quantity %d)", sourceID, targetID, merged.Quantity)
+	result["item"] = merged.toMap(s.location)
+	return result, nil
+}
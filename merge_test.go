@@ -0,0 +1,88 @@
+package inventorykeeper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMergeItems(t *testing.T) {
+	ctx := context.Background()
+
+	// setup stocks the same apples twice under different ids
+	setup := func(t *testing.T) *inventoryKeeperKeeper {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple", "quantity": 4, "tags": []interface{}{"fruit"},
+		})
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "apple-dup", "item_name": "Apple (dup)", "quantity": 3,
+			"location": "aisle-3", "tags": []interface{}{"fruit", "organic"}, "supplier": "Orchard Co",
+		})
+		return svc
+	}
+
+	t.Run("quantities combine and the source is removed", func(t *testing.T) {
+		svc := setup(t)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "merge_items", "source_id": "apple-dup", "target_id": "apple-001"})
+
+		item := result["item"].(map[string]interface{})
+		if item["quantity"] != 7 || item["item_name"] != "Honeycrisp Apple" {
+			t.Errorf("expected 7 Honeycrisp Apples, got: %v", item)
+		}
+		// Target fields win; empty ones are filled from the source
+		if item["location"] != "aisle-3" || item["supplier"] != "Orchard Co" {
+			t.Errorf("expected location and supplier filled from the source, got: %v", item)
+		}
+		if tags := item["tags"].([]interface{}); !reflect.DeepEqual(tags, []interface{}{"fruit", "organic"}) {
+			t.Errorf("expected combined tags, got: %v", tags)
+		}
+
+		items := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})["items"].([]interface{})
+		if len(items) != 1 || items[0].(map[string]interface{})["item_id"] != "apple-001" {
+			t.Errorf("expected only apple-001 left, got: %v", items)
+		}
+
+		var merged map[string]interface{}
+		for _, raw := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_history"})["events"].([]interface{}) {
+			if event := raw.(map[string]interface{}); event["type"] == "items_merged" {
+				merged = event
+			}
+		}
+		if merged == nil {
+			t.Fatal("expected an items_merged history event")
+		}
+		details := merged["details"].(map[string]interface{})
+		if merged["item_id"] != "apple-001" || details["source_id"] != "apple-dup" || details["merged_quantity"] != 7 {
+			t.Errorf("unexpected merge event: %v", merged)
+		}
+	})
+
+	t.Run("regenerate_qr returns a code for the target", func(t *testing.T) {
+		svc := setup(t)
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "merge_items", "source_id": "apple-dup", "target_id": "apple-001", "regenerate_qr": true,
+		})
+		data, err := svc.decodeQRPayload(result["qr_data"].(string))
+		if err != nil || data.ItemID != "apple-001" || result["qr_code"] == "" {
+			t.Errorf("expected a code for apple-001, got: %v (%v)", data, err)
+		}
+	})
+
+	t.Run("missing or identical ids are rejected", func(t *testing.T) {
+		svc := setup(t)
+		for _, cmd := range []map[string]interface{}{
+			{"command": "merge_items", "source_id": "pear-009", "target_id": "apple-001"},
+			{"command": "merge_items", "source_id": "apple-dup", "target_id": "pear-009"},
+			{"command": "merge_items", "source_id": "apple-001", "target_id": "apple-001"},
+			{"command": "merge_items", "target_id": "apple-001"},
+		} {
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+		if count := len(mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})["items"].([]interface{})); count != 2 {
+			t.Errorf("expected both items untouched, got %d", count)
+		}
+	})
+}
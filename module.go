@@ -3,18 +3,22 @@ package inventorykeeper
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/skip2/go-qrcode"
 	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/input"
+	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	generic "go.viam.com/rdk/services/generic"
 	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/vision/objectdetection"
 )
 
 var (
@@ -25,8 +29,20 @@ var (
 // ItemQRData represents the data encoded in a QR code for an inventory item
 // Fields are added only as features require them - start minimal
 type ItemQRData struct {
-	ItemID   string `json:"item_id"`
-	ItemName string `json:"item_name"`
+	V         int    `json:"v,omitempty"`         // Payload schema version; labels printed before versioning omit it and are v1
+	Namespace string `json:"namespace,omitempty"` // Omitted for the default namespace
+	ItemID    string `json:"item_id"`
+	ItemName  string `json:"item_name"`
+	Quantity  int    `json:"quantity,omitempty"` // Units the label stands for, e.g. a case of 12 (omitted for one unit)
+	Sig       string `json:"sig,omitempty"`      // HMAC-SHA256 signature (only when signing_secret is configured)
+}
+
+// units returns how many units the label stands for, at least one
+func (d ItemQRData) units() int {
+	if d.Quantity > 1 {
+		return d.Quantity
+	}
+	return 1
 }
 
 // DetectedQRCode tracks a QR code that's currently visible in the camera view
@@ -55,12 +71,80 @@ type Config struct {
 	// Vision service for QR detection
 	QRVisionService string `json:"qr_vision_service"`
 
+	// Sensor whose "weight" reading is the shelf weight (optional)
+	// - empty: weight-based inventory disabled
+	// - set: read_weight estimates quantities from per-item unit_weight
+	ScaleSensor string `json:"scale_sensor,omitempty"`
+
+	// Vision service that detects stocked items, for shelf_fullness (optional)
+	// - empty: fullness is estimated from QR label boxes
+	// - set: fullness is the share of the shelf covered by its detections
+	FullnessVisionService string `json:"fullness_vision_service,omitempty"`
+
+	// Vision service that recognizes faces, labeling each detection with the
+	// person's name (optional)
+	// - empty: face recognition disabled
+	// - face_camera_name: camera faces are detected from (default: camera_name)
+	// - face_confidence: lowest score accepted as a recognized person, in
+	//   (0, 1] (default 0.8)
+	// When items leave the shelf, the face camera is checked and a removal
+	// seen by a person authorized in the item's namespace (see
+	// namespace_authorized_people) is attributed to them rather than alerted.
+	FaceVisionService string   `json:"face_vision_service,omitempty"`
+	FaceCameraName    string   `json:"face_camera_name,omitempty"`
+	FaceConfidence    *float64 `json:"face_confidence,omitempty"`
+
+	// Gate removals on a recognized person (optional, needs face_vision_service)
+	// - false (default): remove_item and check-ins are trusted as sent
+	// - true: remove_item, check_in, and check_in_batch fail with UNAUTHORIZED
+	//   unless an authorized person is recognized, and record who it was
+	// - authorized_people: names that may authorize; empty allows anyone the
+	//   face service recognizes
+	RequireFaceForRemoval bool     `json:"require_face_for_removal,omitempty"`
+	AuthorizedPeople      []string `json:"authorized_people,omitempty"`
+
+	// People whose removals from a namespace are attributed rather than
+	// alerted, keyed by namespace (optional)
+	// - a namespace listed here uses its own names instead of authorized_people
+	// - other namespaces use authorized_people; with neither, a recognized
+	//   face never excuses a removal
+	NamespaceAuthorizedPeople map[string][]string `json:"namespace_authorized_people,omitempty"`
+
+	// Statuses set_item_status accepts (optional)
+	// - empty: "active", "damaged", "quarantined", and "on_hold"
+	// - otherwise: the listed statuses; "active", every item's default, is
+	//   always allowed
+	AllowedStatuses []string `json:"allowed_statuses,omitempty"`
+
 	// Scan interval in milliseconds (optional)
 	// - nil: defaults to 1000ms, monitoring enabled
 	// - 0: monitoring explicitly disabled (useful for tests)
 	// - positive value: custom interval, monitoring enabled
 	ScanIntervalMs *int `json:"scan_interval_ms,omitempty"`
 
+	// Scan interval in seconds, instead of scan_interval_ms (optional)
+	// - nil: scan_interval_ms applies
+	// - 0: monitoring explicitly disabled
+	// - positive value: custom interval of at least 0.001, monitoring enabled
+	// Setting both is an error.
+	PollIntervalSeconds *float64 `json:"poll_interval_seconds,omitempty"`
+
+	// Random delay added to each scan interval, in seconds (optional)
+	// - nil or 0: scans run exactly on the interval
+	// - positive value: each scan waits the interval plus up to this much more,
+	//   so keepers sharing a camera backend don't capture in lockstep
+	// Consecutive failed scans also double the wait, up to one minute.
+	ScanIntervalJitterSeconds *float64 `json:"scan_interval_jitter_seconds,omitempty"`
+
+	// Item field limits, applied by add_item, rename_item, and generate_qr (optional)
+	// - max_item_name_length / max_item_id_length: nil defaults to 128 characters
+	// - item_name_pattern / item_id_pattern: regex the whole value must match;
+	//   empty allows any characters
+	MaxItemNameLength *int   `json:"max_item_name_length,omitempty"`
+	MaxItemIDLength   *int   `json:"max_item_id_length,omitempty"`
+	ItemNamePattern   string `json:"item_name_pattern,omitempty"`
+	ItemIDPattern     string `json:"item_id_pattern,omitempty"`
+
 	// Grace period in milliseconds before considering a QR code truly disappeared (optional)
 	// - nil: defaults to 2000ms (2 seconds)
 	// - 0: no grace period, immediate removal
@@ -68,11 +152,351 @@ type Config struct {
 	// This prevents false "disappeared" events from temporary detection failures
 	GracePeriodMs *int `json:"grace_period_ms,omitempty"`
 
-	// Future config fields will be added incrementally as features are implemented:
-	// - Vision service for facial recognition
-	// - Face camera for person detection
-	// - Optional integrations (streamdeck, slack_webhook_url)
-	// - Timing configuration (check_in_delay_seconds, theft_alert_delay_seconds)
+	// Consecutive scans required to change an item's presence (optional)
+	// - nil: defaults to 2
+	// - positive value: an item joins the present-set after this many consecutive
+	//   detections and leaves it after this many consecutive misses
+	PresenceDebounceScans *int `json:"presence_debounce_scans,omitempty"`
+
+	// Deadline in seconds for commands that talk to the camera, vision service,
+	// scale, or notification integrations (optional)
+	// - nil: defaults to 30 seconds
+	// - 0: no extra deadline beyond the caller's context
+	// - positive value: custom deadline (fractional seconds allowed)
+	CommandTimeoutSeconds *float64 `json:"command_timeout_seconds,omitempty"`
+
+	// Input size limits; arguments over them fail with PAYLOAD_TOO_LARGE
+	// before anything is decoded (optional)
+	// - max_image_bytes: decoded size of a base64 image (nil: 10 MiB)
+	// - max_batch_items: entries in a list such as item_ids or images (nil: 500)
+	// - max_import_items: inventory items or history events in a snapshot
+	//   passed to restore_snapshot or diff_snapshot (nil: 10000)
+	MaxImageBytes  *int `json:"max_image_bytes,omitempty"`
+	MaxBatchItems  *int `json:"max_batch_items,omitempty"`
+	MaxImportItems *int `json:"max_import_items,omitempty"`
+
+	// Retries for a failed camera capture within one scan (optional)
+	// - nil: defaults to 2 retries, with a short backoff between attempts
+	// - 0: no retry, a failed capture skips the scan
+	// - positive value: custom retry count
+	CaptureRetries *int `json:"capture_retries,omitempty"`
+
+	// Frames to grab and discard before using one, while the camera is cold (optional)
+	// - 0 (default): the first frame is used
+	// - positive value: this many frames are discarded before the first
+	//   capture and after the camera has been idle for a minute, for cameras
+	//   whose first frames are black or overexposed
+	CameraWarmupFrames int `json:"camera_warmup_frames,omitempty"`
+
+	// Response when scans keep failing because the camera or vision service
+	// is gone or restarting (optional)
+	// - empty or "retry": keep scanning, backing off the interval
+	// - "degrade": after 3 failed scans, probe only every minute and report
+	//   dependencies as degraded until a scan succeeds
+	// - "refetch": every 3 failed scans, re-resolve the camera and vision
+	//   service from the keeper's dependencies
+	DependencyFailurePolicy string `json:"dependency_failure_policy,omitempty"`
+
+	// Resolution when labels in one frame share an item_id but carry
+	// different item_names, e.g. after a reprint drifted (optional)
+	// - empty or "first-wins": keep the name the item is already tracked
+	//   under, or the first label's name for a newly seen item
+	// - "inventory-wins": use the item's name in inventory, falling back to
+	//   first-wins for items not in inventory
+	// - "flag": leave the item's present-set entry unchanged for that scan
+	//   and raise a name_conflict warning alert
+	// Conflicts are always logged and reported by get_conflicts.
+	NameConflictPolicy string `json:"name_conflict_policy,omitempty"`
+
+	// How long a check-in authorizes removing the checked-in items (optional)
+	// - nil: defaults to 60 seconds
+	// - positive value: custom window
+	// Removing an item from the shelf outside a check-in window raises a theft alert.
+	CheckInWindowSeconds *int `json:"check_in_window_seconds,omitempty"`
+
+	// Seconds after any check-in during which all theft alerts are suppressed (optional)
+	// - nil or 0: no grace, only per-item check-ins authorize removal
+	// - positive value: removals this soon after the latest check-in are not alerted
+	GraceAfterCheckInSeconds *int `json:"grace_after_checkin_seconds,omitempty"`
+
+	// Seconds an item must be gone from the present-set before it counts as
+	// checked out (optional)
+	// - nil: defaults to 60 seconds
+	// - 0: checked out on the first scan after it leaves
+	// Items back in view before then return to present; list_checked_out
+	// reports the rest.
+	CheckInDelaySeconds *int `json:"check_in_delay_seconds,omitempty"`
+
+	// Seconds after a removal that a check-in can still authorize it (optional)
+	// - nil or 0: a removal without a prior check-in alerts at once
+	// - positive value: the theft alert waits this long and is dropped if the
+	//   item is checked in or put back meanwhile
+	TheftAlertDelaySeconds *int `json:"theft_alert_delay_seconds,omitempty"`
+
+	// Seconds after the first monitoring scan before theft detection starts (optional)
+	// - nil or 0: removals are checked from the first scan
+	// - positive value: scans build the present-set but raise no theft
+	//   alerts until this long has passed, so boot doesn't alert spuriously
+	StartupGraceSeconds *int `json:"startup_grace_seconds,omitempty"`
+
+	// JSON file the inventory and history are saved to (optional)
+	// - empty: inventory lives in memory only
+	// - set: loaded at startup and saved after every change; each save is
+	//   verified by re-reading it before it replaces the previous file
+	StoragePath string `json:"storage_path,omitempty"`
+
+	// Previous versions of storage_path kept as storage_path.1, .2, ... (optional)
+	// - nil: defaults to 3
+	// - 0: no backups
+	// - positive value: custom count, rotated on each successful save
+	StorageBackups *int `json:"storage_backups,omitempty"`
+
+	// Where inventory items are kept (optional)
+	// - empty: "file" when storage_path is set, otherwise "memory"
+	// - "memory": in process only
+	// - "file": in process, saved to storage_path (required)
+	// - "redis": a Redis hash shared by every keeper pointed at it; items are
+	//   loaded from it at startup and again before each command and scan, and
+	//   changed items are written back after each command. History stays
+	//   local, saved to storage_path if set.
+	// - "bolt": an embedded bbolt database at db_path (required), with one
+	//   record per item; items are loaded from it at startup and each
+	//   command writes only the items it changed. An empty database starts
+	//   from storage_path's inventory. History stays in storage_path if set.
+	StoreBackend string `json:"store_backend,omitempty"`
+
+	// Database file for store_backend "bolt" (optional)
+	// - empty: no database
+	// - set: created if missing; store_backend defaults to "bolt". Only one
+	//   keeper can have it open at a time.
+	DBPath string `json:"db_path,omitempty"`
+
+	// Redis connection for store_backend "redis"
+	// - redis_address: host:port (required)
+	// - redis_password: sent with AUTH when set
+	// - redis_db: database number (default 0)
+	// - redis_key: hash holding the items (default "inventory-keeper:items")
+	RedisAddress  string `json:"redis_address,omitempty"`
+	RedisPassword string `json:"redis_password,omitempty"`
+	RedisDB       int    `json:"redis_db,omitempty"`
+	RedisKey      string `json:"redis_key,omitempty"`
+
+	// File to append theft detector events to as JSON lines (optional)
+	// - empty: events are not logged
+	// - set: every check-in and removal is appended; replay_events re-runs the file
+	EventLogFile string `json:"event_log_file,omitempty"`
+
+	// Interval in seconds between automatic inventory audits (optional)
+	// - nil or 0: no scheduled audit (the audit command still works)
+	// - positive value: reconcile inventory against the present-set on this interval
+	AuditIntervalSeconds *int `json:"audit_interval_seconds,omitempty"`
+
+	// Raise an alert for each new discrepancy an audit finds (optional)
+	AuditAlerts bool `json:"audit_alerts,omitempty"`
+
+	// Alert when a perishable item comes within this long of its expires_at,
+	// as a duration string such as "48h" (optional)
+	// - empty: no expiry alerts (expiring_soon still works)
+	// - set: checked every minute; each item is alerted once per expiry
+	ExpiryAlertWithin string `json:"expiry_alert_within,omitempty"`
+
+	// Raise an alert when a background scan sees one item_id on more than one
+	// label in the same frame (optional; duplicates are always logged)
+	DuplicateAlerts bool `json:"duplicate_alerts,omitempty"`
+
+	// Raise an info alert when an item is checked out or returned (optional;
+	// transitions are always logged), e.g. to post them to Slack
+	CheckoutAlerts bool `json:"checkout_alerts,omitempty"`
+
+	// Skip decoding a frame identical to the previous one (optional)
+	// - false: every scan calls the vision service
+	// - true: frames are captured locally and hashed; an exact repeat reuses
+	//   the previous detections and counts as skipped in get_scan_stats
+	SkipDuplicateFrames bool `json:"skip_duplicate_frames,omitempty"`
+
+	// Decode QR codes locally when the vision service fails (optional)
+	// - false: a vision failure fails the scan
+	// - true: the frame is captured and decoded with a pure-Go decoder;
+	//   results are marked source "local" and get_status reports the fallback
+	LocalDecodeFallback bool `json:"local_decode_fallback,omitempty"`
+
+	// Allow the benchmark_scan command (optional)
+	// - false: benchmark_scan is refused, so a production device can't be
+	//   loaded by accident
+	// - true: benchmark_scan times the decode pipeline on demand
+	EnableBenchmark bool `json:"enable_benchmark,omitempty"`
+
+	// Symbologies to scan for (optional)
+	// - empty: QR codes only, via the vision service
+	// - any of "qr", "ean13", "upca", "code128": barcodes are decoded locally
+	//   from the captured frame and their value looked up with sku_map, then
+	//   inventory item IDs and supplier SKUs, else taken as the item ID
+	CodeTypes []string `json:"code_types,omitempty"`
+
+	// Lookup table from barcode or plain-text SKU to item_id (optional)
+	// - empty: SKUs are matched against item IDs and supplier_sku only
+	// - entries: a scanned SKU listed here maps to that item in the default
+	//   namespace, ahead of any other match
+	SKUMap map[string]string `json:"sku_map,omitempty"`
+
+	// Number of recent background scans get_scan_stats summarizes (optional)
+	// - nil: defaults to 100
+	// - positive value: custom window
+	ScanStatsWindow *int `json:"scan_stats_window,omitempty"`
+
+	// Number of raw decoded payloads get_recent_decodes keeps (optional)
+	// - nil: defaults to 50
+	// - 0: nothing is kept
+	// - positive value: custom size
+	RecentDecodes *int `json:"recent_decodes,omitempty"`
+
+	// Vision service calls allowed in flight at once, shared by background
+	// scans and commands (optional)
+	// - nil: defaults to 2
+	// - positive value: custom limit; further calls wait for a free slot
+	MaxConcurrentVisionCalls *int `json:"max_concurrent_vision_calls,omitempty"`
+
+	// Fail vision calls beyond max_concurrent_vision_calls with a BUSY error
+	// instead of waiting (optional)
+	VisionBusyFailFast bool `json:"vision_busy_fail_fast,omitempty"`
+
+	// Seconds during which a repeat of an item's alert of the same type is
+	// dropped (optional)
+	// - nil or 0: every alert is raised
+	// - positive value: custom cooldown; with storage_path the cooldowns and
+	//   alert ids are saved to storage_path.alerts and survive rebuilds
+	AlertCooldownSeconds *int `json:"alert_cooldown_seconds,omitempty"`
+
+	// Lowest alert severity sent to notifiers (optional)
+	// - empty: defaults to "info", every alert is sent
+	// - "warning" or "critical": quieter alerts are still recorded for
+	//   get_alerts/poll_alerts but not sent
+	MinAlertSeverity string `json:"min_alert_severity,omitempty"`
+
+	// Alerts queued for each notifier awaiting delivery (optional)
+	// - nil: defaults to 100
+	// - positive value: custom queue size
+	// Raising an alert never waits on a notifier; when its queue is full an
+	// alert is dropped per event_overflow_policy and counted in
+	// integrations_status and get_status.
+	EventBufferSize *int `json:"event_buffer_size,omitempty"`
+
+	// Which alert a full notifier queue drops (optional)
+	// - empty or "drop_oldest": the longest-queued alert, so the latest are sent
+	// - "drop_newest": the alert being raised
+	EventOverflowPolicy string `json:"event_overflow_policy,omitempty"`
+
+	// Maximum number of inventory mutations that can be undone (optional)
+	// - nil: defaults to 10
+	// - 0: undo disabled
+	// - positive value: custom depth
+	MaxUndo *int `json:"max_undo,omitempty"`
+
+	// Cap on the number of inventory items across all namespaces (optional)
+	// - nil: unbounded
+	// - positive value: add_item applies inventory_eviction once this many items exist
+	// - inventory_eviction: "reject" (default) fails the add; "evict_lru" removes
+	//   the least-recently-updated item to make room
+	MaxInventoryItems *int   `json:"max_inventory_items,omitempty"`
+	InventoryEviction string `json:"inventory_eviction,omitempty"`
+
+	// Deflate QR payloads before encoding them (optional)
+	// - false: payloads are stored as the codec produced them
+	// - true: payloads are compressed and marked "ikz:" whenever that makes
+	//   them shorter, keeping rich payloads scannable at small sizes
+	// Compressed payloads are decoded either way.
+	CompressPayload bool `json:"compress_payload,omitempty"`
+
+	// AES-256 key for encrypting QR payloads, base64-encoded (optional)
+	// - empty: payloads are plaintext JSON
+	// - set: must decode to exactly 32 bytes; generated payloads are encrypted
+	//   with AES-GCM and scanned payloads are decrypted with the same key
+	EncryptionKey string `json:"encryption_key,omitempty"`
+
+	// Encoding for QR payloads (optional)
+	// - empty: defaults to "json"
+	// - set: must name a registered PayloadCodec
+	PayloadCodec string `json:"payload_codec,omitempty"`
+
+	// Shared secret for HMAC-signing QR payloads (optional)
+	// - empty: payloads are unsigned
+	// - set: generated payloads include a "sig" field that verify_qr can check
+	SigningSecret string `json:"signing_secret,omitempty"`
+
+	// Shared secret DoCommand callers must send as "auth" (optional)
+	// - empty: every command is open
+	// - set: mutating commands (add_item, set_config, undo, ...) without a
+	//   matching auth field fail with UNAUTHORIZED
+	// - auth_all_commands: require it for every command except ping
+	CommandSecret   string `json:"command_secret,omitempty"`
+	AuthAllCommands bool   `json:"auth_all_commands,omitempty"`
+
+	// Image preprocessing applied to frames before QR decoding (optional)
+	// - rotate_degrees: clockwise rotation, one of 0, 90, 180, 270
+	// - grayscale: convert frames to luminance
+	// - contrast_stretch: rescale pixel values to the full 0-255 range
+	// When any is set, frames are captured from the camera by the keeper and
+	// passed to the vision service rather than letting it capture directly.
+	RotateDegrees   int  `json:"rotate_degrees,omitempty"`
+	Grayscale       bool `json:"grayscale,omitempty"`
+	ContrastStretch bool `json:"contrast_stretch,omitempty"`
+
+	// Region of the preprocessed frame to scan (optional)
+	// - nil: the whole frame is scanned
+	// - set: frames are cropped to x, y, width, height before detection, as
+	//   fractions of the frame (default) or with units "pixels"
+	// Like preprocessing, this makes the keeper capture frames itself.
+	ROI *RegionOfInterest `json:"roi,omitempty"`
+
+	// IANA time zone for timestamps in history, alert, and status responses
+	// (optional), e.g. "America/New_York"
+	// - empty: UTC
+	// - set: timestamps are shown in this zone with its offset; stored times
+	//   and snapshots stay in UTC
+	Timezone string `json:"timezone,omitempty"`
+
+	// Log line format (optional)
+	// - "text" (default): human-readable messages
+	// - "json": each message is a JSON object with "msg" and its fields
+	//   (command, item_id, event, ...); secret fields are redacted
+	LogFormat string `json:"log_format,omitempty"`
+
+	// Frame format requested from the camera (optional)
+	// - empty: "image/jpeg"
+	// - "image/png" or "image/vnd.viam.rgba": requested instead
+	// Cameras may ignore the hint; frames are decoded by the type they report.
+	CaptureMimeType string `json:"capture_mime_type,omitempty"`
+
+	// SMTP email notifications for alerts (optional)
+	// - smtp_host empty: email disabled
+	// - set: smtp_host, smtp_port, and alert_email_to are required;
+	//   smtp_username/smtp_password enable PLAIN auth
+	// - alert_email_to: comma-separated recipients
+	SMTPHost     string `json:"smtp_host,omitempty"`
+	SMTPPort     int    `json:"smtp_port,omitempty"`
+	SMTPUsername string `json:"smtp_username,omitempty"`
+	SMTPPassword string `json:"smtp_password,omitempty"`
+	AlertEmailTo string `json:"alert_email_to,omitempty"`
+
+	// Slack incoming webhook alerts are posted to (optional)
+	// - empty: Slack disabled
+	// - set: an https URL; failed posts are retried with backoff
+	SlackWebhookURL string `json:"slack_webhook_url,omitempty"`
+
+	// Further alert destinations (optional), each {type, name, url, headers,
+	// alert_types, min_severity} with type "slack", "webhook", or "log".
+	// Entries filter alerts already past min_alert_severity, so several can
+	// split alert types between channels.
+	Notifiers []NotifierConfig `json:"notifiers,omitempty"`
+
+	// Input controller whose buttons run commands, e.g. a Stream Deck
+	// (optional; requires button_commands)
+	ButtonController string `json:"button_controller,omitempty"`
+
+	// Command each button runs, keyed by control name, e.g.
+	// {"ButtonSouth": {"command": "scan_shelf"}} (optional). Commands run as
+	// if sent with command_secret, so they must not set auth.
+	ButtonCommands map[string]map[string]interface{} `json:"button_commands,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid and important fields exist.
@@ -101,33 +525,566 @@ func (cfg *Config) Validate(path string) ([]string, []string, error) {
 		return nil, nil, fmt.Errorf("scan_interval_ms must be non-negative, got: %d", *cfg.ScanIntervalMs)
 	}
 
+	// Validate poll_interval_seconds if provided
+	if cfg.PollIntervalSeconds != nil {
+		if *cfg.PollIntervalSeconds < 0 {
+			return nil, nil, fmt.Errorf("poll_interval_seconds must be non-negative, got: %v", *cfg.PollIntervalSeconds)
+		}
+		// Anything shorter would truncate to a zero interval and spin the loop
+		if poll := *cfg.PollIntervalSeconds; poll > 0 && poll < time.Millisecond.Seconds() {
+			return nil, nil, fmt.Errorf("poll_interval_seconds must be 0 or at least 0.001, got: %v", poll)
+		}
+		if cfg.ScanIntervalMs != nil {
+			return nil, nil, errors.New("set only one of scan_interval_ms and poll_interval_seconds")
+		}
+	}
+
+	// Validate scan_interval_jitter_seconds if provided
+	if cfg.ScanIntervalJitterSeconds != nil && *cfg.ScanIntervalJitterSeconds < 0 {
+		return nil, nil, fmt.Errorf("scan_interval_jitter_seconds must be non-negative, got: %v", *cfg.ScanIntervalJitterSeconds)
+	}
+
 	// Validate grace_period_ms if provided
 	if cfg.GracePeriodMs != nil && *cfg.GracePeriodMs < 0 {
 		return nil, nil, fmt.Errorf("grace_period_ms must be non-negative, got: %d", *cfg.GracePeriodMs)
 	}
 
-	// Return both camera and QR vision service as required dependencies
+	// Validate face recognition settings if provided
+	if cfg.FaceConfidence != nil && (*cfg.FaceConfidence <= 0 || *cfg.FaceConfidence > 1) {
+		return nil, nil, fmt.Errorf("face_confidence must be in (0, 1], got: %v", *cfg.FaceConfidence)
+	}
+	if cfg.RequireFaceForRemoval && cfg.FaceVisionService == "" {
+		return nil, nil, errors.New("require_face_for_removal requires face_vision_service")
+	}
+	for _, name := range cfg.AuthorizedPeople {
+		if strings.TrimSpace(name) == "" {
+			return nil, nil, errors.New("authorized_people must not contain empty names")
+		}
+	}
+	for namespace, names := range cfg.NamespaceAuthorizedPeople {
+		if err := validateNamespace(namespace); err != nil {
+			return nil, nil, fmt.Errorf("namespace_authorized_people: %w", err)
+		}
+		for _, name := range names {
+			if strings.TrimSpace(name) == "" {
+				return nil, nil, fmt.Errorf("namespace_authorized_people[%q] must not contain empty names", namespace)
+			}
+		}
+	}
+
+	// Validate allowed_statuses if provided
+	if err := validateAllowedStatuses(cfg.AllowedStatuses); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate item field limits if provided
+	if cfg.MaxItemNameLength != nil && *cfg.MaxItemNameLength < 1 {
+		return nil, nil, fmt.Errorf("max_item_name_length must be at least 1, got: %d", *cfg.MaxItemNameLength)
+	}
+	if cfg.MaxItemIDLength != nil && *cfg.MaxItemIDLength < 1 {
+		return nil, nil, fmt.Errorf("max_item_id_length must be at least 1, got: %d", *cfg.MaxItemIDLength)
+	}
+	if _, err := compileFieldPattern("item_name_pattern", cfg.ItemNamePattern); err != nil {
+		return nil, nil, err
+	}
+	if _, err := compileFieldPattern("item_id_pattern", cfg.ItemIDPattern); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate presence_debounce_scans if provided
+	if cfg.PresenceDebounceScans != nil && *cfg.PresenceDebounceScans < 1 {
+		return nil, nil, fmt.Errorf("presence_debounce_scans must be at least 1, got: %d", *cfg.PresenceDebounceScans)
+	}
+
+	// Validate input size limits if provided
+	for _, limit := range []struct {
+		name  string
+		value *int
+	}{
+		{"max_image_bytes", cfg.MaxImageBytes},
+		{"max_batch_items", cfg.MaxBatchItems},
+		{"max_import_items", cfg.MaxImportItems},
+	} {
+		if limit.value != nil && *limit.value < 1 {
+			return nil, nil, fmt.Errorf("%s must be at least 1, got: %d", limit.name, *limit.value)
+		}
+	}
+
+	// Validate command_timeout_seconds if provided
+	if cfg.CommandTimeoutSeconds != nil && *cfg.CommandTimeoutSeconds < 0 {
+		return nil, nil, fmt.Errorf("command_timeout_seconds must be non-negative, got: %v", *cfg.CommandTimeoutSeconds)
+	}
+
+	// Validate capture_retries if provided
+	if cfg.CaptureRetries != nil && *cfg.CaptureRetries < 0 {
+		return nil, nil, fmt.Errorf("capture_retries must be non-negative, got: %d", *cfg.CaptureRetries)
+	}
+
+	// Validate camera_warmup_frames if provided
+	if cfg.CameraWarmupFrames < 0 || cfg.CameraWarmupFrames > maxCameraWarmupFrames {
+		return nil, nil, fmt.Errorf("camera_warmup_frames must be between 0 and %d, got: %d", maxCameraWarmupFrames, cfg.CameraWarmupFrames)
+	}
+
+	// Validate check_in_window_seconds if provided
+	if cfg.CheckInWindowSeconds != nil && *cfg.CheckInWindowSeconds < 1 {
+		return nil, nil, fmt.Errorf("check_in_window_seconds must be at least 1, got: %d", *cfg.CheckInWindowSeconds)
+	}
+
+	// Validate grace_after_checkin_seconds if provided
+	if cfg.GraceAfterCheckInSeconds != nil && *cfg.GraceAfterCheckInSeconds < 0 {
+		return nil, nil, fmt.Errorf("grace_after_checkin_seconds must be non-negative, got: %d", *cfg.GraceAfterCheckInSeconds)
+	}
+
+	// Validate check_in_delay_seconds if provided
+	if cfg.CheckInDelaySeconds != nil && *cfg.CheckInDelaySeconds < 0 {
+		return nil, nil, fmt.Errorf("check_in_delay_seconds must be non-negative, got: %d", *cfg.CheckInDelaySeconds)
+	}
+
+	// Validate theft_alert_delay_seconds if provided
+	if cfg.TheftAlertDelaySeconds != nil && *cfg.TheftAlertDelaySeconds < 0 {
+		return nil, nil, fmt.Errorf("theft_alert_delay_seconds must be non-negative, got: %d", *cfg.TheftAlertDelaySeconds)
+	}
+
+	// Validate startup_grace_seconds if provided
+	if cfg.StartupGraceSeconds != nil && *cfg.StartupGraceSeconds < 0 {
+		return nil, nil, fmt.Errorf("startup_grace_seconds must be non-negative, got: %d", *cfg.StartupGraceSeconds)
+	}
+
+	// Validate audit_interval_seconds if provided
+	if cfg.AuditIntervalSeconds != nil && *cfg.AuditIntervalSeconds < 0 {
+		return nil, nil, fmt.Errorf("audit_interval_seconds must be non-negative, got: %d", *cfg.AuditIntervalSeconds)
+	}
+
+	// Validate store_backend if provided
+	if err := cfg.validateStoreBackend(); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate storage_backups if provided
+	if cfg.StorageBackups != nil && *cfg.StorageBackups < 0 {
+		return nil, nil, fmt.Errorf("storage_backups must be non-negative, got: %d", *cfg.StorageBackups)
+	}
+
+	// Validate scan_stats_window if provided
+	if cfg.ScanStatsWindow != nil && *cfg.ScanStatsWindow < 1 {
+		return nil, nil, fmt.Errorf("scan_stats_window must be at least 1, got: %d", *cfg.ScanStatsWindow)
+	}
+
+	// Validate recent_decodes if provided
+	if cfg.RecentDecodes != nil && *cfg.RecentDecodes < 0 {
+		return nil, nil, fmt.Errorf("recent_decodes must be non-negative, got: %d", *cfg.RecentDecodes)
+	}
+
+	// Validate max_concurrent_vision_calls if provided
+	if cfg.MaxConcurrentVisionCalls != nil && *cfg.MaxConcurrentVisionCalls < 1 {
+		return nil, nil, fmt.Errorf("max_concurrent_vision_calls must be at least 1, got: %d", *cfg.MaxConcurrentVisionCalls)
+	}
+
+	// Validate expiry_alert_within if provided
+	if _, err := cfg.expiryAlertWindow(); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate dependency_failure_policy if provided
+	if cfg.DependencyFailurePolicy != "" && !validDependencyPolicies[cfg.DependencyFailurePolicy] {
+		return nil, nil, fmt.Errorf("dependency_failure_policy must be %q, %q, or %q, got: %q", dependencyRetry, dependencyDegrade, dependencyRefetch, cfg.DependencyFailurePolicy)
+	}
+
+	// Validate name_conflict_policy if provided
+	if cfg.NameConflictPolicy != "" && !validNameConflictPolicies[cfg.NameConflictPolicy] {
+		return nil, nil, fmt.Errorf("name_conflict_policy must be %q, %q, or %q, got: %q", nameConflictFirstWins, nameConflictInventoryWins, nameConflictFlag, cfg.NameConflictPolicy)
+	}
+
+	// Validate alert_cooldown_seconds if provided
+	if cfg.AlertCooldownSeconds != nil && *cfg.AlertCooldownSeconds < 0 {
+		return nil, nil, fmt.Errorf("alert_cooldown_seconds must be non-negative, got: %d", *cfg.AlertCooldownSeconds)
+	}
+
+	// Validate max_undo if provided
+	if cfg.MaxUndo != nil && *cfg.MaxUndo < 0 {
+		return nil, nil, fmt.Errorf("max_undo must be non-negative, got: %d", *cfg.MaxUndo)
+	}
+
+	// Validate inventory size cap if provided
+	if cfg.MaxInventoryItems != nil && *cfg.MaxInventoryItems < 1 {
+		return nil, nil, fmt.Errorf("max_inventory_items must be at least 1, got: %d", *cfg.MaxInventoryItems)
+	}
+	if cfg.InventoryEviction != "" && !validEvictionPolicies[cfg.InventoryEviction] {
+		return nil, nil, fmt.Errorf("inventory_eviction must be %q or %q, got: %q", evictionReject, evictionLRU, cfg.InventoryEviction)
+	}
+
+	// Validate min_alert_severity if provided
+	if cfg.MinAlertSeverity != "" {
+		if err := validateSeverity("min_alert_severity", cfg.MinAlertSeverity); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Validate notifier queue settings if provided
+	if cfg.EventBufferSize != nil && *cfg.EventBufferSize < 1 {
+		return nil, nil, fmt.Errorf("event_buffer_size must be at least 1, got: %d", *cfg.EventBufferSize)
+	}
+	if cfg.EventOverflowPolicy != "" && !validOverflowPolicies[cfg.EventOverflowPolicy] {
+		return nil, nil, fmt.Errorf("event_overflow_policy must be %q or %q, got: %q", overflowDropOldest, overflowDropNewest, cfg.EventOverflowPolicy)
+	}
+
+	// Validate rotate_degrees
+	if !validRotations[cfg.RotateDegrees] {
+		return nil, nil, fmt.Errorf("rotate_degrees must be one of 0, 90, 180, 270, got: %d", cfg.RotateDegrees)
+	}
+
+	// Validate code_types if provided
+	if err := validateCodeTypes(cfg.CodeTypes); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate sku_map if provided
+	if err := validateSKUMap(cfg.SKUMap); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate roi if provided
+	if cfg.ROI != nil {
+		if err := cfg.ROI.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Validate log_format if provided
+	if cfg.LogFormat != "" && !validLogFormats[cfg.LogFormat] {
+		return nil, nil, fmt.Errorf("log_format must be %q or %q, got: %q", logFormatText, logFormatJSON, cfg.LogFormat)
+	}
+
+	// Validate capture_mime_type if provided
+	if cfg.CaptureMimeType != "" && !supportedCaptureMimeType(cfg.CaptureMimeType) {
+		return nil, nil, fmt.Errorf("capture_mime_type must be one of %s, got: %q", strings.Join(captureMimeTypes, ", "), cfg.CaptureMimeType)
+	}
+
+	// Validate timezone if provided
+	if _, err := cfg.location(); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate payload_codec if provided
+	if _, err := lookupPayloadCodec(cfg.PayloadCodec); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate encryption_key if provided
+	if cfg.EncryptionKey != "" {
+		if _, err := parseEncryptionKey(cfg.EncryptionKey); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Validate SMTP settings: if any is set, the required ones must all be
+	if cfg.SMTPHost != "" || cfg.SMTPPort != 0 || cfg.SMTPUsername != "" || cfg.SMTPPassword != "" || cfg.AlertEmailTo != "" {
+		if cfg.SMTPHost == "" {
+			return nil, nil, errors.New("smtp_host is required when SMTP notifications are configured")
+		}
+		if cfg.SMTPPort < 1 || cfg.SMTPPort > 65535 {
+			return nil, nil, fmt.Errorf("smtp_port must be between 1 and 65535, got: %d", cfg.SMTPPort)
+		}
+		if len(parseEmailList(cfg.AlertEmailTo)) == 0 {
+			return nil, nil, errors.New("alert_email_to is required when SMTP notifications are configured")
+		}
+		if cfg.SMTPPassword != "" && cfg.SMTPUsername == "" {
+			return nil, nil, errors.New("smtp_username is required when smtp_password is set")
+		}
+	}
+
+	// Validate slack_webhook_url if provided
+	if cfg.SlackWebhookURL != "" {
+		if err := validateWebhookURL(cfg.SlackWebhookURL); err != nil {
+			return nil, nil, fmt.Errorf("slack_webhook_url %w", err)
+		}
+	}
+
+	// Validate notifiers if provided
+	if err := validateNotifiers(cfg.Notifiers, cfg.builtinNotifierNames()); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate button_controller and button_commands if provided
+	if err := cfg.validateButtonCommands(); err != nil {
+		return nil, nil, err
+	}
+
+	// Return both camera and QR vision service as required dependencies,
+	// plus the scale sensor, fullness and face services, and button
+	// controller when configured
 	required := []string{cfg.CameraName, cfg.QRVisionService}
+	if cfg.ScaleSensor != "" {
+		required = append(required, cfg.ScaleSensor)
+	}
+	if cfg.FullnessVisionService != "" {
+		required = append(required, cfg.FullnessVisionService)
+	}
+	if cfg.FaceVisionService != "" {
+		required = append(required, cfg.FaceVisionService)
+		if cfg.FaceCameraName != "" && cfg.FaceCameraName != cfg.CameraName {
+			required = append(required, cfg.FaceCameraName)
+		}
+	}
+	if cfg.ButtonController != "" {
+		required = append(required, cfg.ButtonController)
+	}
 	return required, nil, nil
 }
 
-type inventoryKeeperKeeper struct {
-	resource.AlwaysRebuild
+// monitoringEnabled reports whether background scanning runs (scan_interval_ms
+// or poll_interval_seconds is not 0)
+func (cfg *Config) monitoringEnabled() bool {
+	if cfg.PollIntervalSeconds != nil {
+		return *cfg.PollIntervalSeconds > 0
+	}
+	return cfg.ScanIntervalMs == nil || *cfg.ScanIntervalMs > 0
+}
+
+// scanInterval returns the background scan interval, defaulting to 1s
+func (cfg *Config) scanInterval() time.Duration {
+	if cfg.PollIntervalSeconds != nil {
+		return time.Duration(*cfg.PollIntervalSeconds * float64(time.Second))
+	}
+	if cfg.ScanIntervalMs == nil {
+		return 1 * time.Second
+	}
+	return time.Duration(*cfg.ScanIntervalMs) * time.Millisecond
+}
+
+// location returns the zone for response timestamps, defaulting to UTC
+func (cfg *Config) location() (*time.Location, error) {
+	if cfg.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone must be an IANA time zone name like \"America/New_York\", got: %q: %w", cfg.Timezone, err)
+	}
+	return loc, nil
+}
+
+// minAlertSeverity returns the lowest severity sent to notifiers, defaulting to info
+func (cfg *Config) minAlertSeverity() string {
+	if cfg.MinAlertSeverity == "" {
+		return severityInfo
+	}
+	return cfg.MinAlertSeverity
+}
+
+// scanJitter returns the maximum random delay added to each scan, defaulting to none
+func (cfg *Config) scanJitter() time.Duration {
+	if cfg.ScanIntervalJitterSeconds == nil {
+		return 0
+	}
+	return time.Duration(*cfg.ScanIntervalJitterSeconds * float64(time.Second))
+}
+
+// gracePeriod returns how long a vanished QR code stays visible, defaulting to 2s
+func (cfg *Config) gracePeriod() time.Duration {
+	if cfg.GracePeriodMs == nil {
+		return 2 * time.Second
+	}
+	return time.Duration(*cfg.GracePeriodMs) * time.Millisecond
+}
+
+// preprocessingEnabled reports whether any frame preprocessing is configured
+func (cfg *Config) preprocessingEnabled() bool {
+	return cfg.RotateDegrees != 0 || cfg.Grayscale || cfg.ContrastStretch
+}
 
+// presenceDebounceScans returns the configured presence debounce, defaulting to 2
+func (cfg *Config) presenceDebounceScans() int {
+	if cfg.PresenceDebounceScans == nil {
+		return 2
+	}
+	return *cfg.PresenceDebounceScans
+}
+
+// commandTimeout returns the configured command deadline, defaulting to 30s.
+// Zero means no deadline.
+func (cfg *Config) commandTimeout() time.Duration {
+	if cfg.CommandTimeoutSeconds == nil {
+		return defaultCommandTimeout
+	}
+	return time.Duration(*cfg.CommandTimeoutSeconds * float64(time.Second))
+}
+
+// checkInWindow returns how long a check-in authorizes removal, defaulting to 60s
+func (cfg *Config) checkInWindow() time.Duration {
+	if cfg.CheckInWindowSeconds == nil {
+		return 60 * time.Second
+	}
+	return time.Duration(*cfg.CheckInWindowSeconds) * time.Second
+}
+
+// graceAfterCheckIn returns the post-check-in grace period, zero when unset
+func (cfg *Config) graceAfterCheckIn() time.Duration {
+	if cfg.GraceAfterCheckInSeconds == nil {
+		return 0
+	}
+	return time.Duration(*cfg.GraceAfterCheckInSeconds) * time.Second
+}
+
+// theftAlertDelay returns how long a removal waits for a late check-in
+// before alerting, zero when unset
+func (cfg *Config) theftAlertDelay() time.Duration {
+	if cfg.TheftAlertDelaySeconds == nil {
+		return 0
+	}
+	return time.Duration(*cfg.TheftAlertDelaySeconds) * time.Second
+}
+
+// checkInDelay returns how long an item must be gone to count as checked
+// out, defaulting to 60s
+func (cfg *Config) checkInDelay() time.Duration {
+	if cfg.CheckInDelaySeconds == nil {
+		return 60 * time.Second
+	}
+	return time.Duration(*cfg.CheckInDelaySeconds) * time.Second
+}
+
+// captureRetries returns the configured capture retry count, defaulting to 2
+func (cfg *Config) captureRetries() int {
+	if cfg.CaptureRetries == nil {
+		return 2
+	}
+	return *cfg.CaptureRetries
+}
+
+// maxUndo returns the configured undo depth, defaulting to 10
+func (cfg *Config) maxUndo() int {
+	if cfg.MaxUndo == nil {
+		return 10
+	}
+	return *cfg.MaxUndo
+}
+
+type inventoryKeeperKeeper struct {
 	name resource.Name
 
 	logger logging.Logger
-	cfg    *Config
+	cfg    *Config      // Current config; replaced whole by set_config, read via config()
+	cfgMu  sync.RWMutex // Protects cfg
 
-	camera          camera.Camera  // Camera for shelf monitoring
-	qrVisionService vision.Service // Vision service for QR detection
+	camera          camera.Camera         // Camera for shelf monitoring; read via shelfCamera()
+	qrVisionService vision.Service        // Vision service for QR detection; read via qrVision()
+	scaleSensor     sensor.Sensor         // Shelf scale (nil when not configured)
+	deps            resource.Dependencies // Dependencies from the latest build or reconfigure, for refetching
+	depsMu          sync.RWMutex          // Protects camera, qrVisionService, and deps
 
-	// QR code monitoring state
-	visibleCodes map[string]*DetectedQRCode // Keyed by QR content
-	monitorMu    sync.Mutex                  // Protects visibleCodes
+	fullnessVisionService vision.Service   // Stocked-item detector for shelf_fullness (nil when not configured)
+	faceVisionService     vision.Service   // Face recognizer for detect_person and removal confirmation (nil when not configured)
+	buttonController      input.Controller // Buttons mapped to commands by button_commands (nil when not configured)
 
-	cancelCtx  context.Context
-	cancelFunc func()
+	codec         PayloadCodec   // Encoding for QR payloads
+	fieldRules    itemFieldRules // Length and pattern limits for item fields
+	encryptionKey []byte         // Decoded encryption_key (nil when payloads are plaintext)
+	location      *time.Location // Zone for timestamps in history, alert, and status responses
+
+	// QR code monitoring state
+	visibleCodes  map[string]*DetectedQRCode       // Keyed by QR content
+	presence      map[itemKey]*PresentItem         // Debounced present-set, keyed by namespace and ItemID
+	presenceLog   map[itemKey][]presenceTransition // Recent present-set transitions per item, oldest first
+	duplicates    map[itemKey]bool                 // Items on more than one label in the last scanned frame
+	nameConflicts map[itemKey]*nameConflict        // Items whose labels disagreed on item_name, kept after the conflict clears
+	lastSeen      map[itemKey]time.Time            // Latest detection of each item since startup, kept after it leaves the present-set
+	checkouts     map[itemKey]*itemCheckout        // Check-out state of every item seen since startup
+	shelfLow      map[itemKey]bool                 // Items alerted for a shelf count below min_quantity, until it recovers
+	lastScanAt    time.Time                        // Completion time of the last successful scan
+	monitorMu     sync.Mutex                       // Protects visibleCodes, presence, presenceLog, duplicates, nameConflicts, lastSeen, checkouts, shelfLow, and lastScanAt
+
+	// Inventory state
+	inventory   map[itemKey]*InventoryItem // Keyed by namespace and ItemID
+	history     []HistoryEvent             // Recorded inventory changes, oldest first
+	historySeq  int64                      // Seq of the latest history event
+	undoStack   []undoEntry                // Recent reversible mutations, newest last
+	inventoryMu sync.RWMutex               // Protects inventory, history, historySeq, undoStack, restock requests, and storageDirty
+
+	// Restock requests opened at reorder points; protected by inventoryMu
+	restockRequests []*RestockRequest // Oldest first
+	restockSeq      int64             // Last assigned request ID
+
+	// Inventory store backend
+	store     InventoryStore // External store changed items are written to after each command (nil for memory and file)
+	storeSync storeSyncState // What the store holds; protected by inventoryMu
+
+	// Inventory revisions served to standby keepers; protected by inventoryMu
+	replication replicationState
+
+	// Persistence to storage_path
+	storageDirty     bool                                                   // Inventory or history changed since the last save
+	storageMu        sync.Mutex                                             // Serializes saves
+	writeStorageFile func(name string, data []byte, perm os.FileMode) error // Writes a file durably; replaced in tests
+
+	// Alert state
+	alerts          []Alert                   // Raised alerts, oldest first
+	alertSeq        int64                     // Last assigned alert ID
+	lastAlerted     map[cooldownKey]time.Time // When each item's alert type was last raised, for alert_cooldown_seconds
+	alertStateDirty bool                      // alertSeq or lastAlerted changed since the last save
+	alertsMu        sync.Mutex                // Protects alerts, alertSeq, lastAlerted, and alertStateDirty
+	notifiers       []alertNotifier           // External alert channels (email, etc.)
+
+	// Per-notifier alert queues, created on first delivery
+	notifyQueues   map[alertNotifier]*notifyQueue
+	notifyQueuesMu sync.Mutex // Protects notifyQueues
+
+	// Alert delivery outcomes per notifier, for integrations_status
+	deliveries   map[string]*deliveryRecord // Keyed by notifier name; created on first delivery
+	deliveriesMu sync.Mutex                 // Protects deliveries
+
+	// Theft detection
+	theft    *theftDetector // Decides whether removals were checked in
+	eventLog *os.File       // Append-only detector event log (nil when not configured)
+	theftMu  sync.Mutex     // Protects theft and eventLog
+
+	// Saved scan_qr results served page by page
+	scanPages   map[int64]*scanPage // Keyed by scan id
+	scanPageSeq int64               // Id of the latest saved scan
+	scanPagesMu sync.Mutex          // Protects scanPages and scanPageSeq
+
+	// Maintenance pause of background scans
+	pause   monitorPause // Pause state, including post-resume quiet scans
+	pauseMu sync.Mutex   // Protects pause
+
+	// Startup grace before theft detection
+	startup   startupGrace // When detection begins after the first scan
+	startupMu sync.Mutex   // Protects startup
+
+	// Maintenance mode muting alert delivery
+	maintenance   maintenanceMode // Maintenance window state
+	maintenanceMu sync.Mutex      // Protects maintenance
+
+	// Perishable expiry
+	expiryAlerted map[itemKey]time.Time // Expiry each item was last alerted for; created on first alert
+	expiryMu      sync.Mutex            // Protects expiryAlerted
+
+	// Audit state
+	auditDiscrepancies map[itemKey]string // Discrepant items from the last audit, mapped to bucket
+	auditMu            sync.Mutex         // Protects auditDiscrepancies
+
+	// Camera health
+	health   captureHealth // Consecutive failure tracking for captures
+	healthMu sync.Mutex    // Protects health
+
+	// Clock and randomness sources; replaced in tests for deterministic runs
+	now      func() time.Time // Every timestamp, deadline, and expiry check reads this clock
+	scanRand func() float64   // Random source in [0, 1) for scan jitter
+
+	// Background scan scheduling
+	scanStats *scanStats // Outcomes of recent background scans
+	decodeLog *decodeLog // Recently decoded raw payloads, for get_recent_decodes
+
+	visionGate *visionGate // Limits concurrent vision service calls
+
+	// Last decoded frame per camera, for skip_duplicate_frames
+	lastFrames map[string]frameMemo // Keyed by camera name; created on first use
+	framesMu   sync.Mutex           // Protects lastFrames
+
+	cancelCtx   context.Context
+	cancelFunc  func()
+	stopMonitor context.CancelFunc // Stops the background scan loop (nil when not running); set only at build and reconfigure
+	closeOnce   sync.Once          // Makes Close idempotent
 }
 
 func newInventoryKeeperKeeper(ctx context.Context, deps resource.Dependencies, rawConf resource.Config, logger logging.Logger) (resource.Resource, error) {
@@ -141,6 +1098,9 @@ func newInventoryKeeperKeeper(ctx context.Context, deps resource.Dependencies, r
 }
 
 func NewKeeper(ctx context.Context, deps resource.Dependencies, name resource.Name, conf *Config, logger logging.Logger) (resource.Resource, error) {
+	if conf.LogFormat == logFormatJSON {
+		logger = newJSONLogger(logger)
+	}
 
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 
@@ -156,22 +1116,194 @@ func NewKeeper(ctx context.Context, deps resource.Dependencies, name resource.Na
 		return nil, fmt.Errorf("failed to get QR vision service %s: %w", conf.QRVisionService, err)
 	}
 
+	// Get the scale sensor from dependencies if configured
+	var scale sensor.Sensor
+	if conf.ScaleSensor != "" {
+		scale, err = sensor.FromDependencies(deps, conf.ScaleSensor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scale sensor %s: %w", conf.ScaleSensor, err)
+		}
+	}
+
+	// Get the fullness vision service from dependencies if configured
+	var fullnessVis vision.Service
+	if conf.FullnessVisionService != "" {
+		fullnessVis, err = vision.FromDependencies(deps, conf.FullnessVisionService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fullness vision service %s: %w", conf.FullnessVisionService, err)
+		}
+	}
+
+	// Get the face vision service from dependencies if configured
+	var faceVis vision.Service
+	if conf.FaceVisionService != "" {
+		faceVis, err = vision.FromDependencies(deps, conf.FaceVisionService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get face vision service %s: %w", conf.FaceVisionService, err)
+		}
+	}
+
+	// Get the button controller from dependencies if configured
+	var buttons input.Controller
+	if conf.ButtonController != "" {
+		buttons, err = input.FromDependencies(deps, conf.ButtonController)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get button controller %s: %w", conf.ButtonController, err)
+		}
+	}
+
+	// Compile item field rules
+	fieldRules, err := newItemFieldRules(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the payload codec
+	codec, err := lookupPayloadCodec(conf.PayloadCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the payload encryption key if configured
+	var encryptionKey []byte
+	if conf.EncryptionKey != "" {
+		encryptionKey, err = parseEncryptionKey(conf.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	location, err := conf.location()
+	if err != nil {
+		return nil, err
+	}
+
+	// Set up alert notifiers
+	var notifiers []alertNotifier
+	if email := newEmailNotifier(conf, location); email != nil {
+		notifiers = append(notifiers, email)
+	}
+	if slack := newSlackNotifier(conf, location); slack != nil {
+		notifiers = append(notifiers, slack)
+	}
+	notifiers = append(notifiers, newConfiguredNotifiers(conf.Notifiers, notifiers, location, logger)...)
+
+	// Load saved inventory and alert state if configured
+	inventory := make(map[itemKey]*InventoryItem)
+	var history []HistoryEvent
+	var alertSeq int64
+	var lastAlerted map[cooldownKey]time.Time
+	if conf.StoragePath != "" {
+		inventory, history, err = loadStoredInventory(conf.StoragePath, conf.storageBackups())
+		if err != nil {
+			return nil, err
+		}
+		logger.Infof("Loaded %d items from %s", len(inventory), conf.StoragePath)
+		alertSeq, lastAlerted = loadAlertState(alertStatePath(conf.StoragePath), logger)
+	}
+
+	// An external store's inventory replaces any local copy, except that a
+	// new database starts from storage_path's
+	store, err := newInventoryStore(conf)
+	if err != nil {
+		return nil, err
+	}
+	fromStore := store != nil
+	if store != nil {
+		items, err := store.List(ctx)
+		if err != nil {
+			store.Close()
+			return nil, fmt.Errorf("failed to load inventory from %s: %w", conf.storeLocation(), err)
+		}
+		if len(items) == 0 && len(inventory) > 0 && !conf.storeShared() {
+			logger.Infof("Importing %d items from %s into %s", len(inventory), conf.StoragePath, conf.storeLocation())
+			fromStore = false
+		} else {
+			inventory = make(map[itemKey]*InventoryItem, len(items))
+			for _, item := range items {
+				inventory[item.key()] = item
+			}
+			logger.Infof("Loaded %d items from %s", len(inventory), conf.storeLocation())
+		}
+	}
+
+	// Open the event log if configured (last, so earlier failures don't leak the file)
+	var eventLog *os.File
+	if conf.EventLogFile != "" {
+		eventLog, err = openEventLog(conf.EventLogFile)
+		if err != nil {
+			if store != nil {
+				store.Close()
+			}
+			return nil, err
+		}
+	}
+
 	s := &inventoryKeeperKeeper{
-		name:            name,
-		logger:          logger,
-		cfg:             conf,
-		camera:          cam,
-		qrVisionService: qrVis,
-		visibleCodes:    make(map[string]*DetectedQRCode),
-		cancelCtx:       cancelCtx,
-		cancelFunc:      cancelFunc,
+		name:                  name,
+		logger:                logger,
+		cfg:                   conf,
+		camera:                cam,
+		deps:                  deps,
+		qrVisionService:       qrVis,
+		scaleSensor:           scale,
+		fullnessVisionService: fullnessVis,
+		faceVisionService:     faceVis,
+		buttonController:      buttons,
+		codec:                 codec,
+		fieldRules:            fieldRules,
+		encryptionKey:         encryptionKey,
+		location:              location,
+		visibleCodes:          make(map[string]*DetectedQRCode),
+		presence:              make(map[itemKey]*PresentItem),
+		presenceLog:           make(map[itemKey][]presenceTransition),
+		lastSeen:              make(map[itemKey]time.Time),
+		checkouts:             make(map[itemKey]*itemCheckout),
+		shelfLow:              make(map[itemKey]bool),
+		inventory:             inventory,
+		history:               history,
+		historySeq:            numberHistory(history),
+		store:                 store,
+		writeStorageFile:      writeFileSync,
+		theft:                 newTheftDetector(conf.checkInWindow(), conf.graceAfterCheckIn(), conf.theftAlertDelay()),
+		eventLog:              eventLog,
+		alertSeq:              alertSeq,
+		lastAlerted:           lastAlerted,
+		notifiers:             notifiers,
+		scanRand:              rand.Float64,
+		now:                   time.Now,
+		scanStats:             newScanStats(conf.scanStatsWindow()),
+		decodeLog:             newDecodeLog(conf.recentDecodes()),
+		visionGate:            newVisionGate(conf.maxConcurrentVisionCalls()),
+		cancelCtx:             cancelCtx,
+		cancelFunc:            cancelFunc,
 	}
+	s.seedStore(fromStore)
+	s.seedReplication()
 
 	// Start background monitoring (only if not explicitly disabled)
-	if conf.ScanIntervalMs == nil || *conf.ScanIntervalMs > 0 {
+	if conf.monitoringEnabled() {
 		s.startMonitoring()
 	} else {
-		logger.Info("QR code monitoring explicitly disabled (scan_interval_ms=0)")
+		logger.Info("QR code monitoring explicitly disabled (scan_interval_ms or poll_interval_seconds is 0)")
+	}
+
+	// Start scheduled audits if configured
+	if conf.AuditIntervalSeconds != nil && *conf.AuditIntervalSeconds > 0 {
+		s.startAuditLoop(time.Duration(*conf.AuditIntervalSeconds) * time.Second)
+	}
+
+	// Start expiry alerts if configured
+	if conf.ExpiryAlertWithin != "" {
+		s.startExpiryLoop()
+	}
+
+	// Subscribe to button presses if configured
+	if s.buttonController != nil {
+		if err := s.startButtons(ctx); err != nil {
+			s.Close(ctx)
+			return nil, err
+		}
 	}
 
 	logger.Infof("Inventory keeper initialized with camera: %s, QR vision service: %s", conf.CameraName, conf.QRVisionService)
@@ -189,6 +1321,17 @@ func (s *inventoryKeeperKeeper) DoCommand(ctx context.Context, cmd map[string]in
 		return nil, fmt.Errorf("command field is required and must be a string")
 	}
 
+	if err := s.authorizeCommand(cmdType, cmd); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugw("Command received", "command", cmdType)
+
+	// Work from a shared store's latest items, and save any inventory
+	// changes the command made once it returns
+	s.refreshFromStore(ctx)
+	defer s.flushStorage()
+
 	// Route to the appropriate handler based on command type
 	switch cmdType {
 	case "ping":
@@ -198,6 +1341,10 @@ func (s *inventoryKeeperKeeper) DoCommand(ctx context.Context, cmd map[string]in
 			"message": "Inventory keeper is running!",
 		}, nil
 
+	case "version":
+		// Module version, build details, and enabled features
+		return s.handleVersion(ctx, cmd)
+
 	case "echo":
 		// Simple echo command for testing - returns what was sent
 		return s.handleEcho(ctx, cmd)
@@ -206,6 +1353,298 @@ func (s *inventoryKeeperKeeper) DoCommand(ctx context.Context, cmd map[string]in
 		// Generate QR code for an inventory item
 		return s.handleGenerateQR(ctx, cmd)
 
+	case "generate_label":
+		// Render a printable label with the QR code and the item name in large text
+		return s.handleGenerateLabel(ctx, cmd)
+
+	case "generate_label_sheet":
+		// Lay out labels on a grid of label stock and return a printable PDF
+		return s.handleGenerateLabelSheet(ctx, cmd)
+
+	case "generate_barcode":
+		// Render a Code 128, EAN-13, or UPC-A barcode for a SKU or item
+		return s.handleGenerateBarcode(ctx, cmd)
+
+	case "export_qr_bundle":
+		// Zip of codes for every inventory item, for label reprints
+		return s.handleExportQRBundle(ctx, cmd)
+
+	case "generate_qr_range":
+		// Codes for sequentially numbered item IDs, for pre-printing labels
+		return s.handleGenerateQRRange(ctx, cmd)
+
+	case "generate_qr_batch":
+		// Codes for a list of items in one call, with per-item errors
+		return s.handleGenerateQRBatch(ctx, cmd)
+
+	case "create_item":
+		// add_item and generate_qr in one step
+		return s.handleCreateItem(ctx, cmd)
+
+	case "get_image":
+		// Current frame as seen by the QR detector, optionally annotated
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleGetImage)
+
+	case "detect_person":
+		// Report the people recognized on the face camera
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleDetectPerson)
+
+	case "list_people":
+		// People the face vision service can recognize
+		return s.handleListPeople(ctx, cmd)
+
+	case "enroll_person":
+		// Teach the face vision service a new person
+		return s.handleEnrollPerson(ctx, cmd)
+
+	case "shelf_fullness":
+		// Rough share of the shelf that is stocked, for restocking
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleShelfFullness)
+
+	case "scan_qr":
+		// On-demand scan returning the QR codes currently in view
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleScanQR)
+
+	case "scan_shelf":
+		// On-demand scan returning the items in view with bounding boxes
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleScanShelf)
+
+	case "simulate_scan":
+		// Dry-run supplied images through the scan pipeline for on-site tuning
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleSimulateScan)
+
+	case "benchmark_scan":
+		// Time the decode pipeline for capacity planning (needs enable_benchmark)
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleBenchmarkScan)
+
+	case "get_conflicts":
+		// Items whose labels disagreed on item_name, and how each was resolved
+		return s.handleGetConflicts(ctx, cmd)
+
+	case "get_present_items", "get_current_inventory":
+		// Debounced present-set from the latest scans, without a new capture
+		return s.handleGetPresentItems(ctx, cmd)
+
+	case "presence_history":
+		// Intervals an item was on the shelf, from recorded presence transitions
+		return s.handlePresenceHistory(ctx, cmd)
+
+	case "audit":
+		// Reconcile recorded inventory against the present-set
+		return s.handleAudit(ctx, cmd)
+
+	case "expiring_soon":
+		// Perishable items expiring within a duration, soonest first
+		return s.handleExpiringSoon(ctx, cmd)
+
+	case "stale_items":
+		// Items the camera hasn't seen within a duration, oldest first
+		return s.handleStaleItems(ctx, cmd)
+
+	case "quantity_discrepancies":
+		// Present items whose recorded quantity differs from their label count
+		return s.handleQuantityDiscrepancies(ctx, cmd)
+
+	case "get_stock_levels":
+		// Recorded quantity of every item against the units last seen
+		return s.handleGetStockLevels(ctx, cmd)
+
+	case "read_weight":
+		// Shelf weight from the scale sensor, with optional quantity estimate
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleReadWeight)
+
+	case "pause_monitoring":
+		// Skip background scans for maintenance, optionally for a duration
+		return s.handlePauseMonitoring(ctx, cmd)
+
+	case "resume_monitoring":
+		// End a maintenance pause
+		return s.handleResumeMonitoring(ctx, cmd)
+
+	case "set_maintenance_mode":
+		// Mute alert delivery while scanning continues, optionally for a duration
+		return s.handleSetMaintenanceMode(ctx, cmd)
+
+	case "get_status":
+		// Camera health and tracked state summary
+		return s.handleGetStatus(ctx, cmd)
+
+	case "get_recent_decodes":
+		// Raw payloads from recent scans, including ones that failed to parse
+		return s.handleGetRecentDecodes(ctx, cmd)
+
+	case "set_supplier_info":
+		// Set an item's supplier, SKU, reorder quantity, and reorder threshold
+		return s.handleSetSupplierInfo(ctx, cmd)
+
+	case "get_reorder_list":
+		// Items at or below their reorder threshold, with what to order
+		return s.handleGetReorderList(ctx, cmd)
+
+	case "get_restock_requests":
+		// List restock requests opened when items reached their reorder point
+		return s.handleGetRestockRequests(ctx, cmd)
+
+	case "fulfill_restock_request":
+		// Close a restock request, optionally adding the received units
+		return s.handleFulfillRestockRequest(ctx, cmd)
+
+	case "shrinkage_report":
+		// Theft alerts over a time range, grouped by item and by day
+		return s.handleShrinkageReport(ctx, cmd)
+
+	case "get_scan_stats":
+		// Decode success rate and latency over recent background scans
+		return s.handleGetScanStats(ctx, cmd)
+
+	case "verify_label":
+		// Scan one held-up label and check it against its inventory record
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleVerifyLabel)
+
+	case "verify_qr":
+		// Check a payload's signature without scanning
+		return s.handleVerifyQR(ctx, cmd)
+
+	case "decode_qr_data":
+		// Describe a payload's schema version and missing or unknown fields
+		return s.handleDecodeQRData(ctx, cmd)
+
+	case "generate_access_token":
+		// Signed, time-limited token QR for kiosk check-in rights
+		return s.handleGenerateAccessToken(ctx, cmd)
+
+	case "redeem_access_token":
+		return s.handleRedeemAccessToken(ctx, cmd)
+
+	case "add_item":
+		return s.handleAddItem(ctx, cmd)
+
+	case "get_item":
+		return s.handleGetItem(ctx, cmd)
+
+	case "update_item":
+		// Change several of an item's fields as one undoable edit
+		return s.handleUpdateItem(ctx, cmd)
+
+	case "remove_item", "delete_item":
+		return s.handleRemoveItem(ctx, cmd)
+
+	case "rename_item":
+		return s.handleRenameItem(ctx, cmd)
+
+	case "merge_items":
+		// Fold a duplicate item into another and remove the duplicate
+		return s.handleMergeItems(ctx, cmd)
+
+	case "repair_inventory":
+		// Report inventory inconsistencies, fixing them with apply: true
+		return s.handleRepairInventory(ctx, cmd)
+
+	case "move_item":
+		// Relocate an item and log the transfer
+		return s.handleMoveItem(ctx, cmd)
+
+	case "adjust_quantity":
+		return s.handleAdjustQuantity(ctx, cmd)
+
+	case "set_quantity":
+		// Absolute quantity correction after a recount
+		return s.handleSetQuantity(ctx, cmd)
+
+	case "set_item_status":
+		// Flag an item damaged, quarantined, on hold, or back to active
+		return s.handleSetItemStatus(ctx, cmd)
+
+	case "query_by_status":
+		// Items in a given status, for quality and hold workflows
+		return s.handleQueryByStatus(ctx, cmd)
+
+	case "list_items":
+		return s.handleListItems(ctx, cmd)
+
+	case "search_items":
+		return s.handleSearchItems(ctx, cmd)
+
+	case "count_items":
+		return s.handleCountItems(ctx, cmd)
+
+	case "check_in":
+		// Authorize removal of an item for the check-in window
+		return s.handleCheckIn(ctx, cmd)
+
+	case "check_in_batch":
+		// Authorize removal of several items in one check-in
+		return s.handleCheckInBatch(ctx, cmd)
+
+	case "list_checked_out":
+		// Items gone from the shelf for check_in_delay_seconds or longer
+		return s.handleListCheckedOut(ctx, cmd)
+
+	case "force_check_in":
+		// Mark a removed or checked-out item returned without seeing it
+		return s.handleForceCheckIn(ctx, cmd)
+
+	case "replay_events":
+		// Re-run a recorded event log through theft detection without raising alerts
+		return s.handleReplayEvents(ctx, cmd)
+
+	case "get_history":
+		return s.handleGetHistory(ctx, cmd)
+
+	case "get_alerts":
+		return s.handleGetAlerts(ctx, cmd)
+
+	case "integrations_status":
+		// Configured alert integrations and their latest delivery results
+		return s.handleIntegrationsStatus(ctx, cmd)
+
+	case "test_notification":
+		// Send a test alert to the configured integrations
+		return s.withCommandTimeout(ctx, cmdType, cmd, s.handleTestNotification)
+
+	case "poll_alerts":
+		// Incremental alert retrieval using an after_id cursor
+		return s.handlePollAlerts(ctx, cmd)
+
+	case "acknowledge_alert":
+		return s.handleAcknowledgeAlert(ctx, cmd)
+
+	case "clear_acknowledged":
+		// Drop acknowledged alerts from the active list; history keeps them
+		return s.handleClearAcknowledged(ctx, cmd)
+
+	case "snapshot":
+		// Full state dump with secrets redacted
+		return s.handleSnapshot(ctx, cmd)
+
+	case "replicate_pull":
+		// Inventory changes since a revision, for a warm standby keeper
+		return s.handleReplicatePull(ctx, cmd)
+
+	case "replicate_apply":
+		// Apply a replicate_pull result from the primary keeper
+		return s.handleReplicateApply(ctx, cmd)
+
+	case "restore_snapshot":
+		// Repopulate inventory and history from a snapshot dump
+		return s.handleRestoreSnapshot(ctx, cmd)
+
+	case "diff_snapshot":
+		// Compare a snapshot dump's inventory with the current inventory
+		return s.handleDiffSnapshot(ctx, cmd)
+
+	case "undo":
+		// Revert the most recent inventory mutation
+		return s.handleUndo(ctx, cmd)
+
+	case "selftest_qr":
+		// Generate a QR and decode it back to prove labels are scannable
+		return s.handleSelftestQR(ctx, cmd)
+
+	case "set_config":
+		// Change tunable thresholds without a full reconfigure
+		return s.handleSetConfig(ctx, cmd)
+
 	default:
 		return nil, fmt.Errorf("unknown command: %s", cmdType)
 	}
@@ -228,19 +1667,35 @@ func (s *inventoryKeeperKeeper) handleEcho(ctx context.Context, cmd map[string]i
 	}, nil
 }
 
-// handleGenerateQR generates a QR code for an inventory item
-func (s *inventoryKeeperKeeper) handleGenerateQR(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
-	s.logger.Info("Generate QR command received")
-
-	// Extract required fields
+// qrItemArgs builds a label payload from a command's item_id, item_name,
+// namespace, and optional quantity
+func (s *inventoryKeeperKeeper) qrItemArgs(cmd map[string]interface{}) (ItemQRData, error) {
 	itemID, ok := cmd["item_id"].(string)
 	if !ok || itemID == "" {
-		return nil, errors.New("item_id is required and must be a string")
+		return ItemQRData{}, errors.New("item_id is required and must be a string")
 	}
-
 	itemName, ok := cmd["item_name"].(string)
 	if !ok || itemName == "" {
-		return nil, errors.New("item_name is required and must be a string")
+		return ItemQRData{}, errors.New("item_name is required and must be a string")
+	}
+	if err := s.fieldRules.validateItemID(itemID); err != nil {
+		return ItemQRData{}, err
+	}
+	if err := s.fieldRules.validateItemName(itemName); err != nil {
+		return ItemQRData{}, err
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return ItemQRData{}, err
+	}
+
+	// A label may stand for several units, e.g. a sealed case
+	quantity, hasQuantity, err := intArg(cmd, "quantity")
+	if err != nil {
+		return ItemQRData{}, err
+	}
+	if hasQuantity && quantity < 1 {
+		return ItemQRData{}, fmt.Errorf("quantity must be at least 1, got: %d", quantity)
 	}
 
 	// Create QR data structure (minimal - only what we need now)
@@ -248,99 +1703,168 @@ func (s *inventoryKeeperKeeper) handleGenerateQR(ctx context.Context, cmd map[st
 		ItemID:   itemID,
 		ItemName: itemName,
 	}
+	if namespace != defaultNamespace {
+		qrData.Namespace = namespace
+	}
+	if quantity > 1 {
+		qrData.Quantity = quantity
+	}
+	return qrData, nil
+}
 
-	// Encode data as JSON
-	jsonData, err := json.Marshal(qrData)
+// handleGenerateQR generates a QR code for an inventory item
+func (s *inventoryKeeperKeeper) handleGenerateQR(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	s.logger.Info("Generate QR command received")
+
+	qrData, err := s.qrItemArgs(cmd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to encode QR data: %w", err)
+		return nil, err
+	}
+	opts, err := codeRenderArgs(cmd)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate QR code (256x256 pixels, medium recovery level)
-	qrCode, err := qrcode.Encode(string(jsonData), qrcode.Medium, 256)
+	qrCode, payload, size, err := s.renderItemCode(qrData, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+		return nil, err
 	}
 
 	// Encode as base64 for easy transmission
 	qrBase64 := base64.StdEncoding.EncodeToString(qrCode)
 
-	s.logger.Infof("Generated QR code for item: %s", itemID)
+	s.logger.Infof("Generated %s code for item: %s", opts.CodeType, qrData.ItemID)
 
-	return map[string]interface{}{
-		"item_id":   itemID,
-		"item_name": itemName,
+	result := map[string]interface{}{
+		"namespace": normalizeNamespace(qrData.Namespace),
+		"item_id":   qrData.ItemID,
+		"item_name": qrData.ItemName,
 		"qr_code":   qrBase64,
-		"qr_data":   string(jsonData), // Include the encoded data for reference
-		"format":    "base64-png",
-		"size":      256,
-	}, nil
+		"qr_data":   payload, // Include the encoded data for reference
+		"encrypted": s.encryptionKey != nil,
+		"code_type": opts.CodeType,
+		"format":    opts.responseFormat(),
+		"size":      size,
+		"quantity":  qrData.units(),
+	}
+	if opts.CodeType == codeTypeQR {
+		result["error_correction"] = opts.recoveryLevel()
+	}
+	return result, nil
 }
 
 // startMonitoring starts the background QR code monitoring loop
 func (s *inventoryKeeperKeeper) startMonitoring() {
-	// Determine scan interval
-	var interval time.Duration
-	if s.cfg.ScanIntervalMs == nil {
-		// Default to 1 second when not specified
-		interval = 1 * time.Second
-	} else {
-		// Use specified interval (caller ensures this is > 0)
-		interval = time.Duration(*s.cfg.ScanIntervalMs) * time.Millisecond
-	}
+	// Determine scan interval (caller ensures monitoring is enabled)
+	interval := s.config().scanInterval()
 
 	s.logger.Infof("Starting QR code monitoring with interval: %v", interval)
 
+	ctx, stop := context.WithCancel(s.cancelCtx)
+	s.stopMonitor = stop
 	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
+		// Each delay is computed from the current config, so set_config
+		// changes to the interval or jitter apply from the next scan
+		failures := 0
+		timer := time.NewTimer(s.scanDelay(failures))
+		defer timer.Stop()
 
 		for {
 			select {
-			case <-s.cancelCtx.Done():
+			case <-ctx.Done():
 				s.logger.Debug("QR code monitoring stopped")
 				return
-			case <-ticker.C:
-				s.scanAndCompare(s.cancelCtx)
+			case <-timer.C:
+				s.refreshFromStore(ctx)
+				if err := s.scanAndCompare(ctx); err != nil {
+					failures++
+				} else {
+					failures = 0
+				}
+				// A failed or paused scan never reaches processDetections
+				s.tickTheftDetector(s.now())
+
+				delay := s.scanDelay(failures)
+				if failures > 0 {
+					s.logger.Debugf("Backing off after %d failed scans, next scan in %v", failures, delay)
+				}
+				timer.Reset(delay)
 			}
 		}
 	}()
 }
 
-// scanAndCompare performs a single scan for QR codes and compares to previous state
-func (s *inventoryKeeperKeeper) scanAndCompare(ctx context.Context) {
+// stopMonitoring stops the background monitoring loop if it is running
+func (s *inventoryKeeperKeeper) stopMonitoring() {
+	if s.stopMonitor != nil {
+		s.stopMonitor()
+		s.stopMonitor = nil
+	}
+}
+
+// scanAndCompare performs a single scan for QR codes and compares to previous
+// state. The returned error reports a failed scan, which has already been logged.
+func (s *inventoryKeeperKeeper) scanAndCompare(ctx context.Context) error {
+	if s.skipPausedScan() {
+		return nil
+	}
+
 	// Get detections from vision service
-	detections, err := s.qrVisionService.DetectionsFromCamera(ctx, s.cfg.CameraName, nil)
+	start := time.Now() // Latency is wall-clock time, even under a test clock
+	detections, err := s.detectQRCodes(ctx)
 	if err != nil {
+		s.scanStats.record(scanOutcome{At: s.now(), Latency: time.Since(start), Failed: true})
 		s.logger.Warnf("Failed to scan QR codes: %v", err)
-		return
+		return err
 	}
+	s.scanStats.record(scanOutcome{
+		At:      s.now(),
+		Latency: time.Since(start),
+		Codes:   len(detections),
+		Decoded: s.countDecodable(detections),
+	})
+
+	s.processDetections(detections, s.now())
+	return nil
+}
 
+// processDetections applies one scan's detections to the visible codes,
+// present-set, duplicate tracking, and theft detector, returning the
+// present-set changes
+func (s *inventoryKeeperKeeper) processDetections(detections []objectdetection.Detection, now time.Time) presenceChanges {
 	// Determine grace period
-	var gracePeriod time.Duration
-	if s.cfg.GracePeriodMs == nil {
-		// Default to 2 seconds
-		gracePeriod = 2 * time.Second
-	} else {
-		gracePeriod = time.Duration(*s.cfg.GracePeriodMs) * time.Millisecond
-	}
+	gracePeriod := s.config().gracePeriod()
 
-	// Track currently detected codes
+	// Track currently detected codes and the items they decode to
 	currentlyDetected := make(map[string]bool)
-	now := time.Now()
+	detectedItems := make(map[itemKey]ItemQRData)
+	labelCounts := make(map[itemKey]int)
+	unitCounts := make(map[itemKey]int)
+	labelNames := make(map[itemKey][]string)
 
 	// Process each detection
 	for _, detection := range detections {
 		content := detection.Label()
 		currentlyDetected[content] = true
 
-		// Try to parse as ItemQRData JSON
-		var itemData ItemQRData
+		// Try to parse as ItemQRData JSON (decrypting if needed)
 		itemID := ""
 		itemName := ""
-		if err := json.Unmarshal([]byte(content), &itemData); err == nil {
+		itemData, decodeErr := s.decodeQRPayload(content)
+		s.recordDecode(content, "", itemData, decodeErr, now)
+		if decodeErr == nil {
 			// Successfully parsed as ItemQRData
 			itemID = itemData.ItemID
 			itemName = itemData.ItemName
+			if itemID != "" {
+				key := keyFor(itemData.Namespace, itemID)
+				if _, seen := detectedItems[key]; !seen {
+					detectedItems[key] = itemData
+				}
+				labelCounts[key]++
+				unitCounts[key] += itemData.units()
+				labelNames[key] = append(labelNames[key], itemName)
+			}
 		}
 
 		s.monitorMu.Lock()
@@ -351,6 +1875,8 @@ func (s *inventoryKeeperKeeper) scanAndCompare(ctx context.Context) {
 			// New code appeared
 			if itemID != "" {
 				s.logger.Debugf("QR code appeared: %s (%s)", itemID, itemName)
+			} else if errors.Is(decodeErr, errPayloadDecryption) || errors.Is(decodeErr, errPayloadEncrypted) {
+				s.logger.Warnf("QR code appeared with unreadable encrypted payload: %v", decodeErr)
 			} else {
 				s.logger.Debugf("QR code appeared: unknown content - %s", content)
 			}
@@ -381,6 +1907,10 @@ func (s *inventoryKeeperKeeper) scanAndCompare(ctx context.Context) {
 		}
 	}
 
+	// Settle labels that disagree on an item's name before it reaches the
+	// present-set
+	conflicts, held := s.resolveNameConflicts(detectedItems, labelNames)
+
 	// Handle codes that are not currently detected
 	s.monitorMu.Lock()
 	toRemove := []string{}
@@ -417,11 +1947,92 @@ func (s *inventoryKeeperKeeper) scanAndCompare(ctx context.Context) {
 	for _, content := range toRemove {
 		delete(s.visibleCodes, content)
 	}
+
+	// Update the debounced present-set
+	changes := s.updatePresenceLocked(detectedItems, held, now)
+	for key, labels := range labelCounts {
+		if !held[key] {
+			s.presence[key].Labels = labels
+			s.presence[key].Units = unitCounts[key]
+		}
+	}
+	transitions := s.updateCheckoutsLocked(changes, now)
+	s.lastScanAt = now
 	s.monitorMu.Unlock()
+
+	s.alertCheckouts(transitions)
+
+	s.reportDuplicates(s.findDuplicateItemIDs(detections))
+	s.reportNameConflicts(conflicts, now)
+	s.checkShelfStock(now)
+	s.returnToTheftDetector(changes.Appeared, now)
+	s.tickTheftDetector(now)
+
+	// Right after a maintenance pause, removals are the maintenance itself
+	if s.takeQuietScan() {
+		if len(changes.Removed) > 0 {
+			s.logger.Infof("Not checking %d removals for theft, monitoring just resumed", len(changes.Removed))
+		}
+		return changes
+	}
+
+	// While the baseline settles after startup, removals aren't thefts
+	if s.inStartupGrace(now) {
+		if len(changes.Removed) > 0 {
+			s.logger.Infof("Not checking %d removals for theft during startup grace", len(changes.Removed))
+		}
+		return changes
+	}
+
+	// Removals a person authorized in the namespace was seen making are
+	// theirs, not thefts
+	takers := s.recognizeRemover(changes.Removed)
+
+	// Removals from the present-set are checked against check-ins, one
+	// event per namespace (Removed is sorted by namespace)
+	for start := 0; start < len(changes.Removed); {
+		namespace := changes.Removed[start].Namespace
+		var itemIDs []string
+		for ; start < len(changes.Removed) && changes.Removed[start].Namespace == namespace; start++ {
+			itemIDs = append(itemIDs, changes.Removed[start].ItemID)
+		}
+		s.feedTheftDetector(detectorEvent{
+			Type:      detectorEventItemRemoved,
+			Time:      now,
+			Namespace: namespace,
+			ItemIDs:   itemIDs,
+			Person:    takers[namespace],
+		})
+	}
+	return changes
 }
 
 func (s *inventoryKeeperKeeper) Close(context.Context) error {
-	// Put close code here
-	s.cancelFunc()
-	return nil
+	// Teardown runs once; later calls are no-ops returning nil
+	var err error
+	s.closeOnce.Do(func() {
+		// cancelFunc is nil if construction never got as far as starting anything
+		if s.cancelFunc != nil {
+			s.cancelFunc()
+		}
+		if s.buttonController != nil {
+			s.stopButtons(context.Background())
+		}
+
+		// cfg is nil if construction never finished
+		if s.config() != nil {
+			s.flushStorage()
+		}
+		if s.store != nil {
+			s.store.Close()
+		}
+
+		s.theftMu.Lock()
+		defer s.theftMu.Unlock()
+		if s.eventLog != nil {
+			err = s.eventLog.Close()
+			s.eventLog = nil
+		}
+	})
+	return err
 }
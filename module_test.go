@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"image"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -80,6 +81,41 @@ func TestConfigValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("poll_interval_seconds must be non-negative and exclusive of scan_interval_ms", func(t *testing.T) {
+		negativePoll := -1.0
+		cfg := &Config{
+			CameraName:          "shelf-camera",
+			QRVisionService:     "qr-detector",
+			PollIntervalSeconds: &negativePoll,
+		}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for negative poll_interval_seconds")
+		}
+
+		poll := 5.0
+		interval := 1000
+		cfg.PollIntervalSeconds = &poll
+		cfg.ScanIntervalMs = &interval
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error when both poll_interval_seconds and scan_interval_ms are set")
+		}
+
+		tiny := 0.0000001
+		cfg.ScanIntervalMs = nil
+		cfg.PollIntervalSeconds = &tiny
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for a poll_interval_seconds below 1ms")
+		}
+
+		cfg.PollIntervalSeconds = &poll
+		if _, _, err := cfg.Validate(""); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+		if cfg.scanInterval() != 5*time.Second || !cfg.monitoringEnabled() {
+			t.Errorf("expected 5s monitoring interval, got: %v (enabled %v)", cfg.scanInterval(), cfg.monitoringEnabled())
+		}
+	})
+
 	t.Run("negative grace_period_ms returns error", func(t *testing.T) {
 		negativeGracePeriod := -100
 		cfg := &Config{
@@ -93,6 +129,20 @@ func TestConfigValidate(t *testing.T) {
 			t.Error("expected error for negative grace_period_ms")
 		}
 	})
+
+	t.Run("negative max_undo returns error", func(t *testing.T) {
+		negativeMaxUndo := -1
+		cfg := &Config{
+			CameraName:      "shelf-camera",
+			QRVisionService: "qr-detector",
+			MaxUndo:         &negativeMaxUndo,
+		}
+
+		_, _, err := cfg.Validate("")
+		if err == nil {
+			t.Error("expected error for negative max_undo")
+		}
+	})
 }
 
 func TestDoCommand(t *testing.T) {
@@ -700,6 +750,68 @@ func TestMonitoringStartBehavior(t *testing.T) {
 			t.Error("expected DetectionsFromCamera to be called (monitoring should have started with custom interval)")
 		}
 	})
+
+	t.Run("monitoring uses poll_interval_seconds and get_current_inventory reads its results", func(t *testing.T) {
+		payload, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+		callCount := 0
+		var mu sync.Mutex
+
+		mockCam := &inject.Camera{}
+		mockVision := inject.NewVisionService("test-qr-vision")
+
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{}, nil
+		}
+
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			mu.Lock()
+			callCount++
+			mu.Unlock()
+			return []objectdetection.Detection{testDetection(0, string(payload))}, nil
+		}
+
+		deps := resource.Dependencies{
+			camera.Named("test-camera"):    mockCam,
+			vision.Named("test-qr-vision"): mockVision,
+		}
+
+		pollInterval := 0.05
+		cfg := &Config{
+			CameraName:          "test-camera",
+			QRVisionService:     "test-qr-vision",
+			PollIntervalSeconds: &pollInterval,
+		}
+
+		keeper, err := NewKeeper(ctx, deps, resource.NewName(generic.API, "test"), cfg, logger)
+		if err != nil {
+			t.Fatalf("failed to create keeper: %v", err)
+		}
+		defer keeper.Close(ctx)
+
+		// Enough cycles for the default presence debounce to confirm the item
+		time.Sleep(300 * time.Millisecond)
+
+		mu.Lock()
+		before := callCount
+		mu.Unlock()
+		result, err := keeper.DoCommand(ctx, map[string]interface{}{"command": "get_current_inventory"})
+		if err != nil {
+			t.Fatalf("get_current_inventory failed: %v", err)
+		}
+		if result["count"] != 1 || result["scanned"] != true {
+			t.Errorf("expected apple-001 present from background scans, got: %v", result)
+		}
+		mu.Lock()
+		after := callCount
+		mu.Unlock()
+
+		if before == 0 {
+			t.Error("expected DetectionsFromCamera to be called (monitoring should have started with poll_interval_seconds)")
+		}
+		if after > before+1 {
+			t.Errorf("expected get_current_inventory not to capture, calls went from %d to %d", before, after)
+		}
+	})
 }
 
 func TestDebouncingBehavior(t *testing.T) {
@@ -1048,3 +1160,31 @@ func TestDebouncingBehavior(t *testing.T) {
 		svc.monitorMu.Unlock()
 	})
 }
+
+func TestCloseIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("second close is a no-op", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{EventLogFile: filepath.Join(t.TempDir(), "events.jsonl")})
+
+		if err := svc.Close(ctx); err != nil {
+			t.Fatalf("first close failed: %v", err)
+		}
+		if err := svc.Close(ctx); err != nil {
+			t.Errorf("expected second close to return nil, got: %v", err)
+		}
+		if svc.eventLog != nil {
+			t.Error("expected event log to be released")
+		}
+	})
+
+	t.Run("close before construction finished", func(t *testing.T) {
+		svc := &inventoryKeeperKeeper{}
+		if err := svc.Close(ctx); err != nil {
+			t.Errorf("expected nil from close of an unstarted keeper, got: %v", err)
+		}
+		if err := svc.Close(ctx); err != nil {
+			t.Errorf("expected nil from repeated close, got: %v", err)
+		}
+	})
+}
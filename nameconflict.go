@@ -0,0 +1,212 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Resolutions when labels in one frame share an item_id but carry different
+// item_names
+const (
+	nameConflictFirstWins     = "first-wins"     // Keep the name the item was first seen with
+	nameConflictInventoryWins = "inventory-wins" // Use the name recorded in inventory
+	nameConflictFlag          = "flag"           // Leave the item unchanged and raise an alert
+)
+
+// validNameConflictPolicies are the accepted name_conflict_policy values
+var validNameConflictPolicies = map[string]bool{
+	nameConflictFirstWins:     true,
+	nameConflictInventoryWins: true,
+	nameConflictFlag:          true,
+}
+
+// nameConflictPolicy returns the configured policy, defaulting to first-wins
+func (cfg *Config) nameConflictPolicy() string {
+	if cfg.NameConflictPolicy == "" {
+		return nameConflictFirstWins
+	}
+	return cfg.NameConflictPolicy
+}
+
+// nameConflict is an item_id decoded from labels with different item_names,
+// usually a reprint that drifted from the original
+type nameConflict struct {
+	Key       itemKey
+	Names     []string // Distinct names on the labels, in frame order
+	Policy    string   // Policy in effect when it was last seen
+	Resolved  string   // Name committed to the present-set ("" when flagged)
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Scans     int  // Scans the conflict was seen in
+	Active    bool // Seen in the most recent scan
+}
+
+// toMap converts the conflict into a DoCommand-friendly response map
+func (c *nameConflict) toMap(loc *time.Location) map[string]interface{} {
+	names := make([]interface{}, 0, len(c.Names))
+	for _, name := range c.Names {
+		names = append(names, name)
+	}
+	result := map[string]interface{}{
+		"namespace":  c.Key.Namespace,
+		"item_id":    c.Key.ItemID,
+		"names":      names,
+		"policy":     c.Policy,
+		"flagged":    c.Policy == nameConflictFlag,
+		"first_seen": formatTimestampIn(c.FirstSeen, loc),
+		"last_seen":  formatTimestampIn(c.LastSeen, loc),
+		"scans":      c.Scans,
+		"active":     c.Active,
+	}
+	if c.Policy != nameConflictFlag {
+		result["resolved_name"] = c.Resolved
+	}
+	return result
+}
+
+// distinctNames returns names without repeats, keeping the first occurrence
+func distinctNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var distinct []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			distinct = append(distinct, name)
+		}
+	}
+	return distinct
+}
+
+// resolveNameConflicts settles items whose labels in this frame disagree on
+// item_name, per name_conflict_policy. detected is updated in place with the
+// winning name; under "flag" the item is dropped from detected and returned
+// in held so the present-set neither counts it seen nor missing this scan.
+func (s *inventoryKeeperKeeper) resolveNameConflicts(detected map[itemKey]ItemQRData, labelNames map[itemKey][]string) (conflicts []nameConflict, held map[itemKey]bool) {
+	policy := s.config().nameConflictPolicy()
+	var keys []itemKey
+	for key, names := range labelNames {
+		if len(distinctNames(names)) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	sortItemKeys(keys)
+
+	// The name each item is already tracked under, for first-wins and as
+	// the inventory-wins fallback for items not in inventory
+	tracked := make(map[itemKey]string, len(keys))
+	s.monitorMu.Lock()
+	for _, key := range keys {
+		if entry, ok := s.presence[key]; ok {
+			tracked[key] = entry.ItemName
+		}
+	}
+	s.monitorMu.Unlock()
+	if policy == nameConflictInventoryWins {
+		s.inventoryMu.RLock()
+		for _, key := range keys {
+			if item, ok := s.inventory[key]; ok {
+				tracked[key] = item.ItemName
+			}
+		}
+		s.inventoryMu.RUnlock()
+	}
+
+	held = make(map[itemKey]bool)
+	for _, key := range keys {
+		conflict := nameConflict{Key: key, Names: distinctNames(labelNames[key]), Policy: policy}
+		if policy == nameConflictFlag {
+			delete(detected, key)
+			held[key] = true
+		} else {
+			data := detected[key]
+			data.ItemName = conflict.Names[0]
+			if name, ok := tracked[key]; ok {
+				data.ItemName = name
+			}
+			detected[key] = data
+			conflict.Resolved = data.ItemName
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, held
+}
+
+// reportNameConflicts records the conflicts seen by a scan and, under the
+// flag policy, raises an alert for each item that wasn't already in conflict
+// in the previous scan
+func (s *inventoryKeeperKeeper) reportNameConflicts(conflicts []nameConflict, now time.Time) {
+	s.monitorMu.Lock()
+	if s.nameConflicts == nil {
+		s.nameConflicts = make(map[itemKey]*nameConflict)
+	}
+	previouslyActive := make(map[itemKey]bool)
+	details := make(map[itemKey]map[string]interface{}) // Newly active conflicts
+	for key, recorded := range s.nameConflicts {
+		previouslyActive[key] = recorded.Active
+		recorded.Active = false
+	}
+	for _, conflict := range conflicts {
+		recorded, ok := s.nameConflicts[conflict.Key]
+		if !ok {
+			recorded = &nameConflict{Key: conflict.Key, FirstSeen: now}
+			s.nameConflicts[conflict.Key] = recorded
+		}
+		recorded.Names = conflict.Names
+		recorded.Policy = conflict.Policy
+		recorded.Resolved = conflict.Resolved
+		recorded.LastSeen = now
+		recorded.Scans++
+		recorded.Active = true
+		if !previouslyActive[conflict.Key] {
+			details[conflict.Key] = recorded.toMap(s.location)
+		}
+	}
+	s.monitorMu.Unlock()
+
+	for _, conflict := range conflicts {
+		if details[conflict.Key] == nil {
+			continue
+		}
+		s.logger.Warnf("Item %s in namespace %s has conflicting names on its labels: %q", conflict.Key.ItemID, conflict.Key.Namespace, conflict.Names)
+		if conflict.Policy == nameConflictFlag {
+			s.raiseAlert("name_conflict", severityWarning, conflict.Key.ItemID,
+				fmt.Sprintf("Item %s has labels with different names: %q", conflict.Key.ItemID, conflict.Names),
+				details[conflict.Key])
+		}
+	}
+}
+
+// handleGetConflicts returns the namespace's recorded item name conflicts,
+// or with active_only just those in the most recent scan
+func (s *inventoryKeeperKeeper) handleGetConflicts(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	activeOnly, _ := cmd["active_only"].(bool)
+
+	s.monitorMu.Lock()
+	var keys []itemKey
+	for key, recorded := range s.nameConflicts {
+		if key.Namespace == namespace && (recorded.Active || !activeOnly) {
+			keys = append(keys, key)
+		}
+	}
+	sortItemKeys(keys)
+	conflicts := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		conflicts = append(conflicts, s.nameConflicts[key].toMap(s.location))
+	}
+	s.monitorMu.Unlock()
+
+	return map[string]interface{}{
+		"namespace": namespace,
+		"policy":    s.config().nameConflictPolicy(),
+		"conflicts": conflicts,
+		"count":     len(conflicts),
+	}, nil
+}
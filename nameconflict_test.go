@@ -0,0 +1,129 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestNameConflicts(t *testing.T) {
+	apple := `{"item_id":"apple-001","item_name":"Apple"}`
+	reprint := `{"item_id":"apple-001","item_name":"Apple (reprint)"}`
+	conflicting := []objectdetection.Detection{testDetection(0, reprint), testDetection(1, apple)}
+	clean := []objectdetection.Detection{testDetection(0, apple)}
+
+	// scanFrames runs one background scan per frame
+	scanFrames := func(t *testing.T, svc *inventoryKeeperKeeper, vision *frameSetter, frames ...[]objectdetection.Detection) {
+		t.Helper()
+		for _, frame := range frames {
+			vision.frame = frame
+			if err := svc.scanAndCompare(context.Background()); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+		}
+	}
+
+	// setup returns a keeper with the given policy whose vision service
+	// returns whatever frame was set last
+	setup := func(t *testing.T, policy string) (*inventoryKeeperKeeper, *frameSetter) {
+		svc, mockVision := newTestKeeper(t, &Config{NameConflictPolicy: policy})
+		frames := &frameSetter{}
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return frames.frame, nil
+		}
+		return svc, frames
+	}
+
+	presentName := func(svc *inventoryKeeperKeeper) string {
+		svc.monitorMu.Lock()
+		defer svc.monitorMu.Unlock()
+		if entry, ok := svc.presence[keyFor("", "apple-001")]; ok {
+			return entry.ItemName
+		}
+		return ""
+	}
+
+	t.Run("first-wins keeps the tracked name, or the first label for a new item", func(t *testing.T) {
+		svc, frames := setup(t, "")
+		scanFrames(t, svc, frames, clean, conflicting)
+		if name := presentName(svc); name != "Apple" {
+			t.Errorf("expected tracked name Apple to win, got: %q", name)
+		}
+
+		fresh, freshFrames := setup(t, nameConflictFirstWins)
+		scanFrames(t, fresh, freshFrames, conflicting)
+		if name := presentName(fresh); name != "Apple (reprint)" {
+			t.Errorf("expected the first label's name for a new item, got: %q", name)
+		}
+		if len(fresh.alerts) != 0 {
+			t.Errorf("expected no alerts under first-wins, got: %v", fresh.alerts)
+		}
+	})
+
+	t.Run("inventory-wins uses the recorded item name", func(t *testing.T) {
+		svc, frames := setup(t, nameConflictInventoryWins)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+		scanFrames(t, svc, frames, conflicting)
+		if name := presentName(svc); name != "Honeycrisp Apple" {
+			t.Errorf("expected inventory name to win, got: %q", name)
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_conflicts"})
+		conflicts := result["conflicts"].([]interface{})
+		if len(conflicts) != 1 || conflicts[0].(map[string]interface{})["resolved_name"] != "Honeycrisp Apple" {
+			t.Errorf("expected one conflict resolved to Honeycrisp Apple, got: %v", conflicts)
+		}
+	})
+
+	t.Run("flag leaves the present-set unchanged and alerts once", func(t *testing.T) {
+		svc, frames := setup(t, nameConflictFlag)
+		scanFrames(t, svc, frames, clean, clean)
+		svc.monitorMu.Lock()
+		before := *svc.presence[keyFor("", "apple-001")]
+		svc.monitorMu.Unlock()
+
+		scanFrames(t, svc, frames, conflicting, conflicting, conflicting)
+		svc.monitorMu.Lock()
+		after := *svc.presence[keyFor("", "apple-001")]
+		svc.monitorMu.Unlock()
+		if after.ItemName != before.ItemName || after.Hits != before.Hits || after.Misses != before.Misses || !after.Present {
+			t.Errorf("expected present-set entry unchanged, before: %+v, after: %+v", before, after)
+		}
+		if len(svc.alerts) != 1 || svc.alerts[0].Type != "name_conflict" || svc.alerts[0].Severity != severityWarning {
+			t.Fatalf("expected one name_conflict warning, got: %v", svc.alerts)
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_conflicts", "active_only": true})
+		conflicts := result["conflicts"].([]interface{})
+		if len(conflicts) != 1 {
+			t.Fatalf("expected 1 active conflict, got: %v", conflicts)
+		}
+		conflict := conflicts[0].(map[string]interface{})
+		names := conflict["names"].([]interface{})
+		if conflict["flagged"] != true || conflict["scans"] != 3 || len(names) != 2 || names[0] != "Apple (reprint)" {
+			t.Errorf("expected a flagged conflict over 3 scans with both names, got: %v", conflict)
+		}
+
+		// Once the labels agree the conflict is kept but no longer active
+		scanFrames(t, svc, frames, clean)
+		if result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_conflicts", "active_only": true}); result["count"] != 0 {
+			t.Errorf("expected no active conflicts, got: %v", result["conflicts"])
+		}
+		if result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_conflicts"}); result["count"] != 1 {
+			t.Errorf("expected the cleared conflict to stay recorded, got: %v", result["conflicts"])
+		}
+	})
+
+	t.Run("unknown policy rejected", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", NameConflictPolicy: "last-wins"}
+		if _, _, err := cfg.Validate("test"); err == nil {
+			t.Error("expected name_conflict_policy last-wins to be rejected")
+		}
+	})
+}
+
+// frameSetter holds the frame a mock vision service returns
+type frameSetter struct {
+	frame []objectdetection.Detection
+}
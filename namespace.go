@@ -0,0 +1,76 @@
+package inventorykeeper
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// defaultNamespace holds items added, scanned, or queried without a namespace
+const defaultNamespace = "default"
+
+// namespacePattern limits namespaces to short identifier-like names
+var namespacePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// itemKey identifies an item within its namespace. Inventory, the present-set,
+// and the theft detector are all keyed by it, so the same item_id in two
+// namespaces never collides.
+type itemKey struct {
+	Namespace string
+	ItemID    string
+}
+
+// keyFor builds the key for an item, treating an empty namespace as the default
+func keyFor(namespace, itemID string) itemKey {
+	return itemKey{Namespace: normalizeNamespace(namespace), ItemID: itemID}
+}
+
+// sortItemKeys orders keys by namespace, then item_id
+func sortItemKeys(keys []itemKey) {
+	sort.Slice(keys, func(i, j int) bool { return itemKeyLess(keys[i], keys[j]) })
+}
+
+// itemKeyLess orders keys by namespace, then item_id
+func itemKeyLess(a, b itemKey) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.ItemID < b.ItemID
+}
+
+// normalizeNamespace maps the empty namespace (old payloads, log lines, and
+// snapshots written before namespaces existed) to the default
+func normalizeNamespace(namespace string) string {
+	if namespace == "" {
+		return defaultNamespace
+	}
+	return namespace
+}
+
+// validateNamespace checks a namespace against namespacePattern
+func validateNamespace(namespace string) error {
+	if !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("namespace must be 1-64 letters, digits, '-' or '_', got: %q", namespace)
+	}
+	return nil
+}
+
+// namespaceArg returns the command's optional namespace, or the default
+func namespaceArg(cmd map[string]interface{}) (string, error) {
+	raw, ok := cmd["namespace"]
+	if !ok {
+		return defaultNamespace, nil
+	}
+	namespace, ok := raw.(string)
+	if !ok {
+		return "", errors.New("namespace must be a string")
+	}
+	if namespace == "" {
+		return defaultNamespace, nil
+	}
+	if err := validateNamespace(namespace); err != nil {
+		return "", err
+	}
+	return namespace, nil
+}
@@ -0,0 +1,109 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNamespaces(t *testing.T) {
+	t.Run("same item_id in different namespaces does not interfere", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "bolt-001", "item_name": "Bolt", "quantity": 5.0, "namespace": "zone-a",
+		})
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "bolt-001", "item_name": "Anchor Bolt", "quantity": 2.0, "namespace": "zone-b",
+		})
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "adjust_quantity", "item_id": "bolt-001", "delta": -1.0, "namespace": "zone-a",
+		})
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "remove_item", "item_id": "bolt-001", "namespace": "zone-b",
+		})
+
+		a, ok := svc.inventory[keyFor("zone-a", "bolt-001")]
+		if !ok || a.Quantity != 4 || a.ItemName != "Bolt" {
+			t.Errorf("expected zone-a bolt with quantity 4, got: %+v", a)
+		}
+		if _, ok := svc.inventory[keyFor("zone-b", "bolt-001")]; ok {
+			t.Error("expected zone-b bolt to be removed")
+		}
+		if _, ok := svc.inventory[keyFor(defaultNamespace, "bolt-001")]; ok {
+			t.Error("expected nothing in the default namespace")
+		}
+	})
+
+	t.Run("list_items respects the namespace filter", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "nut-001", "item_name": "Nut", "namespace": "zone-a"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "nut-002", "item_name": "Wing Nut", "namespace": "zone-a"})
+
+		zoneA := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items", "namespace": "zone-a"})
+		items := zoneA["items"].([]interface{})
+		if len(items) != 2 {
+			t.Fatalf("expected 2 items in zone-a, got: %d", len(items))
+		}
+		for _, raw := range items {
+			if ns := raw.(map[string]interface{})["namespace"]; ns != "zone-a" {
+				t.Errorf("expected only zone-a items, got namespace: %v", ns)
+			}
+		}
+
+		defaults := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})
+		items = defaults["items"].([]interface{})
+		if len(items) != 1 || items[0].(map[string]interface{})["item_id"] != "apple-001" {
+			t.Errorf("expected only apple-001 in the default namespace, got: %v", items)
+		}
+	})
+
+	t.Run("invalid namespace is rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "list_items", "namespace": "zone/a",
+		})
+		if err == nil {
+			t.Fatal("expected error for namespace containing '/'")
+		}
+	})
+
+	t.Run("check-in in one namespace does not authorize another", func(t *testing.T) {
+		d := newTheftDetector(time.Minute, 0, 0)
+		start := time.Now()
+
+		d.process(detectorEvent{Type: detectorEventCheckIn, Time: start, Namespace: "zone-a", ItemIDs: []string{"bolt-001"}})
+		findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(time.Second), Namespace: "zone-b", ItemIDs: []string{"bolt-001"}})
+		if len(findings) != 1 || findings[0].Namespace != "zone-b" {
+			t.Fatalf("expected theft in zone-b, got: %+v", findings)
+		}
+
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(time.Second), Namespace: "zone-a", ItemIDs: []string{"bolt-001"}}); len(findings) != 0 {
+			t.Errorf("expected zone-a removal to be authorized, got: %+v", findings)
+		}
+	})
+
+	t.Run("snapshot round trip keeps namespaces separate", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "bolt-001", "item_name": "Bolt", "namespace": "zone-a"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "bolt-001", "item_name": "Bolt"})
+
+		snapshot := mustDoCommand(t, svc, map[string]interface{}{"command": "snapshot"})
+
+		restored, _ := newTestKeeper(t, nil)
+		result := mustDoCommand(t, restored, map[string]interface{}{"command": "restore_snapshot", "snapshot": snapshot})
+		if result["items_restored"] != 2 {
+			t.Fatalf("expected 2 items restored, got: %v", result["items_restored"])
+		}
+		if _, ok := restored.inventory[keyFor("zone-a", "bolt-001")]; !ok {
+			t.Error("expected zone-a bolt after restore")
+		}
+		if _, ok := restored.inventory[keyFor(defaultNamespace, "bolt-001")]; !ok {
+			t.Error("expected default bolt after restore")
+		}
+	})
+}
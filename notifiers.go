@@ -0,0 +1,321 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// Notifier types accepted in the notifiers config array
+const (
+	notifierTypeSlack   = "slack"   // Slack incoming webhook
+	notifierTypeWebhook = "webhook" // POST each alert as JSON to any URL
+	notifierTypeLog     = "log"     // Write each alert to the module log only
+)
+
+// Webhook delivery retry defaults
+const (
+	webhookAttempts       = 3                // Tries per alert, including the first
+	webhookInitialBackoff = time.Second      // Wait before the first retry, doubled for each after
+	webhookRequestTimeout = 10 * time.Second // Limit on one request
+)
+
+// NotifierConfig is one entry of the notifiers config array: a destination
+// and the alerts it receives
+type NotifierConfig struct {
+	Type        string            `json:"type"`                   // "slack", "webhook", or "log"
+	Name        string            `json:"name,omitempty"`         // Shown in integrations_status (default: the type)
+	URL         string            `json:"url,omitempty"`          // Required for slack (https) and webhook (http or https)
+	Headers     map[string]string `json:"headers,omitempty"`      // Extra webhook request headers, e.g. Authorization
+	AlertTypes  []string          `json:"alert_types,omitempty"`  // Alert types sent here (default: all)
+	MinSeverity string            `json:"min_severity,omitempty"` // Lowest severity sent here (default: info)
+}
+
+// validate checks the entry's type, destination, and filters
+func (nc *NotifierConfig) validate(index int) error {
+	field := fmt.Sprintf("notifiers[%d]", index)
+	switch nc.Type {
+	case notifierTypeSlack:
+		if err := validateWebhookURL(nc.URL); err != nil {
+			return fmt.Errorf("%s url %w", field, err)
+		}
+	case notifierTypeWebhook:
+		u, err := url.Parse(nc.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s url must be an http or https URL", field)
+		}
+	case notifierTypeLog:
+		if nc.URL != "" {
+			return fmt.Errorf("%s url is not used by type %q", field, notifierTypeLog)
+		}
+	default:
+		return fmt.Errorf("%s type must be %q, %q, or %q, got: %q", field, notifierTypeSlack, notifierTypeWebhook, notifierTypeLog, nc.Type)
+	}
+	if len(nc.Headers) > 0 && nc.Type != notifierTypeWebhook {
+		return fmt.Errorf("%s headers are only used by type %q", field, notifierTypeWebhook)
+	}
+	for _, alertType := range nc.AlertTypes {
+		if strings.TrimSpace(alertType) == "" {
+			return fmt.Errorf("%s alert_types must not contain empty strings", field)
+		}
+	}
+	if nc.MinSeverity != "" {
+		if err := validateSeverity(field+" min_severity", nc.MinSeverity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// builtinNotifierNames lists the names of the notifiers configured outside
+// the notifiers array: "email" for smtp_host and "slack" for
+// slack_webhook_url
+func (cfg *Config) builtinNotifierNames() []string {
+	var names []string
+	if cfg.SMTPHost != "" {
+		names = append(names, "email")
+	}
+	if cfg.SlackWebhookURL != "" {
+		names = append(names, "slack")
+	}
+	return names
+}
+
+// validateNotifiers checks each notifiers entry and that explicit names are
+// unique, both among the entries and against the builtin notifiers'
+// names, since delivery stats and test_notification go by name
+func validateNotifiers(entries []NotifierConfig, builtin []string) error {
+	names := make(map[string]bool, len(entries)+len(builtin))
+	for _, name := range builtin {
+		names[name] = true
+	}
+	for i := range entries {
+		if err := entries[i].validate(i); err != nil {
+			return err
+		}
+		if name := entries[i].Name; name != "" {
+			if names[name] {
+				return fmt.Errorf("notifiers[%d] name %q is used more than once", i, name)
+			}
+			names[name] = true
+		}
+	}
+	return nil
+}
+
+// alertFilter is implemented by notifiers that only want some alerts
+type alertFilter interface {
+	accepts(alert Alert) bool
+}
+
+// configuredNotifier is a notifiers entry: a backend under the entry's name,
+// limited to its alert types and minimum severity
+type configuredNotifier struct {
+	alertNotifier
+	name        string
+	target      string          // Redacted destination for integrations_status
+	alertTypes  map[string]bool // nil: every type
+	minSeverity string
+}
+
+// Name implements alertNotifier
+func (n *configuredNotifier) Name() string {
+	return n.name
+}
+
+// accepts implements alertFilter
+func (n *configuredNotifier) accepts(alert Alert) bool {
+	if n.alertTypes != nil && !n.alertTypes[alert.Type] {
+		return false
+	}
+	return alert.atLeast(n.minSeverity)
+}
+
+// newConfiguredNotifiers builds the notifiers array's backends. Unnamed
+// entries are named for their type, numbered when a name is already taken.
+// Slack messages show alert times in loc.
+func newConfiguredNotifiers(entries []NotifierConfig, taken []alertNotifier, loc *time.Location, logger logging.Logger) []alertNotifier {
+	used := make(map[string]bool, len(taken)+len(entries))
+	for _, n := range taken {
+		used[n.Name()] = true
+	}
+	for _, nc := range entries {
+		used[nc.Name] = nc.Name != ""
+	}
+
+	notifiers := make([]alertNotifier, 0, len(entries))
+	for _, nc := range entries {
+		name := nc.Name
+		if name == "" {
+			name = nc.Type
+			for i := 2; used[name]; i++ {
+				name = fmt.Sprintf("%s-%d", nc.Type, i)
+			}
+			used[name] = true
+		}
+
+		var backend alertNotifier
+		target := redactURL(nc.URL)
+		switch nc.Type {
+		case notifierTypeSlack:
+			backend = &slackNotifier{newWebhookPoster(nc.URL, nil), loc}
+		case notifierTypeWebhook:
+			backend = &webhookNotifier{newWebhookPoster(nc.URL, nc.Headers)}
+		case notifierTypeLog:
+			backend = &logNotifier{logger: logger}
+			target = "module log"
+		}
+
+		n := &configuredNotifier{alertNotifier: backend, name: name, target: target, minSeverity: nc.MinSeverity}
+		if len(nc.AlertTypes) > 0 {
+			n.alertTypes = make(map[string]bool, len(nc.AlertTypes))
+			for _, alertType := range nc.AlertTypes {
+				n.alertTypes[alertType] = true
+			}
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute https URL
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("is not a valid URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("must be an https URL, got: %q", u.Redacted())
+	}
+	return nil
+}
+
+// redactURL keeps a URL's scheme and host, hiding the path and query where
+// webhook secrets live
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	return u.Scheme + "://" + u.Host + "/***"
+}
+
+// webhookPoster POSTs JSON bodies to a URL, retrying network errors, rate
+// limiting, and server errors with exponential backoff
+type webhookPoster struct {
+	url      string
+	headers  map[string]string
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+}
+
+func newWebhookPoster(target string, headers map[string]string) *webhookPoster {
+	return &webhookPoster{
+		url:      target,
+		headers:  headers,
+		client:   &http.Client{Timeout: webhookRequestTimeout},
+		attempts: webhookAttempts,
+		backoff:  webhookInitialBackoff,
+	}
+}
+
+// send delivers body, retrying as configured. It stops retrying, and
+// abandons a request in flight, once ctx is done.
+func (p *webhookPoster) send(ctx context.Context, body []byte) error {
+	wait := p.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := p.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= p.attempts {
+			return fmt.Errorf("webhook failed after %d attempts: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("webhook gave up after %d attempts: %w (last error: %v)", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// post sends one request, reporting whether a failure is worth retrying
+func (p *webhookPoster) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.New("invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// The URL may hold the webhook secret, so keep only the cause
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return true, err
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(reply)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// webhookNotifier POSTs each alert as the JSON get_alerts returns for it
+type webhookNotifier struct {
+	*webhookPoster
+}
+
+// Name implements alertNotifier
+func (n *webhookNotifier) Name() string {
+	return notifierTypeWebhook
+}
+
+// Notify implements alertNotifier
+func (n *webhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert.toMap(time.UTC))
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, body)
+}
+
+// logNotifier writes each alert to the module log, for trying out alert
+// rules or keeping a record without an external service
+type logNotifier struct {
+	logger logging.Logger
+}
+
+// Name implements alertNotifier
+func (n *logNotifier) Name() string {
+	return notifierTypeLog
+}
+
+// Notify implements alertNotifier
+func (n *logNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.logger.Infow(fmt.Sprintf("Notification for alert %d (%s, %s): %s", alert.ID, alert.Type, alert.Severity, alert.Message),
+		"event", "notification", "alert_type", alert.Type, "severity", alert.Severity, "item_id", alert.ItemID)
+	return nil
+}
@@ -0,0 +1,112 @@
+package inventorykeeper
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfiguredNotifiers(t *testing.T) {
+	t.Run("entries are validated", func(t *testing.T) {
+		for _, entries := range [][]NotifierConfig{
+			{{Type: "pager"}},
+			{{Type: notifierTypeSlack, URL: "http://hooks.slack.com/services/x"}},
+			{{Type: notifierTypeWebhook, URL: "ftp://example.com/alerts"}},
+			{{Type: notifierTypeLog, MinSeverity: "urgent"}},
+			{{Type: notifierTypeLog, Headers: map[string]string{"X-Token": "t"}}},
+			{{Type: notifierTypeLog, Name: "audit"}, {Type: notifierTypeLog, Name: "audit"}},
+		} {
+			cfg := &Config{CameraName: "cam", QRVisionService: "qr", Notifiers: entries}
+			if _, _, err := cfg.Validate("test"); err == nil {
+				t.Errorf("expected error for notifiers %+v", entries)
+			}
+		}
+	})
+
+	t.Run("names must not repeat a builtin notifier's", func(t *testing.T) {
+		entries := []NotifierConfig{{Type: notifierTypeLog, Name: "slack"}}
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", Notifiers: entries}
+		if _, _, err := cfg.Validate("test"); err != nil {
+			t.Errorf("expected slack to be free without slack_webhook_url, got: %v", err)
+		}
+		cfg.SlackWebhookURL = "https://hooks.slack.com/services/T000/B000/x"
+		if _, _, err := cfg.Validate("test"); err == nil {
+			t.Error("expected error for a name taken by slack_webhook_url")
+		}
+	})
+
+	t.Run("webhook receives only its alert types", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []map[string]interface{}
+		var token string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var alert map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&alert)
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, alert)
+			token = r.Header.Get("X-Token")
+		}))
+		defer server.Close()
+
+		svc, _ := newTestKeeper(t, &Config{Notifiers: []NotifierConfig{
+			{Type: notifierTypeWebhook, Name: "ops", URL: server.URL, Headers: map[string]string{"X-Token": "shelf"}, AlertTypes: []string{"theft"}},
+			{Type: notifierTypeLog},
+		}})
+
+		svc.raiseAlert("low_stock", severityWarning, "apple-001", "Item apple-001 is low", nil)
+		svc.raiseAlert("theft", severityCritical, "apple-001", "Item apple-001 removed without check-in", nil)
+
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			n := len(received)
+			mu.Unlock()
+			if n > 0 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(received) != 1 {
+			t.Fatalf("expected only the theft alert, got: %v", received)
+		}
+		if received[0]["type"] != "theft" || received[0]["item_id"] != "apple-001" {
+			t.Errorf("unexpected webhook body: %v", received[0])
+		}
+		if token != "shelf" {
+			t.Errorf("expected configured header, got: %q", token)
+		}
+	})
+
+	t.Run("integrations_status lists configured entries", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{Notifiers: []NotifierConfig{
+			{Type: notifierTypeLog},
+			{Type: notifierTypeLog},
+			{Type: notifierTypeWebhook, URL: "https://alerts.example.com/hook?token=secret"},
+		}})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "integrations_status"})
+		integrations := result["integrations"].([]interface{})
+		if len(integrations) != 5 {
+			t.Fatalf("expected email, slack, and 3 configured entries, got: %v", integrations)
+		}
+		want := []struct{ name, typ, target string }{
+			{"log", "log", "module log"},
+			{"log-2", "log", "module log"},
+			{"webhook", "webhook", "https://alerts.example.com/***"},
+		}
+		for i, w := range want {
+			entry := integrations[i+2].(map[string]interface{})
+			if entry["name"] != w.name || entry["type"] != w.typ || entry["target"] != w.target || entry["configured"] != true {
+				t.Errorf("expected %+v, got: %v", w, entry)
+			}
+		}
+	})
+}
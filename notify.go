@@ -0,0 +1,296 @@
+package inventorykeeper
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// alertNotifier delivers alerts to an external channel. Notify gives up
+// waiting to retry once ctx is done.
+type alertNotifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Responses when a notifier's queue is full
+const (
+	overflowDropOldest = "drop_oldest" // Discard the longest-queued alert to make room
+	overflowDropNewest = "drop_newest" // Discard the alert being queued
+)
+
+// validOverflowPolicies are the accepted event_overflow_policy values
+var validOverflowPolicies = map[string]bool{
+	overflowDropOldest: true,
+	overflowDropNewest: true,
+}
+
+// defaultEventBufferSize is how many alerts wait for each notifier when
+// event_buffer_size isn't set
+const defaultEventBufferSize = 100
+
+// eventBufferSize returns the configured per-notifier queue size, defaulting to 100
+func (cfg *Config) eventBufferSize() int {
+	if cfg.EventBufferSize == nil {
+		return defaultEventBufferSize
+	}
+	return *cfg.EventBufferSize
+}
+
+// eventOverflowPolicy returns the configured policy, defaulting to drop_oldest
+func (cfg *Config) eventOverflowPolicy() string {
+	if cfg.EventOverflowPolicy == "" {
+		return overflowDropOldest
+	}
+	return cfg.EventOverflowPolicy
+}
+
+// notifyQueue buffers alerts for one notifier, delivered in order by a
+// single worker goroutine
+type notifyQueue struct {
+	alerts chan Alert
+	mu     sync.Mutex // Makes drop-oldest's receive and send one step
+}
+
+// notifyQueueFor returns the notifier's queue, starting its worker on first
+// use. The worker stops when the keeper closes.
+func (s *inventoryKeeperKeeper) notifyQueueFor(n alertNotifier) *notifyQueue {
+	s.notifyQueuesMu.Lock()
+	defer s.notifyQueuesMu.Unlock()
+	if q, ok := s.notifyQueues[n]; ok {
+		return q
+	}
+	if s.notifyQueues == nil {
+		s.notifyQueues = make(map[alertNotifier]*notifyQueue)
+	}
+	q := &notifyQueue{alerts: make(chan Alert, s.config().eventBufferSize())}
+	s.notifyQueues[n] = q
+
+	go func() {
+		for {
+			select {
+			case <-s.cancelCtx.Done():
+				return
+			case alert := <-q.alerts:
+				err := n.Notify(s.cancelCtx, alert)
+				if err != nil {
+					s.logger.Warnf("Failed to send alert %d via %s: %v", alert.ID, n.Name(), err)
+				}
+				s.recordDelivery(n.Name(), err)
+			}
+		}
+	}()
+	return q
+}
+
+// dispatchAlert queues an alert for every notifier without ever waiting, so
+// a slow or stuck mail server can't block the scan loop. When a queue is
+// full an alert is dropped per event_overflow_policy and counted for
+// integrations_status.
+func (s *inventoryKeeperKeeper) dispatchAlert(alert Alert) {
+	policy := s.config().eventOverflowPolicy()
+	for _, n := range s.notifiers {
+		if f, ok := n.(alertFilter); ok && !f.accepts(alert) {
+			continue
+		}
+		q := s.notifyQueueFor(n)
+		q.mu.Lock()
+		select {
+		case q.alerts <- alert:
+			q.mu.Unlock()
+			continue
+		default:
+		}
+
+		dropped := alert
+		if policy == overflowDropOldest {
+			select {
+			case dropped = <-q.alerts:
+			default:
+				// The worker emptied the queue meanwhile
+				dropped = Alert{}
+			}
+			q.alerts <- alert
+		}
+		q.mu.Unlock()
+
+		if dropped.ID != 0 {
+			s.logger.Warnf("Dropped alert %d for %s, its queue of %d is full", dropped.ID, n.Name(), cap(q.alerts))
+			s.recordDrop(n.Name())
+		}
+	}
+}
+
+// smtpSendTimeout limits one email delivery, from dial to QUIT, so a server
+// that stops responding can't hold up the alert queue
+const smtpSendTimeout = 30 * time.Second
+
+// sendMailFunc is smtp.SendMail with a context, so tests can capture outgoing mail
+type sendMailFunc func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// sendMail delivers msg like smtp.SendMail, but gives up once ctx is done or
+// smtpSendTimeout has passed
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	for _, line := range append([]string{from}, to...) {
+		if strings.ContainsAny(line, "\r\n") {
+			return errors.New("smtp: address contains a line break")
+		}
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpSendTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	// Cut off a stalled exchange as soon as ctx is cancelled
+	defer context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// emailNotifier sends alerts as plain-text email over SMTP
+type emailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	loc  *time.Location
+	send sendMailFunc
+}
+
+// newEmailNotifier builds an SMTP notifier from config, or returns nil when
+// SMTP is not configured. Alert times are shown in loc.
+func newEmailNotifier(cfg *Config, loc *time.Location) *emailNotifier {
+	if cfg.SMTPHost == "" {
+		return nil
+	}
+
+	n := &emailNotifier{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		from: cfg.SMTPUsername,
+		to:   parseEmailList(cfg.AlertEmailTo),
+		loc:  loc,
+		send: sendMail,
+	}
+	if n.from == "" {
+		n.from = "inventory-keeper@" + cfg.SMTPHost
+	}
+	if cfg.SMTPUsername != "" {
+		n.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return n
+}
+
+// parseEmailList splits a comma-separated recipient list
+func parseEmailList(list string) []string {
+	var out []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
+
+// Name implements alertNotifier
+func (n *emailNotifier) Name() string {
+	return "email"
+}
+
+// Notify implements alertNotifier
+func (n *emailNotifier) Notify(ctx context.Context, alert Alert) error {
+	return n.send(ctx, n.addr, n.auth, n.from, n.to, formatAlertEmail(n.from, n.to, alert, n.loc))
+}
+
+// headerLineBreaks flattens line breaks in header values
+var headerLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// headerValue makes s safe for a single header line. Alert messages carry
+// item names from scanned labels, and a line break in one would otherwise
+// start a new header.
+func headerValue(s string) string {
+	return headerLineBreaks.Replace(s)
+}
+
+// formatAlertEmail renders an alert as an RFC 5322 message, with its time in loc
+func formatAlertEmail(from string, to []string, alert Alert, loc *time.Location) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue(from))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(strings.Join(to, ", ")))
+	fmt.Fprintf(&b, "Subject: [inventory-keeper] %s alert: %s\r\n", headerValue(alert.Type), headerValue(alert.Message))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+
+	fmt.Fprintf(&b, "%s\r\n\r\n", alert.Message)
+	fmt.Fprintf(&b, "Alert ID: %d\r\n", alert.ID)
+	fmt.Fprintf(&b, "Type: %s\r\n", alert.Type)
+	fmt.Fprintf(&b, "Severity: %s\r\n", alert.Severity)
+	if alert.ItemID != "" {
+		fmt.Fprintf(&b, "Item: %s\r\n", alert.ItemID)
+	}
+	fmt.Fprintf(&b, "Time: %s\r\n", formatTimestampIn(alert.CreatedAt, loc))
+
+	keys := make([]string, 0, len(alert.Details))
+	for k := range alert.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %v\r\n", k, alert.Details[k])
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,272 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// capturedMail is one message handed to the injected SMTP sender
+type capturedMail struct {
+	addr string
+	from string
+	to   []string
+	body string
+}
+
+func TestEmailNotifications(t *testing.T) {
+	smtpConfig := func() *Config {
+		return &Config{
+			SMTPHost:     "mail.example.com",
+			SMTPPort:     587,
+			SMTPUsername: "keeper@example.com",
+			SMTPPassword: "hunter2",
+			AlertEmailTo: "ops@example.com, owner@example.com",
+		}
+	}
+
+	t.Run("theft alert produces the expected email", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, smtpConfig())
+
+		sent := make(chan capturedMail, 1)
+		svc.notifiers[0].(*emailNotifier).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			sent <- capturedMail{addr: addr, from: from, to: to, body: string(msg)}
+			return nil
+		}
+
+		svc.feedTheftDetector(detectorEvent{Type: detectorEventItemRemoved, Time: time.Now(), ItemIDs: []string{"apple-001"}})
+
+		select {
+		case mail := <-sent:
+			if mail.addr != "mail.example.com:587" {
+				t.Errorf("expected mail.example.com:587, got: %s", mail.addr)
+			}
+			if mail.from != "keeper@example.com" {
+				t.Errorf("expected sender keeper@example.com, got: %s", mail.from)
+			}
+			if !reflect.DeepEqual(mail.to, []string{"ops@example.com", "owner@example.com"}) {
+				t.Errorf("unexpected recipients: %v", mail.to)
+			}
+			for _, want := range []string{
+				"Subject: [inventory-keeper] theft alert: Item apple-001 removed without check-in",
+				"Item: apple-001",
+				"To: ops@example.com, owner@example.com",
+			} {
+				if !strings.Contains(mail.body, want) {
+					t.Errorf("expected email to contain %q, got:\n%s", want, mail.body)
+				}
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected an email for the theft alert")
+		}
+	})
+
+	t.Run("line breaks in an item name can't add headers", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, smtpConfig())
+		sent := make(chan capturedMail, 1)
+		svc.notifiers[0].(*emailNotifier).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			sent <- capturedMail{addr: addr, from: from, to: to, body: string(msg)}
+			return nil
+		}
+
+		svc.alertLowStock(&InventoryItem{ItemID: "apple-001", ItemName: "Apple\r\nBcc: attacker@example.com\nX-Injected: yes", Quantity: 1, ReorderThreshold: 2})
+
+		select {
+		case mail := <-sent:
+			headers, _, _ := strings.Cut(mail.body, "\r\n\r\n")
+			for _, line := range strings.Split(headers, "\r\n") {
+				if strings.HasPrefix(line, "Bcc:") || strings.HasPrefix(line, "X-Injected:") || strings.ContainsAny(line, "\r\n") {
+					t.Errorf("expected no injected header, got line %q in:\n%s", line, headers)
+				}
+			}
+			if !strings.Contains(headers, "(Apple Bcc: attacker@example.com X-Injected: yes)") {
+				t.Errorf("expected the item name flattened into the subject, got:\n%s", headers)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected an email for the low_stock alert")
+		}
+	})
+
+	t.Run("send failure is logged without blocking alerts", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, smtpConfig())
+		svc.notifiers[0].(*emailNotifier).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			return errors.New("connection refused")
+		}
+
+		svc.raiseAlert("theft", severityCritical, "apple-001", "Item apple-001 removed without check-in", nil)
+		if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]; count != 1 {
+			t.Errorf("expected alert to be recorded despite send failure, got: %v", count)
+		}
+	})
+
+	t.Run("a stalled SMTP server gives up when ctx is done", func(t *testing.T) {
+		// The server accepts the connection and never sends a greeting
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer listener.Close()
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+			}
+		}()
+
+		n := &emailNotifier{addr: listener.Addr().String(), from: "keeper@example.com", to: []string{"ops@example.com"}, loc: time.UTC, send: sendMail}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() { done <- n.Notify(ctx, Alert{ID: 1, Type: "test", Message: "hello"}) }()
+
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Error("expected an error from the stalled server")
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected Notify to return once ctx was done")
+		}
+	})
+
+	t.Run("partial SMTP config is rejected", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", SMTPHost: "mail.example.com", SMTPPort: 25}
+		if _, _, err := cfg.Validate(""); err == nil || !strings.Contains(err.Error(), "alert_email_to") {
+			t.Errorf("expected alert_email_to error, got: %v", err)
+		}
+
+		cfg = &Config{CameraName: "cam", QRVisionService: "qr", AlertEmailTo: "ops@example.com"}
+		if _, _, err := cfg.Validate(""); err == nil || !strings.Contains(err.Error(), "smtp_host") {
+			t.Errorf("expected smtp_host error, got: %v", err)
+		}
+	})
+
+	t.Run("no notifiers without SMTP config", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		if len(svc.notifiers) != 0 {
+			t.Errorf("expected no notifiers, got: %d", len(svc.notifiers))
+		}
+	})
+}
+
+func TestAlertBackpressure(t *testing.T) {
+	// blockedKeeper returns a keeper whose email notifier holds its first
+	// alert until the test ends, once that alert has reached the notifier
+	blockedKeeper := func(t *testing.T, policy string) *inventoryKeeperKeeper {
+		t.Helper()
+		bufferSize := 1
+		svc, _ := newTestKeeper(t, &Config{
+			SMTPHost:            "mail.example.com",
+			SMTPPort:            587,
+			AlertEmailTo:        "ops@example.com",
+			EventBufferSize:     &bufferSize,
+			EventOverflowPolicy: policy,
+		})
+
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+		svc.notifiers[0].(*emailNotifier).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		}
+		t.Cleanup(func() { close(release) })
+
+		svc.raiseAlert("theft", severityCritical, "item-0", "Item item-0 removed without check-in", nil)
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected the notifier to receive the first alert")
+		}
+		return svc
+	}
+
+	t.Run("full queue drops alerts without blocking the scan loop", func(t *testing.T) {
+		svc := blockedKeeper(t, "")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 1; i <= 4; i++ {
+				svc.raiseAlert("theft", severityCritical, fmt.Sprintf("item-%d", i), "removed without check-in", nil)
+			}
+			if err := svc.scanAndCompare(context.Background()); err != nil {
+				t.Errorf("expected scan to succeed, got: %v", err)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("raising alerts blocked on a stuck notifier")
+		}
+
+		// One alert is held by the notifier and one fits the queue
+		if dropped := svc.droppedAlerts(); dropped != 3 {
+			t.Errorf("expected 3 dropped alerts, got: %d", dropped)
+		}
+		status := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})
+		if status["dropped_alerts"] != 3 {
+			t.Errorf("expected get_status dropped_alerts 3, got: %v", status["dropped_alerts"])
+		}
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "integrations_status"})
+		entry := result["integrations"].([]interface{})[0].(map[string]interface{})
+		if entry["dropped"] != 3 {
+			t.Errorf("expected integrations_status dropped 3, got: %v", entry["dropped"])
+		}
+		if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]; count != 5 {
+			t.Errorf("expected all 5 alerts recorded despite drops, got: %v", count)
+		}
+	})
+
+	t.Run("drop_oldest keeps the latest alert queued", func(t *testing.T) {
+		svc := blockedKeeper(t, "drop_oldest")
+		for i := 1; i <= 3; i++ {
+			svc.raiseAlert("theft", severityCritical, fmt.Sprintf("item-%d", i), fmt.Sprintf("Item item-%d removed", i), nil)
+		}
+
+		q := svc.notifyQueueFor(svc.notifiers[0])
+		if queued := <-q.alerts; queued.ItemID != "item-3" {
+			t.Errorf("expected item-3 queued, got: %s", queued.ItemID)
+		}
+	})
+
+	t.Run("drop_newest keeps the earliest alert queued", func(t *testing.T) {
+		svc := blockedKeeper(t, "drop_newest")
+		for i := 1; i <= 3; i++ {
+			svc.raiseAlert("theft", severityCritical, fmt.Sprintf("item-%d", i), fmt.Sprintf("Item item-%d removed", i), nil)
+		}
+
+		q := svc.notifyQueueFor(svc.notifiers[0])
+		if queued := <-q.alerts; queued.ItemID != "item-1" {
+			t.Errorf("expected item-1 queued, got: %s", queued.ItemID)
+		}
+		if dropped := svc.droppedAlerts(); dropped != 2 {
+			t.Errorf("expected 2 dropped alerts, got: %d", dropped)
+		}
+	})
+
+	t.Run("invalid queue settings are rejected", func(t *testing.T) {
+		zero := 0
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", EventBufferSize: &zero}
+		if _, _, err := cfg.Validate(""); err == nil || !strings.Contains(err.Error(), "event_buffer_size") {
+			t.Errorf("expected event_buffer_size error, got: %v", err)
+		}
+
+		cfg = &Config{CameraName: "cam", QRVisionService: "qr", EventOverflowPolicy: "block"}
+		if _, _, err := cfg.Validate(""); err == nil || !strings.Contains(err.Error(), "event_overflow_policy") {
+			t.Errorf("expected event_overflow_policy error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package inventorykeeper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxPageSize bounds page_size for paginated list_items, get_history, and
+// scan_qr responses
+const maxPageSize = 500
+
+// Scan result pages are kept for follow-up calls for a while; the oldest are
+// dropped once too many are held
+const (
+	scanPagesTTL = 5 * time.Minute
+	maxScanPages = 16
+)
+
+// pageCursor is the state behind an opaque next_cursor token. list_items and
+// get_history resume after the last key or sequence number returned, so
+// items and events present throughout are returned exactly once even as
+// others change. scan_qr pages are served from a saved copy of one scan.
+type pageCursor struct {
+	Command   string `json:"c"`
+	Namespace string `json:"ns,omitempty"`
+	AfterID   string `json:"id,omitempty"`   // list_items: last item_id returned
+	AfterSeq  int64  `json:"seq,omitempty"`  // get_history: last event seq returned
+	ScanID    int64  `json:"scan,omitempty"` // scan_qr: saved scan to read from
+	Offset    int    `json:"off,omitempty"`  // scan_qr: codes already returned
+}
+
+// encode renders the cursor as a URL-safe token
+func (c pageCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// pageArgs reads page_size and cursor. A cursor must come from the same
+// command and namespace. Given only a cursor, pages are maxPageSize long;
+// pageSize is 0 when neither is given and the response isn't paginated.
+func pageArgs(cmd map[string]interface{}, command, namespace string) (pageSize int, cursor *pageCursor, err error) {
+	pageSize, hasPageSize, err := intArg(cmd, "page_size")
+	if err != nil {
+		return 0, nil, err
+	}
+	if hasPageSize && (pageSize < 1 || pageSize > maxPageSize) {
+		return 0, nil, fmt.Errorf("page_size must be between 1 and %d, got: %d", maxPageSize, pageSize)
+	}
+
+	token, err := optionalStringArg(cmd, "cursor")
+	if err != nil || token == "" {
+		return pageSize, nil, err
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, nil, errors.New("cursor is not a valid page token")
+	}
+	cursor = &pageCursor{}
+	if err := json.Unmarshal(raw, cursor); err != nil {
+		return 0, nil, errors.New("cursor is not a valid page token")
+	}
+	if cursor.Command != command || cursor.Namespace != namespace {
+		return 0, nil, fmt.Errorf("cursor belongs to %s in namespace %q, not %s in %q", cursor.Command, cursor.Namespace, command, namespace)
+	}
+	if !hasPageSize {
+		pageSize = maxPageSize
+	}
+	return pageSize, cursor, nil
+}
+
+// scanPage is the saved result of a paginated scan_qr call
+type scanPage struct {
+	result  map[string]interface{} // The scan's response minus its codes
+	codes   []interface{}          // Every code the scan found
+	savedAt time.Time
+}
+
+// saveScanPage keeps a scan's codes for later pages and returns its id,
+// dropping expired scans and then the oldest beyond maxScanPages
+func (s *inventoryKeeperKeeper) saveScanPage(result map[string]interface{}, codes []interface{}) int64 {
+	s.scanPagesMu.Lock()
+	defer s.scanPagesMu.Unlock()
+
+	s.scanPageSeq++
+	id := s.scanPageSeq
+	now := s.now()
+	if s.scanPages == nil {
+		s.scanPages = make(map[int64]*scanPage)
+	}
+	for key, page := range s.scanPages {
+		if now.Sub(page.savedAt) > scanPagesTTL {
+			delete(s.scanPages, key)
+		}
+	}
+	for len(s.scanPages) >= maxScanPages {
+		oldest := id
+		for key := range s.scanPages {
+			oldest = min(oldest, key)
+		}
+		delete(s.scanPages, oldest)
+	}
+	s.scanPages[id] = &scanPage{result: result, codes: codes, savedAt: now}
+	return id
+}
+
+// scanPageResult returns a page of codes from a saved scan, with a next
+// cursor while codes remain
+func (s *inventoryKeeperKeeper) scanPageResult(cursor pageCursor, pageSize int) (map[string]interface{}, error) {
+	s.scanPagesMu.Lock()
+	page, ok := s.scanPages[cursor.ScanID]
+	if ok && s.now().Sub(page.savedAt) > scanPagesTTL {
+		delete(s.scanPages, cursor.ScanID)
+		ok = false
+	}
+	s.scanPagesMu.Unlock()
+	if !ok {
+		return nil, errors.New("cursor has expired; run scan_qr again")
+	}
+	return pagedScanResult(page.result, page.codes, cursor, pageSize), nil
+}
+
+// pagedScanResult builds the response for one page of a saved scan
+func pagedScanResult(base map[string]interface{}, codes []interface{}, cursor pageCursor, pageSize int) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+3)
+	for key, value := range base {
+		result[key] = value
+	}
+	start := min(cursor.Offset, len(codes))
+	end := min(start+pageSize, len(codes))
+	result["codes"] = codes[start:end]
+	result["count"] = end - start
+	result["total_count"] = len(codes)
+	if end < len(codes) {
+		cursor.Offset = end
+		result["next_cursor"] = cursor.encode()
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestPagination(t *testing.T) {
+	ctx := context.Background()
+
+	// stock adds n items named item-000, item-001, ...
+	stock := func(t *testing.T, svc *inventoryKeeperKeeper, n int) {
+		for i := 0; i < n; i++ {
+			mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": fmt.Sprintf("item-%03d", i), "item_name": "Widget"})
+		}
+	}
+	// collect pages through a command, returning the listed field values
+	// and the number of pages
+	collect := func(t *testing.T, svc *inventoryKeeperKeeper, cmd map[string]interface{}, listKey, field string) ([]interface{}, int) {
+		t.Helper()
+		var values []interface{}
+		pages := 0
+		for {
+			result := mustDoCommand(t, svc, cmd)
+			pages++
+			for _, raw := range result[listKey].([]interface{}) {
+				values = append(values, raw.(map[string]interface{})[field])
+			}
+			next, ok := result["next_cursor"].(string)
+			if !ok {
+				return values, pages
+			}
+			cmd = map[string]interface{}{"command": cmd["command"], "cursor": next, "page_size": cmd["page_size"]}
+		}
+	}
+
+	t.Run("50 items across pages with no duplicates or gaps", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		stock(t, svc, 50)
+
+		first := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items", "page_size": 20})
+		if first["count"] != 20 || first["total_count"] != 50 {
+			t.Fatalf("expected 20 of 50 on the first page, got count %v total %v", first["count"], first["total_count"])
+		}
+
+		ids, pages := collect(t, svc, map[string]interface{}{"command": "list_items", "page_size": 20}, "items", "item_id")
+		if pages != 3 || len(ids) != 50 {
+			t.Fatalf("expected 50 items over 3 pages, got %d over %d", len(ids), pages)
+		}
+		for i, id := range ids {
+			if want := fmt.Sprintf("item-%03d", i); id != want {
+				t.Fatalf("item %d: expected %s, got %v", i, want, id)
+			}
+		}
+	})
+
+	t.Run("list cursor tolerates changes between pages", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		stock(t, svc, 10)
+
+		first := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items", "page_size": 5})
+		// Removing an item already returned doesn't shift the next page
+		mustDoCommand(t, svc, map[string]interface{}{"command": "remove_item", "item_id": "item-002"})
+		second := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items", "page_size": 5, "cursor": first["next_cursor"]})
+
+		items := second["items"].([]interface{})
+		if len(items) != 5 || items[0].(map[string]interface{})["item_id"] != "item-005" {
+			t.Errorf("expected item-005 through item-009, got: %v", items)
+		}
+		if _, ok := second["next_cursor"]; ok {
+			t.Error("expected no cursor after the last page")
+		}
+	})
+
+	t.Run("history pages follow event order", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		stock(t, svc, 12)
+
+		seqs, pages := collect(t, svc, map[string]interface{}{"command": "get_history", "page_size": 5}, "events", "seq")
+		if pages != 3 || len(seqs) != 12 {
+			t.Fatalf("expected 12 events over 3 pages, got %d over %d", len(seqs), pages)
+		}
+		for i, seq := range seqs {
+			if seq != int64(i+1) {
+				t.Fatalf("event %d: expected seq %d, got %v", i, i+1, seq)
+			}
+		}
+	})
+
+	t.Run("scan pages come from one saved scan", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		scans := 0
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			scans++
+			detections := make([]objectdetection.Detection, 0, 30)
+			for i := 0; i < 30; i++ {
+				payload, _ := json.Marshal(ItemQRData{ItemID: fmt.Sprintf("item-%03d", i), ItemName: "Widget"})
+				detections = append(detections, testDetection(i, string(payload)))
+			}
+			return detections, nil
+		}
+
+		ids, pages := collect(t, svc, map[string]interface{}{"command": "scan_qr", "page_size": 12}, "codes", "item_id")
+		if pages != 3 || len(ids) != 30 || scans != 1 {
+			t.Fatalf("expected 30 codes over 3 pages from 1 scan, got %d over %d from %d", len(ids), pages, scans)
+		}
+		seen := map[interface{}]bool{}
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate code %v", id)
+			}
+			seen[id] = true
+		}
+	})
+
+	t.Run("bad page arguments are rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		stock(t, svc, 3)
+		historyCursor := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history", "page_size": 1})["next_cursor"]
+		for _, cmd := range []map[string]interface{}{
+			{"command": "list_items", "page_size": 0},
+			{"command": "list_items", "page_size": maxPageSize + 1},
+			{"command": "list_items", "cursor": "not-a-cursor"},
+			{"command": "list_items", "cursor": historyCursor},
+			{"command": "get_history", "limit": 2, "page_size": 1},
+		} {
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// monitorPause is the maintenance pause state of the background scan loop
+type monitorPause struct {
+	paused       bool      // Background scans are skipped
+	pausedAt     time.Time // When the pause began
+	reason       string    // Why monitoring was paused ("" if not given)
+	resumesAt    time.Time // Automatic resume time (zero when paused until resume_monitoring)
+	skippedScans int       // Scans skipped during the current pause
+	quietScans   int       // Scans left after a resume whose removals skip theft detection
+}
+
+// resumeLocked ends the pause and records it in history. Removals seen in the
+// first presence_debounce_scans scans afterwards reflect the maintenance, not
+// theft, so they skip theft detection. Caller must hold pauseMu.
+func (s *inventoryKeeperKeeper) resumeLocked(auto bool) map[string]interface{} {
+	details := map[string]interface{}{
+		"paused_at":     formatTimestampIn(s.pause.pausedAt, s.location),
+		"skipped_scans": s.pause.skippedScans,
+		"auto":          auto,
+	}
+	if s.pause.reason != "" {
+		details["reason"] = s.pause.reason
+	}
+	s.pause = monitorPause{quietScans: s.config().presenceDebounceScans()}
+
+	s.inventoryMu.Lock()
+	s.recordHistoryLocked("monitoring_resumed", defaultNamespace, "", details)
+	s.inventoryMu.Unlock()
+	s.logger.Infof("Monitoring resumed (%v scans skipped while paused)", details["skipped_scans"])
+	return details
+}
+
+// skipPausedScan reports whether the scan about to run should be skipped for
+// a maintenance pause, resuming first if a timed pause has run out
+func (s *inventoryKeeperKeeper) skipPausedScan() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if !s.pause.paused {
+		return false
+	}
+	if !s.pause.resumesAt.IsZero() && !s.now().Before(s.pause.resumesAt) {
+		s.resumeLocked(true)
+		return false
+	}
+	s.pause.skippedScans++
+	return true
+}
+
+// takeQuietScan reports whether this scan's removals should skip theft
+// detection because monitoring just resumed
+func (s *inventoryKeeperKeeper) takeQuietScan() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if s.pause.quietScans == 0 {
+		return false
+	}
+	s.pause.quietScans--
+	return true
+}
+
+// pauseStatus summarizes the maintenance pause for status responses
+func (s *inventoryKeeperKeeper) pauseStatus() map[string]interface{} {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	result := map[string]interface{}{"paused": s.pause.paused}
+	if !s.pause.paused {
+		return result
+	}
+	result["paused_at"] = formatTimestampIn(s.pause.pausedAt, s.location)
+	result["skipped_scans"] = s.pause.skippedScans
+	if s.pause.reason != "" {
+		result["reason"] = s.pause.reason
+	}
+	if !s.pause.resumesAt.IsZero() {
+		result["resumes_at"] = formatTimestampIn(s.pause.resumesAt, s.location)
+	}
+	return result
+}
+
+// handlePauseMonitoring pauses background scans for maintenance, optionally
+// resuming automatically after duration. Pausing again replaces the duration.
+func (s *inventoryKeeperKeeper) handlePauseMonitoring(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	rawDuration, err := optionalStringArg(cmd, "duration")
+	if err != nil {
+		return nil, err
+	}
+	var duration time.Duration
+	if rawDuration != "" {
+		if duration, err = time.ParseDuration(rawDuration); err != nil {
+			return nil, fmt.Errorf("duration must be a duration such as \"30m\": %w", err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("duration must be positive, got: %s", rawDuration)
+		}
+	}
+	reason, err := optionalStringArg(cmd, "reason")
+	if err != nil {
+		return nil, err
+	}
+
+	s.pauseMu.Lock()
+	now := s.now()
+	if !s.pause.paused {
+		s.pause = monitorPause{paused: true, pausedAt: now}
+	}
+	s.pause.reason = reason
+	s.pause.resumesAt = time.Time{}
+	if duration > 0 {
+		s.pause.resumesAt = now.Add(duration)
+	}
+
+	details := map[string]interface{}{}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	if duration > 0 {
+		details["resumes_at"] = formatTimestampIn(s.pause.resumesAt, s.location)
+	}
+	s.inventoryMu.Lock()
+	s.recordHistoryLocked("monitoring_paused", defaultNamespace, "", details)
+	s.inventoryMu.Unlock()
+	s.pauseMu.Unlock()
+
+	s.logger.Infof("Monitoring paused for maintenance: %s", reason)
+	return s.pauseStatus(), nil
+}
+
+// handleResumeMonitoring ends a maintenance pause
+func (s *inventoryKeeperKeeper) handleResumeMonitoring(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if !s.pause.paused {
+		return map[string]interface{}{"paused": false, "resumed": false}, nil
+	}
+	details := s.resumeLocked(false)
+	return map[string]interface{}{
+		"paused":        false,
+		"resumed":       true,
+		"skipped_scans": details["skipped_scans"],
+	}, nil
+}
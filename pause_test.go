@@ -0,0 +1,153 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestPauseMonitoring(t *testing.T) {
+	ctx := context.Background()
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	// setup confirms apple present and counts vision calls; the returned
+	// func switches the shelf between stocked and empty
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, *int, func(stocked bool)) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+
+		calls := 0
+		stocked := true
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			calls++
+			if !stocked {
+				return []objectdetection.Detection{}, nil
+			}
+			return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+		}
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		return svc, &calls, func(s bool) { stocked = s }
+	}
+	theftAlerts := func(t *testing.T, svc *inventoryKeeperKeeper) int {
+		t.Helper()
+		count := 0
+		for _, alert := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{}) {
+			if alert.(map[string]interface{})["type"] == "theft" {
+				count++
+			}
+		}
+		return count
+	}
+	historyTypes := func(t *testing.T, svc *inventoryKeeperKeeper) map[string]int {
+		t.Helper()
+		types := map[string]int{}
+		for _, event := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_history"})["events"].([]interface{}) {
+			types[event.(map[string]interface{})["type"].(string)]++
+		}
+		return types
+	}
+
+	t.Run("paused scans are skipped and restocking raises no alert", func(t *testing.T) {
+		svc, calls, setStocked := setup(t)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "pause_monitoring", "reason": "restocking"})
+		if result["paused"] != true || result["reason"] != "restocking" {
+			t.Fatalf("expected paused state, got: %v", result)
+		}
+
+		before := *calls
+		setStocked(false)
+		for i := 0; i < 4; i++ {
+			svc.scanAndCompare(ctx)
+		}
+		if *calls != before {
+			t.Errorf("expected no vision calls while paused, got %d", *calls-before)
+		}
+		status := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["monitoring_paused"].(map[string]interface{})
+		if status["paused"] != true || status["skipped_scans"] != 4 {
+			t.Errorf("expected get_status to report the pause, got: %v", status)
+		}
+
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "resume_monitoring"})
+		if result["resumed"] != true || result["skipped_scans"] != 4 {
+			t.Fatalf("expected resume, got: %v", result)
+		}
+
+		// The apple taken during maintenance leaves the present-set quietly
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		if *calls != before+2 {
+			t.Errorf("expected scans to resume, got %d vision calls", *calls-before)
+		}
+		if n := theftAlerts(t, svc); n != 0 {
+			t.Errorf("expected no theft alerts for maintenance removals, got: %d", n)
+		}
+
+		// Once settled, an unchecked removal is a theft again
+		setStocked(true)
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		setStocked(false)
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		if n := theftAlerts(t, svc); n != 1 {
+			t.Errorf("expected theft detection to resume, got %d alerts", n)
+		}
+
+		types := historyTypes(t, svc)
+		if types["monitoring_paused"] != 1 || types["monitoring_resumed"] != 1 {
+			t.Errorf("expected pause and resume in history, got: %v", types)
+		}
+	})
+
+	t.Run("timed pause resumes on its own", func(t *testing.T) {
+		svc, calls, _ := setup(t)
+		now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		svc.now = func() time.Time { return now }
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "pause_monitoring", "duration": "10m"})
+		status := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["monitoring_paused"].(map[string]interface{})
+		if status["resumes_at"] != "2026-03-01T09:10:00Z" {
+			t.Errorf("expected resumes_at 09:10, got: %v", status["resumes_at"])
+		}
+
+		before := *calls
+		svc.scanAndCompare(ctx)
+		if *calls != before {
+			t.Fatal("expected scan to be skipped before the pause runs out")
+		}
+
+		now = now.Add(10 * time.Minute)
+		svc.scanAndCompare(ctx)
+		if *calls != before+1 {
+			t.Error("expected scanning to resume once the pause ran out")
+		}
+		status = mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["monitoring_paused"].(map[string]interface{})
+		if status["paused"] != false {
+			t.Errorf("expected pause to have ended, got: %v", status)
+		}
+		if types := historyTypes(t, svc); types["monitoring_resumed"] != 1 {
+			t.Errorf("expected automatic resume in history, got: %v", types)
+		}
+	})
+
+	t.Run("invalid duration is rejected", func(t *testing.T) {
+		svc, _, _ := setup(t)
+		for _, duration := range []string{"soon", "-5m"} {
+			if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "pause_monitoring", "duration": duration}); err == nil {
+				t.Errorf("expected error for duration %q", duration)
+			}
+		}
+	})
+
+	t.Run("resume without a pause is a no-op", func(t *testing.T) {
+		svc, _, _ := setup(t)
+		if result := mustDoCommand(t, svc, map[string]interface{}{"command": "resume_monitoring"}); result["resumed"] != false {
+			t.Errorf("expected resumed=false, got: %v", result)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package inventorykeeper
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedPayloadPrefix marks QR content that holds an AES-GCM encrypted
+// ItemQRData payload rather than plaintext JSON
+const encryptedPayloadPrefix = "ikenc:"
+
+var (
+	errPayloadEncrypted  = errors.New("payload is encrypted but no encryption_key is configured")
+	errPayloadDecryption = errors.New("failed to decrypt payload: wrong key or tampered ciphertext")
+)
+
+// parseEncryptionKey decodes a base64 encryption key and checks it is 32 bytes (AES-256)
+func parseEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("encryption_key must be base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption_key must decode to 32 bytes, got: %d", len(key))
+	}
+	return key, nil
+}
+
+// encodeQRPayload serializes item data into the string stored in a QR code
+// using the configured codec. When a signing secret is configured the payload
+// carries an HMAC signature, with compress_payload the encoded bytes are
+// deflated if that makes them shorter, and when an encryption key is
+// configured the result is encrypted with AES-GCM.
+func (s *inventoryKeeperKeeper) encodeQRPayload(data ItemQRData) (string, error) {
+	data.V = currentPayloadVersion
+	if s.config().SigningSecret != "" {
+		sig, err := signPayload(s.config().SigningSecret, data)
+		if err != nil {
+			return "", err
+		}
+		data.Sig = sig
+	}
+
+	encoded, err := s.codec.Encode(data)
+	if err != nil {
+		return "", err
+	}
+
+	if s.config().CompressPayload {
+		if encoded, err = compressPayload(encoded); err != nil {
+			return "", err
+		}
+	}
+
+	if s.encryptionKey == nil {
+		return string(encoded), nil
+	}
+
+	return encryptPayload(s.encryptionKey, encoded)
+}
+
+// decodeQRPayload parses QR content back into item data with the configured
+// codec, decrypting and inflating it first if it carries the encrypted or
+// compressed payload markers. Compressed payloads are read whether or not
+// compress_payload is set. Access tokens are rejected rather than handed to the codec.
+// Barcode values, and plain-text content naming a known SKU, are resolved to
+// items with resolveSKU.
+func (s *inventoryKeeperKeeper) decodeQRPayload(content string) (ItemQRData, error) {
+	if strings.HasPrefix(content, accessTokenPrefix) {
+		return ItemQRData{}, errAccessTokenPayload
+	}
+	if _, value, ok := parseBarcodeLabel(content); ok {
+		return s.resolveSKU(value), nil
+	}
+	plaintext, err := s.unwrapPayload(content)
+	if err != nil {
+		return ItemQRData{}, err
+	}
+	data, err := s.codec.Decode(plaintext)
+	if err != nil {
+		if sku, ok := s.plainSKUPayload(content); ok {
+			return sku, nil
+		}
+		return ItemQRData{}, err
+	}
+	if err := checkPayloadVersion(data.V); err != nil {
+		return ItemQRData{}, err
+	}
+	return data, nil
+}
+
+// unwrapPayload decrypts and inflates QR content as needed, returning the
+// bytes the codec reads
+func (s *inventoryKeeperKeeper) unwrapPayload(content string) ([]byte, error) {
+	plaintext := []byte(content)
+	if strings.HasPrefix(content, encryptedPayloadPrefix) {
+		if s.encryptionKey == nil {
+			return nil, errPayloadEncrypted
+		}
+		decrypted, err := decryptPayload(s.encryptionKey, content)
+		if err != nil {
+			return nil, err
+		}
+		plaintext = decrypted
+	}
+	return inflatePayload(plaintext)
+}
+
+// signPayload computes the hex HMAC-SHA256 of the payload's JSON encoding
+// with the signature field cleared. The signature always covers the JSON
+// form, so it stays valid whichever codec carries the payload.
+func signPayload(secret string, data ItemQRData) (string, error) {
+	data.Sig = ""
+	unsigned, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode QR data for signing: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(unsigned)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyPayloadSignature reports whether the payload's signature is valid
+// for the secret. A payload from a newer schema never verifies: the
+// signature covers fields this module drops, so it can't be checked.
+func verifyPayloadSignature(secret string, data ItemQRData) bool {
+	if data.Sig == "" || data.newerVersion() {
+		return false
+	}
+	expected, err := signPayload(secret, data)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(expected), []byte(data.Sig))
+}
+
+// handleVerifyQR checks whether a QR payload (as produced by generate_qr)
+// carries a valid signature under the configured signing_secret. Purely
+// computational - no camera or vision service is involved.
+func (s *inventoryKeeperKeeper) handleVerifyQR(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	qrData, ok := cmd["qr_data"].(string)
+	if !ok || qrData == "" {
+		return nil, errors.New("qr_data is required and must be a string")
+	}
+
+	if s.config().SigningSecret == "" {
+		return map[string]interface{}{
+			"signing_enabled": false,
+			"valid":           false,
+			"message":         "signing not enabled",
+		}, nil
+	}
+
+	data, err := s.decodeQRPayload(qrData)
+	if err != nil {
+		return map[string]interface{}{
+			"signing_enabled": true,
+			"valid":           false,
+			"reason":          err.Error(),
+		}, nil
+	}
+
+	if data.Sig == "" {
+		return map[string]interface{}{
+			"signing_enabled": true,
+			"valid":           false,
+			"reason":          "payload is not signed",
+		}, nil
+	}
+
+	if data.newerVersion() {
+		return map[string]interface{}{
+			"signing_enabled": true,
+			"valid":           false,
+			"reason":          errNewerSignedPayload(data).Error(),
+		}, nil
+	}
+
+	if !verifyPayloadSignature(s.config().SigningSecret, data) {
+		return map[string]interface{}{
+			"signing_enabled": true,
+			"valid":           false,
+			"reason":          "signature mismatch",
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"signing_enabled": true,
+		"valid":           true,
+		"item_id":         data.ItemID,
+		"item_name":       data.ItemName,
+	}, nil
+}
+
+// encryptPayload seals plaintext with AES-GCM and returns the prefixed
+// base64 encoding of nonce||ciphertext
+func encryptPayload(key, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return encryptedPayloadPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptPayload reverses encryptPayload
+func decryptPayload(key []byte, content string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(content, encryptedPayloadPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return nil, errPayloadDecryption
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errPayloadDecryption
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
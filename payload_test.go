@@ -0,0 +1,214 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// testEncryptionKey returns a base64 32-byte key filled with the given byte
+func testEncryptionKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), 32)))
+}
+
+func TestEncryptionKeyValidation(t *testing.T) {
+	t.Run("valid 32-byte key", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", EncryptionKey: testEncryptionKey('k')}
+		if _, _, err := cfg.Validate(""); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("short key returns error", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", EncryptionKey: base64.StdEncoding.EncodeToString([]byte("too-short"))}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for short encryption_key")
+		}
+	})
+
+	t.Run("non-base64 key returns error", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", EncryptionKey: "not base64!"}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for non-base64 encryption_key")
+		}
+	})
+}
+
+func TestPayloadEncryption(t *testing.T) {
+	item := ItemQRData{V: currentPayloadVersion, ItemID: "cheese-099", ItemName: "Cheddar Cheese"}
+
+	t.Run("plaintext when no key configured", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		payload, err := svc.encodeQRPayload(item)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded ItemQRData
+		if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+			t.Errorf("expected plaintext JSON payload, got: %s", payload)
+		}
+	})
+
+	t.Run("encrypt then decrypt round trip", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{EncryptionKey: testEncryptionKey('a')})
+
+		payload, err := svc.encodeQRPayload(item)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(payload, encryptedPayloadPrefix) {
+			t.Fatalf("expected encrypted payload marker, got: %s", payload)
+		}
+		if strings.Contains(payload, "Cheddar") {
+			t.Error("encrypted payload should not contain plaintext item name")
+		}
+
+		decoded, err := svc.decodeQRPayload(payload)
+		if err != nil {
+			t.Fatalf("unexpected decode error: %v", err)
+		}
+		if decoded != item {
+			t.Errorf("expected %+v, got %+v", item, decoded)
+		}
+	})
+
+	t.Run("wrong key fails clearly", func(t *testing.T) {
+		writer, _ := newTestKeeper(t, &Config{EncryptionKey: testEncryptionKey('a')})
+		reader, _ := newTestKeeper(t, &Config{EncryptionKey: testEncryptionKey('b')})
+
+		payload, err := writer.encodeQRPayload(item)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := reader.decodeQRPayload(payload); !errors.Is(err, errPayloadDecryption) {
+			t.Errorf("expected decryption error, got: %v", err)
+		}
+	})
+
+	t.Run("tampered ciphertext fails", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{EncryptionKey: testEncryptionKey('a')})
+
+		payload, err := svc.encodeQRPayload(item)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(payload, encryptedPayloadPrefix))
+		sealed[len(sealed)-1] ^= 0xff
+		tampered := encryptedPayloadPrefix + base64.StdEncoding.EncodeToString(sealed)
+
+		if _, err := svc.decodeQRPayload(tampered); !errors.Is(err, errPayloadDecryption) {
+			t.Errorf("expected decryption error, got: %v", err)
+		}
+	})
+
+	t.Run("encrypted payload without key is rejected", func(t *testing.T) {
+		writer, _ := newTestKeeper(t, &Config{EncryptionKey: testEncryptionKey('a')})
+		reader, _ := newTestKeeper(t, nil)
+
+		payload, _ := writer.encodeQRPayload(item)
+		if _, err := reader.decodeQRPayload(payload); !errors.Is(err, errPayloadEncrypted) {
+			t.Errorf("expected missing-key error, got: %v", err)
+		}
+	})
+
+	t.Run("generate_qr and scan use encrypted payloads", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{EncryptionKey: testEncryptionKey('a')})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   item.ItemID,
+			"item_name": item.ItemName,
+		})
+		if result["encrypted"] != true {
+			t.Errorf("expected encrypted=true, got: %v", result["encrypted"])
+		}
+		payload := result["qr_data"].(string)
+
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, payload)}, nil
+		}
+		svc.scanAndCompare(context.Background())
+
+		svc.monitorMu.Lock()
+		defer svc.monitorMu.Unlock()
+		code, ok := svc.visibleCodes[payload]
+		if !ok {
+			t.Fatal("expected encrypted code to be tracked")
+		}
+		if code.ItemID != item.ItemID || code.ItemName != item.ItemName {
+			t.Errorf("expected decrypted item fields, got: %s (%s)", code.ItemID, code.ItemName)
+		}
+	})
+}
+
+func TestVerifyQR(t *testing.T) {
+	t.Run("valid signed payload", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{SigningSecret: "shelf-secret"})
+
+		generated := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "bread-305",
+			"item_name": "Sourdough Loaf",
+		})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_qr", "qr_data": generated["qr_data"]})
+		if result["valid"] != true {
+			t.Fatalf("expected valid signature, got: %v", result)
+		}
+		if result["item_id"] != "bread-305" || result["item_name"] != "Sourdough Loaf" {
+			t.Errorf("expected decoded fields, got: %v", result)
+		}
+	})
+
+	t.Run("tampered payload is invalid", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{SigningSecret: "shelf-secret"})
+
+		generated := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "bread-305",
+			"item_name": "Sourdough Loaf",
+		})
+		tampered := strings.Replace(generated["qr_data"].(string), "Sourdough Loaf", "Rye Loaf", 1)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_qr", "qr_data": tampered})
+		if result["valid"] != false {
+			t.Error("expected tampered payload to be invalid")
+		}
+		if _, leaked := result["item_id"]; leaked {
+			t.Error("invalid payload should not return decoded fields")
+		}
+	})
+
+	t.Run("payload signed with another secret is invalid", func(t *testing.T) {
+		signer, _ := newTestKeeper(t, &Config{SigningSecret: "other-secret"})
+		verifier, _ := newTestKeeper(t, &Config{SigningSecret: "shelf-secret"})
+
+		payload, _ := signer.encodeQRPayload(ItemQRData{ItemID: "bread-305", ItemName: "Sourdough Loaf"})
+		result := mustDoCommand(t, verifier, map[string]interface{}{"command": "verify_qr", "qr_data": payload})
+		if result["valid"] != false {
+			t.Error("expected signature from another secret to be invalid")
+		}
+	})
+
+	t.Run("no secret configured", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_qr", "qr_data": `{"item_id":"a","item_name":"A"}`})
+		if result["signing_enabled"] != false || result["message"] != "signing not enabled" {
+			t.Errorf("expected signing not enabled response, got: %v", result)
+		}
+	})
+
+	t.Run("missing qr_data returns error", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{SigningSecret: "shelf-secret"})
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "verify_qr"}); err == nil {
+			t.Error("expected error for missing qr_data")
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// currentPayloadVersion is the ItemQRData schema version new labels are
+// printed with. Bump it when a change would be misread by older modules, and
+// keep decoding every earlier version: printed labels outlive releases.
+const currentPayloadVersion = 1
+
+// knownPayloadFields are the JSON names of ItemQRData's fields
+var knownPayloadFields = map[string]bool{
+	"v": true, "namespace": true, "item_id": true, "item_name": true, "quantity": true, "sig": true,
+}
+
+// version returns the payload's schema version. Labels printed before
+// payloads were versioned have no v field and are v1.
+func (d ItemQRData) version() int {
+	if d.V == 0 {
+		return 1
+	}
+	return d.V
+}
+
+// newerVersion reports whether the payload is from a schema newer than this
+// module's. Such payloads are read on a best-effort basis: the fields known
+// here decode, and the rest are ignored.
+func (d ItemQRData) newerVersion() bool {
+	return d.version() > currentPayloadVersion
+}
+
+// errNewerSignedPayload explains why a signed payload from a newer schema
+// can't be verified
+func errNewerSignedPayload(d ItemQRData) error {
+	return fmt.Errorf("payload version %d is newer than this module reads (up to %d), so its signature can't be checked", d.version(), currentPayloadVersion)
+}
+
+// missingFields lists the required fields the payload lacks
+func (d ItemQRData) missingFields() []string {
+	var missing []string
+	if d.ItemID == "" {
+		missing = append(missing, "item_id")
+	}
+	if d.ItemName == "" {
+		missing = append(missing, "item_name")
+	}
+	return missing
+}
+
+// checkPayloadVersion rejects payload versions that can't exist. Versions
+// newer than this module are decoded on a best-effort basis, so old readers
+// keep working on newer labels; their signatures can't be checked, so
+// signature checks reject signed ones instead.
+func checkPayloadVersion(v int) error {
+	if v < 0 {
+		return fmt.Errorf("payload version must be non-negative, got: %d", v)
+	}
+	return nil
+}
+
+// payloadItemMap describes the item a payload decodes to
+func payloadItemMap(data ItemQRData) map[string]interface{} {
+	return map[string]interface{}{
+		"namespace": normalizeNamespace(data.Namespace),
+		"item_id":   data.ItemID,
+		"item_name": data.ItemName,
+		"quantity":  data.units(),
+	}
+}
+
+// handleDecodeQRData parses any QR payload string and describes it: what
+// kind of code it is, its schema version, which required fields it lacks,
+// and any fields from a newer schema this module doesn't know. Undecodable
+// payloads are reported invalid rather than returned as errors. Payloads
+// from a newer schema are flagged newer_version; with signing_secret set,
+// signed ones are invalid, as their signatures can't be checked.
+func (s *inventoryKeeperKeeper) handleDecodeQRData(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	content, ok := cmd["qr_data"].(string)
+	if !ok || content == "" {
+		return nil, errors.New("qr_data is required and must be a string")
+	}
+
+	result := map[string]interface{}{"current_version": currentPayloadVersion}
+	invalid := func(reason error) (map[string]interface{}, error) {
+		result["valid"] = false
+		result["reason"] = reason.Error()
+		return result, nil
+	}
+
+	if strings.HasPrefix(content, accessTokenPrefix) {
+		result["kind"] = "access_token"
+		return invalid(errAccessTokenPayload)
+	}
+	if symbology, value, ok := parseBarcodeLabel(content); ok {
+		result["kind"] = "barcode"
+		result["symbology"] = symbology
+		result["value"] = value
+		result["item"] = payloadItemMap(s.resolveSKU(value))
+		result["valid"] = true
+		return result, nil
+	}
+
+	result["kind"] = "item"
+	result["encrypted"] = strings.HasPrefix(content, encryptedPayloadPrefix)
+	plaintext, err := s.unwrapPayload(content)
+	if err != nil {
+		return invalid(err)
+	}
+	data, err := s.codec.Decode(plaintext)
+	if err != nil {
+		if sku, ok := s.plainSKUPayload(content); ok {
+			result["kind"] = "sku"
+			result["value"] = content
+			result["item"] = payloadItemMap(sku)
+			result["valid"] = true
+			return result, nil
+		}
+		return invalid(err)
+	}
+
+	result["version"] = data.version()
+	result["version_declared"] = data.V != 0
+	result["known_version"] = !data.newerVersion()
+	result["newer_version"] = data.newerVersion()
+	result["item"] = payloadItemMap(data)
+	result["signed"] = data.Sig != ""
+	versionErr := checkPayloadVersion(data.V)
+	if secret := s.config().SigningSecret; secret != "" {
+		result["signature_valid"] = verifyPayloadSignature(secret, data)
+		if versionErr == nil && data.Sig != "" && data.newerVersion() {
+			versionErr = errNewerSignedPayload(data)
+		}
+	}
+
+	// Fields a newer schema added show up only in the raw JSON
+	var unknown []string
+	var fields map[string]interface{}
+	if json.Unmarshal(plaintext, &fields) == nil {
+		for name := range fields {
+			if !knownPayloadFields[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+	}
+	result["unknown_fields"] = toInterfaceSlice(unknown)
+
+	missing := data.missingFields()
+	result["missing_fields"] = toInterfaceSlice(missing)
+	if versionErr != nil {
+		return invalid(versionErr)
+	}
+	if len(missing) > 0 {
+		return invalid(fmt.Errorf("payload is missing %s", strings.Join(missing, ", ")))
+	}
+	result["valid"] = true
+	return result, nil
+}
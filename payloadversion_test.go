@@ -0,0 +1,107 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPayloadVersion(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestKeeper(t, nil)
+
+	t.Run("new labels carry the current version", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_qr", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+		var data ItemQRData
+		if err := json.Unmarshal([]byte(result["qr_data"].(string)), &data); err != nil {
+			t.Fatalf("expected JSON payload, got: %v", result["qr_data"])
+		}
+		if data.V != currentPayloadVersion {
+			t.Errorf("expected v %d, got: %d", currentPayloadVersion, data.V)
+		}
+	})
+
+	t.Run("unversioned and newer payloads still decode", func(t *testing.T) {
+		for _, content := range []string{
+			`{"item_id":"apple-001","item_name":"Honeycrisp Apple"}`,
+			`{"v":7,"item_id":"apple-001","item_name":"Honeycrisp Apple","lot":"A12"}`,
+		} {
+			data, err := svc.decodeQRPayload(content)
+			if err != nil || data.ItemID != "apple-001" {
+				t.Errorf("expected %s to decode as apple-001, got %+v (%v)", content, data, err)
+			}
+		}
+		_, err := svc.decodeQRPayload(`{"v":-1,"item_id":"apple-001","item_name":"Honeycrisp Apple"}`)
+		if err == nil || !strings.Contains(err.Error(), "non-negative") {
+			t.Errorf("expected a negative version to be rejected, got: %v", err)
+		}
+	})
+
+	t.Run("signed payloads from a newer schema fail signature checks", func(t *testing.T) {
+		signer, _ := newTestKeeper(t, &Config{SigningSecret: "shelf-secret"})
+		newer := ItemQRData{V: currentPayloadVersion + 1, ItemID: "apple-001", ItemName: "Honeycrisp Apple"}
+		newer.Sig, _ = signPayload("shelf-secret", newer)
+		raw, _ := json.Marshal(newer)
+
+		result := mustDoCommand(t, signer, map[string]interface{}{"command": "decode_qr_data", "qr_data": string(raw)})
+		if result["valid"] != false || result["signature_valid"] != false || result["newer_version"] != true {
+			t.Errorf("expected a signed newer payload to be invalid, got %v", result)
+		}
+		verified := mustDoCommand(t, signer, map[string]interface{}{"command": "verify_qr", "qr_data": string(raw)})
+		if verified["valid"] != false || !strings.Contains(verified["reason"].(string), "newer") {
+			t.Errorf("expected verify_qr to reject the newer signed payload, got %v", verified)
+		}
+
+		// Unsigned, it reads like any other label
+		newer.Sig = ""
+		raw, _ = json.Marshal(newer)
+		if result := mustDoCommand(t, signer, map[string]interface{}{"command": "decode_qr_data", "qr_data": string(raw)}); result["valid"] != true {
+			t.Errorf("expected an unsigned newer payload to be valid, got %v", result)
+		}
+	})
+
+	t.Run("decode_qr_data describes the payload", func(t *testing.T) {
+		tests := []struct {
+			content  string
+			valid    bool
+			version  int
+			declared bool
+			missing  int
+			unknown  []string
+		}{
+			{`{"item_id":"apple-001","item_name":"Honeycrisp Apple"}`, true, 1, false, 0, nil},
+			{`{"v":1,"item_id":"apple-001"}`, false, 1, true, 1, nil},
+			{`{"v":3,"item_id":"apple-001","item_name":"Honeycrisp Apple","lot":"A12","best_by":"2027-01"}`, true, 3, true, 0, []string{"best_by", "lot"}},
+		}
+		for _, tc := range tests {
+			result := mustDoCommand(t, svc, map[string]interface{}{"command": "decode_qr_data", "qr_data": tc.content})
+			if result["kind"] != "item" || result["valid"] != tc.valid || result["version"] != tc.version || result["version_declared"] != tc.declared {
+				t.Errorf("%s: unexpected result %v", tc.content, result)
+			}
+			if missing := result["missing_fields"].([]interface{}); len(missing) != tc.missing {
+				t.Errorf("%s: expected %d missing fields, got %v", tc.content, tc.missing, missing)
+			}
+			unknown := result["unknown_fields"].([]interface{})
+			if len(unknown) != len(tc.unknown) {
+				t.Errorf("%s: expected unknown fields %v, got %v", tc.content, tc.unknown, unknown)
+				continue
+			}
+			for i, name := range tc.unknown {
+				if unknown[i] != name {
+					t.Errorf("%s: expected unknown fields %v, got %v", tc.content, tc.unknown, unknown)
+				}
+			}
+		}
+
+		if result := mustDoCommand(t, svc, map[string]interface{}{"command": "decode_qr_data", "qr_data": "not a payload"}); result["valid"] != false || result["reason"] == nil {
+			t.Errorf("expected garbage to be reported invalid, got %v", result)
+		}
+		if result := mustDoCommand(t, svc, map[string]interface{}{"command": "decode_qr_data", "qr_data": `{"v":3,"item_id":"a","item_name":"A"}`}); result["known_version"] != false || result["newer_version"] != true {
+			t.Errorf("expected a newer version to be flagged unknown, got %v", result)
+		}
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "decode_qr_data"}); err == nil {
+			t.Error("expected error without qr_data")
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+)
+
+// pdfWriter assembles a minimal PDF: numbered objects written in order with
+// a cross-reference table. It supports just what label sheets need, pages
+// of grayscale images.
+type pdfWriter struct {
+	objects [][]byte // Object bodies; object n is objects[n-1]
+}
+
+// reserve allocates an object number to be filled in later with set
+func (w *pdfWriter) reserve() int {
+	w.objects = append(w.objects, nil)
+	return len(w.objects)
+}
+
+// set fills in a reserved object
+func (w *pdfWriter) set(id int, body string) {
+	w.objects[id-1] = []byte(body)
+}
+
+// add appends an object and returns its number
+func (w *pdfWriter) add(body []byte) int {
+	w.objects = append(w.objects, body)
+	return len(w.objects)
+}
+
+// addStream appends a stream object with the given dictionary entries
+func (w *pdfWriter) addStream(dict string, data []byte) int {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<< %s /Length %d >>\nstream\n", dict, len(data))
+	b.Write(data)
+	b.WriteString("\nendstream")
+	return w.add(b.Bytes())
+}
+
+// addGrayImage appends img as a Flate-compressed 8-bit grayscale image
+func (w *pdfWriter) addGrayImage(img *image.Gray) (int, error) {
+	bounds := img.Bounds()
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		start := img.PixOffset(bounds.Min.X, y)
+		if _, err := zw.Write(img.Pix[start : start+bounds.Dx()]); err != nil {
+			return 0, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+	dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode",
+		bounds.Dx(), bounds.Dy())
+	return w.addStream(dict, compressed.Bytes()), nil
+}
+
+// bytes renders the document with root as its catalog
+func (w *pdfWriter) bytes(root int) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(w.objects))
+	for i, body := range w.objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		b.Write(body)
+		b.WriteString("\nendobj\n")
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(w.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.objects)+1, root, xref)
+	return b.Bytes()
+}
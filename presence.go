@@ -0,0 +1,123 @@
+package inventorykeeper
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// PresentItem tracks an item in the debounced present-set. An item only
+// becomes present after consecutive detections and is only removed after
+// consecutive misses, so a single dropped frame doesn't churn the set.
+type PresentItem struct {
+	Namespace string    // Namespace from the QR payload (default when absent)
+	ItemID    string    // Item identifier from the QR payload
+	ItemName  string    // Item name from the QR payload
+	Camera    string    // Camera that most recently saw the item
+	FirstSeen time.Time // First detection in the current run of sightings
+	LastSeen  time.Time // Most recent detection
+	Present   bool      // True once the item has been confirmed present
+	Hits      int       // Consecutive scans the item was detected in
+	Misses    int       // Consecutive scans the item was absent from
+	Labels    int       // Labels decoding to the item in the latest scan that saw it
+	Units     int       // Units those labels stand for (Labels unless a label carries a quantity)
+}
+
+// presenceChanges lists items that entered or left the present-set in one scan
+type presenceChanges struct {
+	Appeared []itemKey
+	Removed  []itemKey
+}
+
+// updatePresenceLocked applies one scan's detected items to the present-set.
+// Caller must hold monitorMu.
+func (s *inventoryKeeperKeeper) updatePresenceLocked(detected map[itemKey]ItemQRData, held map[itemKey]bool, now time.Time) presenceChanges {
+	threshold := s.config().presenceDebounceScans()
+	var changes presenceChanges
+
+	// Count hits for everything seen in this scan
+	for key, data := range detected {
+		entry, exists := s.presence[key]
+		if !exists {
+			entry = &PresentItem{Namespace: key.Namespace, ItemID: key.ItemID, FirstSeen: now}
+			s.presence[key] = entry
+		}
+		entry.ItemName = data.ItemName
+		entry.Camera = s.config().CameraName
+		entry.LastSeen = now
+		s.lastSeen[key] = now
+		entry.Hits++
+		entry.Misses = 0
+
+		if !entry.Present && entry.Hits >= threshold {
+			entry.Present = true
+			changes.Appeared = append(changes.Appeared, key)
+			s.recordPresenceTransitionLocked(key, true, entry.FirstSeen)
+			s.logger.Debugf("Item present: %s/%s (%s)", key.Namespace, key.ItemID, entry.ItemName)
+		}
+	}
+
+	// Count misses for everything tracked but not seen
+	for key, entry := range s.presence {
+		if _, seen := detected[key]; seen || held[key] {
+			continue
+		}
+		entry.Misses++
+		entry.Hits = 0
+
+		if entry.Misses < threshold {
+			continue
+		}
+		if entry.Present {
+			changes.Removed = append(changes.Removed, key)
+			s.recordPresenceTransitionLocked(key, false, entry.LastSeen)
+			s.logger.Debugf("Item no longer present: %s/%s (%s)", key.Namespace, key.ItemID, entry.ItemName)
+		}
+		delete(s.presence, key)
+	}
+
+	sortItemKeys(changes.Appeared)
+	sortItemKeys(changes.Removed)
+	return changes
+}
+
+// handleGetPresentItems returns the namespace's debounced present-set: what
+// the camera currently sees, independent of recorded inventory. scanned is
+// false until the first successful scan, so an empty set can be told apart
+// from no data.
+func (s *inventoryKeeperKeeper) handleGetPresentItems(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.monitorMu.Lock()
+	lastScan := s.lastScanAt
+	ids := make([]string, 0, len(s.presence))
+	for key, entry := range s.presence {
+		if entry.Present && key.Namespace == namespace {
+			ids = append(ids, key.ItemID)
+		}
+	}
+	sort.Strings(ids)
+	items := make([]interface{}, 0, len(ids))
+	for _, itemID := range ids {
+		entry := s.presence[keyFor(namespace, itemID)]
+		items = append(items, map[string]interface{}{
+			"item_id":    entry.ItemID,
+			"item_name":  entry.ItemName,
+			"camera":     entry.Camera,
+			"first_seen": formatTimestampIn(entry.FirstSeen, s.location),
+			"last_seen":  formatTimestampIn(entry.LastSeen, s.location),
+		})
+	}
+	s.monitorMu.Unlock()
+
+	return map[string]interface{}{
+		"namespace":    namespace,
+		"items":        items,
+		"count":        len(items),
+		"scanned":      !lastScan.IsZero(),
+		"last_scan_at": formatTimestampIn(lastScan, s.location),
+	}, nil
+}
@@ -0,0 +1,131 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestPresenceDebounce(t *testing.T) {
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+	visible := func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{testDetection(0, string(payload))}, nil
+	}
+	empty := func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{}, nil
+	}
+
+	isPresent := func(svc *inventoryKeeperKeeper) bool {
+		svc.monitorMu.Lock()
+		defer svc.monitorMu.Unlock()
+		entry, ok := svc.presence[keyFor(defaultNamespace, "apple-001")]
+		return ok && entry.Present
+	}
+
+	t.Run("item becomes present only after N consecutive detections", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFromCameraFunc = visible
+
+		svc.scanAndCompare(ctx)
+		if isPresent(svc) {
+			t.Error("item should not be present after a single detection")
+		}
+		svc.scanAndCompare(ctx)
+		if !isPresent(svc) {
+			t.Error("item should be present after 2 consecutive detections")
+		}
+	})
+
+	t.Run("single-scan dropout does not remove item", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFromCameraFunc = visible
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+
+		mockVision.DetectionsFromCameraFunc = empty
+		svc.scanAndCompare(ctx)
+		if !isPresent(svc) {
+			t.Error("item should survive a single missed scan")
+		}
+
+		// Reappearing resets the miss counter
+		mockVision.DetectionsFromCameraFunc = visible
+		svc.scanAndCompare(ctx)
+		mockVision.DetectionsFromCameraFunc = empty
+		svc.scanAndCompare(ctx)
+		if !isPresent(svc) {
+			t.Error("miss counter should reset after the item reappears")
+		}
+	})
+
+	t.Run("N consecutive dropouts remove item", func(t *testing.T) {
+		debounce := 3
+		svc, mockVision := newTestKeeper(t, &Config{PresenceDebounceScans: &debounce})
+		mockVision.DetectionsFromCameraFunc = visible
+		for i := 0; i < 3; i++ {
+			svc.scanAndCompare(ctx)
+		}
+		if !isPresent(svc) {
+			t.Fatal("item should be present after 3 detections")
+		}
+
+		mockVision.DetectionsFromCameraFunc = empty
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		if !isPresent(svc) {
+			t.Error("item should survive 2 misses with debounce of 3")
+		}
+		svc.scanAndCompare(ctx)
+		if isPresent(svc) {
+			t.Error("item should be removed after 3 consecutive misses")
+		}
+	})
+
+	t.Run("presence_debounce_scans below 1 rejected", func(t *testing.T) {
+		zero := 0
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", PresenceDebounceScans: &zero}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for presence_debounce_scans=0")
+		}
+	})
+}
+
+func TestGetPresentItems(t *testing.T) {
+	ctx := context.Background()
+
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	t.Run("before any scan the set is empty and flagged", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_present_items"})
+		if result["scanned"] != false || result["count"] != 0 {
+			t.Errorf("expected empty unscanned set, got: %v", result)
+		}
+	})
+
+	t.Run("present items reflect decoded QRs independent of inventory", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, string(apple)), testDetection(1, "not an item")}, nil
+		}
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_present_items"})
+		if result["scanned"] != true || result["count"] != 1 {
+			t.Fatalf("expected one present item after scanning, got: %v", result)
+		}
+		item := result["items"].([]interface{})[0].(map[string]interface{})
+		if item["item_id"] != "apple-001" || item["camera"] != "test-camera" {
+			t.Errorf("expected apple-001 seen by test-camera, got: %v", item)
+		}
+		if item["last_seen"] == "" {
+			t.Error("expected last_seen timestamp")
+		}
+	})
+}
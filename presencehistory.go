@@ -0,0 +1,133 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxPresenceTransitions bounds the transitions kept per item. Oldest are
+// dropped first, so history only reaches back as far as recent churn allows.
+const maxPresenceTransitions = 200
+
+// presenceTransition records an item entering or leaving the present-set
+type presenceTransition struct {
+	Time    time.Time // When the item was first seen (appeared) or last seen (removed)
+	Present bool      // True for absent->present, false for present->absent
+}
+
+// presenceInterval is a span during which an item was present. A zero End
+// means the item is still present.
+type presenceInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// recordPresenceTransitionLocked appends a transition to the item's log,
+// trimming it to maxPresenceTransitions. The log always starts with an
+// appearance so every interval has a known start. Caller must hold monitorMu.
+func (s *inventoryKeeperKeeper) recordPresenceTransitionLocked(key itemKey, present bool, at time.Time) {
+	log := append(s.presenceLog[key], presenceTransition{Time: at, Present: present})
+	if len(log) > maxPresenceTransitions {
+		log = log[len(log)-maxPresenceTransitions:]
+	}
+	if len(log) > 0 && !log[0].Present {
+		log = log[1:]
+	}
+	s.presenceLog[key] = log
+}
+
+// presenceIntervals pairs transitions into present intervals and clips them
+// to [from, to]. Zero from or to leaves that side unbounded.
+func presenceIntervals(transitions []presenceTransition, from, to time.Time) []presenceInterval {
+	var intervals []presenceInterval
+	var open *presenceInterval
+	for _, tr := range transitions {
+		if tr.Present {
+			if open == nil {
+				open = &presenceInterval{Start: tr.Time}
+			}
+			continue
+		}
+		if open != nil {
+			open.End = tr.Time
+			intervals = append(intervals, *open)
+			open = nil
+		}
+	}
+	if open != nil {
+		intervals = append(intervals, *open)
+	}
+
+	clipped := make([]presenceInterval, 0, len(intervals))
+	for _, iv := range intervals {
+		if !to.IsZero() && iv.Start.After(to) {
+			continue
+		}
+		if !from.IsZero() && !iv.End.IsZero() && iv.End.Before(from) {
+			continue
+		}
+		if !from.IsZero() && iv.Start.Before(from) {
+			iv.Start = from
+		}
+		if !to.IsZero() && (iv.End.IsZero() || iv.End.After(to)) {
+			iv.End = to
+		}
+		clipped = append(clipped, iv)
+	}
+	return clipped
+}
+
+// handlePresenceHistory returns the intervals an item was on the shelf,
+// optionally limited to the from/to time range (RFC 3339). An interval with
+// an empty end is still ongoing.
+func (s *inventoryKeeperKeeper) handlePresenceHistory(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	from, _, err := timeArg(cmd, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, _, err := timeArg(cmd, "to")
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, fmt.Errorf("to (%s) must not be before from (%s)", formatTimestampIn(to, s.location), formatTimestampIn(from, s.location))
+	}
+
+	s.monitorMu.Lock()
+	intervals := presenceIntervals(s.presenceLog[keyFor(namespace, itemID)], from, to)
+	s.monitorMu.Unlock()
+
+	now := s.now()
+	var total time.Duration
+	result := make([]interface{}, 0, len(intervals))
+	for _, iv := range intervals {
+		end := iv.End
+		if end.IsZero() {
+			end = now
+		}
+		total += end.Sub(iv.Start)
+		result = append(result, map[string]interface{}{
+			"start":   formatTimestampIn(iv.Start, s.location),
+			"end":     formatTimestampIn(iv.End, s.location),
+			"ongoing": iv.End.IsZero(),
+		})
+	}
+
+	return map[string]interface{}{
+		"namespace":             namespace,
+		"item_id":               itemID,
+		"intervals":             result,
+		"count":                 len(result),
+		"total_present_seconds": total.Seconds(),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package inventorykeeper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPresenceHistory(t *testing.T) {
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	apple := map[itemKey]ItemQRData{keyFor(defaultNamespace, "apple-001"): {ItemID: "apple-001", ItemName: "Apple"}}
+	nothing := map[itemKey]ItemQRData{}
+
+	// simulate applies scans one minute apart, starting at base
+	simulate := func(svc *inventoryKeeperKeeper, scans ...map[itemKey]ItemQRData) {
+		svc.monitorMu.Lock()
+		defer svc.monitorMu.Unlock()
+		for i, detected := range scans {
+			svc.updatePresenceLocked(detected, nil, base.Add(time.Duration(i)*time.Minute))
+		}
+	}
+
+	t.Run("appear and disappear events reconstruct intervals", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		// With the default debounce of 2 scans: present 0-2m, absent, present again from 6m
+		simulate(svc, apple, apple, apple, nothing, nothing, nothing, apple, apple)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "presence_history", "item_id": "apple-001"})
+		intervals := result["intervals"].([]interface{})
+		if len(intervals) != 2 {
+			t.Fatalf("expected 2 intervals, got: %v", intervals)
+		}
+
+		first := intervals[0].(map[string]interface{})
+		if first["start"] != formatTimestamp(base) || first["end"] != formatTimestamp(base.Add(2*time.Minute)) {
+			t.Errorf("expected first interval 0m-2m, got: %v", first)
+		}
+		second := intervals[1].(map[string]interface{})
+		if second["start"] != formatTimestamp(base.Add(6*time.Minute)) || second["end"] != "" || second["ongoing"] != true {
+			t.Errorf("expected ongoing interval from 6m, got: %v", second)
+		}
+	})
+
+	t.Run("time range clips and filters intervals", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		simulate(svc, apple, apple, apple, nothing, nothing, nothing, apple, apple)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "presence_history",
+			"item_id": "apple-001",
+			"from":    formatTimestamp(base.Add(time.Minute)),
+			"to":      formatTimestamp(base.Add(5 * time.Minute)),
+		})
+		intervals := result["intervals"].([]interface{})
+		if len(intervals) != 1 {
+			t.Fatalf("expected 1 interval in range, got: %v", intervals)
+		}
+		only := intervals[0].(map[string]interface{})
+		if only["start"] != formatTimestamp(base.Add(time.Minute)) || only["end"] != formatTimestamp(base.Add(2*time.Minute)) {
+			t.Errorf("expected interval clipped to 1m-2m, got: %v", only)
+		}
+		if result["total_present_seconds"] != 60.0 {
+			t.Errorf("expected 60 present seconds, got: %v", result["total_present_seconds"])
+		}
+	})
+
+	t.Run("transitions per item are capped", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		key := keyFor(defaultNamespace, "apple-001")
+
+		svc.monitorMu.Lock()
+		for i := 0; i < maxPresenceTransitions+11; i++ {
+			svc.recordPresenceTransitionLocked(key, i%2 == 0, base.Add(time.Duration(i)*time.Second))
+		}
+		log := svc.presenceLog[key]
+		svc.monitorMu.Unlock()
+
+		if len(log) > maxPresenceTransitions {
+			t.Errorf("expected at most %d transitions, got: %d", maxPresenceTransitions, len(log))
+		}
+		if !log[0].Present {
+			t.Error("expected trimmed log to start with an appearance")
+		}
+	})
+}
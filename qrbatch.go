@@ -0,0 +1,99 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// handleGenerateQRBatch renders codes for a list of items, each an object
+// with item_id, item_name, and optional namespace and quantity. Render
+// options (code_type, size, border, ...) apply to every item, and entries
+// without a namespace use the command's. A bad entry is reported in its
+// result rather than failing the batch.
+func (s *inventoryKeeperKeeper) handleGenerateQRBatch(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	entries, ok := cmd["items"].([]interface{})
+	if !ok || len(entries) == 0 {
+		return nil, errors.New("items is required and must be a non-empty list of objects")
+	}
+	if err := s.config().checkBatchArg("items", len(entries)); err != nil {
+		return nil, err
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := codeRenderArgs(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, 0, len(entries))
+	failed := 0
+	for i, raw := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := map[string]interface{}{"index": i}
+		code, err := s.renderBatchEntry(raw, namespace, opts)
+		if err != nil {
+			failed++
+			if fields, ok := raw.(map[string]interface{}); ok {
+				if itemID, ok := fields["item_id"].(string); ok {
+					result["item_id"] = itemID
+				}
+			}
+			result["error"] = err.Error()
+		} else {
+			for k, v := range code {
+				result[k] = v
+			}
+		}
+		results = append(results, result)
+	}
+
+	s.logger.Infof("Generated %d of %d %s codes in a batch", len(entries)-failed, len(entries), opts.CodeType)
+	return map[string]interface{}{
+		"codes":     results,
+		"count":     len(results),
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+		"code_type": opts.CodeType,
+		"format":    opts.responseFormat(),
+	}, nil
+}
+
+// renderBatchEntry renders one generate_qr_batch entry
+func (s *inventoryKeeperKeeper) renderBatchEntry(raw interface{}, namespace string, opts codeRenderOptions) (map[string]interface{}, error) {
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("entry must be an object with item_id and item_name")
+	}
+	if _, set := fields["namespace"]; !set {
+		withNamespace := make(map[string]interface{}, len(fields)+1)
+		for k, v := range fields {
+			withNamespace[k] = v
+		}
+		withNamespace["namespace"] = namespace
+		fields = withNamespace
+	}
+
+	data, err := s.qrItemArgs(fields)
+	if err != nil {
+		return nil, err
+	}
+	code, payload, size, err := s.renderItemCode(data, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render: %w", err)
+	}
+	return map[string]interface{}{
+		"namespace": normalizeNamespace(data.Namespace),
+		"item_id":   data.ItemID,
+		"item_name": data.ItemName,
+		"quantity":  data.units(),
+		"qr_code":   base64.StdEncoding.EncodeToString(code),
+		"qr_data":   payload,
+		"size":      size,
+	}, nil
+}
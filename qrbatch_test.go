@@ -0,0 +1,50 @@
+package inventorykeeper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGenerateQRBatch(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	result := mustDoCommand(t, svc, map[string]interface{}{
+		"command":   "generate_qr_batch",
+		"namespace": "zone-a",
+		"items": []interface{}{
+			map[string]interface{}{"item_id": "bolt-001", "item_name": "Bolt"},
+			map[string]interface{}{"item_id": "nut-002"},
+			"not an object",
+			map[string]interface{}{"item_id": "washer-003", "item_name": "Washer", "namespace": "zone-b", "quantity": 50},
+		},
+	})
+	if result["count"] != 4 || result["succeeded"] != 2 || result["failed"] != 2 {
+		t.Fatalf("unexpected batch summary: %v", result)
+	}
+
+	codes := result["codes"].([]interface{})
+	bolt := codes[0].(map[string]interface{})
+	if bolt["item_id"] != "bolt-001" || bolt["namespace"] != "zone-a" || bolt["qr_code"] == "" {
+		t.Errorf("unexpected first code: %v", bolt)
+	}
+	data, err := svc.decodeQRPayload(bolt["qr_data"].(string))
+	if err != nil || data.ItemID != "bolt-001" || data.Namespace != "zone-a" {
+		t.Errorf("expected a decodable zone-a payload, got %+v, err %v", data, err)
+	}
+
+	nut := codes[1].(map[string]interface{})
+	if nut["item_id"] != "nut-002" || !strings.Contains(nut["error"].(string), "item_name is required") {
+		t.Errorf("expected a per-item error for nut-002, got: %v", nut)
+	}
+	if bad := codes[2].(map[string]interface{}); bad["index"] != 2 || bad["error"] == nil {
+		t.Errorf("expected an error for a non-object entry, got: %v", bad)
+	}
+	if washer := codes[3].(map[string]interface{}); washer["namespace"] != "zone-b" || washer["quantity"] != 50 {
+		t.Errorf("expected a zone-b case label of 50, got: %v", washer)
+	}
+
+	if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "generate_qr_batch", "items": []interface{}{}}); err == nil {
+		t.Error("expected error for an empty batch")
+	}
+}
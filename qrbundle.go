@@ -0,0 +1,142 @@
+package inventorykeeper
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// unsafeFileChars matches characters not allowed in bundle entry names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// bundleManifestEntry describes one code image in an export_qr_bundle zip
+type bundleManifestEntry struct {
+	Namespace string `json:"namespace"`
+	ItemID    string `json:"item_id"`
+	ItemName  string `json:"item_name"`
+	File      string `json:"file"`
+}
+
+// bundleFileName returns a zip entry name for the item, unique among used.
+// Items outside the default namespace go in a directory named after it.
+func bundleFileName(key itemKey, ext string, used map[string]bool) string {
+	base := unsafeFileChars.ReplaceAllString(key.ItemID, "_")
+	if key.Namespace != defaultNamespace {
+		base = key.Namespace + "/" + base
+	}
+	name := base + "." + ext
+	for n := 2; used[name]; n++ {
+		name = fmt.Sprintf("%s-%d.%s", base, n, ext)
+	}
+	used[name] = true
+	return name
+}
+
+// handleExportQRBundle renders a code for every inventory item (or every item
+// in one namespace) and returns them as a base64 zip with a manifest.json.
+// Accepts the same rendering options as generate_qr.
+func (s *inventoryKeeperKeeper) handleExportQRBundle(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	opts, err := codeRenderArgs(cmd)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := optionalStringArg(cmd, "namespace")
+	if err != nil {
+		return nil, err
+	}
+	if namespace != "" {
+		if err := validateNamespace(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	// Copy out just what's needed so rendering doesn't hold the lock
+	s.inventoryMu.RLock()
+	keys := make([]itemKey, 0, len(s.inventory))
+	names := make(map[itemKey]string, len(s.inventory))
+	for key, item := range s.inventory {
+		if namespace != "" && key.Namespace != namespace {
+			continue
+		}
+		keys = append(keys, key)
+		names[key] = item.ItemName
+	}
+	s.inventoryMu.RUnlock()
+	sortItemKeys(keys)
+
+	bundle, count, err := s.buildCodeBundle(ctx, keys, names, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Exported %s bundle with %d codes", opts.CodeType, count)
+	return map[string]interface{}{
+		"bundle":    base64.StdEncoding.EncodeToString(bundle),
+		"format":    "base64-zip",
+		"code_type": opts.CodeType,
+		"count":     count,
+	}, nil
+}
+
+// buildCodeBundle renders a code for each key, in order, into a zip with a
+// manifest.json. Returns the zip and the number of codes in it.
+func (s *inventoryKeeperKeeper) buildCodeBundle(ctx context.Context, keys []itemKey, names map[itemKey]string, opts codeRenderOptions) ([]byte, int, error) {
+	// Each image is written to the archive as soon as it's rendered
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	used := make(map[string]bool, len(keys))
+	manifest := make([]bundleManifestEntry, 0, len(keys))
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+
+		data := ItemQRData{ItemID: key.ItemID, ItemName: names[key]}
+		if key.Namespace != defaultNamespace {
+			data.Namespace = key.Namespace
+		}
+		code, _, _, err := s.renderItemCode(data, opts)
+		if err != nil {
+			return nil, 0, fmt.Errorf("item %s: %w", key.ItemID, err)
+		}
+
+		name := bundleFileName(key, opts.imageFormat(), used)
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to add %s to bundle: %w", name, err)
+		}
+		if _, err := w.Write(code); err != nil {
+			return nil, 0, fmt.Errorf("failed to add %s to bundle: %w", name, err)
+		}
+		manifest = append(manifest, bundleManifestEntry{
+			Namespace: key.Namespace,
+			ItemID:    key.ItemID,
+			ItemName:  names[key],
+			File:      name,
+		})
+	}
+
+	manifestJSON, err := json.MarshalIndent(map[string]interface{}{
+		"code_type": opts.CodeType,
+		"count":     len(manifest),
+		"items":     manifest,
+	}, "", "  ")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	w, err := zw.Create("manifest.json")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to add manifest to bundle: %w", err)
+	}
+	if _, err := w.Write(manifestJSON); err != nil {
+		return nil, 0, fmt.Errorf("failed to add manifest to bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, 0, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return buf.Bytes(), len(manifest), nil
+}
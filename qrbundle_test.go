@@ -0,0 +1,81 @@
+package inventorykeeper
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"testing"
+)
+
+func TestExportQRBundle(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+	for _, item := range []map[string]interface{}{
+		{"item_id": "apple-001", "item_name": "Apple"},
+		{"item_id": "banana/001", "item_name": "Banana"},
+		{"item_id": "apple-001", "item_name": "Cold Apple", "namespace": "cold"},
+	} {
+		item["command"] = "add_item"
+		mustDoCommand(t, svc, item)
+	}
+
+	result := mustDoCommand(t, svc, map[string]interface{}{"command": "export_qr_bundle"})
+	if result["count"] != 3 {
+		t.Errorf("expected 3 codes, got: %v", result["count"])
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(result["bundle"].(string))
+	if err != nil {
+		t.Fatalf("bundle is not valid base64: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		t.Fatalf("bundle is not a zip: %v", err)
+	}
+	if len(zr.File) != 4 {
+		t.Fatalf("expected 3 images plus manifest, got %d entries", len(zr.File))
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	for _, name := range []string{"apple-001.png", "banana_001.png", "cold/apple-001.png"} {
+		f, ok := files[name]
+		if !ok {
+			t.Errorf("expected entry %s, got: %v", name, files)
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", name, err)
+		}
+		if _, err := png.Decode(rc); err != nil {
+			t.Errorf("%s is not a PNG: %v", name, err)
+		}
+		rc.Close()
+	}
+
+	rc, err := files["manifest.json"].Open()
+	if err != nil {
+		t.Fatalf("failed to open manifest: %v", err)
+	}
+	defer rc.Close()
+	var manifest struct {
+		Items []bundleManifestEntry `json:"items"`
+	}
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		t.Fatalf("manifest is not JSON: %v", err)
+	}
+	if len(manifest.Items) != 3 {
+		t.Errorf("expected 3 manifest items, got: %v", manifest.Items)
+	}
+
+	t.Run("namespace filter", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "export_qr_bundle", "namespace": "cold"})
+		if result["count"] != 1 {
+			t.Errorf("expected 1 code in namespace cold, got: %v", result["count"])
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxQRRangeCount bounds how many codes one generate_qr_range call renders
+const maxQRRangeCount = 500
+
+// defaultQRRangePadWidth zero-pads range numbers to four digits (PREFIX-0001)
+const defaultQRRangePadWidth = 4
+
+// rangeItemID returns the item ID for number n, zero-padded to width digits
+func rangeItemID(prefix string, n, width int) string {
+	return fmt.Sprintf("%s-%0*d", prefix, width, n)
+}
+
+// rangeItemName fills a name template's {id} and {n} placeholders; an empty
+// template names the item after its ID
+func rangeItemName(template, itemID string, n int) string {
+	if template == "" {
+		return itemID
+	}
+	return strings.NewReplacer("{id}", itemID, "{n}", strconv.Itoa(n)).Replace(template)
+}
+
+// handleGenerateQRRange renders codes for sequentially numbered item IDs
+// (prefix-0001, prefix-0002, ...) for pre-printing labels. Nothing is added to
+// the inventory. Returns the codes as a list, or as an export_qr_bundle style
+// zip with bundle set. Accepts generate_qr's rendering options.
+func (s *inventoryKeeperKeeper) handleGenerateQRRange(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	prefix, ok := cmd["prefix"].(string)
+	if !ok || prefix == "" {
+		return nil, errors.New("prefix is required and must be a string")
+	}
+	count, hasCount, err := intArg(cmd, "count")
+	if err != nil {
+		return nil, err
+	}
+	if !hasCount || count < 1 || count > maxQRRangeCount {
+		return nil, fmt.Errorf("count is required and must be between 1 and %d", maxQRRangeCount)
+	}
+	start, hasStart, err := intArg(cmd, "start")
+	if err != nil {
+		return nil, err
+	}
+	if !hasStart {
+		start = 1
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("start must be non-negative, got: %d", start)
+	}
+	width, hasWidth, err := intArg(cmd, "pad_width")
+	if err != nil {
+		return nil, err
+	}
+	if !hasWidth {
+		width = defaultQRRangePadWidth
+	}
+	if width < 0 || width > 12 {
+		return nil, fmt.Errorf("pad_width must be between 0 and 12, got: %d", width)
+	}
+	template, err := optionalStringArg(cmd, "name_template")
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := codeRenderArgs(cmd)
+	if err != nil {
+		return nil, err
+	}
+	asBundle, _ := cmd["bundle"].(bool)
+
+	// Check every ID and name before rendering anything
+	keys := make([]itemKey, 0, count)
+	names := make(map[itemKey]string, count)
+	for n := start; n < start+count; n++ {
+		itemID := rangeItemID(prefix, n, width)
+		itemName := rangeItemName(template, itemID, n)
+		if err := s.fieldRules.validateItemID(itemID); err != nil {
+			return nil, err
+		}
+		if err := s.fieldRules.validateItemName(itemName); err != nil {
+			return nil, err
+		}
+		key := keyFor(namespace, itemID)
+		keys = append(keys, key)
+		names[key] = itemName
+	}
+
+	result := map[string]interface{}{
+		"namespace": namespace,
+		"code_type": opts.CodeType,
+		"count":     count,
+		"first_id":  keys[0].ItemID,
+		"last_id":   keys[len(keys)-1].ItemID,
+	}
+	if asBundle {
+		bundle, _, err := s.buildCodeBundle(ctx, keys, names, opts)
+		if err != nil {
+			return nil, err
+		}
+		result["bundle"] = base64.StdEncoding.EncodeToString(bundle)
+		result["format"] = "base64-zip"
+		s.logger.Infof("Generated %s bundle for %s through %s", opts.CodeType, result["first_id"], result["last_id"])
+		return result, nil
+	}
+
+	codes := make([]interface{}, 0, count)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data := ItemQRData{ItemID: key.ItemID, ItemName: names[key]}
+		if namespace != defaultNamespace {
+			data.Namespace = namespace
+		}
+		code, payload, size, err := s.renderItemCode(data, opts)
+		if err != nil {
+			return nil, fmt.Errorf("item %s: %w", key.ItemID, err)
+		}
+		codes = append(codes, map[string]interface{}{
+			"item_id":   key.ItemID,
+			"item_name": names[key],
+			"qr_code":   base64.StdEncoding.EncodeToString(code),
+			"qr_data":   payload,
+			"size":      size,
+		})
+	}
+	result["codes"] = codes
+	result["format"] = opts.responseFormat()
+	s.logger.Infof("Generated %d %s codes for %s through %s", count, opts.CodeType, result["first_id"], result["last_id"])
+	return result, nil
+}
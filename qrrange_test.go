@@ -0,0 +1,98 @@
+package inventorykeeper
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestGenerateQRRange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("range of 5 yields numbered decodable codes", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "generate_qr_range", "prefix": "BIN", "start": 8, "count": 5, "name_template": "Bin {n}",
+		})
+		codes := result["codes"].([]interface{})
+		if len(codes) != 5 || result["first_id"] != "BIN-0008" || result["last_id"] != "BIN-0012" {
+			t.Fatalf("expected BIN-0008 through BIN-0012, got: %v", result)
+		}
+
+		for i, raw := range codes {
+			code := raw.(map[string]interface{})
+			img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(code["qr_code"].(string))))
+			if err != nil {
+				t.Fatalf("qr_code is not a PNG: %v", err)
+			}
+			content, _, err := decodeCodeImage(img)
+			if err != nil {
+				t.Fatalf("failed to decode QR image: %v", err)
+			}
+			data, err := svc.decodeQRPayload(content)
+			if err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+			wantID := []string{"BIN-0008", "BIN-0009", "BIN-0010", "BIN-0011", "BIN-0012"}[i]
+			wantName := []string{"Bin 8", "Bin 9", "Bin 10", "Bin 11", "Bin 12"}[i]
+			if data.ItemID != wantID || data.ItemName != wantName {
+				t.Errorf("code %d: expected %s (%s), got: %+v", i, wantID, wantName, data)
+			}
+		}
+
+		if count := len(mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})["items"].([]interface{})); count != 0 {
+			t.Errorf("expected inventory left untouched, got %d items", count)
+		}
+	})
+
+	t.Run("pad_width and default names", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_qr_range", "prefix": "SHELF", "count": 2, "pad_width": 2})
+		code := result["codes"].([]interface{})[0].(map[string]interface{})
+		if code["item_id"] != "SHELF-01" || code["item_name"] != "SHELF-01" {
+			t.Errorf("expected SHELF-01 named after its ID, got: %v", code)
+		}
+	})
+
+	t.Run("bundle reuses the export format", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_qr_range", "prefix": "BIN", "count": 3, "bundle": true})
+		raw, err := base64.StdEncoding.DecodeString(result["bundle"].(string))
+		if err != nil {
+			t.Fatalf("bundle is not valid base64: %v", err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+		if err != nil {
+			t.Fatalf("bundle is not a zip: %v", err)
+		}
+		names := map[string]bool{}
+		for _, f := range zr.File {
+			names[f.Name] = true
+		}
+		for _, name := range []string{"BIN-0001.png", "BIN-0002.png", "BIN-0003.png", "manifest.json"} {
+			if !names[name] {
+				t.Errorf("expected %s in bundle, got: %v", name, names)
+			}
+		}
+	})
+
+	t.Run("invalid arguments are rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		for _, cmd := range []map[string]interface{}{
+			{"command": "generate_qr_range", "count": 5},
+			{"command": "generate_qr_range", "prefix": "BIN"},
+			{"command": "generate_qr_range", "prefix": "BIN", "count": 0},
+			{"command": "generate_qr_range", "prefix": "BIN", "count": maxQRRangeCount + 1},
+			{"command": "generate_qr_range", "prefix": "BIN", "count": 5, "start": -1},
+			{"command": "generate_qr_range", "prefix": "BIN", "count": 5, "pad_width": -2},
+		} {
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+	})
+}
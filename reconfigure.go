@@ -0,0 +1,135 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+)
+
+// liveConfigFields are the config keys Reconfigure applies to the running
+// keeper on top of the set_config tunables: the camera and QR vision service,
+// and scan settings read fresh on every scan. A change to any other key
+// (optional dependencies, storage, notifiers, codecs, keys, background
+// loops) rebuilds the keeper.
+var liveConfigFields = map[string]bool{
+	"camera_name":       true,
+	"qr_vision_service": true,
+	"rotate_degrees":    true,
+	"grayscale":         true,
+	"contrast_stretch":  true,
+	"roi":               true,
+	"code_types":        true,
+	"sku_map":           true,
+	"checkout_alerts":   true,
+}
+
+// changedConfigFields lists, sorted, the config keys whose values differ
+// between prev and next
+func changedConfigFields(prev, next *Config) ([]string, error) {
+	fields := func(cfg *Config) (map[string]interface{}, error) {
+		raw, err := json.Marshal(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config: %w", err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("failed to encode config: %w", err)
+		}
+		return m, nil
+	}
+	before, err := fields(prev)
+	if err != nil {
+		return nil, err
+	}
+	after, err := fields(next)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, kept := after[key]; !kept {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Reconfigure applies a new config without tearing down the keeper, so
+// inventory, presence, checkouts, and alert history carry over. The camera
+// and QR vision service are fetched again from deps, and timing and scan
+// settings take effect from the next scan. A new camera_name starts the
+// camera over: its health, warm-up, and duplicate-frame memos are reset. Monitoring starts or stops if
+// scan_interval_ms or poll_interval_seconds turned it on or off. Changes to
+// anything else ask the robot to rebuild the keeper instead.
+func (s *inventoryKeeperKeeper) Reconfigure(ctx context.Context, deps resource.Dependencies, rawConf resource.Config) error {
+	conf, err := resource.NativeConfig[*Config](rawConf)
+	if err != nil {
+		return err
+	}
+	prev := s.config()
+	changed, err := changedConfigFields(prev, conf)
+	if err != nil {
+		return err
+	}
+
+	var structural []string
+	for _, key := range changed {
+		if !tunableConfigFields[key] && !liveConfigFields[key] {
+			structural = append(structural, key)
+		}
+	}
+	if len(structural) > 0 {
+		s.logger.Infof("Rebuilding for config changes to %s", strings.Join(structural, ", "))
+		return resource.NewMustRebuildError(rawConf.ResourceName())
+	}
+
+	cam, err := camera.FromDependencies(deps, conf.CameraName)
+	if err != nil {
+		return fmt.Errorf("failed to get camera %s: %w", conf.CameraName, err)
+	}
+	qrVis, err := vision.FromDependencies(deps, conf.QRVisionService)
+	if err != nil {
+		return fmt.Errorf("failed to get QR vision service %s: %w", conf.QRVisionService, err)
+	}
+
+	s.depsMu.Lock()
+	s.deps = deps
+	s.camera = cam
+	s.qrVisionService = qrVis
+	s.depsMu.Unlock()
+
+	s.cfgMu.Lock()
+	s.cfg = conf
+	s.cfgMu.Unlock()
+	s.applyTheftTimings(conf)
+	if conf.CameraName != prev.CameraName {
+		s.resetCameraState()
+	}
+
+	switch {
+	case conf.monitoringEnabled() && !prev.monitoringEnabled():
+		s.startMonitoring()
+	case !conf.monitoringEnabled() && prev.monitoringEnabled():
+		s.stopMonitoring()
+		s.logger.Info("QR code monitoring disabled by reconfigure")
+	}
+
+	if len(changed) > 0 {
+		s.logger.Infof("Reconfigured in place: %s", strings.Join(changed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/generic"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestReconfigure(t *testing.T) {
+	ctx := context.Background()
+	reconfigure := func(svc *inventoryKeeperKeeper, deps resource.Dependencies, cfg *Config) error {
+		return svc.Reconfigure(ctx, deps, resource.Config{Name: "keeper", API: generic.API, Model: Keeper, ConvertedAttributes: cfg})
+	}
+	// baseConfig copies the keeper's config so edits start from what it runs
+	baseConfig := func(svc *inventoryKeeperKeeper) *Config {
+		cfg := *svc.config()
+		return &cfg
+	}
+
+	t.Run("swaps the camera and vision service and keeps state", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+		svc.raiseAlert("item_removed", severityWarning, "apple-001", "Item apple-001 removed", nil)
+		svc.healthMu.Lock()
+		svc.health.consecutiveFailures, svc.health.lastError = 5, "camera offline"
+		svc.health.lastFrameAt = svc.now()
+		svc.healthMu.Unlock()
+		svc.framesMu.Lock()
+		svc.lastFrames = map[string]frameMemo{"test-camera": {hash: 1}}
+		svc.framesMu.Unlock()
+
+		newCam := &inject.Camera{}
+		newVision := inject.NewVisionService("qr-2")
+		deps := resource.Dependencies{camera.Named("cam-2"): newCam, vision.Named("qr-2"): newVision}
+		cfg := baseConfig(svc)
+		cfg.CameraName, cfg.QRVisionService = "cam-2", "qr-2"
+		grace, window := 4000, 90
+		cfg.GracePeriodMs, cfg.CheckInWindowSeconds = &grace, &window
+		if err := reconfigure(svc, deps, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if svc.shelfCamera() != newCam || svc.qrVision() != newVision {
+			t.Error("expected the new camera and vision service to be in use")
+		}
+		if got := svc.config().gracePeriod(); got != 4*time.Second {
+			t.Errorf("expected grace period 4s, got %v", got)
+		}
+		svc.theftMu.Lock()
+		theftWindow := svc.theft.window
+		svc.theftMu.Unlock()
+		if theftWindow != 90*time.Second {
+			t.Errorf("expected the theft detector window to follow, got %v", theftWindow)
+		}
+		svc.healthMu.Lock()
+		health := svc.health
+		svc.healthMu.Unlock()
+		if health.consecutiveFailures != 0 || health.lastError != "" || !health.lastFrameAt.IsZero() {
+			t.Errorf("expected the old camera's health to be reset, got: %+v", health)
+		}
+		svc.framesMu.Lock()
+		memos := len(svc.lastFrames)
+		svc.framesMu.Unlock()
+		if memos != 0 {
+			t.Errorf("expected the frame memos to be cleared, got %d", memos)
+		}
+		if items := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"}); items["count"] != 1 {
+			t.Errorf("expected inventory to survive, got: %v", items)
+		}
+		if alerts := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"}); alerts["count"] != 1 {
+			t.Errorf("expected alert history to survive, got: %v", alerts)
+		}
+	})
+
+	t.Run("monitoring starts and stops", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		deps := svc.deps
+
+		cfg := baseConfig(svc)
+		interval := 60000
+		cfg.ScanIntervalMs = &interval
+		if err := reconfigure(svc, deps, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if svc.stopMonitor == nil {
+			t.Fatal("expected monitoring to start")
+		}
+
+		cfg = baseConfig(svc)
+		disabled := 0
+		cfg.ScanIntervalMs = &disabled
+		if err := reconfigure(svc, deps, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if svc.stopMonitor != nil {
+			t.Error("expected monitoring to stop")
+		}
+	})
+
+	t.Run("structural changes ask for a rebuild", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		cfg := baseConfig(svc)
+		cfg.StoragePath = t.TempDir() + "/inventory.json"
+		err := reconfigure(svc, svc.deps, cfg)
+		if !resource.IsMustRebuildError(err) {
+			t.Errorf("expected a must-rebuild error, got: %v", err)
+		}
+		if svc.config().StoragePath != "" {
+			t.Error("expected the config to be left unchanged")
+		}
+	})
+
+	t.Run("missing dependencies are an error", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		cfg := baseConfig(svc)
+		cfg.CameraName = "nowhere"
+		if err := reconfigure(svc, svc.deps, cfg); err == nil || resource.IsMustRebuildError(err) {
+			t.Errorf("expected a dependency error, got: %v", err)
+		}
+		if svc.config().CameraName == "nowhere" {
+			t.Error("expected the config to be left unchanged")
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package inventorykeeper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRedisKey is the hash holding items when redis_key isn't set
+const defaultRedisKey = "inventory-keeper:items"
+
+// redisDialTimeout bounds connecting to Redis; commands are bounded by the
+// caller's context, or this when it has no deadline
+const redisDialTimeout = 5 * time.Second
+
+// redisKey returns the hash the redis store uses, defaulting to defaultRedisKey
+func (cfg *Config) redisKey() string {
+	if cfg.RedisKey == "" {
+		return defaultRedisKey
+	}
+	return cfg.RedisKey
+}
+
+// redisClient runs one Redis command. Replies are a string (simple or bulk),
+// int64, nil (null), or []interface{} of replies; Redis error replies are
+// returned as a redisError.
+type redisClient interface {
+	Do(ctx context.Context, args ...string) (interface{}, error)
+	Close() error
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// redisStore keeps each item as JSON in one Redis hash, under the field
+// "<namespace>/<item_id>". Namespaces can't contain "/", so the field splits
+// unambiguously.
+type redisStore struct {
+	client redisClient
+	key    string // Hash holding the items
+}
+
+func newRedisStore(client redisClient, key string) *redisStore {
+	return &redisStore{client: client, key: key}
+}
+
+// redisField returns the hash field for an item key
+func redisField(key itemKey) string {
+	return key.Namespace + "/" + key.ItemID
+}
+
+// decodeRedisItem parses a stored item
+func decodeRedisItem(field string, reply interface{}) (*InventoryItem, error) {
+	raw, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply for %s: %T", field, reply)
+	}
+	var item InventoryItem
+	if err := json.Unmarshal([]byte(raw), &item); err != nil {
+		return nil, fmt.Errorf("failed to decode item %s: %w", field, err)
+	}
+	item.Namespace = normalizeNamespace(item.Namespace)
+	return &item, nil
+}
+
+// Get implements InventoryStore
+func (r *redisStore) Get(ctx context.Context, key itemKey) (*InventoryItem, bool, error) {
+	reply, err := r.client.Do(ctx, "HGET", r.key, redisField(key))
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	item, err := decodeRedisItem(redisField(key), reply)
+	return item, err == nil, err
+}
+
+// Put implements InventoryStore
+func (r *redisStore) Put(ctx context.Context, item *InventoryItem) error {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.Do(ctx, "HSET", r.key, redisField(item.key()), string(encoded))
+	return err
+}
+
+// Delete implements InventoryStore
+func (r *redisStore) Delete(ctx context.Context, key itemKey) error {
+	_, err := r.client.Do(ctx, "HDEL", r.key, redisField(key))
+	return err
+}
+
+// List implements InventoryStore
+func (r *redisStore) List(ctx context.Context) ([]*InventoryItem, error) {
+	reply, err := r.client.Do(ctx, "HGETALL", r.key)
+	if err != nil {
+		return nil, err
+	}
+	pairs, ok := reply.([]interface{})
+	if !ok || len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("unexpected HGETALL reply: %v", reply)
+	}
+
+	byKey := make(map[itemKey]*InventoryItem, len(pairs)/2)
+	keys := make([]itemKey, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		field, _ := pairs[i].(string)
+		item, err := decodeRedisItem(field, pairs[i+1])
+		if err != nil {
+			return nil, err
+		}
+		byKey[item.key()] = item
+		keys = append(keys, item.key())
+	}
+	sortItemKeys(keys)
+	items := make([]*InventoryItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, byKey[key])
+	}
+	return items, nil
+}
+
+// Close implements InventoryStore
+func (r *redisStore) Close() error { return r.client.Close() }
+
+// respClient is a minimal Redis client speaking RESP over one connection.
+// It connects on first use and reconnects after a connection error.
+type respClient struct {
+	address  string
+	password string
+	db       int
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRESPClient(address, password string, db int) *respClient {
+	return &respClient{address: address, password: password, db: db}
+}
+
+// Do implements redisClient
+func (c *respClient) Do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connectLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTripLocked(ctx, args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.closeLocked()
+	}
+	return reply, err
+}
+
+// connectLocked dials the server and authenticates and selects the database
+// if configured
+func (c *respClient) connectLocked(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: redisDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis at %s: %w", c.address, err)
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	if c.password != "" {
+		if _, err := c.roundTripLocked(ctx, []string{"AUTH", c.password}); err != nil {
+			c.closeLocked()
+			return fmt.Errorf("redis AUTH failed: %w", err)
+		}
+	}
+	if c.db > 0 {
+		if _, err := c.roundTripLocked(ctx, []string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			c.closeLocked()
+			return fmt.Errorf("redis SELECT %d failed: %w", c.db, err)
+		}
+	}
+	return nil
+}
+
+// roundTripLocked sends one command and reads its reply
+func (c *respClient) roundTripLocked(ctx context.Context, args []string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisDialTimeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(c.conn, encodeRESPCommand(args)); err != nil {
+		return nil, err
+	}
+	return readRESPReply(c.reader)
+}
+
+// closeLocked drops the connection so the next command reconnects
+func (c *respClient) closeLocked() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.reader = nil
+}
+
+// Close implements redisClient
+func (c *respClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeLocked()
+	return nil
+}
+
+// encodeRESPCommand renders a command as a RESP array of bulk strings
+func encodeRESPCommand(args []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return b.String()
+}
+
+// readRESPReply reads one RESP reply
+func readRESPReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("bad bulk length %q: %w", line, err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("bad array length %q: %w", line, err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		elems := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			elem, err := readRESPReply(r)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return elems, nil
+	default:
+		return nil, fmt.Errorf("unexpected redis reply %q", line)
+	}
+}
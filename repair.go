@@ -0,0 +1,224 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Rules repair_inventory applies, in the order they're checked per entry
+const (
+	repairNilEntry       = "nil_entry"             // Map entry with no item: dropped
+	repairEmptyItemID    = "empty_item_id"         // Item with a blank item_id: dropped
+	repairNegativeQty    = "negative_quantity"     // Quantity below zero: clamped to 0
+	repairNegativeField  = "negative_field"        // Negative reorder or unit figure: reset to 0
+	repairEmptyItemName  = "empty_item_name"       // Blank item_name: set to the item_id
+	repairTags           = "tags"                  // Blank or repeated tags: removed
+	repairMismatchedKey  = "mismatched_key"        // Stored under another item's key: moved to its own
+	repairDuplicateID    = "duplicate_item_id"     // Two entries for one key: the older copy is dropped
+	repairDanglingSeen   = "dangling_last_seen"    // Last-seen time of an item neither stocked nor present: dropped
+	repairDanglingExpiry = "dangling_expiry_alert" // Expiry alert record for a removed item or old expiry: dropped
+)
+
+// repairChange is one inconsistency found by repair_inventory and what was
+// (or would be) done about it
+type repairChange struct {
+	Namespace string
+	ItemID    string
+	Rule      string
+	Action    string
+}
+
+func (c repairChange) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"namespace": c.Namespace,
+		"item_id":   c.ItemID,
+		"rule":      c.Rule,
+		"action":    c.Action,
+	}
+}
+
+// repairItemFields fixes field-level inconsistencies in item in place. A
+// quantity of zero is a valid out-of-stock count, so only negative
+// quantities are clamped.
+func repairItemFields(item *InventoryItem) []repairChange {
+	var changes []repairChange
+	note := func(rule, action string, args ...interface{}) {
+		changes = append(changes, repairChange{
+			Namespace: normalizeNamespace(item.Namespace),
+			ItemID:    item.ItemID,
+			Rule:      rule,
+			Action:    fmt.Sprintf(action, args...),
+		})
+	}
+
+	if item.Quantity < 0 {
+		note(repairNegativeQty, "quantity %d clamped to 0", item.Quantity)
+		item.Quantity = 0
+	}
+	resetInt := func(field string, v *int) {
+		if *v < 0 {
+			note(repairNegativeField, "%s %d reset to 0", field, *v)
+			*v = 0
+		}
+	}
+	resetFloat := func(field string, v *float64) {
+		if *v < 0 {
+			note(repairNegativeField, "%s %g reset to 0", field, *v)
+			*v = 0
+		}
+	}
+	resetInt("reorder_quantity", &item.ReorderQuantity)
+	resetInt("reorder_threshold", &item.ReorderThreshold)
+	resetFloat("unit_weight", &item.UnitWeight)
+	resetFloat("value", &item.Value)
+
+	if strings.TrimSpace(item.ItemName) == "" {
+		note(repairEmptyItemName, "item_name set to %q", item.ItemID)
+		item.ItemName = item.ItemID
+	}
+
+	seen := make(map[string]bool, len(item.Tags))
+	var tags []string
+	for _, tag := range item.Tags {
+		if strings.TrimSpace(tag) == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) != len(item.Tags) {
+		note(repairTags, "%d blank or repeated tags removed", len(item.Tags)-len(tags))
+		item.Tags = tags
+	}
+	return changes
+}
+
+// planInventoryRepairs returns a repaired copy of inventory and the changes
+// made to produce it, leaving inventory itself untouched. Entries are
+// visited in key order so the report is stable. When two entries end up
+// under one key, the more recently updated wins.
+func planInventoryRepairs(inventory map[itemKey]*InventoryItem) (map[itemKey]*InventoryItem, []repairChange) {
+	keys := make([]itemKey, 0, len(inventory))
+	for key := range inventory {
+		keys = append(keys, key)
+	}
+	sortItemKeys(keys)
+
+	repaired := make(map[itemKey]*InventoryItem, len(inventory))
+	var changes []repairChange
+	for _, key := range keys {
+		item := inventory[key]
+		if item == nil {
+			changes = append(changes, repairChange{key.Namespace, key.ItemID, repairNilEntry, "dropped"})
+			continue
+		}
+		if strings.TrimSpace(item.ItemID) == "" {
+			changes = append(changes, repairChange{key.Namespace, key.ItemID, repairEmptyItemID, fmt.Sprintf("dropped (item_name %q)", item.ItemName)})
+			continue
+		}
+
+		fixed := item.clone()
+		changes = append(changes, repairItemFields(fixed)...)
+
+		own := fixed.key()
+		if own != key {
+			changes = append(changes, repairChange{key.Namespace, key.ItemID, repairMismatchedKey,
+				fmt.Sprintf("moved to %s/%s", own.Namespace, own.ItemID)})
+		}
+		if existing, dup := repaired[own]; dup {
+			if !fixed.UpdatedAt.After(existing.UpdatedAt) {
+				changes = append(changes, repairChange{own.Namespace, own.ItemID, repairDuplicateID,
+					fmt.Sprintf("dropped copy stored under %s/%s", key.Namespace, key.ItemID)})
+				continue
+			}
+			changes = append(changes, repairChange{own.Namespace, own.ItemID, repairDuplicateID, "dropped older copy"})
+		}
+		repaired[own] = fixed
+	}
+	return repaired, changes
+}
+
+// pruneDanglingMetadata finds last-seen times and expiry alert records for
+// items that won't exist in inventory, removing them when apply is set.
+// Last-seen times of items still on the shelf are kept, since they may be
+// added to inventory later.
+func (s *inventoryKeeperKeeper) pruneDanglingMetadata(inventory map[itemKey]*InventoryItem, apply bool) []repairChange {
+	var changes []repairChange
+
+	s.monitorMu.Lock()
+	var seen []itemKey
+	for key := range s.lastSeen {
+		if _, exists := inventory[key]; exists {
+			continue
+		}
+		if entry := s.presence[key]; entry != nil && entry.Present {
+			continue
+		}
+		seen = append(seen, key)
+	}
+	sortItemKeys(seen)
+	for _, key := range seen {
+		changes = append(changes, repairChange{key.Namespace, key.ItemID, repairDanglingSeen, "last-seen time dropped"})
+		if apply {
+			delete(s.lastSeen, key)
+		}
+	}
+	s.monitorMu.Unlock()
+
+	s.expiryMu.Lock()
+	var alerted []itemKey
+	for key, expiry := range s.expiryAlerted {
+		if item, exists := inventory[key]; !exists || !item.ExpiresAt.Equal(expiry) {
+			alerted = append(alerted, key)
+		}
+	}
+	sortItemKeys(alerted)
+	for _, key := range alerted {
+		changes = append(changes, repairChange{key.Namespace, key.ItemID, repairDanglingExpiry, "expiry alert record dropped"})
+		if apply {
+			delete(s.expiryAlerted, key)
+		}
+	}
+	s.expiryMu.Unlock()
+
+	return changes
+}
+
+// handleRepairInventory checks inventory for inconsistencies left by crashes
+// or hand-edited storage and reports what it would change. Nothing is
+// changed unless apply is true. Repairs aren't undoable.
+func (s *inventoryKeeperKeeper) handleRepairInventory(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	apply, _ := cmd["apply"].(bool)
+
+	s.inventoryMu.Lock()
+	repaired, changes := planInventoryRepairs(s.inventory)
+	itemRepairs := len(changes)
+	if apply && itemRepairs > 0 {
+		s.inventory = repaired
+		s.recordHistoryLocked("inventory_repaired", defaultNamespace, "", map[string]interface{}{
+			"repairs": itemRepairs,
+		})
+	}
+	s.inventoryMu.Unlock()
+
+	changes = append(changes, s.pruneDanglingMetadata(repaired, apply)...)
+
+	byRule := map[string]interface{}{}
+	report := make([]interface{}, 0, len(changes))
+	for _, change := range changes {
+		count, _ := byRule[change.Rule].(int)
+		byRule[change.Rule] = count + 1
+		report = append(report, change.toMap())
+	}
+
+	if apply && len(changes) > 0 {
+		s.logger.Infof("Repaired inventory: %d changes", len(changes))
+	}
+	return map[string]interface{}{
+		"applied": apply,
+		"changes": report,
+		"count":   len(changes),
+		"by_rule": byRule,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package inventorykeeper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRepairInventory(t *testing.T) {
+	older := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	// seed plants one of each inconsistency next to a healthy item
+	seed := func(t *testing.T) *inventoryKeeperKeeper {
+		svc, _ := newTestKeeper(t, nil)
+		svc.inventory = map[itemKey]*InventoryItem{
+			keyFor("", "apple-001"):  {ItemID: "apple-001", ItemName: "Apple", Quantity: 4},
+			keyFor("", "broken"):     nil,
+			keyFor("", ""):           {ItemName: "No ID", Quantity: 1},
+			keyFor("", "pear-002"):   {ItemID: "pear-002", ItemName: " ", Quantity: -3, Tags: []string{"fruit", "", "fruit"}, ReorderThreshold: -1},
+			keyFor("", "plum-003"):   {ItemID: "plum-003", ItemName: "Plum", Quantity: 1, UpdatedAt: newer},
+			keyFor("", "plum-003-x"): {ItemID: "plum-003", ItemName: "Old Plum", Quantity: 9, UpdatedAt: older},
+		}
+		svc.lastSeen[keyFor("", "gone-404")] = older
+		svc.lastSeen[keyFor("", "apple-001")] = older
+		svc.expiryAlerted = map[itemKey]time.Time{keyFor("", "gone-404"): newer}
+		return svc
+	}
+	rules := func(result map[string]interface{}) map[string]int {
+		got := map[string]int{}
+		for rule, count := range result["by_rule"].(map[string]interface{}) {
+			got[rule] = count.(int)
+		}
+		return got
+	}
+	want := map[string]int{
+		repairNilEntry:       1,
+		repairEmptyItemID:    1,
+		repairNegativeQty:    1,
+		repairNegativeField:  1,
+		repairEmptyItemName:  1,
+		repairTags:           1,
+		repairMismatchedKey:  1,
+		repairDuplicateID:    1,
+		repairDanglingSeen:   1,
+		repairDanglingExpiry: 1,
+	}
+
+	t.Run("dry run reports without changing anything", func(t *testing.T) {
+		svc := seed(t)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "repair_inventory"})
+
+		if result["applied"] != false {
+			t.Error("repair should be a dry run by default")
+		}
+		got := rules(result)
+		for rule, count := range want {
+			if got[rule] != count {
+				t.Errorf("rule %s: got %d, want %d (report %v)", rule, got[rule], count, result["changes"])
+			}
+		}
+		if result["count"] != 10 {
+			t.Errorf("count = %v, want 10", result["count"])
+		}
+		if len(svc.inventory) != 6 || svc.inventory[keyFor("", "pear-002")].Quantity != -3 {
+			t.Error("dry run must not modify inventory")
+		}
+		if _, ok := svc.lastSeen[keyFor("", "gone-404")]; !ok {
+			t.Error("dry run must not drop last-seen times")
+		}
+	})
+
+	t.Run("apply fixes every inconsistency", func(t *testing.T) {
+		svc := seed(t)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "repair_inventory", "apply": true})
+		if result["applied"] != true || result["count"] != 10 {
+			t.Fatalf("unexpected apply result: %v", result)
+		}
+
+		if len(svc.inventory) != 3 {
+			t.Errorf("expected apple, pear, and plum to remain, got %d items", len(svc.inventory))
+		}
+		pear := svc.inventory[keyFor("", "pear-002")]
+		if pear.Quantity != 0 || pear.ItemName != "pear-002" || pear.ReorderThreshold != 0 || len(pear.Tags) != 1 {
+			t.Errorf("pear not repaired: %+v", pear)
+		}
+		if plum := svc.inventory[keyFor("", "plum-003")]; plum == nil || plum.ItemName != "Plum" {
+			t.Errorf("newer plum should win the duplicate, got %+v", plum)
+		}
+		if _, ok := svc.lastSeen[keyFor("", "gone-404")]; ok {
+			t.Error("dangling last-seen time should be dropped")
+		}
+		if _, ok := svc.lastSeen[keyFor("", "apple-001")]; !ok {
+			t.Error("stocked item's last-seen time should be kept")
+		}
+		if len(svc.expiryAlerted) != 0 {
+			t.Error("dangling expiry alert record should be dropped")
+		}
+		if last := svc.history[len(svc.history)-1]; last.Type != "inventory_repaired" {
+			t.Errorf("expected inventory_repaired history event, got %s", last.Type)
+		}
+
+		// A second pass finds nothing left to fix
+		again := mustDoCommand(t, svc, map[string]interface{}{"command": "repair_inventory", "apply": true})
+		if again["count"] != 0 {
+			t.Errorf("second repair should find nothing, got %v", again["changes"])
+		}
+	})
+}
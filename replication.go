@@ -0,0 +1,259 @@
+package inventorykeeper
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// maxReplicationChanges bounds the change log replicate_pull serves deltas
+// from. A standby further behind than this gets a full snapshot.
+const maxReplicationChanges = 1000
+
+// replicationChange is one item's new state at a revision
+type replicationChange struct {
+	Revision int64
+	Key      itemKey
+	Item     *InventoryItem // nil when the item was removed
+}
+
+// replicationState tracks inventory revisions for replicate_pull. Like
+// storeSyncState it finds changed items by comparing encodings, so every
+// mutation is covered without each handler reporting what it touched.
+type replicationState struct {
+	id       string              // Identifies this keeper's revision sequence; a restart starts a new one
+	revision int64               // Latest inventory revision
+	base     int64               // Revision the oldest logged change follows; deltas are served from here on
+	dirty    bool                // Inventory may have changed since the last recorded revision
+	encoded  map[itemKey][]byte  // Each item as of the latest revision
+	changes  []replicationChange // Changes after base, oldest first
+}
+
+// seedReplication starts the revision sequence from the startup inventory.
+// Loaded items aren't in the change log, so a standby starting from zero
+// gets a full snapshot. Called before the keeper is shared.
+func (s *inventoryKeeperKeeper) seedReplication() {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		s.logger.Warnf("Failed to generate a replication id, using the start time: %v", err)
+		id = []byte(s.now().UTC().Format("20060102150405"))
+	}
+	s.replication = replicationState{
+		id:      hex.EncodeToString(id),
+		encoded: make(map[itemKey][]byte, len(s.inventory)),
+	}
+	for key, item := range s.inventory {
+		if encoded, err := json.Marshal(item); err == nil {
+			s.replication.encoded[key] = encoded
+		}
+	}
+	if len(s.inventory) > 0 {
+		s.replication.revision = 1
+		s.replication.base = 1
+	}
+}
+
+// recordReplicationChanges bumps the revision for each item that changed
+// since the last call and logs the change, dropping the oldest past
+// maxReplicationChanges
+func (s *inventoryKeeperKeeper) recordReplicationChanges() {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+	if !s.replication.dirty {
+		return
+	}
+	s.replication.dirty = false
+
+	var changed []itemKey
+	current := make(map[itemKey][]byte, len(s.inventory))
+	for key, item := range s.inventory {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			s.logger.Errorf("Failed to encode item %s for replication: %v", key.ItemID, err)
+			continue
+		}
+		current[key] = encoded
+		if string(encoded) != string(s.replication.encoded[key]) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range s.replication.encoded {
+		if _, exists := current[key]; !exists {
+			changed = append(changed, key)
+		}
+	}
+	sortItemKeys(changed)
+
+	for _, key := range changed {
+		s.replication.revision++
+		change := replicationChange{Revision: s.replication.revision, Key: key}
+		if item, exists := s.inventory[key]; exists {
+			change.Item = item.clone()
+		}
+		s.replication.changes = append(s.replication.changes, change)
+	}
+	if excess := len(s.replication.changes) - maxReplicationChanges; excess > 0 {
+		s.replication.base = s.replication.changes[excess-1].Revision
+		s.replication.changes = s.replication.changes[excess:]
+	}
+	s.replication.encoded = current
+}
+
+// handleReplicatePull returns the inventory changes after since_version,
+// for a standby keeper to apply with replicate_apply. Each changed item is
+// reported once with its latest state. When the changes are no longer in
+// the log, or replication_id shows the standby followed an earlier run of
+// this keeper, the whole inventory is returned with full_snapshot set.
+func (s *inventoryKeeperKeeper) handleReplicatePull(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	sinceArg, _, err := intArg(cmd, "since_version")
+	if err != nil {
+		return nil, err
+	}
+	if sinceArg < 0 {
+		return nil, fmt.Errorf("since_version must be non-negative, got: %d", sinceArg)
+	}
+	since := int64(sinceArg)
+	replicationID, err := optionalStringArg(cmd, "replication_id")
+	if err != nil {
+		return nil, err
+	}
+
+	// Pick up changes made since the last command finished
+	s.recordReplicationChanges()
+
+	s.inventoryMu.RLock()
+	defer s.inventoryMu.RUnlock()
+	state := &s.replication
+	result := map[string]interface{}{
+		"replication_id": state.id,
+		"version":        state.revision,
+	}
+
+	full := since < state.base || since > state.revision || (replicationID != "" && replicationID != state.id)
+	if full {
+		keys := make([]itemKey, 0, len(s.inventory))
+		for key := range s.inventory {
+			keys = append(keys, key)
+		}
+		sortItemKeys(keys)
+		inventory := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			inventory = append(inventory, s.inventory[key].toMap(s.location))
+		}
+		result["full_snapshot"] = true
+		result["inventory"] = inventory
+		result["removed"] = []interface{}{}
+		return result, nil
+	}
+
+	// Later changes to an item supersede earlier ones
+	latest := make(map[itemKey]*InventoryItem)
+	for _, change := range state.changes {
+		if change.Revision > since {
+			latest[change.Key] = change.Item
+		}
+	}
+	keys := make([]itemKey, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+	sortItemKeys(keys)
+	inventory := make([]interface{}, 0, len(keys))
+	removed := make([]interface{}, 0)
+	for _, key := range keys {
+		if item := latest[key]; item != nil {
+			inventory = append(inventory, item.toMap(s.location))
+		} else {
+			removed = append(removed, map[string]interface{}{"namespace": key.Namespace, "item_id": key.ItemID})
+		}
+	}
+	result["full_snapshot"] = false
+	result["inventory"] = inventory
+	result["removed"] = removed
+	return result, nil
+}
+
+// handleReplicateApply applies a replicate_pull result to this keeper's
+// inventory: a full snapshot replaces it, a delta updates and removes the
+// listed items. The undo stack is cleared since it refers to the old state.
+// Applied changes aren't recorded in history.
+func (s *inventoryKeeperKeeper) handleReplicateApply(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	pull, ok := cmd["changes"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("changes is required and must be a replicate_pull result")
+	}
+	full, _ := pull["full_snapshot"].(bool)
+	version, hasVersion, err := intArg(pull, "version")
+	if err != nil {
+		return nil, err
+	}
+	if !hasVersion {
+		return nil, errors.New("changes is missing version")
+	}
+	items, _, err := parseSnapshotDump(pull, s.config())
+	if err != nil {
+		return nil, err
+	}
+	removed, err := replicationRemovedArg(pull["removed"])
+	if err != nil {
+		return nil, err
+	}
+
+	if limit := s.config().maxInventoryItems(); full && limit > 0 && len(items) > limit {
+		return nil, fmt.Errorf("invalid snapshot: %d items exceeds max_inventory_items of %d", len(items), limit)
+	}
+
+	s.inventoryMu.Lock()
+	if full {
+		s.inventory = items
+	} else {
+		for key, item := range items {
+			s.inventory[key] = item
+		}
+		for _, key := range removed {
+			delete(s.inventory, key)
+		}
+	}
+	s.undoStack = nil
+	s.markStorageDirtyLocked()
+	s.inventoryMu.Unlock()
+
+	replicationID, _ := pull["replication_id"].(string)
+	s.logger.Debugf("Applied replication version %d from %s: %d updated, %d removed (full: %v)", version, replicationID, len(items), len(removed), full)
+	return map[string]interface{}{
+		"applied":        true,
+		"replication_id": replicationID,
+		"version":        version,
+		"full_snapshot":  full,
+		"items_updated":  len(items),
+		"items_removed":  len(removed),
+	}, nil
+}
+
+// replicationRemovedArg parses the removed items of a replicate_pull result
+func replicationRemovedArg(raw interface{}) ([]itemKey, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("removed must be a list")
+	}
+	keys := make([]itemKey, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("removed entries must be objects with namespace and item_id")
+		}
+		itemID, _ := fields["item_id"].(string)
+		if itemID == "" {
+			return nil, errors.New("removed entry missing item_id")
+		}
+		namespace, _ := fields["namespace"].(string)
+		keys = append(keys, keyFor(namespace, itemID))
+	}
+	return keys, nil
+}
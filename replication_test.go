@@ -0,0 +1,106 @@
+package inventorykeeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplication(t *testing.T) {
+	// replicate pulls the primary's changes since version into the standby
+	// and returns the pull result
+	replicate := func(t *testing.T, primary, standby *inventoryKeeperKeeper, version interface{}, replicationID string) map[string]interface{} {
+		t.Helper()
+		pull := mustDoCommand(t, primary, map[string]interface{}{
+			"command":        "replicate_pull",
+			"since_version":  version,
+			"replication_id": replicationID,
+		})
+		mustDoCommand(t, standby, map[string]interface{}{"command": "replicate_apply", "changes": pull})
+		return pull
+	}
+
+	// assertMirrored fails unless both keepers list the same inventory
+	assertMirrored := func(t *testing.T, primary, standby *inventoryKeeperKeeper) {
+		t.Helper()
+		for _, namespace := range []string{"", "backroom"} {
+			want := mustDoCommand(t, primary, map[string]interface{}{"command": "list_items", "namespace": namespace})
+			got := mustDoCommand(t, standby, map[string]interface{}{"command": "list_items", "namespace": namespace})
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("standby inventory differs in namespace %q:\nprimary: %v\nstandby: %v", namespace, want, got)
+			}
+		}
+	}
+
+	t.Run("successive deltas reproduce the primary's inventory", func(t *testing.T) {
+		primary, _ := newTestKeeper(t, nil)
+		standby, _ := newTestKeeper(t, nil)
+
+		pull := replicate(t, primary, standby, 0.0, "")
+		if pull["full_snapshot"] != false || pull["version"] != int64(0) {
+			t.Errorf("expected an empty delta from an empty primary, got: %v", pull)
+		}
+
+		mustDoCommand(t, primary, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple", "quantity": 4.0})
+		mustDoCommand(t, primary, map[string]interface{}{"command": "add_item", "item_id": "flour-210", "item_name": "Bread Flour"})
+		mustDoCommand(t, primary, map[string]interface{}{"command": "add_item", "item_id": "bolt-7", "item_name": "Bolt", "namespace": "backroom"})
+		pull = replicate(t, primary, standby, pull["version"], pull["replication_id"].(string))
+		if pull["full_snapshot"] != false || len(pull["inventory"].([]interface{})) != 3 {
+			t.Errorf("expected a delta of 3 added items, got: %v", pull)
+		}
+		assertMirrored(t, primary, standby)
+
+		mustDoCommand(t, primary, map[string]interface{}{"command": "set_quantity", "item_id": "apple-001", "quantity": 9.0})
+		mustDoCommand(t, primary, map[string]interface{}{"command": "adjust_quantity", "item_id": "apple-001", "delta": -2.0})
+		mustDoCommand(t, primary, map[string]interface{}{"command": "remove_item", "item_id": "flour-210"})
+		pull = replicate(t, primary, standby, pull["version"], pull["replication_id"].(string))
+		inventory := pull["inventory"].([]interface{})
+		removed := pull["removed"].([]interface{})
+		if len(inventory) != 1 || inventory[0].(map[string]interface{})["quantity"] != 7 {
+			t.Errorf("expected only apple-001 at its latest quantity, got: %v", inventory)
+		}
+		if len(removed) != 1 || removed[0].(map[string]interface{})["item_id"] != "flour-210" {
+			t.Errorf("expected flour-210 removed, got: %v", removed)
+		}
+		assertMirrored(t, primary, standby)
+
+		// Nothing changed, nothing to send
+		pull = replicate(t, primary, standby, pull["version"], pull["replication_id"].(string))
+		if len(pull["inventory"].([]interface{})) != 0 || len(pull["removed"].([]interface{})) != 0 {
+			t.Errorf("expected an empty delta, got: %v", pull)
+		}
+	})
+
+	t.Run("a standby too far behind gets a full snapshot", func(t *testing.T) {
+		primary, _ := newTestKeeper(t, nil)
+		standby, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, primary, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+		first := replicate(t, primary, standby, 0.0, "")
+
+		mustDoCommand(t, primary, map[string]interface{}{"command": "add_item", "item_id": "flour-210", "item_name": "Bread Flour"})
+		for i := 0; i < maxReplicationChanges; i++ {
+			mustDoCommand(t, primary, map[string]interface{}{"command": "set_quantity", "item_id": "apple-001", "quantity": float64(i % 5)})
+		}
+		// Standby-only state a full snapshot must discard
+		mustDoCommand(t, standby, map[string]interface{}{"command": "add_item", "item_id": "stray-1", "item_name": "Stray"})
+
+		pull := replicate(t, primary, standby, first["version"], first["replication_id"].(string))
+		if pull["full_snapshot"] != true {
+			t.Fatalf("expected a full snapshot once the change log moved past since_version, got: %v", pull["full_snapshot"])
+		}
+		assertMirrored(t, primary, standby)
+	})
+
+	t.Run("a different replication_id or a future version gets a full snapshot", func(t *testing.T) {
+		primary, _ := newTestKeeper(t, nil)
+		standby, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, primary, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+
+		if pull := replicate(t, primary, standby, 0.0, "from-an-earlier-run"); pull["full_snapshot"] != true {
+			t.Errorf("expected a full snapshot for a replication_id from another run, got: %v", pull)
+		}
+		if pull := replicate(t, primary, standby, 500.0, ""); pull["full_snapshot"] != true {
+			t.Errorf("expected a full snapshot for a version the primary hasn't reached, got: %v", pull)
+		}
+		assertMirrored(t, primary, standby)
+	})
+}
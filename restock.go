@@ -0,0 +1,287 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// maxRestockRequests caps the requests kept; the oldest fulfilled ones are
+// dropped first
+const maxRestockRequests = 500
+
+// Restock request statuses accepted by get_restock_requests
+const (
+	restockStatusOpen      = "open"
+	restockStatusFulfilled = "fulfilled"
+	restockStatusAll       = "all"
+)
+
+// RestockRequest is opened when an item falls to its reorder threshold and
+// stays open until fulfill_restock_request closes it. An item has at most
+// one open request.
+type RestockRequest struct {
+	ID          int64     // Monotonically increasing sequence number (starts at 1)
+	Namespace   string    // Namespace of the item
+	ItemID      string    // Item to restock
+	ItemName    string    // Item name when the request was opened
+	Quantity    int       // Quantity on hand when the request was opened
+	OrderQty    int       // Units to order (the item's recommended reorder)
+	Supplier    string    // Who to order from (empty if unset)
+	SupplierSKU string    // The supplier's code for the item (empty if unset)
+	CreatedAt   time.Time // When the item reached its reorder point
+
+	FulfilledAt time.Time // When the request was closed (zero while open)
+	Received    int       // Units added to inventory when it was closed
+}
+
+// open reports whether the request is still waiting to be fulfilled
+func (r *RestockRequest) open() bool {
+	return r.FulfilledAt.IsZero()
+}
+
+// qrPayload is the JSON a purchasing system reads from the request's QR code
+func (r *RestockRequest) qrPayload() (string, error) {
+	payload := map[string]interface{}{
+		"type":      "restock_request",
+		"id":        r.ID,
+		"item_id":   r.ItemID,
+		"item_name": r.ItemName,
+		"quantity":  r.OrderQty,
+	}
+	if r.Namespace != defaultNamespace {
+		payload["namespace"] = r.Namespace
+	}
+	if r.Supplier != "" {
+		payload["supplier"] = r.Supplier
+	}
+	if r.SupplierSKU != "" {
+		payload["supplier_sku"] = r.SupplierSKU
+	}
+	raw, err := json.Marshal(payload)
+	return string(raw), err
+}
+
+// toMap converts the request into a DoCommand-friendly response map, with
+// times shown in loc
+func (r *RestockRequest) toMap(loc *time.Location) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":             r.ID,
+		"namespace":      r.Namespace,
+		"item_id":        r.ItemID,
+		"item_name":      r.ItemName,
+		"quantity":       r.Quantity,
+		"order_quantity": r.OrderQty,
+		"created_at":     formatTimestampIn(r.CreatedAt, loc),
+		"status":         restockStatusOpen,
+	}
+	if r.Supplier != "" {
+		m["supplier"] = r.Supplier
+	}
+	if r.SupplierSKU != "" {
+		m["supplier_sku"] = r.SupplierSKU
+	}
+	if !r.open() {
+		m["status"] = restockStatusFulfilled
+		m["fulfilled_at"] = formatTimestampIn(r.FulfilledAt, loc)
+		m["received"] = r.Received
+	}
+	return m
+}
+
+// openRestockRequestLocked opens a request for an item that just reached its
+// reorder point, unless one is already open. A nil item is ignored. Caller
+// must hold inventoryMu for writing.
+func (s *inventoryKeeperKeeper) openRestockRequestLocked(item *InventoryItem) {
+	if item == nil {
+		return
+	}
+	for _, existing := range s.restockRequests {
+		if existing.open() && existing.Namespace == item.Namespace && existing.ItemID == item.ItemID {
+			return
+		}
+	}
+
+	s.restockSeq++
+	request := &RestockRequest{
+		ID:          s.restockSeq,
+		Namespace:   item.Namespace,
+		ItemID:      item.ItemID,
+		ItemName:    item.ItemName,
+		Quantity:    item.Quantity,
+		OrderQty:    item.recommendedReorder(),
+		Supplier:    item.Supplier,
+		SupplierSKU: item.SupplierSKU,
+		CreatedAt:   s.now(),
+	}
+	s.restockRequests = append(s.restockRequests, request)
+	s.pruneRestockRequestsLocked()
+
+	s.recordHistoryLocked("restock_requested", item.Namespace, item.ItemID, map[string]interface{}{
+		"request_id":     request.ID,
+		"quantity":       request.Quantity,
+		"order_quantity": request.OrderQty,
+	})
+	s.logger.Infof("Opened restock request %d for %s: order %d", request.ID, item.ItemID, request.OrderQty)
+}
+
+// pruneRestockRequestsLocked drops the oldest fulfilled requests past
+// maxRestockRequests. Open requests are never dropped. Caller must hold
+// inventoryMu for writing.
+func (s *inventoryKeeperKeeper) pruneRestockRequestsLocked() {
+	excess := len(s.restockRequests) - maxRestockRequests
+	if excess <= 0 {
+		return
+	}
+	kept := s.restockRequests[:0]
+	for _, request := range s.restockRequests {
+		if excess > 0 && !request.open() {
+			excess--
+			continue
+		}
+		kept = append(kept, request)
+	}
+	s.restockRequests = kept
+}
+
+// handleGetRestockRequests returns restock requests, oldest first. status
+// selects "open" (default), "fulfilled", or "all"; an optional namespace
+// narrows them to one. With qr, each request carries a QR code of its
+// details for scanning into a purchasing system.
+func (s *inventoryKeeperKeeper) handleGetRestockRequests(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	status, err := optionalStringArg(cmd, "status")
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case "":
+		status = restockStatusOpen
+	case restockStatusOpen, restockStatusFulfilled, restockStatusAll:
+	default:
+		return nil, fmt.Errorf("status must be %q, %q, or %q, got: %q", restockStatusOpen, restockStatusFulfilled, restockStatusAll, status)
+	}
+	namespace, err := optionalStringArg(cmd, "namespace")
+	if err != nil {
+		return nil, err
+	}
+	if namespace != "" {
+		if err := validateNamespace(namespace); err != nil {
+			return nil, err
+		}
+	}
+	withQR, _ := cmd["qr"].(bool)
+
+	s.inventoryMu.RLock()
+	var matched []RestockRequest
+	for _, request := range s.restockRequests {
+		if namespace != "" && request.Namespace != namespace {
+			continue
+		}
+		if status != restockStatusAll && request.open() != (status == restockStatusOpen) {
+			continue
+		}
+		matched = append(matched, *request)
+	}
+	s.inventoryMu.RUnlock()
+
+	requests := make([]interface{}, 0, len(matched))
+	units := 0
+	for i := range matched {
+		request := &matched[i]
+		m := request.toMap(s.location)
+		if withQR {
+			payload, err := request.qrPayload()
+			if err != nil {
+				return nil, err
+			}
+			png, err := qrcode.Encode(payload, qrcode.Medium, 256)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate QR code for request %d: %w", request.ID, err)
+			}
+			m["qr_code"] = base64.StdEncoding.EncodeToString(png)
+			m["qr_data"] = payload
+		}
+		requests = append(requests, m)
+		if request.open() {
+			units += request.OrderQty
+		}
+	}
+	return map[string]interface{}{
+		"status":     status,
+		"requests":   requests,
+		"count":      len(requests),
+		"open_units": units,
+	}, nil
+}
+
+// handleFulfillRestockRequest closes an open restock request. With
+// received, that many units are added to the item's quantity, which can
+// clear its low-stock state. Fulfilling isn't undoable.
+func (s *inventoryKeeperKeeper) handleFulfillRestockRequest(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	id, ok, err := intArg(cmd, "request_id")
+	if err != nil {
+		return nil, err
+	}
+	if !ok || id < 1 {
+		return nil, errors.New("request_id is required and must be a positive number")
+	}
+	received, _, err := intArg(cmd, "received")
+	if err != nil {
+		return nil, err
+	}
+	if received < 0 {
+		return nil, fmt.Errorf("received must be non-negative, got: %d", received)
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	var request *RestockRequest
+	for _, candidate := range s.restockRequests {
+		if candidate.ID == int64(id) {
+			request = candidate
+			break
+		}
+	}
+	if request == nil {
+		return nil, fmt.Errorf("restock request %d not found", id)
+	}
+	if !request.open() {
+		return nil, fmt.Errorf("restock request %d was already fulfilled", id)
+	}
+
+	// Check the item before closing anything, so a failed bump leaves the request open
+	item, exists := s.inventory[keyFor(request.Namespace, request.ItemID)]
+	if received > 0 && !exists {
+		return nil, fmt.Errorf("item %s is no longer in inventory; fulfill without received", request.ItemID)
+	}
+
+	now := s.now()
+	request.FulfilledAt = now
+	request.Received = received
+
+	details := map[string]interface{}{
+		"request_id": request.ID,
+		"received":   received,
+	}
+	result := map[string]interface{}{
+		"request": request.toMap(s.location),
+	}
+	if received > 0 {
+		previous := item.Quantity
+		item.Quantity += received
+		item.UpdatedAt = now
+		details["previous_quantity"] = previous
+		details["quantity"] = item.Quantity
+		result["item"] = item.toMap(s.location)
+	}
+	s.recordHistoryLocked("restock_fulfilled", request.Namespace, request.ItemID, details)
+
+	s.logger.Infof("Fulfilled restock request %d for %s, received %d", request.ID, request.ItemID, received)
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"testing"
+)
+
+func TestRestockRequests(t *testing.T) {
+	// setup stocks 10 bolts with a reorder point of 3
+	setup := func(t *testing.T) *inventoryKeeperKeeper {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "bolt-m8", "item_name": "M8 Bolt", "quantity": 10})
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "set_supplier_info", "item_id": "bolt-m8",
+			"supplier": "Grainger", "supplier_sku": "G-1138", "reorder_threshold": 3, "reorder_quantity": 50,
+		})
+		return svc
+	}
+	openRequests := func(t *testing.T, svc *inventoryKeeperKeeper) []interface{} {
+		t.Helper()
+		return mustDoCommand(t, svc, map[string]interface{}{"command": "get_restock_requests"})["requests"].([]interface{})
+	}
+
+	t.Run("crossing the threshold opens exactly one request", func(t *testing.T) {
+		svc := setup(t)
+		if got := openRequests(t, svc); len(got) != 0 {
+			t.Fatalf("expected no requests above the threshold, got: %v", got)
+		}
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "bolt-m8", "delta": -8})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "bolt-m8", "delta": -1})
+		// Back above and down again while the first request is still open
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_quantity", "item_id": "bolt-m8", "quantity": 5})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_quantity", "item_id": "bolt-m8", "quantity": 2})
+
+		got := openRequests(t, svc)
+		if len(got) != 1 {
+			t.Fatalf("expected exactly one open request, got: %v", got)
+		}
+		request := got[0].(map[string]interface{})
+		if request["item_id"] != "bolt-m8" || request["quantity"] != 2 || request["order_quantity"] != 50 || request["supplier"] != "Grainger" {
+			t.Errorf("unexpected request: %v", request)
+		}
+	})
+
+	t.Run("fulfilling closes the request and adds the received units", func(t *testing.T) {
+		svc := setup(t)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "bolt-m8", "delta": -8})
+		id := openRequests(t, svc)[0].(map[string]interface{})["id"]
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "fulfill_restock_request", "request_id": id, "received": 50})
+		if item := result["item"].(map[string]interface{}); item["quantity"] != 52 {
+			t.Errorf("expected quantity 52 after receiving 50, got: %v", item["quantity"])
+		}
+		if got := openRequests(t, svc); len(got) != 0 {
+			t.Errorf("expected no open requests after fulfilling, got: %v", got)
+		}
+		fulfilled := mustDoCommand(t, svc, map[string]interface{}{"command": "get_restock_requests", "status": "fulfilled"})
+		if fulfilled["count"] != 1 {
+			t.Errorf("expected one fulfilled request, got: %v", fulfilled["requests"])
+		}
+
+		if _, err := svc.DoCommand(t.Context(), map[string]interface{}{"command": "fulfill_restock_request", "request_id": id}); err == nil {
+			t.Error("expected fulfilling a closed request to fail")
+		}
+
+		// Dropping to the threshold again opens a new request
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_quantity", "item_id": "bolt-m8", "quantity": 1})
+		if got := openRequests(t, svc); len(got) != 1 {
+			t.Errorf("expected a new open request, got: %v", got)
+		}
+	})
+
+	t.Run("qr renders the request details", func(t *testing.T) {
+		svc := setup(t)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "bolt-m8", "delta": -9})
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_restock_requests", "qr": true})
+		request := result["requests"].([]interface{})[0].(map[string]interface{})
+
+		raw, err := base64.StdEncoding.DecodeString(request["qr_code"].(string))
+		if err != nil {
+			t.Fatalf("qr_code is not base64: %v", err)
+		}
+		img, err := png.Decode(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("qr_code is not a PNG: %v", err)
+		}
+		content, _, err := decodeCodeImage(img)
+		if err != nil || content != request["qr_data"] {
+			t.Errorf("qr_code decoded to %q (%v), want %q", content, err, request["qr_data"])
+		}
+	})
+}
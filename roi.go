@@ -0,0 +1,127 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Units for RegionOfInterest coordinates
+const (
+	roiUnitsFraction = "fraction"
+	roiUnitsPixels   = "pixels"
+)
+
+// RegionOfInterest limits scanning to part of the frame. Coordinates are in
+// the preprocessed frame (after rotation), as shown by get_image.
+type RegionOfInterest struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Units  string  `json:"units,omitempty"` // "fraction" (default) or "pixels"
+}
+
+// units returns the coordinate units, defaulting to fractions of the frame
+func (r *RegionOfInterest) units() string {
+	if r.Units == "" {
+		return roiUnitsFraction
+	}
+	return r.Units
+}
+
+// validate checks what can be checked without a frame: positive dimensions,
+// a non-negative origin, whole pixels, and fractions within the frame
+func (r *RegionOfInterest) validate() error {
+	if r.Width <= 0 || r.Height <= 0 {
+		return fmt.Errorf("roi width and height must be positive, got: %vx%v", r.Width, r.Height)
+	}
+	if r.X < 0 || r.Y < 0 {
+		return fmt.Errorf("roi x and y must be non-negative, got: %v,%v", r.X, r.Y)
+	}
+
+	switch r.units() {
+	case roiUnitsFraction:
+		if r.X+r.Width > 1 || r.Y+r.Height > 1 {
+			return fmt.Errorf("roi must lie within the frame, got x+width=%v and y+height=%v (max 1)", r.X+r.Width, r.Y+r.Height)
+		}
+	case roiUnitsPixels:
+		for _, v := range []float64{r.X, r.Y, r.Width, r.Height} {
+			if v != math.Trunc(v) {
+				return fmt.Errorf("roi values must be whole numbers when units is %q, got: %v", roiUnitsPixels, v)
+			}
+		}
+	default:
+		return fmt.Errorf("roi units must be %q or %q, got: %q", roiUnitsFraction, roiUnitsPixels, r.Units)
+	}
+	return nil
+}
+
+// rect resolves the region against a frame's bounds. Pixel regions that
+// extend past the frame are an error rather than silently clipped.
+func (r *RegionOfInterest) rect(bounds image.Rectangle) (image.Rectangle, error) {
+	var rect image.Rectangle
+	if r.units() == roiUnitsPixels {
+		rect = image.Rect(int(r.X), int(r.Y), int(r.X+r.Width), int(r.Y+r.Height))
+	} else {
+		w, h := float64(bounds.Dx()), float64(bounds.Dy())
+		rect = image.Rect(
+			int(math.Round(r.X*w)), int(math.Round(r.Y*h)),
+			int(math.Round((r.X+r.Width)*w)), int(math.Round((r.Y+r.Height)*h)),
+		)
+	}
+	rect = rect.Add(bounds.Min)
+
+	if !rect.In(bounds) {
+		return image.Rectangle{}, fmt.Errorf("roi %v lies outside the %dx%d frame", rect, bounds.Dx(), bounds.Dy())
+	}
+	if rect.Empty() {
+		return image.Rectangle{}, errors.New("roi is smaller than one pixel in this frame")
+	}
+	return rect, nil
+}
+
+// frameDetector finds codes in an image
+type frameDetector func(ctx context.Context, img image.Image) ([]objectdetection.Detection, error)
+
+// detectInFrame runs the configured code detectors on a preprocessed frame,
+// cropped to the configured region of interest. Detections are returned in frame
+// coordinates; anything outside the region is never seen by the detector.
+func (s *inventoryKeeperKeeper) detectInFrame(ctx context.Context, frame image.Image) ([]objectdetection.Detection, error) {
+	return s.detectInFrameWith(ctx, frame, s.codeDetector())
+}
+
+// detectInFrameWith is detectInFrame with the given detector
+func (s *inventoryKeeperKeeper) detectInFrameWith(ctx context.Context, frame image.Image, detect frameDetector) ([]objectdetection.Detection, error) {
+	roi := s.config().ROI
+	if roi == nil {
+		return detect(ctx, frame)
+	}
+
+	rect, err := roi.rect(frame.Bounds())
+	if err != nil {
+		return nil, err
+	}
+	cropped := toRGBA(toRGBA(frame).SubImage(rect))
+
+	detections, err := detect(ctx, cropped)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shift boxes from crop coordinates back into the full frame
+	offset := rect.Min.Sub(frame.Bounds().Min)
+	shifted := make([]objectdetection.Detection, 0, len(detections))
+	for _, detection := range detections {
+		box := cropped.Bounds()
+		if b := detection.BoundingBox(); b != nil {
+			box = *b
+		}
+		shifted = append(shifted, objectdetection.NewDetection(frame.Bounds(), box.Add(offset), detection.Score(), detection.Label()))
+	}
+	return shifted, nil
+}
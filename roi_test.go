@@ -0,0 +1,98 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/draw"
+	"image/png"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestRegionOfInterest(t *testing.T) {
+	// twoCodeFrame places apple on the left half of the frame and banana on the right
+	twoCodeFrame := func(t *testing.T) image.Image {
+		t.Helper()
+		frame := image.NewRGBA(image.Rect(0, 0, 512, 256))
+		for i, content := range []string{`{"item_id":"apple-001","item_name":"Apple"}`, `{"item_id":"banana-001","item_name":"Banana"}`} {
+			pngBytes, err := qrcode.Encode(content, qrcode.Medium, 256)
+			if err != nil {
+				t.Fatalf("failed to render QR: %v", err)
+			}
+			code, err := png.Decode(bytes.NewReader(pngBytes))
+			if err != nil {
+				t.Fatalf("failed to read QR: %v", err)
+			}
+			draw.Draw(frame, image.Rect(i*256, 0, (i+1)*256, 256), code, image.Point{}, draw.Src)
+		}
+		return frame
+	}
+
+	// decodingVision makes the mock vision service report the single QR it can read in the image
+	decodingVision := func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		content, _, err := decodeCodeImage(img)
+		if err != nil {
+			return nil, nil
+		}
+		return []objectdetection.Detection{objectdetection.NewDetection(img.Bounds(), img.Bounds(), 1.0, content)}, nil
+	}
+
+	for _, tc := range []struct {
+		name   string
+		roi    *RegionOfInterest
+		wantID string
+		wantX  int
+	}{
+		{"fraction roi keeps the left code", &RegionOfInterest{X: 0, Y: 0, Width: 0.5, Height: 1}, "apple-001", 0},
+		{"pixel roi keeps the right code", &RegionOfInterest{X: 256, Y: 0, Width: 256, Height: 256, Units: roiUnitsPixels}, "banana-001", 256},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, mockVision := newTestKeeper(t, &Config{ROI: tc.roi})
+			mockVision.DetectionsFunc = decodingVision
+			setCameraFrame(t, svc, twoCodeFrame(t))
+
+			detections, err := svc.detectQRCodes(context.Background())
+			if err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+			if len(detections) != 1 {
+				t.Fatalf("expected only the code inside the roi, got: %d detections", len(detections))
+			}
+			data, err := svc.decodeQRPayload(detections[0].Label())
+			if err != nil || data.ItemID != tc.wantID {
+				t.Errorf("expected %s, got: %+v (err %v)", tc.wantID, data, err)
+			}
+			if box := detections[0].BoundingBox(); box == nil || box.Min.X != tc.wantX {
+				t.Errorf("expected box in frame coordinates starting at x=%d, got: %v", tc.wantX, box)
+			}
+		})
+	}
+
+	t.Run("pixel roi past the frame edge fails the scan", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{ROI: &RegionOfInterest{X: 400, Y: 0, Width: 256, Height: 256, Units: roiUnitsPixels}})
+		mockVision.DetectionsFunc = decodingVision
+		setCameraFrame(t, svc, twoCodeFrame(t))
+
+		if _, err := svc.detectQRCodes(context.Background()); err == nil {
+			t.Error("expected error for roi outside the frame")
+		}
+	})
+
+	t.Run("invalid roi config is rejected", func(t *testing.T) {
+		for _, roi := range []*RegionOfInterest{
+			{Width: 0, Height: 0.5},
+			{X: 0.6, Width: 0.5, Height: 0.5},
+			{X: -1, Width: 10, Height: 10, Units: roiUnitsPixels},
+			{Width: 10.5, Height: 10, Units: roiUnitsPixels},
+			{Width: 0.5, Height: 0.5, Units: "inches"},
+		} {
+			cfg := &Config{CameraName: "cam", QRVisionService: "qr", ROI: roi}
+			if _, _, err := cfg.Validate(""); err == nil {
+				t.Errorf("expected validation error for roi %+v", roi)
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tunableConfigFields are the config keys set_config may change on a running
+// keeper. Everything else (dependencies, keys, codecs, files) is structural
+// and needs a reconfigure; see liveConfigFields for what that applies in
+// place.
+var tunableConfigFields = map[string]bool{
+	"scan_interval_ms":             true,
+	"poll_interval_seconds":        true,
+	"scan_interval_jitter_seconds": true,
+	"grace_period_ms":              true,
+	"presence_debounce_scans":      true,
+	"capture_retries":              true,
+	"command_timeout_seconds":      true,
+	"check_in_window_seconds":      true,
+	"grace_after_checkin_seconds":  true,
+	"check_in_delay_seconds":       true,
+	"theft_alert_delay_seconds":    true,
+	"audit_alerts":                 true,
+	"duplicate_alerts":             true,
+	"min_alert_severity":           true,
+	"max_undo":                     true,
+	"max_inventory_items":          true,
+	"inventory_eviction":           true,
+	"vision_busy_fail_fast":        true,
+	"local_decode_fallback":        true,
+	"skip_duplicate_frames":        true,
+	"dependency_failure_policy":    true,
+	"name_conflict_policy":         true,
+	"alert_cooldown_seconds":       true,
+}
+
+// config returns the current config. The returned value is never mutated;
+// set_config swaps in a new one, so callers get a consistent view.
+func (s *inventoryKeeperKeeper) config() *Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+// applyTheftTimings updates the theft detector's windows from cfg
+func (s *inventoryKeeperKeeper) applyTheftTimings(cfg *Config) {
+	s.theftMu.Lock()
+	s.theft.window = cfg.checkInWindow()
+	s.theft.grace = cfg.graceAfterCheckIn()
+	s.theft.delay = cfg.theftAlertDelay()
+	s.theftMu.Unlock()
+}
+
+// effectiveTunables reports the value in effect for each tunable field,
+// with defaults filled in
+func effectiveTunables(cfg *Config) map[string]interface{} {
+	return map[string]interface{}{
+		"scan_interval_ms":             cfg.scanInterval().Milliseconds(),
+		"scan_interval_jitter_seconds": cfg.scanJitter().Seconds(),
+		"monitoring_enabled":           cfg.monitoringEnabled(),
+		"grace_period_ms":              cfg.gracePeriod().Milliseconds(),
+		"presence_debounce_scans":      cfg.presenceDebounceScans(),
+		"capture_retries":              cfg.captureRetries(),
+		"command_timeout_seconds":      cfg.commandTimeout().Seconds(),
+		"check_in_window_seconds":      cfg.checkInWindow().Seconds(),
+		"grace_after_checkin_seconds":  cfg.graceAfterCheckIn().Seconds(),
+		"check_in_delay_seconds":       cfg.checkInDelay().Seconds(),
+		"theft_alert_delay_seconds":    cfg.theftAlertDelay().Seconds(),
+		"audit_alerts":                 cfg.AuditAlerts,
+		"duplicate_alerts":             cfg.DuplicateAlerts,
+		"min_alert_severity":           cfg.minAlertSeverity(),
+		"max_undo":                     cfg.maxUndo(),
+		"max_inventory_items":          cfg.maxInventoryItems(),
+		"inventory_eviction":           cfg.inventoryEviction(),
+		"vision_busy_fail_fast":        cfg.VisionBusyFailFast,
+		"local_decode_fallback":        cfg.LocalDecodeFallback,
+		"skip_duplicate_frames":        cfg.SkipDuplicateFrames,
+		"dependency_failure_policy":    cfg.dependencyFailurePolicy(),
+		"name_conflict_policy":         cfg.nameConflictPolicy(),
+		"alert_cooldown_seconds":       cfg.alertCooldown().Seconds(),
+	}
+}
+
+// handleSetConfig applies tunable settings to the running keeper. Changes are
+// validated with the same rules as Config.Validate and applied all-or-nothing.
+func (s *inventoryKeeperKeeper) handleSetConfig(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	changes, ok := cmd["config"].(map[string]interface{})
+	if !ok || len(changes) == 0 {
+		return nil, errors.New("config is required and must be a non-empty object")
+	}
+
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var rejected []string
+	for _, key := range keys {
+		if !tunableConfigFields[key] {
+			rejected = append(rejected, key)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("cannot change %s at runtime; use set_config only for tunable settings and reconfigure for the rest", strings.Join(rejected, ", "))
+	}
+
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+
+	// Overlay the changes on the current config via its JSON form
+	raw, err := json.Marshal(s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(raw, &merged); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	for key, value := range changes {
+		merged[key] = value
+	}
+	// The two interval keys are alternatives, so setting one replaces the other
+	if _, ok := changes["poll_interval_seconds"]; ok {
+		if _, both := changes["scan_interval_ms"]; !both {
+			delete(merged, "scan_interval_ms")
+		}
+	} else if _, ok := changes["scan_interval_ms"]; ok {
+		delete(merged, "poll_interval_seconds")
+	}
+	raw, err = json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	next := &Config{}
+	if err := json.Unmarshal(raw, next); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if _, _, err := next.Validate(""); err != nil {
+		return nil, err
+	}
+
+	// Background scanning can't be started or stopped without a reconfigure
+	if next.monitoringEnabled() != s.cfg.monitoringEnabled() {
+		return nil, errors.New("scan_interval_ms and poll_interval_seconds cannot enable or disable monitoring at runtime; reconfigure instead")
+	}
+
+	s.cfg = next
+	s.applyTheftTimings(next)
+
+	s.logger.Infof("Applied runtime config changes: %s", strings.Join(keys, ", "))
+	return map[string]interface{}{
+		"applied":   toInterfaceSlice(keys),
+		"effective": effectiveTunables(next),
+	}, nil
+}
@@ -0,0 +1,114 @@
+package inventorykeeper
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetConfig(t *testing.T) {
+	t.Run("valid grace period is applied immediately", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "set_config",
+			"config": map[string]interface{}{
+				"grace_period_ms":         2500.0,
+				"check_in_window_seconds": 30.0,
+			},
+		})
+
+		effective := result["effective"].(map[string]interface{})
+		if effective["grace_period_ms"] != int64(2500) {
+			t.Errorf("expected effective grace_period_ms 2500, got: %v", effective["grace_period_ms"])
+		}
+		if got := svc.config().gracePeriod(); got != 2500*time.Millisecond {
+			t.Errorf("expected grace period 2.5s, got: %v", got)
+		}
+
+		svc.theftMu.Lock()
+		window := svc.theft.window
+		svc.theftMu.Unlock()
+		if window != 30*time.Second {
+			t.Errorf("expected theft detector window 30s, got: %v", window)
+		}
+
+		applied := result["applied"].([]interface{})
+		if len(applied) != 2 || applied[0] != "check_in_window_seconds" || applied[1] != "grace_period_ms" {
+			t.Errorf("expected both keys applied, got: %v", applied)
+		}
+	})
+
+	t.Run("negative value is rejected and config is unchanged", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		before := svc.config()
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "set_config",
+			"config":  map[string]interface{}{"grace_period_ms": -100.0},
+		})
+		if err == nil || !strings.Contains(err.Error(), "grace_period_ms") {
+			t.Fatalf("expected grace_period_ms validation error, got: %v", err)
+		}
+		if svc.config() != before {
+			t.Error("expected config to be unchanged after rejected set_config")
+		}
+	})
+
+	t.Run("structural field is rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "set_config",
+			"config":  map[string]interface{}{"camera_name": "other-camera"},
+		})
+		if err == nil || !strings.Contains(err.Error(), "camera_name") {
+			t.Fatalf("expected camera_name to be rejected, got: %v", err)
+		}
+		if svc.config().CameraName != "test-camera" {
+			t.Errorf("expected camera_name unchanged, got: %s", svc.config().CameraName)
+		}
+	})
+
+	t.Run("either interval key replaces the other", func(t *testing.T) {
+		interval := 60000
+		svc, _ := newTestKeeper(t, &Config{ScanIntervalMs: &interval})
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "set_config",
+			"config":  map[string]interface{}{"poll_interval_seconds": 2.5},
+		})
+		if cfg := svc.config(); cfg.ScanIntervalMs != nil || cfg.scanInterval() != 2500*time.Millisecond {
+			t.Errorf("expected poll_interval_seconds to replace scan_interval_ms, got: %v", cfg.scanInterval())
+		}
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "set_config",
+			"config":  map[string]interface{}{"scan_interval_ms": 500.0},
+		})
+		if cfg := svc.config(); cfg.PollIntervalSeconds != nil || cfg.scanInterval() != 500*time.Millisecond {
+			t.Errorf("expected scan_interval_ms to replace poll_interval_seconds, got: %v", cfg.scanInterval())
+		}
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "set_config",
+			"config":  map[string]interface{}{"scan_interval_ms": 500.0, "poll_interval_seconds": 2.5},
+		})
+		if err == nil {
+			t.Error("expected error setting both interval keys at once")
+		}
+	})
+
+	t.Run("monitoring cannot be toggled at runtime", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "set_config",
+			"config":  map[string]interface{}{"scan_interval_ms": 500.0},
+		})
+		if err == nil {
+			t.Fatal("expected error enabling monitoring on a keeper started without it")
+		}
+	})
+}
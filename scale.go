@@ -0,0 +1,103 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// scaleWeightReading is the sensor reading key holding the shelf weight
+const scaleWeightReading = "weight"
+
+// readWeight returns the current shelf weight from the scale sensor
+func (s *inventoryKeeperKeeper) readWeight(ctx context.Context) (float64, error) {
+	readings, err := s.scaleSensor.Readings(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read scale sensor %s: %w", s.config().ScaleSensor, err)
+	}
+
+	raw, ok := readings[scaleWeightReading]
+	if !ok {
+		return 0, fmt.Errorf("scale sensor %s returned no %q reading", s.config().ScaleSensor, scaleWeightReading)
+	}
+	weight, ok := toFloat(raw)
+	if !ok {
+		return 0, fmt.Errorf("scale sensor %s returned a non-numeric %q reading: %v", s.config().ScaleSensor, scaleWeightReading, raw)
+	}
+	return weight, nil
+}
+
+// estimateQuantity converts a measured weight into a unit count, rounding to
+// the nearest whole unit. Negative readings (scale drift) count as empty.
+func estimateQuantity(weight, unitWeight float64) (int, error) {
+	if unitWeight <= 0 {
+		return 0, errors.New("unit_weight must be positive to estimate quantity")
+	}
+	if weight <= 0 {
+		return 0, nil
+	}
+	return int(math.Round(weight / unitWeight)), nil
+}
+
+// handleReadWeight returns the current shelf weight and, when item_id names an
+// item with a unit_weight, the quantity that weight corresponds to
+func (s *inventoryKeeperKeeper) handleReadWeight(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if s.scaleSensor == nil {
+		return map[string]interface{}{
+			"configured": false,
+			"message":    "scale_sensor not configured",
+		}, nil
+	}
+
+	itemID := ""
+	if raw, ok := cmd["item_id"]; ok {
+		itemID, ok = raw.(string)
+		if !ok {
+			return nil, errors.New("item_id must be a string")
+		}
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	weight, err := s.readWeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"configured": true,
+		"weight":     weight,
+	}
+	if itemID == "" {
+		return result, nil
+	}
+
+	s.inventoryMu.RLock()
+	item, exists := s.inventory[keyFor(namespace, itemID)]
+	var unitWeight float64
+	var recorded int
+	if exists {
+		unitWeight = item.UnitWeight
+		recorded = item.Quantity
+	}
+	s.inventoryMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	estimated, err := estimateQuantity(weight, unitWeight)
+	if err != nil {
+		return nil, fmt.Errorf("cannot estimate quantity for %s: %w", itemID, err)
+	}
+
+	result["namespace"] = namespace
+	result["item_id"] = itemID
+	result["unit_weight"] = unitWeight
+	result["estimated_quantity"] = estimated
+	result["recorded_quantity"] = recorded
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// setScaleWeight makes the keeper's mock scale report the given weight
+func setScaleWeight(svc *inventoryKeeperKeeper, weight float64) {
+	svc.scaleSensor.(*inject.Sensor).ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"weight": weight}, nil
+	}
+}
+
+func TestEstimateQuantity(t *testing.T) {
+	cases := []struct {
+		name       string
+		weight     float64
+		unitWeight float64
+		expected   int
+	}{
+		{"exact multiple", 2.5, 0.5, 5},
+		{"rounds to nearest unit", 2.7, 0.5, 5},
+		{"rounds up past half", 2.8, 0.5, 6},
+		{"negative drift reads empty", -0.05, 0.5, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := estimateQuantity(tc.weight, tc.unitWeight)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %d, got: %d", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := estimateQuantity(1.0, 0); err == nil {
+		t.Error("expected error for zero unit_weight")
+	}
+}
+
+func TestReadWeight(t *testing.T) {
+	t.Run("unconfigured scale reports not configured", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "read_weight"})
+		if result["configured"] != false {
+			t.Errorf("expected configured false, got: %v", result["configured"])
+		}
+	})
+
+	t.Run("estimates quantity from mock sensor reading", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{ScaleSensor: "shelf-scale"})
+		setScaleWeight(svc, 3.05)
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command":     "add_item",
+			"item_id":     "rice-300",
+			"item_name":   "Basmati Rice",
+			"quantity":    8.0,
+			"unit_weight": 0.5,
+		})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "read_weight", "item_id": "rice-300"})
+		if result["weight"] != 3.05 {
+			t.Errorf("expected weight 3.05, got: %v", result["weight"])
+		}
+		if result["estimated_quantity"] != 6 {
+			t.Errorf("expected estimated_quantity 6, got: %v", result["estimated_quantity"])
+		}
+		if result["recorded_quantity"] != 8 {
+			t.Errorf("expected recorded_quantity 8, got: %v", result["recorded_quantity"])
+		}
+	})
+
+	t.Run("item without unit_weight returns error", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{ScaleSensor: "shelf-scale"})
+		setScaleWeight(svc, 1.0)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "jar-1", "item_name": "Jam"})
+
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "read_weight", "item_id": "jar-1"}); err == nil {
+			t.Error("expected error estimating an item without unit_weight")
+		}
+	})
+
+	t.Run("scale_sensor is a required dependency when set", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", ScaleSensor: "shelf-scale"}
+		required, _, err := cfg.Validate("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(required) != 3 || required[2] != "shelf-scale" {
+			t.Errorf("expected shelf-scale in required deps, got: %v", required)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package inventorykeeper
+
+import "time"
+
+// maxScanBackoff caps how far failed scans stretch the scan interval. An
+// interval configured above the cap is never shortened.
+const maxScanBackoff = time.Minute
+
+// nextScanDelay returns how long to wait before the next background scan: the
+// base interval doubled once per consecutive failed scan (up to
+// maxScanBackoff), plus a random jitter in [0, jitter). randFloat must return
+// values in [0, 1).
+func nextScanDelay(base, jitter time.Duration, failures int, randFloat func() float64) time.Duration {
+	delay := base
+	limit := max(base, maxScanBackoff)
+	for i := 0; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	delay = min(delay, limit)
+
+	if jitter > 0 {
+		delay += time.Duration(randFloat() * float64(jitter))
+	}
+	return delay
+}
+
+// scanDelay applies the current config and the keeper's random source to
+// nextScanDelay. Under dependency_failure_policy "degrade", a degraded keeper
+// skips straight to the longest delay, probing rather than scanning.
+func (s *inventoryKeeperKeeper) scanDelay(failures int) time.Duration {
+	cfg := s.config()
+	if cfg.dependencyFailurePolicy() == dependencyDegrade && failures >= cameraDegradedThreshold {
+		return nextScanDelay(max(cfg.scanInterval(), maxScanBackoff), cfg.scanJitter(), 0, s.scanRand)
+	}
+	return nextScanDelay(cfg.scanInterval(), cfg.scanJitter(), failures, s.scanRand)
+}
@@ -0,0 +1,65 @@
+package inventorykeeper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextScanDelay(t *testing.T) {
+	t.Run("jitter stays within bounds", func(t *testing.T) {
+		base, jitter := time.Second, 500*time.Millisecond
+
+		for _, r := range []float64{0, 0.25, 0.5, 0.999999} {
+			delay := nextScanDelay(base, jitter, 0, func() float64 { return r })
+			if delay < base || delay >= base+jitter {
+				t.Errorf("rand %v: expected delay in [%v, %v), got: %v", r, base, base+jitter, delay)
+			}
+		}
+	})
+
+	t.Run("no jitter when not configured", func(t *testing.T) {
+		delay := nextScanDelay(time.Second, 0, 0, func() float64 { return 0.9 })
+		if delay != time.Second {
+			t.Errorf("expected exact interval, got: %v", delay)
+		}
+	})
+
+	t.Run("repeated failures increase the delay up to the cap", func(t *testing.T) {
+		noJitter := func() float64 { return 0 }
+
+		previous := nextScanDelay(time.Second, 0, 0, noJitter)
+		for failures := 1; failures <= 5; failures++ {
+			delay := nextScanDelay(time.Second, 0, failures, noJitter)
+			if delay <= previous {
+				t.Errorf("failures=%d: expected delay above %v, got: %v", failures, previous, delay)
+			}
+			previous = delay
+		}
+
+		if delay := nextScanDelay(time.Second, 0, 50, noJitter); delay != maxScanBackoff {
+			t.Errorf("expected delay capped at %v, got: %v", maxScanBackoff, delay)
+		}
+		if delay := nextScanDelay(2*time.Minute, 0, 3, noJitter); delay != 2*time.Minute {
+			t.Errorf("expected interval above the cap to be kept, got: %v", delay)
+		}
+	})
+
+	t.Run("keeper uses configured jitter and injected random source", func(t *testing.T) {
+		jitter := 2.0
+		svc, _ := newTestKeeper(t, &Config{ScanIntervalJitterSeconds: &jitter})
+		svc.scanRand = func() float64 { return 0.5 }
+
+		// Test keepers run with scan_interval_ms=0, leaving only the jitter
+		if delay := svc.scanDelay(0); delay != time.Second {
+			t.Errorf("expected half of the 2s jitter, got: %v", delay)
+		}
+	})
+
+	t.Run("negative jitter is rejected", func(t *testing.T) {
+		jitter := -1.0
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", ScanIntervalJitterSeconds: &jitter}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for negative scan_interval_jitter_seconds")
+		}
+	})
+}
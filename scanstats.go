@@ -0,0 +1,132 @@
+package inventorykeeper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// defaultScanStatsWindow is how many recent scans get_scan_stats covers by default
+const defaultScanStatsWindow = 100
+
+// scanOutcome is the result of one background scan
+type scanOutcome struct {
+	At      time.Time     // When the scan finished
+	Latency time.Duration // Time spent capturing and detecting
+	Failed  bool          // Capture or detection returned an error
+	Codes   int           // Detections in the frame
+	Decoded int           // Detections that decoded to an item payload
+}
+
+// scanStats is a fixed-size ring of recent scan outcomes. Recording is O(1)
+// and summarizing is O(window).
+type scanStats struct {
+	mu       sync.Mutex
+	outcomes []scanOutcome // Ring buffer, len == window size
+	next     int           // Slot the next outcome is written to
+	count    int           // Filled slots, up to len(outcomes)
+	skipped  int           // Frames skipped as duplicates since startup (not windowed)
+}
+
+func newScanStats(window int) *scanStats {
+	return &scanStats{outcomes: make([]scanOutcome, window)}
+}
+
+// record adds an outcome, overwriting the oldest once the window is full
+func (st *scanStats) record(outcome scanOutcome) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.outcomes[st.next] = outcome
+	st.next = (st.next + 1) % len(st.outcomes)
+	if st.count < len(st.outcomes) {
+		st.count++
+	}
+}
+
+// recordSkippedFrame counts a frame whose decoding was skipped because it
+// matched the previous frame
+func (st *scanStats) recordSkippedFrame() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.skipped++
+}
+
+// summary computes windowed figures. A scan succeeds when at least one code
+// in its frame decodes to an item; latency and codes per frame average over
+// every scan in the window, including failures.
+func (st *scanStats) summary(loc *time.Location) map[string]interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var successful, failed, empty, codes int
+	var latency time.Duration
+	var oldest, newest time.Time
+	for i := 0; i < st.count; i++ {
+		// Walk from the oldest slot to the newest
+		o := st.outcomes[(st.next-st.count+i+len(st.outcomes))%len(st.outcomes)]
+		if i == 0 {
+			oldest = o.At
+		}
+		newest = o.At
+
+		latency += o.Latency
+		codes += o.Codes
+		switch {
+		case o.Failed:
+			failed++
+		case o.Decoded > 0:
+			successful++
+		default:
+			empty++
+		}
+	}
+
+	result := map[string]interface{}{
+		"window_size":              len(st.outcomes),
+		"scans":                    st.count,
+		"successful":               successful,
+		"empty":                    empty,
+		"failed":                   failed,
+		"success_rate":             0.0,
+		"average_latency_ms":       0.0,
+		"average_codes_per_frame":  0.0,
+		"oldest_at":                formatTimestampIn(oldest, loc),
+		"newest_at":                formatTimestampIn(newest, loc),
+		"duplicate_frames_skipped": st.skipped,
+	}
+	if st.count > 0 {
+		n := float64(st.count)
+		result["success_rate"] = float64(successful) / n
+		result["average_latency_ms"] = float64(latency.Milliseconds()) / n
+		result["average_codes_per_frame"] = float64(codes) / n
+	}
+	return result
+}
+
+// scanStatsWindow returns how many recent scans get_scan_stats covers, defaulting to 100
+func (cfg *Config) scanStatsWindow() int {
+	if cfg.ScanStatsWindow == nil {
+		return defaultScanStatsWindow
+	}
+	return *cfg.ScanStatsWindow
+}
+
+// countDecodable returns how many detections decode to an item payload
+func (s *inventoryKeeperKeeper) countDecodable(detections []objectdetection.Detection) int {
+	decoded := 0
+	for _, detection := range detections {
+		if data, err := s.decodeQRPayload(detection.Label()); err == nil && data.ItemID != "" {
+			decoded++
+		}
+	}
+	return decoded
+}
+
+// handleGetScanStats returns decode success rate, latency, and codes per
+// frame over the most recent background scans
+func (s *inventoryKeeperKeeper) handleGetScanStats(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return s.scanStats.summary(s.location), nil
+}
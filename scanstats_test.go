@@ -0,0 +1,81 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestScanStats(t *testing.T) {
+	t.Run("mix of successful and empty scans", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+
+		// Three scans find a decodable code, one finds an unknown code, one finds nothing
+		frames := [][]objectdetection.Detection{
+			{testDetection(0, `{"item_id":"apple-001","item_name":"Apple"}`)},
+			{testDetection(0, `{"item_id":"apple-001","item_name":"Apple"}`), testDetection(1, `{"item_id":"banana-001","item_name":"Banana"}`)},
+			{testDetection(0, "not an item")},
+			{},
+			{testDetection(0, `{"item_id":"apple-001","item_name":"Apple"}`)},
+		}
+		for _, frame := range frames {
+			mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+				return frame, nil
+			}
+			if err := svc.scanAndCompare(context.Background()); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_scan_stats"})
+		if result["scans"] != 5 || result["successful"] != 3 || result["empty"] != 2 {
+			t.Errorf("expected 5 scans, 3 successful, 2 empty, got: %v", result)
+		}
+		if result["success_rate"] != 0.6 {
+			t.Errorf("expected success rate 0.6, got: %v", result["success_rate"])
+		}
+		if result["average_codes_per_frame"] != 1.0 {
+			t.Errorf("expected 1 code per frame, got: %v", result["average_codes_per_frame"])
+		}
+	})
+
+	t.Run("failed scans count against the rate", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, &Config{CaptureRetries: new(int)})
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return nil, errors.New("camera unavailable")
+		}
+		svc.scanAndCompare(context.Background())
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_scan_stats"})
+		if result["failed"] != 1 || result["success_rate"] != 0.0 {
+			t.Errorf("expected 1 failed scan and rate 0, got: %v", result)
+		}
+	})
+
+	t.Run("window keeps only the most recent scans", func(t *testing.T) {
+		stats := newScanStats(3)
+		base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		for i := 0; i < 5; i++ {
+			// Only the first two scans succeed; both fall out of the window
+			decoded := 0
+			if i < 2 {
+				decoded = 1
+			}
+			stats.record(scanOutcome{At: base.Add(time.Duration(i) * time.Second), Latency: 10 * time.Millisecond, Codes: decoded, Decoded: decoded})
+		}
+
+		summary := stats.summary(time.UTC)
+		if summary["scans"] != 3 || summary["success_rate"] != 0.0 {
+			t.Errorf("expected 3 scans with no successes, got: %v", summary)
+		}
+		if summary["oldest_at"] != formatTimestamp(base.Add(2*time.Second)) || summary["newest_at"] != formatTimestamp(base.Add(4*time.Second)) {
+			t.Errorf("expected window from 2s to 4s, got: %v to %v", summary["oldest_at"], summary["newest_at"])
+		}
+		if summary["average_latency_ms"] != 10.0 {
+			t.Errorf("expected 10ms average latency, got: %v", summary["average_latency_ms"])
+		}
+	})
+}
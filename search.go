@@ -0,0 +1,176 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// defaultSearchLimit caps search_items results when no limit is given
+const defaultSearchLimit = 20
+
+// Match kinds reported by search_items, best first
+const (
+	searchMatchSubstring = "substring"
+	searchMatchFuzzy     = "fuzzy"
+)
+
+// searchMatch is one item matched by search_items
+type searchMatch struct {
+	Item *InventoryItem
+	Kind string // searchMatchSubstring or searchMatchFuzzy
+	Rank int    // Lower is better within a kind: substring position, or edit distance
+}
+
+// fuzzyThreshold is the largest edit distance accepted for a query. Very
+// short queries must match exactly, short ones tolerate one typo, and longer
+// ones two.
+func fuzzyThreshold(query []rune) int {
+	if len(query) < 3 {
+		return 0
+	}
+	if len(query) <= 5 {
+		return 1
+	}
+	return 2
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// searchWords returns the text to compare a fuzzy query against: the whole
+// id, the whole name, and each word of the name
+func searchWords(item *InventoryItem) []string {
+	words := []string{strings.ToLower(item.ItemID), strings.ToLower(item.ItemName)}
+	words = append(words, strings.FieldsFunc(strings.ToLower(item.ItemName), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})...)
+	return words
+}
+
+// matchItem compares a lower-cased query against an item. Substring matches
+// rank by where the query appears (earlier is better); fuzzy matches rank by
+// edit distance and are only tried when fuzzy is set.
+func matchItem(item *InventoryItem, query string, fuzzy bool) (searchMatch, bool) {
+	best := -1
+	for _, field := range []string{strings.ToLower(item.ItemID), strings.ToLower(item.ItemName)} {
+		if i := strings.Index(field, query); i >= 0 && (best < 0 || i < best) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		return searchMatch{Item: item, Kind: searchMatchSubstring, Rank: best}, true
+	}
+	if !fuzzy {
+		return searchMatch{}, false
+	}
+
+	q := []rune(query)
+	threshold := fuzzyThreshold(q)
+	distance := -1
+	for _, word := range searchWords(item) {
+		if d := levenshtein(q, []rune(word)); distance < 0 || d < distance {
+			distance = d
+		}
+	}
+	if distance < 0 || distance > threshold {
+		return searchMatch{}, false
+	}
+	return searchMatch{Item: item, Kind: searchMatchFuzzy, Rank: distance}, true
+}
+
+// handleSearchItems finds items in the namespace whose name or id contains
+// query (case-insensitive). With fuzzy set, items within a small edit
+// distance of the id, name, or a word of the name also match, ranked after
+// every substring match.
+func (s *inventoryKeeperKeeper) handleSearchItems(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	query, err := optionalStringArg(cmd, "query")
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, errors.New("query is required")
+	}
+	limit, hasLimit, err := intArg(cmd, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if !hasLimit {
+		limit = defaultSearchLimit
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got: %d", limit)
+	}
+	fuzzy, _ := cmd["fuzzy"].(bool)
+
+	s.inventoryMu.RLock()
+	var matches []searchMatch
+	for key, item := range s.inventory {
+		if key.Namespace != namespace {
+			continue
+		}
+		if m, ok := matchItem(item, query, fuzzy); ok {
+			matches = append(matches, m)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Kind != b.Kind {
+			return a.Kind == searchMatchSubstring
+		}
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		return a.Item.ItemID < b.Item.ItemID
+	})
+
+	total := len(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	result := make([]interface{}, 0, len(matches))
+	for _, m := range matches {
+		entry := m.Item.toMap(s.location)
+		entry["match"] = m.Kind
+		if m.Kind == searchMatchFuzzy {
+			entry["distance"] = m.Rank
+		}
+		result = append(result, entry)
+	}
+	s.inventoryMu.RUnlock()
+
+	return map[string]interface{}{
+		"namespace":     namespace,
+		"query":         query,
+		"fuzzy":         fuzzy,
+		"items":         result,
+		"count":         len(result),
+		"total_matches": total,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearchItems(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+	for _, item := range []struct{ id, name string }{
+		{"apple-001", "Granny Smith Apple"},
+		{"pineapple-002", "Pineapple"},
+		{"banana-042", "Banana"},
+		{"cherry-003", "Cherry Tomatoes"},
+	} {
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": item.id, "item_name": item.name})
+	}
+
+	ids := func(result map[string]interface{}) []string {
+		var out []string
+		for _, raw := range result["items"].([]interface{}) {
+			out = append(out, raw.(map[string]interface{})["item_id"].(string))
+		}
+		return out
+	}
+
+	t.Run("case-insensitive substring on name or id", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "search_items", "query": "APPLE"})
+		got := ids(result)
+		// apple-001 matches at position 0 of its id, pineapple-002 later
+		if len(got) != 2 || got[0] != "apple-001" || got[1] != "pineapple-002" {
+			t.Errorf("expected [apple-001 pineapple-002], got: %v", got)
+		}
+
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "search_items", "query": "042"})
+		if got := ids(result); len(got) != 1 || got[0] != "banana-042" {
+			t.Errorf("expected id match banana-042, got: %v", got)
+		}
+	})
+
+	t.Run("limit caps results", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "search_items", "query": "a", "limit": 1.0})
+		if result["count"] != 1 || result["total_matches"] != 4 {
+			t.Errorf("expected 1 of 4 matches, got: %v of %v", result["count"], result["total_matches"])
+		}
+	})
+
+	t.Run("one-character typo needs fuzzy", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "search_items", "query": "bananna"})
+		if result["count"] != 0 {
+			t.Errorf("expected no exact matches, got: %v", ids(result))
+		}
+
+		result = mustDoCommand(t, svc, map[string]interface{}{"command": "search_items", "query": "bananna", "fuzzy": true})
+		items := result["items"].([]interface{})
+		if len(items) != 1 {
+			t.Fatalf("expected 1 fuzzy match, got: %v", ids(result))
+		}
+		match := items[0].(map[string]interface{})
+		if match["item_id"] != "banana-042" || match["match"] != searchMatchFuzzy || match["distance"] != 1 {
+			t.Errorf("expected fuzzy match banana-042 at distance 1, got: %v", match)
+		}
+	})
+
+	t.Run("substring matches rank above fuzzy", func(t *testing.T) {
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "chard-004", "item_name": "Chard"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "card-005", "item_name": "Card Stock"})
+
+		// "chard" is in chard-004 and one edit from "card"
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "search_items", "query": "chard", "fuzzy": true})
+		items := result["items"].([]interface{})
+		if len(items) != 2 {
+			t.Fatalf("expected 2 matches, got: %v", ids(result))
+		}
+		first, second := items[0].(map[string]interface{}), items[1].(map[string]interface{})
+		if first["item_id"] != "chard-004" || first["match"] != searchMatchSubstring {
+			t.Errorf("expected substring match chard-004 first, got: %v", first)
+		}
+		if second["item_id"] != "card-005" || second["match"] != searchMatchFuzzy {
+			t.Errorf("expected fuzzy match card-005 second, got: %v", second)
+		}
+	})
+
+	t.Run("empty query is rejected", func(t *testing.T) {
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "search_items", "query": " "}); err == nil {
+			t.Error("expected error for empty query")
+		}
+	})
+}
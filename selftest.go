@@ -0,0 +1,125 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// selftestCase is one rendering variant exercised by selftest_qr
+type selftestCase struct {
+	Size          int
+	RecoveryLevel qrcode.RecoveryLevel
+	LevelName     string
+}
+
+// selftestCases covers the default generate_qr output plus larger images and
+// every recovery level, so a regression in any of them shows up
+var selftestCases = []selftestCase{
+	{256, qrcode.Low, "low"},
+	{256, qrcode.Medium, "medium"},
+	{256, qrcode.High, "high"},
+	{256, qrcode.Highest, "highest"},
+	{512, qrcode.Low, "low"},
+	{512, qrcode.Medium, "medium"},
+	{512, qrcode.High, "high"},
+	{512, qrcode.Highest, "highest"},
+}
+
+// runSelftestCase renders the payload with one variant, reads it back through
+// preprocessing, image decoding, and payload decoding, and returns any
+// mismatches against the expected item data
+func (s *inventoryKeeperKeeper) runSelftestCase(tc selftestCase, payload string, want ItemQRData) ([]string, error) {
+	pngBytes, err := qrcode.Encode(payload, tc.RecoveryLevel, tc.Size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	img, err := png.Decode(bytes.NewReader(pngBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered QR code: %w", err)
+	}
+
+	content, _, err := decodeCodeImage(preprocessImage(img, s.config()))
+	if err != nil {
+		return nil, err
+	}
+	if content != payload {
+		return []string{"content differs from the generated payload"}, nil
+	}
+
+	got, err := s.decodeQRPayload(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []string
+	if got.ItemID != want.ItemID {
+		mismatches = append(mismatches, fmt.Sprintf("item_id: expected %q, got %q", want.ItemID, got.ItemID))
+	}
+	if got.ItemName != want.ItemName {
+		mismatches = append(mismatches, fmt.Sprintf("item_name: expected %q, got %q", want.ItemName, got.ItemName))
+	}
+	if secret := s.config().SigningSecret; secret != "" && !verifyPayloadSignature(secret, got) {
+		mismatches = append(mismatches, "signature does not verify")
+	}
+	return mismatches, nil
+}
+
+// handleSelftestQR generates a QR code for an item, decodes it back across
+// several sizes and recovery levels, and reports whether every variant
+// round-trips to the same item fields
+func (s *inventoryKeeperKeeper) handleSelftestQR(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	itemName, ok := cmd["item_name"].(string)
+	if !ok || itemName == "" {
+		return nil, errors.New("item_name is required and must be a string")
+	}
+	if err := s.fieldRules.validateItemID(itemID); err != nil {
+		return nil, err
+	}
+	if err := s.fieldRules.validateItemName(itemName); err != nil {
+		return nil, err
+	}
+
+	want := ItemQRData{ItemID: itemID, ItemName: itemName}
+	payload, err := s.encodeQRPayload(want)
+	if err != nil {
+		return nil, err
+	}
+
+	passed := true
+	cases := make([]interface{}, 0, len(selftestCases))
+	for _, tc := range selftestCases {
+		result := map[string]interface{}{
+			"size":           tc.Size,
+			"recovery_level": tc.LevelName,
+		}
+
+		mismatches, err := s.runSelftestCase(tc, payload, want)
+		if err != nil {
+			result["error"] = err.Error()
+		} else if len(mismatches) > 0 {
+			result["mismatches"] = toInterfaceSlice(mismatches)
+		}
+		casePassed := err == nil && len(mismatches) == 0
+		result["passed"] = casePassed
+		passed = passed && casePassed
+		cases = append(cases, result)
+	}
+
+	if !passed {
+		s.logger.Warnf("QR self-test failed for item: %s", itemID)
+	}
+	return map[string]interface{}{
+		"passed":  passed,
+		"item_id": itemID,
+		"cases":   cases,
+	}, nil
+}
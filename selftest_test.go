@@ -0,0 +1,55 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSelftestQR(t *testing.T) {
+	t.Run("passes on default settings", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "selftest_qr",
+			"item_id":   "item-001",
+			"item_name": "Apple",
+		})
+
+		cases := result["cases"].([]interface{})
+		if len(cases) != len(selftestCases) {
+			t.Fatalf("expected %d cases, got: %d", len(selftestCases), len(cases))
+		}
+		if result["passed"] != true {
+			t.Fatalf("expected self-test to pass, got: %v", cases)
+		}
+	})
+
+	t.Run("passes with signing and preprocessing", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{
+			SigningSecret: "shelf-secret",
+			RotateDegrees: 90,
+			Grayscale:     true,
+		})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "selftest_qr",
+			"item_id":   "item-002",
+			"item_name": "Banana",
+		})
+		if result["passed"] != true {
+			t.Fatalf("expected self-test to pass, got: %v", result["cases"])
+		}
+	})
+
+	t.Run("requires item fields", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "selftest_qr",
+			"item_id": "item-001",
+		})
+		if err == nil {
+			t.Fatal("expected error when item_name is missing")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package inventorykeeper
+
+import "context"
+
+// handleScanShelf captures a frame and returns the items whose labels are in
+// view, each with its bounding box and when the monitor first and last saw
+// it. Unlike scan_qr it skips codes that don't decode to an item and reports
+// whether each item is in inventory. Nothing is recorded in the present-set.
+func (s *inventoryKeeperKeeper) handleScanShelf(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	sourceName, err := optionalStringArg(cmd, "source_name")
+	if err != nil {
+		return nil, err
+	}
+
+	detections, source, err := s.scanFrame(ctx, sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	scannedAt := s.now()
+	items := make([]interface{}, 0, len(detections))
+	var keys []itemKey
+	unrecognized := 0
+	for _, detection := range detections {
+		data, err := s.decodeQRPayload(detection.Label())
+		s.recordDecode(detection.Label(), sourceName, data, err, scannedAt)
+		if err != nil || data.ItemID == "" {
+			unrecognized++
+			continue
+		}
+
+		key := keyFor(data.Namespace, data.ItemID)
+		item := map[string]interface{}{
+			"namespace":  key.Namespace,
+			"item_id":    data.ItemID,
+			"item_name":  data.ItemName,
+			"confidence": detection.Score(),
+			"symbology":  codeSymbology(detection.Label()),
+		}
+		if box := detection.BoundingBox(); box != nil {
+			item["bounding_box"] = map[string]interface{}{
+				"x_min": box.Min.X,
+				"y_min": box.Min.Y,
+				"x_max": box.Max.X,
+				"y_max": box.Max.Y,
+			}
+		}
+		items = append(items, item)
+		keys = append(keys, key)
+	}
+
+	// Sightings come from the monitor; an item it hasn't tracked yet is
+	// first and last seen in this scan
+	s.monitorMu.Lock()
+	for i, key := range keys {
+		item := items[i].(map[string]interface{})
+		firstSeen, lastSeen := scannedAt, scannedAt
+		if entry, ok := s.presence[key]; ok {
+			firstSeen, lastSeen = entry.FirstSeen, entry.LastSeen
+			if scannedAt.After(lastSeen) {
+				lastSeen = scannedAt
+			}
+		}
+		item["first_seen"] = formatTimestampIn(firstSeen, s.location)
+		item["last_seen"] = formatTimestampIn(lastSeen, s.location)
+	}
+	s.monitorMu.Unlock()
+
+	s.inventoryMu.RLock()
+	for i, key := range keys {
+		_, known := s.inventory[key]
+		items[i].(map[string]interface{})["in_inventory"] = known
+	}
+	s.inventoryMu.RUnlock()
+
+	result := map[string]interface{}{
+		"items":        items,
+		"count":        len(items),
+		"unrecognized": unrecognized,
+		"source":       source,
+		"scanned_at":   formatTimestampIn(scannedAt, s.location),
+	}
+	if sourceName != "" {
+		result["source_name"] = sourceName
+	}
+	return result, nil
+}
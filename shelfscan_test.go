@@ -0,0 +1,69 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestScanShelf(t *testing.T) {
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+	pear, _ := json.Marshal(ItemQRData{ItemID: "pear-002", ItemName: "Bosc Pear"})
+
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, *fakeClock) {
+		svc, mockVision, clock := newTestKeeperWithClock(t, nil, start)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{
+				testDetection(0, string(apple)),
+				testDetection(1, "not an item"),
+				testDetection(2, string(pear)),
+			}, nil
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+		return svc, clock
+	}
+
+	t.Run("returns decoded items with bounding boxes", func(t *testing.T) {
+		svc, _ := setup(t)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_shelf"})
+		if result["count"] != 2 || result["unrecognized"] != 1 {
+			t.Fatalf("expected 2 items and 1 unrecognized code, got: %v", result)
+		}
+		if result["scanned_at"] != "2026-01-01T08:00:00Z" {
+			t.Errorf("expected scanned_at from the clock, got: %v", result["scanned_at"])
+		}
+
+		items := result["items"].([]interface{})
+		first := items[0].(map[string]interface{})
+		if first["item_id"] != "apple-001" || first["item_name"] != "Honeycrisp Apple" || first["in_inventory"] != true {
+			t.Errorf("unexpected first item: %v", first)
+		}
+		box := first["bounding_box"].(map[string]interface{})
+		if box["x_min"] != 10 || box["y_min"] != 10 || box["x_max"] != 100 || box["y_max"] != 100 {
+			t.Errorf("unexpected bounding box: %v", box)
+		}
+		if second := items[1].(map[string]interface{}); second["item_id"] != "pear-002" || second["in_inventory"] != false {
+			t.Errorf("expected pear-002 not in inventory, got: %v", second)
+		}
+	})
+
+	t.Run("first_seen comes from the monitor", func(t *testing.T) {
+		svc, clock := setup(t)
+		svc.scanAndCompare(context.Background())
+		clock.Advance(time.Minute)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_shelf"})
+		first := result["items"].([]interface{})[0].(map[string]interface{})
+		if first["first_seen"] != "2026-01-01T08:00:00Z" {
+			t.Errorf("expected first_seen from the earlier scan, got: %v", first["first_seen"])
+		}
+		if first["last_seen"] != "2026-01-01T08:01:00Z" {
+			t.Errorf("expected last_seen at this scan, got: %v", first["last_seen"])
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package inventorykeeper
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// belowMinQuantity reports whether the item has a min_quantity and fewer
+// units than that were seen on the shelf
+func (item *InventoryItem) belowMinQuantity(shelfCount int) bool {
+	return item.MinQuantity > 0 && shelfCount < item.MinQuantity
+}
+
+// shelfReorder is how many units to order for an item seen below its
+// min_quantity: its reorder_quantity, or else enough to refill the shelf
+func (item *InventoryItem) shelfReorder(shelfCount int) int {
+	if item.ReorderQuantity > 0 {
+		return item.ReorderQuantity
+	}
+	return item.MinQuantity - shelfCount
+}
+
+// shelfCountsLocked returns the units seen for each item in the present-set.
+// Items not present count as 0. Caller must hold monitorMu.
+func (s *inventoryKeeperKeeper) shelfCountsLocked() map[itemKey]int {
+	counts := make(map[itemKey]int, len(s.presence))
+	for key, entry := range s.presence {
+		if entry.Present {
+			counts[key] = entry.Units
+		}
+	}
+	return counts
+}
+
+// checkShelfStock raises a low_stock alert for each item whose shelf count
+// has just dropped below its min_quantity. An item alerts again only after
+// its count has recovered. Items still being confirmed present are left
+// until they settle, and nothing is checked during startup grace.
+func (s *inventoryKeeperKeeper) checkShelfStock(now time.Time) {
+	if s.inStartupGrace(now) {
+		return
+	}
+	s.monitorMu.Lock()
+	counts := s.shelfCountsLocked()
+	settling := make(map[itemKey]bool)
+	for key, entry := range s.presence {
+		if !entry.Present && entry.Hits > 0 {
+			settling[key] = true
+		}
+	}
+	s.monitorMu.Unlock()
+
+	low := make(map[itemKey]*InventoryItem)
+	s.inventoryMu.RLock()
+	for key, item := range s.inventory {
+		if !settling[key] && item.belowMinQuantity(counts[key]) {
+			low[key] = item.clone()
+		}
+	}
+	s.inventoryMu.RUnlock()
+
+	var newlyLow []*InventoryItem
+	s.monitorMu.Lock()
+	for key := range s.shelfLow {
+		if low[key] == nil && !settling[key] {
+			delete(s.shelfLow, key)
+		}
+	}
+	for key, item := range low {
+		if !s.shelfLow[key] {
+			s.shelfLow[key] = true
+			newlyLow = append(newlyLow, item)
+		}
+	}
+	s.monitorMu.Unlock()
+
+	sort.Slice(newlyLow, func(i, j int) bool {
+		if newlyLow[i].Namespace != newlyLow[j].Namespace {
+			return newlyLow[i].Namespace < newlyLow[j].Namespace
+		}
+		return newlyLow[i].ItemID < newlyLow[j].ItemID
+	})
+	for _, item := range newlyLow {
+		count := counts[item.key()]
+		details := item.reorderMap()
+		details["min_quantity"] = item.MinQuantity
+		details["shelf_count"] = count
+		details["recommended_quantity"] = item.shelfReorder(count)
+		s.raiseAlert("low_stock", severityWarning, item.ItemID,
+			fmt.Sprintf("Item %s (%s) is down to %d on the shelf, below %d; reorder %d", item.ItemID, item.ItemName, count, item.MinQuantity, item.shelfReorder(count)),
+			details)
+	}
+}
@@ -0,0 +1,72 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestShelfMinQuantity(t *testing.T) {
+	ctx := context.Background()
+	oneScan := 1
+	svc, mockVision := newTestKeeper(t, &Config{PresenceDebounceScans: &oneScan})
+
+	soup, _ := json.Marshal(ItemQRData{ItemID: "soup-003", ItemName: "Tomato Soup"})
+	var mu sync.Mutex
+	labels := 2
+	mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		detections := make([]objectdetection.Detection, 0, labels)
+		for i := 0; i < labels; i++ {
+			detections = append(detections, testDetection(i, string(soup)))
+		}
+		return detections, nil
+	}
+	setLabels := func(n int) {
+		mu.Lock()
+		defer mu.Unlock()
+		labels = n
+	}
+	lowStockAlerts := func() int {
+		count := 0
+		for _, alert := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{}) {
+			if alert.(map[string]interface{})["type"] == "low_stock" {
+				count++
+			}
+		}
+		return count
+	}
+
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "soup-003", "item_name": "Tomato Soup", "quantity": 10, "min_quantity": 3})
+
+	svc.scanAndCompare(ctx)
+	svc.scanAndCompare(ctx)
+	if got := lowStockAlerts(); got != 1 {
+		t.Fatalf("expected one low_stock alert for 2 of 3 on the shelf, got: %d", got)
+	}
+
+	result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_reorder_list"})
+	if result["count"] != 1 {
+		t.Fatalf("expected soup on the reorder list, got: %v", result)
+	}
+	entry := result["items"].([]interface{})[0].(map[string]interface{})
+	if entry["shelf_count"] != 2 || entry["min_quantity"] != 3 || entry["recommended_quantity"] != 1 {
+		t.Errorf("unexpected reorder entry: %v", entry)
+	}
+
+	// Refilled, then low again
+	setLabels(3)
+	svc.scanAndCompare(ctx)
+	if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_reorder_list"})["count"]; count != 0 {
+		t.Errorf("expected an empty reorder list once refilled, got: %v", count)
+	}
+	setLabels(1)
+	svc.scanAndCompare(ctx)
+	if got := lowStockAlerts(); got != 2 {
+		t.Errorf("expected a second low_stock alert after recovering, got: %d", got)
+	}
+}
@@ -0,0 +1,232 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// shrinkageEvent is one theft alert counted by shrinkage_report
+type shrinkageEvent struct {
+	AlertID      int64
+	Namespace    string
+	ItemID       string
+	At           time.Time
+	Acknowledged bool
+	UnitValue    float64 // Value of the item when the alert was raised (0 if unknown)
+}
+
+// shrinkageItem totals the theft events for one item
+type shrinkageItem struct {
+	Namespace    string
+	ItemID       string
+	ItemName     string
+	Events       int
+	Acknowledged int
+	UnitValue    float64
+	Loss         float64
+}
+
+// shrinkageDay totals the theft events on one calendar day
+type shrinkageDay struct {
+	Date   string
+	Events int
+	Items  map[string]bool
+	Loss   float64
+}
+
+// theftEventsBetween gathers theft alerts raised in [since, until) in a
+// namespace: those still in the alert log plus those cleared into history
+func (s *inventoryKeeperKeeper) theftEventsBetween(namespace string, since, until time.Time) []shrinkageEvent {
+	inRange := func(t time.Time) bool {
+		return !t.Before(since) && t.Before(until)
+	}
+	seen := map[int64]bool{}
+	var events []shrinkageEvent
+
+	s.alertsMu.Lock()
+	for _, alert := range s.alerts {
+		if alert.Type != "theft" || !inRange(alert.CreatedAt) {
+			continue
+		}
+		event := theftEventFromDetails(alert.ID, alert.ItemID, alert.CreatedAt, alert.Details)
+		event.Acknowledged = alert.acknowledged()
+		if event.Namespace == namespace {
+			seen[alert.ID] = true
+			events = append(events, event)
+		}
+	}
+	s.alertsMu.Unlock()
+
+	s.inventoryMu.RLock()
+	for _, h := range s.history {
+		if h.Type != "alert_cleared" || h.Details["alert_type"] != "theft" {
+			continue
+		}
+		id, _ := toFloat(h.Details["alert_id"])
+		createdRaw, _ := h.Details["created_at"].(string)
+		created, err := time.Parse(time.RFC3339Nano, createdRaw)
+		if err != nil || seen[int64(id)] || !inRange(created) {
+			continue
+		}
+		alertDetails, _ := h.Details["details"].(map[string]interface{})
+		event := theftEventFromDetails(int64(id), h.ItemID, created, alertDetails)
+		event.Acknowledged = true
+		if event.Namespace == namespace {
+			seen[int64(id)] = true
+			events = append(events, event)
+		}
+	}
+	s.inventoryMu.RUnlock()
+
+	sort.Slice(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events
+}
+
+// theftEventFromDetails builds a shrinkage event from a theft alert's fields
+func theftEventFromDetails(id int64, itemID string, at time.Time, details map[string]interface{}) shrinkageEvent {
+	namespace, _ := details["namespace"].(string)
+	unitValue, _ := toFloat(details["unit_value"])
+	return shrinkageEvent{
+		AlertID:   id,
+		Namespace: normalizeNamespace(namespace),
+		ItemID:    itemID,
+		At:        at,
+		UnitValue: unitValue,
+	}
+}
+
+// handleShrinkageReport summarizes theft alerts over a time range: how many,
+// how many were acknowledged (confirmed by staff), which items, and the
+// estimated loss at one unit per theft for items with a value. Totals are
+// grouped by item and by calendar day in the configured timezone.
+func (s *inventoryKeeperKeeper) handleShrinkageReport(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	since, _, err := timeArg(cmd, "since")
+	if err != nil {
+		return nil, err
+	}
+	until, found, err := timeArg(cmd, "until")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		until = s.now()
+	}
+	if !until.After(since) {
+		return nil, fmt.Errorf("until must be after since, got since %s and until %s", formatTimestampIn(since, s.location), formatTimestampIn(until, s.location))
+	}
+
+	events := s.theftEventsBetween(namespace, since, until)
+
+	// Alerts raised before values were recorded fall back to the item's
+	// current value, when it's still in inventory
+	s.inventoryMu.RLock()
+	names := map[string]string{}
+	for i, event := range events {
+		item, exists := s.inventory[keyFor(namespace, event.ItemID)]
+		if !exists {
+			continue
+		}
+		names[event.ItemID] = item.ItemName
+		if event.UnitValue == 0 {
+			events[i].UnitValue = item.Value
+		}
+	}
+	s.inventoryMu.RUnlock()
+
+	byItem := map[string]*shrinkageItem{}
+	byDay := map[string]*shrinkageDay{}
+	var totalLoss float64
+	acknowledged, unvalued := 0, 0
+	for _, event := range events {
+		item := byItem[event.ItemID]
+		if item == nil {
+			item = &shrinkageItem{Namespace: namespace, ItemID: event.ItemID, ItemName: names[event.ItemID]}
+			byItem[event.ItemID] = item
+		}
+		date := event.At.In(s.location).Format(time.DateOnly)
+		day := byDay[date]
+		if day == nil {
+			day = &shrinkageDay{Date: date, Items: map[string]bool{}}
+			byDay[date] = day
+		}
+
+		item.Events++
+		day.Events++
+		day.Items[event.ItemID] = true
+		if event.Acknowledged {
+			item.Acknowledged++
+			acknowledged++
+		}
+		if event.UnitValue > 0 {
+			item.UnitValue = event.UnitValue
+			item.Loss += event.UnitValue
+			day.Loss += event.UnitValue
+			totalLoss += event.UnitValue
+		} else {
+			unvalued++
+		}
+	}
+
+	items := make([]*shrinkageItem, 0, len(byItem))
+	for _, item := range byItem {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Events != items[j].Events {
+			return items[i].Events > items[j].Events
+		}
+		return items[i].ItemID < items[j].ItemID
+	})
+	itemMaps := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		m := map[string]interface{}{
+			"namespace":    item.Namespace,
+			"item_id":      item.ItemID,
+			"events":       item.Events,
+			"acknowledged": item.Acknowledged,
+		}
+		if item.ItemName != "" {
+			m["item_name"] = item.ItemName
+		}
+		if item.UnitValue > 0 {
+			m["unit_value"] = item.UnitValue
+			m["estimated_loss"] = item.Loss
+		}
+		itemMaps = append(itemMaps, m)
+	}
+
+	dates := make([]string, 0, len(byDay))
+	for date := range byDay {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	dayMaps := make([]interface{}, 0, len(dates))
+	for _, date := range dates {
+		day := byDay[date]
+		dayMaps = append(dayMaps, map[string]interface{}{
+			"date":           day.Date,
+			"events":         day.Events,
+			"items_affected": len(day.Items),
+			"estimated_loss": day.Loss,
+		})
+	}
+
+	return map[string]interface{}{
+		"namespace":            namespace,
+		"since":                formatTimestampIn(since, s.location),
+		"until":                formatTimestampIn(until, s.location),
+		"theft_events":         len(events),
+		"acknowledged":         acknowledged,
+		"items_affected":       len(byItem),
+		"estimated_loss":       totalLoss,
+		"events_without_value": unvalued,
+		"by_item":              itemMaps,
+		"by_day":               dayMaps,
+	}, nil
+}
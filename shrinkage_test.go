@@ -0,0 +1,110 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShrinkageReport(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// setup stocks three items and raises theft alerts across two days:
+	//   Mar 1: apple x2, banana x1    Mar 2: apple x1, cheese x1
+	// plus one apple theft on Feb 28, before the report range
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, *fakeClock) {
+		svc, _, clock := newTestKeeperWithClock(t, nil, start.Add(-24*time.Hour))
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple", "value": 1.5})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "banana-042", "item_name": "Banana"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "cheese-777", "item_name": "Cheddar", "value": 8})
+
+		steal := func(itemID string) {
+			svc.feedTheftDetector(detectorEvent{Type: detectorEventItemRemoved, Time: clock.Now(), ItemIDs: []string{itemID}})
+			clock.Advance(time.Minute)
+		}
+		steal("apple-001")
+		clock.Advance(24 * time.Hour)
+		steal("apple-001")
+		steal("apple-001")
+		steal("banana-042")
+		clock.Advance(24 * time.Hour)
+		steal("apple-001")
+		steal("cheese-777")
+		return svc, clock
+	}
+	report := func(t *testing.T, svc *inventoryKeeperKeeper) map[string]interface{} {
+		return mustDoCommand(t, svc, map[string]interface{}{
+			"command": "shrinkage_report",
+			"since":   formatTimestamp(start),
+			"until":   formatTimestamp(start.Add(48 * time.Hour)),
+		})
+	}
+
+	t.Run("totals grouped by item and by day", func(t *testing.T) {
+		svc, _ := setup(t)
+		result := report(t, svc)
+
+		if result["theft_events"] != 5 || result["items_affected"] != 3 {
+			t.Fatalf("expected 5 events over 3 items, got: %v", result)
+		}
+		if result["estimated_loss"] != 12.5 || result["events_without_value"] != 1 {
+			t.Errorf("expected 12.5 lost with one unvalued event, got: %v", result)
+		}
+
+		byItem := result["by_item"].([]interface{})
+		if len(byItem) != 3 {
+			t.Fatalf("expected 3 items, got: %v", byItem)
+		}
+		apple := byItem[0].(map[string]interface{})
+		if apple["item_id"] != "apple-001" || apple["events"] != 3 || apple["estimated_loss"] != 4.5 || apple["item_name"] != "Apple" {
+			t.Errorf("expected apple first with 3 events and 4.5 lost, got: %v", apple)
+		}
+		for _, raw := range byItem {
+			if item := raw.(map[string]interface{}); item["item_id"] == "banana-042" {
+				if _, ok := item["estimated_loss"]; ok {
+					t.Errorf("expected no loss estimate for unvalued banana, got: %v", item)
+				}
+			}
+		}
+
+		byDay := result["by_day"].([]interface{})
+		if len(byDay) != 2 {
+			t.Fatalf("expected 2 days, got: %v", byDay)
+		}
+		first, second := byDay[0].(map[string]interface{}), byDay[1].(map[string]interface{})
+		if first["date"] != "2026-03-01" || first["events"] != 3 || first["items_affected"] != 2 || first["estimated_loss"] != 3.0 {
+			t.Errorf("unexpected first day: %v", first)
+		}
+		if second["date"] != "2026-03-02" || second["events"] != 2 || second["items_affected"] != 2 || second["estimated_loss"] != 9.5 {
+			t.Errorf("unexpected second day: %v", second)
+		}
+	})
+
+	t.Run("cleared alerts are still counted", func(t *testing.T) {
+		svc, _ := setup(t)
+		for _, raw := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{}) {
+			alert := raw.(map[string]interface{})
+			if alert["item_id"] == "cheese-777" {
+				mustDoCommand(t, svc, map[string]interface{}{"command": "acknowledge_alert", "id": float64(alert["id"].(int64))})
+			}
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "clear_acknowledged"})
+
+		result := report(t, svc)
+		if result["theft_events"] != 5 || result["acknowledged"] != 1 || result["estimated_loss"] != 12.5 {
+			t.Errorf("expected the cleared cheese theft still counted, got: %v", result)
+		}
+	})
+
+	t.Run("until defaults to now and must follow since", func(t *testing.T) {
+		svc, _ := setup(t)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "shrinkage_report"})
+		if result["theft_events"] != 6 {
+			t.Errorf("expected all 6 events without a range, got: %v", result["theft_events"])
+		}
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "shrinkage_report", "since": formatTimestamp(start), "until": formatTimestamp(start)}); err == nil {
+			t.Error("expected error for an empty range")
+		}
+	})
+}
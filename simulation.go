@@ -0,0 +1,247 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register JPEG decoding for supplied images
+	"time"
+)
+
+// clone returns a copy of the detector's state
+func (d *theftDetector) clone() *theftDetector {
+	c := newTheftDetector(d.window, d.grace, d.delay)
+	for key, auth := range d.authorized {
+		c.authorized[key] = auth
+	}
+	for key, removedAt := range d.pending {
+		c.pending[key] = removedAt
+	}
+	for namespace, at := range d.lastCheckIn {
+		c.lastCheckIn[namespace] = at
+	}
+	return c
+}
+
+// newSimulationKeeper returns a keeper sharing this one's config, codec, and
+// vision services, with copies of its monitoring and theft state. It has no
+// notifiers or event log, so alerts it raises are only recorded locally.
+func (s *inventoryKeeperKeeper) newSimulationKeeper() *inventoryKeeperKeeper {
+	sim := &inventoryKeeperKeeper{
+		name:              s.name,
+		logger:            s.logger.Sublogger("simulation"),
+		cfg:               s.config(),
+		qrVisionService:   s.qrVision(),
+		faceVisionService: s.faceVisionService,
+		cancelCtx:         s.cancelCtx,
+		visionGate:        s.visionGate,
+		codec:             s.codec,
+		fieldRules:        s.fieldRules,
+		encryptionKey:     s.encryptionKey,
+		location:          s.location,
+		now:               s.now,
+		visibleCodes:      make(map[string]*DetectedQRCode),
+		presence:          make(map[itemKey]*PresentItem),
+		presenceLog:       make(map[itemKey][]presenceTransition),
+		lastSeen:          make(map[itemKey]time.Time),
+		checkouts:         make(map[itemKey]*itemCheckout),
+		shelfLow:          make(map[itemKey]bool),
+		duplicates:        make(map[itemKey]bool),
+	}
+
+	s.monitorMu.Lock()
+	for content, code := range s.visibleCodes {
+		c := *code
+		sim.visibleCodes[content] = &c
+	}
+	for key, entry := range s.presence {
+		e := *entry
+		sim.presence[key] = &e
+	}
+	for key := range s.duplicates {
+		sim.duplicates[key] = true
+	}
+	for key, seen := range s.lastSeen {
+		sim.lastSeen[key] = seen
+	}
+	for key, entry := range s.checkouts {
+		e := *entry
+		sim.checkouts[key] = &e
+	}
+	for key := range s.shelfLow {
+		sim.shelfLow[key] = true
+	}
+	s.monitorMu.Unlock()
+
+	s.theftMu.Lock()
+	sim.theft = s.theft.clone()
+	s.theftMu.Unlock()
+
+	return sim
+}
+
+// presentKeysLocked returns the confirmed-present items, sorted. Caller must
+// hold monitorMu.
+func (s *inventoryKeeperKeeper) presentKeysLocked() []itemKey {
+	keys := make([]itemKey, 0, len(s.presence))
+	for key, entry := range s.presence {
+		if entry.Present {
+			keys = append(keys, key)
+		}
+	}
+	sortItemKeys(keys)
+	return keys
+}
+
+// itemKeysToInterfaceSlice converts keys for a DoCommand response
+func itemKeysToInterfaceSlice(keys []itemKey) []interface{} {
+	result := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, map[string]interface{}{
+			"namespace": key.Namespace,
+			"item_id":   key.ItemID,
+		})
+	}
+	return result
+}
+
+// handleSimulateScan runs supplied images (base64 PNG or JPEG) through the
+// detection pipeline as consecutive scans against a copy of the keeper's
+// state. Each image is applied scans_per_image times (default: the presence
+// debounce, so every image settles). Live state is untouched and no
+// notifications are sent; alerts that would have fired are returned.
+func (s *inventoryKeeperKeeper) handleSimulateScan(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	encoded, _, err := stringSliceArg(cmd, "images")
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) == 0 {
+		return nil, errors.New("images is required and must be a non-empty list of base64 images")
+	}
+
+	cfg := s.config()
+	if err := cfg.checkBatchArg("images", len(encoded)); err != nil {
+		return nil, err
+	}
+	for i, e := range encoded {
+		if err := cfg.checkImageArg(fmt.Sprintf("images[%d]", i), e); err != nil {
+			return nil, err
+		}
+	}
+	scansPerImage, hasScans, err := intArg(cmd, "scans_per_image")
+	if err != nil {
+		return nil, err
+	}
+	if !hasScans {
+		scansPerImage = cfg.presenceDebounceScans()
+	}
+	if scansPerImage < 1 {
+		return nil, fmt.Errorf("scans_per_image must be at least 1, got: %d", scansPerImage)
+	}
+
+	// Decode everything up front so a bad image fails before any work
+	images := make([]image.Image, 0, len(encoded))
+	for i, e := range encoded {
+		raw, err := base64.StdEncoding.DecodeString(e)
+		if err != nil {
+			return nil, fmt.Errorf("images[%d] is not valid base64: %w", i, err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("images[%d] is not a PNG or JPEG image: %w", i, err)
+		}
+		images = append(images, img)
+	}
+
+	sim := s.newSimulationKeeper()
+	sim.monitorMu.Lock()
+	before := sim.presentKeysLocked()
+	sim.monitorMu.Unlock()
+
+	// Simulated scans are spaced one scan interval apart so grace periods
+	// behave as they would live
+	step := cfg.scanInterval()
+	if step <= 0 {
+		step = time.Second
+	}
+	now := s.now()
+
+	scans := make([]interface{}, 0, len(images))
+	for i, img := range images {
+		detections, err := s.detectInFrame(ctx, preprocessImage(img, cfg))
+		if err != nil {
+			return nil, fmt.Errorf("images[%d]: failed to detect QR codes: %w", i, err)
+		}
+
+		codes := make([]interface{}, 0, len(detections))
+		for _, detection := range detections {
+			code := map[string]interface{}{
+				"content":    detection.Label(),
+				"confidence": detection.Score(),
+			}
+			if data, err := s.decodeQRPayload(detection.Label()); err == nil && data.ItemID != "" {
+				code["namespace"] = normalizeNamespace(data.Namespace)
+				code["item_id"] = data.ItemID
+				code["item_name"] = data.ItemName
+			}
+			codes = append(codes, code)
+		}
+
+		var appeared, removed []itemKey
+		for n := 0; n < scansPerImage; n++ {
+			changes := sim.processDetections(detections, now)
+			appeared = append(appeared, changes.Appeared...)
+			removed = append(removed, changes.Removed...)
+			now = now.Add(step)
+		}
+
+		scans = append(scans, map[string]interface{}{
+			"index":              i,
+			"codes":              codes,
+			"count":              len(codes),
+			"duplicate_item_ids": duplicatesToInterfaceSlice(sim.findDuplicateItemIDs(detections)),
+			"appeared":           itemKeysToInterfaceSlice(appeared),
+			"removed":            itemKeysToInterfaceSlice(removed),
+		})
+	}
+
+	sim.monitorMu.Lock()
+	after := sim.presentKeysLocked()
+	sim.monitorMu.Unlock()
+
+	// Net change to the present-set across the whole simulation
+	wasPresent := make(map[itemKey]bool, len(before))
+	for _, key := range before {
+		wasPresent[key] = true
+	}
+	isPresent := make(map[itemKey]bool, len(after))
+	var appeared, removed []itemKey
+	for _, key := range after {
+		isPresent[key] = true
+		if !wasPresent[key] {
+			appeared = append(appeared, key)
+		}
+	}
+	for _, key := range before {
+		if !isPresent[key] {
+			removed = append(removed, key)
+		}
+	}
+
+	alerts := make([]interface{}, 0, len(sim.alerts))
+	for _, alert := range sim.alerts {
+		alerts = append(alerts, alert.toMap(s.location))
+	}
+
+	return map[string]interface{}{
+		"simulated":     true,
+		"scans":         scans,
+		"present_items": itemKeysToInterfaceSlice(after),
+		"appeared":      itemKeysToInterfaceSlice(appeared),
+		"removed":       itemKeysToInterfaceSlice(removed),
+		"alerts":        alerts,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestSimulateScan(t *testing.T) {
+	apple := `{"item_id":"apple-001","item_name":"Apple"}`
+	banana := `{"item_id":"banana-001","item_name":"Banana"}`
+
+	// encodeFrame returns a base64 PNG; the mock vision service decides what it contains
+	encodeFrame := func(t *testing.T) string {
+		t.Helper()
+		img := image.NewGray(image.Rect(0, 0, 64, 48))
+		for i := range img.Pix {
+			img.Pix[i] = 0xff
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatalf("failed to encode frame: %v", err)
+		}
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+
+	t.Run("two QRs build the present-set without touching live state", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, apple), testDetection(1, banana)}, nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "simulate_scan",
+			"images":  []interface{}{encodeFrame(t)},
+		})
+
+		scan := result["scans"].([]interface{})[0].(map[string]interface{})
+		if scan["count"] != 2 {
+			t.Errorf("expected 2 decoded codes, got: %v", scan["codes"])
+		}
+		present := result["present_items"].([]interface{})
+		if len(present) != 2 {
+			t.Fatalf("expected 2 present items, got: %v", present)
+		}
+		if present[0].(map[string]interface{})["item_id"] != "apple-001" || present[1].(map[string]interface{})["item_id"] != "banana-001" {
+			t.Errorf("expected apple-001 and banana-001 present, got: %v", present)
+		}
+		if appeared := result["appeared"].([]interface{}); len(appeared) != 2 {
+			t.Errorf("expected 2 appeared items, got: %v", appeared)
+		}
+		if alerts := result["alerts"].([]interface{}); len(alerts) != 0 {
+			t.Errorf("expected no alerts, got: %v", alerts)
+		}
+
+		if len(svc.presence) != 0 || !svc.lastScanAt.IsZero() {
+			t.Errorf("expected live present-set untouched, got: %v", svc.presence)
+		}
+	})
+
+	t.Run("removal without check-in reports a theft alert only in the result", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		calls := 0
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			calls++
+			if calls == 1 {
+				return []objectdetection.Detection{testDetection(0, apple), testDetection(1, banana)}, nil
+			}
+			return []objectdetection.Detection{testDetection(0, apple)}, nil
+		}
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command": "simulate_scan",
+			"images":  []interface{}{encodeFrame(t), encodeFrame(t)},
+		})
+
+		removed := result["removed"].([]interface{})
+		if len(removed) != 0 {
+			t.Errorf("expected no net removal relative to an empty live set, got: %v", removed)
+		}
+		second := result["scans"].([]interface{})[1].(map[string]interface{})
+		if r := second["removed"].([]interface{}); len(r) != 1 || r[0].(map[string]interface{})["item_id"] != "banana-001" {
+			t.Errorf("expected banana-001 removed by the second image, got: %v", r)
+		}
+		alerts := result["alerts"].([]interface{})
+		if len(alerts) != 1 || alerts[0].(map[string]interface{})["type"] != "theft" {
+			t.Fatalf("expected one simulated theft alert, got: %v", alerts)
+		}
+		if len(svc.alerts) != 0 {
+			t.Errorf("expected no live alerts, got: %v", svc.alerts)
+		}
+	})
+
+	t.Run("invalid image returns error", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command": "simulate_scan",
+			"images":  []interface{}{"not-an-image"},
+		}); err == nil {
+			t.Error("expected error for invalid image")
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"regexp"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+)
+
+// skuPattern matches content read as a plain SKU when it isn't an item
+// payload: a short run of letters, digits, and common separators
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,63}$`)
+
+// Barcode rendering defaults and limits, in pixels and modules
+const (
+	defaultBarcodeModuleWidth = 2
+	maxBarcodeModuleWidth     = 10
+	defaultBarcodeHeight      = 80
+	minBarcodeHeight          = 20
+	maxBarcodeHeight          = 1000
+	defaultBarcodeBorder      = 10 // Quiet zone each side; EAN and UPC need about 9 modules
+	maxBarcodeBorder          = 100
+	maxBarcodeValueLength     = 80
+)
+
+// validateSKUMap checks sku_map entries are non-empty on both sides
+func validateSKUMap(skuMap map[string]string) error {
+	for sku, itemID := range skuMap {
+		if strings.TrimSpace(sku) == "" {
+			return errors.New("sku_map keys must be non-empty")
+		}
+		if strings.TrimSpace(itemID) == "" {
+			return fmt.Errorf("sku_map entry %q must map to an item_id", sku)
+		}
+	}
+	return nil
+}
+
+// lookupSKU maps a barcode or plain-text SKU to an item: through sku_map,
+// then an inventory item with that item_id in the default namespace, then
+// one whose supplier_sku matches (the first by namespace and item_id if
+// several do). ok is false when nothing matches.
+func (s *inventoryKeeperKeeper) lookupSKU(sku string) (ItemQRData, bool) {
+	if itemID, mapped := s.config().SKUMap[sku]; mapped {
+		data := ItemQRData{ItemID: itemID}
+		s.inventoryMu.RLock()
+		if item, exists := s.inventory[keyFor(defaultNamespace, itemID)]; exists {
+			data.ItemName = item.ItemName
+		}
+		s.inventoryMu.RUnlock()
+		return data, true
+	}
+
+	s.inventoryMu.RLock()
+	defer s.inventoryMu.RUnlock()
+	if item, exists := s.inventory[keyFor(defaultNamespace, sku)]; exists {
+		return ItemQRData{ItemID: item.ItemID, ItemName: item.ItemName}, true
+	}
+	var matches []itemKey
+	for key, item := range s.inventory {
+		if item.SupplierSKU == sku {
+			matches = append(matches, key)
+		}
+	}
+	if len(matches) == 0 {
+		return ItemQRData{}, false
+	}
+	sortItemKeys(matches)
+	item := s.inventory[matches[0]]
+	data := ItemQRData{ItemID: item.ItemID, ItemName: item.ItemName}
+	if item.Namespace != defaultNamespace {
+		data.Namespace = item.Namespace
+	}
+	return data, true
+}
+
+// resolveSKU maps a scanned barcode value to an item, falling back to the
+// value itself as the item ID so unrecorded barcodes are still tracked
+func (s *inventoryKeeperKeeper) resolveSKU(sku string) ItemQRData {
+	if data, ok := s.lookupSKU(sku); ok {
+		return data
+	}
+	return ItemQRData{ItemID: sku}
+}
+
+// plainSKUPayload reads content that failed to decode as an item payload as
+// a plain SKU, as when the vision service returns a 1D barcode's text. Only
+// SKUs that lookupSKU resolves are accepted, so arbitrary text on a shelf
+// isn't taken for an item.
+func (s *inventoryKeeperKeeper) plainSKUPayload(content string) (ItemQRData, bool) {
+	if !skuPattern.MatchString(content) {
+		return ItemQRData{}, false
+	}
+	return s.lookupSKU(content)
+}
+
+// upceanCheckDigit computes the standard UPC/EAN check digit for digits
+func upceanCheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		// Weights alternate 3, 1 from the rightmost digit
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// barcodeValue checks value fits the symbology, appending the check digit to
+// EAN-13 and UPC-A values given without one
+func barcodeValue(symbology, value string) (string, error) {
+	if value == "" || len(value) > maxBarcodeValueLength {
+		return "", fmt.Errorf("value must be 1-%d characters", maxBarcodeValueLength)
+	}
+	length := map[string]int{codeTypeEAN13: 13, codeTypeUPCA: 12}[symbology]
+	if length == 0 {
+		for _, r := range value {
+			if r < ' ' || r > '~' {
+				return "", fmt.Errorf("%s values must be printable ASCII, got: %q", symbology, value)
+			}
+		}
+		return value, nil
+	}
+
+	if strings.Trim(value, "0123456789") != "" {
+		return "", fmt.Errorf("%s values must be digits, got: %q", symbology, value)
+	}
+	switch len(value) {
+	case length - 1:
+		return value + string(upceanCheckDigit(value)), nil
+	case length:
+		if upceanCheckDigit(value[:length-1]) != value[length-1] {
+			return "", fmt.Errorf("%s check digit of %s should be %c", symbology, value, upceanCheckDigit(value[:length-1]))
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("%s values must be %d digits, or %d without the check digit, got %d", symbology, length, length-1, len(value))
+	}
+}
+
+// renderBarcode draws value as a 1D barcode PNG: bars moduleWidth pixels per
+// module and height pixels tall, with a quiet zone of border modules either
+// side. Returns the PNG and its width, which must not exceed maxCodeSize.
+func renderBarcode(symbology, value string, moduleWidth, height, border int) ([]byte, int, error) {
+	var writer gozxing.Writer
+	switch symbology {
+	case codeTypeEAN13:
+		writer = oned.NewEAN13Writer()
+	case codeTypeUPCA:
+		writer = oned.NewUPCAWriter()
+	default:
+		writer = oned.NewCode128Writer()
+	}
+	// A one-pixel row with no margin is one bit per module
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_MARGIN: 0}
+	row, err := writer.Encode(value, barcodeFormats[symbology], 0, 1, hints)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to generate %s barcode: %w", symbology, err)
+	}
+
+	modules := row.GetWidth()
+	width := (modules + 2*border) * moduleWidth
+	if width > maxCodeSize {
+		return nil, 0, fmt.Errorf("barcode would be %d pixels wide, over the %d limit; lower module_width or border", width, maxCodeSize)
+	}
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for x := 0; x < modules; x++ {
+		if row.Get(x, 0) {
+			left := (x + border) * moduleWidth
+			draw.Draw(img, image.Rect(left, 0, left+moduleWidth, height), image.Black, image.Point{}, draw.Src)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, 0, fmt.Errorf("failed to encode barcode: %w", err)
+	}
+	return buf.Bytes(), width, nil
+}
+
+// handleGenerateBarcode renders a 1D barcode (Code 128 by default, or EAN-13
+// or UPC-A) for a value, or for an inventory item's supplier_sku or item_id.
+// Scanning it with the symbology in code_types resolves back to the item.
+func (s *inventoryKeeperKeeper) handleGenerateBarcode(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	symbology, err := optionalStringArg(cmd, "symbology")
+	if err != nil {
+		return nil, err
+	}
+	if symbology == "" {
+		symbology = codeTypeCode128
+	}
+	if _, ok := barcodeFormats[symbology]; !ok {
+		return nil, fmt.Errorf("symbology must be %q, %q, or %q, got: %q", codeTypeCode128, codeTypeEAN13, codeTypeUPCA, symbology)
+	}
+
+	value, err := optionalStringArg(cmd, "value")
+	if err != nil {
+		return nil, err
+	}
+	itemID, err := optionalStringArg(cmd, "item_id")
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		if itemID == "" {
+			return nil, errors.New("value or item_id is required")
+		}
+		namespace, err := namespaceArg(cmd)
+		if err != nil {
+			return nil, err
+		}
+		s.inventoryMu.RLock()
+		item, exists := s.inventory[keyFor(namespace, itemID)]
+		if exists {
+			value = item.SupplierSKU
+		}
+		s.inventoryMu.RUnlock()
+		if !exists {
+			return nil, fmt.Errorf("item %s not found", itemID)
+		}
+		if value == "" {
+			value = itemID
+		}
+	}
+	if value, err = barcodeValue(symbology, value); err != nil {
+		return nil, err
+	}
+
+	moduleWidth, height, border := defaultBarcodeModuleWidth, defaultBarcodeHeight, defaultBarcodeBorder
+	for _, field := range []struct {
+		name   string
+		target *int
+	}{
+		{"module_width", &moduleWidth},
+		{"height", &height},
+		{"border", &border},
+	} {
+		v, found, err := intArg(cmd, field.name)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			*field.target = v
+		}
+	}
+	if moduleWidth < 1 || moduleWidth > maxBarcodeModuleWidth {
+		return nil, fmt.Errorf("module_width must be between 1 and %d, got: %d", maxBarcodeModuleWidth, moduleWidth)
+	}
+	if height < minBarcodeHeight || height > maxBarcodeHeight {
+		return nil, fmt.Errorf("height must be between %d and %d, got: %d", minBarcodeHeight, maxBarcodeHeight, height)
+	}
+	if border < 0 || border > maxBarcodeBorder {
+		return nil, fmt.Errorf("border must be between 0 and %d, got: %d", maxBarcodeBorder, border)
+	}
+
+	pngBytes, width, err := renderBarcode(symbology, value, moduleWidth, height, border)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"barcode":   base64.StdEncoding.EncodeToString(pngBytes),
+		"format":    "base64-png",
+		"symbology": symbology,
+		"value":     value,
+		"width":     width,
+		"height":    height,
+	}
+	if data, ok := s.lookupSKU(value); ok {
+		result["namespace"] = normalizeNamespace(data.Namespace)
+		result["item_id"] = data.ItemID
+		result["item_name"] = data.ItemName
+	}
+	s.logger.Infof("Generated %s barcode for %s", symbology, value)
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestGenerateBarcode(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestKeeper(t, nil)
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "bolt-001", "item_name": "Bolt", "supplier_sku": "FA-1138"})
+
+	t.Run("Code 128 of an item's supplier SKU scans back", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_barcode", "item_id": "bolt-001", "height": 60})
+		if result["symbology"] != codeTypeCode128 || result["value"] != "FA-1138" || result["item_id"] != "bolt-001" {
+			t.Errorf("unexpected result: %v", result)
+		}
+		img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(result["barcode"].(string))))
+		if err != nil {
+			t.Fatalf("barcode is not a PNG: %v", err)
+		}
+		if b := img.Bounds(); b.Dx() != result["width"] || b.Dy() != 60 {
+			t.Errorf("expected %vx60, got %dx%d", result["width"], b.Dx(), b.Dy())
+		}
+		bmp, _ := gozxing.NewBinaryBitmapFromImage(img)
+		decoded, err := oned.NewCode128Reader().Decode(bmp, nil)
+		if err != nil || decoded.GetText() != "FA-1138" {
+			t.Errorf("expected barcode to read FA-1138, got %v (%v)", decoded, err)
+		}
+	})
+
+	t.Run("EAN-13 gains its check digit", func(t *testing.T) {
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_barcode", "value": "400638133393", "symbology": "ean13"})
+		if result["value"] != "4006381333931" {
+			t.Errorf("expected check digit 1 appended, got: %v", result["value"])
+		}
+		if _, ok := result["item_id"]; ok {
+			t.Errorf("expected no item for an unknown SKU, got: %v", result["item_id"])
+		}
+	})
+
+	t.Run("bad values are rejected", func(t *testing.T) {
+		for _, cmd := range []map[string]interface{}{
+			{"value": "4006381333932", "symbology": "ean13"}, // Wrong check digit
+			{"value": "ABC", "symbology": "upca"},
+			{"value": "123", "symbology": "ean13"},
+			{"value": "café"},
+			{"value": "x", "symbology": "aztec"},
+			{"value": "x", "height": 5},
+			{"value": "x", "module_width": 0},
+			{"value": "x", "border": 1 << 40},
+			{"value": "x", "border": -1},
+			{"value": strings.Repeat("W", 80), "module_width": 10}, // Wider than maxCodeSize
+			{"item_id": "ghost-404"},
+			{},
+		} {
+			cmd["command"] = "generate_barcode"
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+	})
+}
+
+func TestSKUScanning(t *testing.T) {
+	t.Run("Code 128 barcodes resolve through sku_map", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{CodeTypes: []string{codeTypeCode128}, SKUMap: map[string]string{"PALLET-7": "bolt-001"}})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "bolt-001", "item_name": "Bolt"})
+
+		pngBytes, _, err := renderBarcode(codeTypeCode128, "PALLET-7", 2, 80, defaultBarcodeBorder)
+		if err != nil {
+			t.Fatalf("failed to render barcode: %v", err)
+		}
+		bars, err := png.Decode(bytes.NewReader(pngBytes))
+		if err != nil {
+			t.Fatalf("failed to read barcode: %v", err)
+		}
+		frame := image.NewRGBA(image.Rect(0, 0, 480, 200))
+		draw.Draw(frame, frame.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(frame, bars.Bounds().Add(image.Pt(40, 60)), bars, image.Point{}, draw.Src)
+		setCameraFrame(t, svc, frame)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		codes := result["codes"].([]interface{})
+		if len(codes) != 1 {
+			t.Fatalf("expected 1 code, got: %v", codes)
+		}
+		code := codes[0].(map[string]interface{})
+		if code["symbology"] != codeTypeCode128 || code["item_id"] != "bolt-001" || code["item_name"] != "Bolt" {
+			t.Errorf("expected PALLET-7 to resolve to bolt-001, got: %v", code)
+		}
+	})
+
+	t.Run("plain-text SKUs from the vision service match supplier_sku", func(t *testing.T) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "namespace": "hardware", "item_id": "bolt-001", "item_name": "Bolt", "supplier_sku": "FA-1138"})
+		mockVision.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, "FA-1138"), testDetection(1, "EXIT")}, nil
+		}
+		setCameraFrame(t, svc, image.NewRGBA(image.Rect(0, 0, 64, 64)))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+		byContent := map[interface{}]map[string]interface{}{}
+		for _, raw := range result["codes"].([]interface{}) {
+			code := raw.(map[string]interface{})
+			byContent[code["content"]] = code
+		}
+		if sku := byContent["FA-1138"]; sku["item_id"] != "bolt-001" || sku["namespace"] != "hardware" {
+			t.Errorf("expected FA-1138 to resolve to hardware/bolt-001, got: %v", sku)
+		}
+		if _, resolved := byContent["EXIT"]["item_id"]; resolved {
+			t.Errorf("expected unknown text not to be taken for an item, got: %v", byContent["EXIT"])
+		}
+
+		decoded := mustDoCommand(t, svc, map[string]interface{}{"command": "decode_qr_data", "qr_data": "FA-1138"})
+		if decoded["kind"] != "sku" || decoded["valid"] != true {
+			t.Errorf("expected decode_qr_data to report a known SKU, got: %v", decoded)
+		}
+	})
+
+	t.Run("invalid sku_map is rejected", func(t *testing.T) {
+		for _, skuMap := range []map[string]string{{"": "bolt-001"}, {"PALLET-7": " "}} {
+			cfg := &Config{CameraName: "cam", QRVisionService: "qr", SKUMap: skuMap}
+			if _, _, err := cfg.Validate(""); err == nil {
+				t.Errorf("expected validation error for sku_map %v", skuMap)
+			}
+		}
+	})
+}
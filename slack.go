@@ -0,0 +1,134 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// slackEmoji prefixes each alert type's Slack message
+var slackEmoji = map[string]string{
+	"theft":       ":rotating_light:",
+	"low_stock":   ":package:",
+	"checked_out": ":outbox_tray:",
+	"returned":    ":inbox_tray:",
+}
+
+// slackNotifier posts alerts to a Slack incoming webhook
+type slackNotifier struct {
+	*webhookPoster
+	loc *time.Location
+}
+
+// newSlackNotifier builds a Slack notifier from config, or returns nil when
+// no webhook is configured. Alert times are shown in loc.
+func newSlackNotifier(cfg *Config, loc *time.Location) *slackNotifier {
+	if cfg.SlackWebhookURL == "" {
+		return nil
+	}
+	return &slackNotifier{newWebhookPoster(cfg.SlackWebhookURL, nil), loc}
+}
+
+// Name implements alertNotifier
+func (n *slackNotifier) Name() string {
+	return "slack"
+}
+
+// Notify implements alertNotifier
+func (n *slackNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]string{"text": formatSlackMessage(alert, n.loc)})
+	if err != nil {
+		return err
+	}
+	if err := n.send(ctx, body); err != nil {
+		return fmt.Errorf("slack %w", err)
+	}
+	return nil
+}
+
+// formatSlackMessage renders an alert as Slack mrkdwn, with its time in loc
+func formatSlackMessage(alert Alert, loc *time.Location) string {
+	var b strings.Builder
+	if emoji, ok := slackEmoji[alert.Type]; ok {
+		b.WriteString(emoji + " ")
+	}
+	fmt.Fprintf(&b, "*%s* (%s): %s", strings.ReplaceAll(alert.Type, "_", " "), alert.Severity, alert.Message)
+
+	keys := make([]string, 0, len(alert.Details))
+	for k := range alert.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n• %s: %v", k, alert.Details[k])
+	}
+	fmt.Fprintf(&b, "\n_Alert %d at %s_", alert.ID, formatTimestampIn(alert.CreatedAt, loc))
+	return b.String()
+}
+
+// slackTarget describes where Slack alerts go without exposing the webhook
+// path, which is the secret
+func slackTarget(cfg *Config) string {
+	return redactURL(cfg.SlackWebhookURL)
+}
+
+// handleTestNotification sends a test alert straight to each configured
+// notifier (or just integration, if given) and reports each result, so a
+// webhook or mail server can be checked from the config UI. The alert is not
+// recorded in get_alerts. Deliveries stop retrying when the command's
+// context ends or the keeper closes.
+func (s *inventoryKeeperKeeper) handleTestNotification(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	integration, _ := cmd["integration"].(string)
+	message, _ := cmd["message"].(string)
+	if message == "" {
+		message = fmt.Sprintf("Test notification from %s", s.name.Name)
+	}
+
+	var targets []alertNotifier
+	for _, n := range s.notifiers {
+		if integration == "" || n.Name() == integration {
+			targets = append(targets, n)
+		}
+	}
+	if len(targets) == 0 {
+		if integration != "" {
+			return nil, fmt.Errorf("integration %s is not configured", integration)
+		}
+		return nil, errors.New("no notification integrations are configured")
+	}
+
+	alert := Alert{
+		Type:      "test",
+		Severity:  severityInfo,
+		Message:   message,
+		CreatedAt: s.now().UTC(),
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(s.cancelCtx, cancel)()
+
+	results := make([]interface{}, 0, len(targets))
+	failed := 0
+	for _, n := range targets {
+		err := n.Notify(ctx, alert)
+		s.recordDelivery(n.Name(), err)
+		result := map[string]interface{}{
+			"name":    n.Name(),
+			"success": err == nil,
+		}
+		if err != nil {
+			failed++
+			result["error"] = err.Error()
+		}
+		results = append(results, result)
+	}
+	return map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+		"failed":  failed,
+	}, nil
+}
@@ -0,0 +1,201 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// slackServer records webhook posts, answering the first failures requests
+// with status
+type slackServer struct {
+	mu       sync.Mutex
+	texts    []string
+	failures int
+	status   int
+}
+
+func (s *slackServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(s.status)
+		return
+	}
+	var payload map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	s.texts = append(s.texts, payload["text"])
+}
+
+func (s *slackServer) posted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.texts...)
+}
+
+// newSlackTestKeeper returns a keeper whose Slack notifier posts to server
+// without waiting between retries
+func newSlackTestKeeper(t *testing.T, cfg *Config, server *httptest.Server) *inventoryKeeperKeeper {
+	t.Helper()
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	cfg.SlackWebhookURL = "https://hooks.slack.com/services/T000/B000/secret"
+	svc, _ := newTestKeeper(t, cfg)
+	slack := svc.notifiers[0].(*slackNotifier)
+	slack.url = server.URL
+	slack.backoff = time.Millisecond
+	return svc
+}
+
+func TestSlackNotifications(t *testing.T) {
+	t.Run("webhook url must be https", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", SlackWebhookURL: "http://hooks.slack.com/services/x"}
+		if _, _, err := cfg.Validate("test"); err == nil {
+			t.Error("expected error for a non-https webhook")
+		}
+	})
+
+	t.Run("theft alert is posted", func(t *testing.T) {
+		recorder := &slackServer{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+		svc := newSlackTestKeeper(t, nil, server)
+
+		svc.raiseAlert("theft", severityCritical, "apple-001", "Item apple-001 removed without check-in", nil)
+
+		deadline := time.Now().Add(2 * time.Second)
+		for len(recorder.posted()) == 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		texts := recorder.posted()
+		if len(texts) != 1 {
+			t.Fatalf("expected 1 Slack post, got: %d", len(texts))
+		}
+		if !strings.Contains(texts[0], ":rotating_light: *theft* (critical): Item apple-001 removed without check-in") {
+			t.Errorf("unexpected Slack message: %s", texts[0])
+		}
+	})
+
+	t.Run("server errors are retried", func(t *testing.T) {
+		recorder := &slackServer{failures: 2, status: http.StatusServiceUnavailable}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+		svc := newSlackTestKeeper(t, nil, server)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "test_notification", "integration": "slack"})
+		if result["failed"] != 0 {
+			t.Fatalf("expected delivery after retries, got: %v", result)
+		}
+		if len(recorder.posted()) != 1 {
+			t.Errorf("expected 1 Slack post, got: %d", len(recorder.posted()))
+		}
+	})
+
+	t.Run("waits between retries end with the command or the keeper", func(t *testing.T) {
+		recorder := &slackServer{failures: 100, status: http.StatusServiceUnavailable}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+		timeout := 0.2
+		svc := newSlackTestKeeper(t, &Config{CommandTimeoutSeconds: &timeout}, server)
+		slack := svc.notifiers[0].(*slackNotifier)
+		slack.backoff = time.Hour
+
+		started := time.Now()
+		if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "test_notification"}); err == nil || !strings.Contains(err.Error(), "TIMEOUT") {
+			t.Errorf("expected TIMEOUT, got: %v", err)
+		}
+		if elapsed := time.Since(started); elapsed > 5*time.Second {
+			t.Errorf("expected test_notification to stop at the command timeout, took %v", elapsed)
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- slack.Notify(svc.cancelCtx, Alert{Type: "test", Message: "retrying"}) }()
+		time.Sleep(50 * time.Millisecond)
+		svc.Close(context.Background())
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Error("expected the abandoned delivery to fail")
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("expected Close to interrupt the wait between retries")
+		}
+	})
+
+	t.Run("client errors fail without retrying", func(t *testing.T) {
+		recorder := &slackServer{failures: 1, status: http.StatusNotFound}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+		svc := newSlackTestKeeper(t, nil, server)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "test_notification"})
+		entry := result["results"].([]interface{})[0].(map[string]interface{})
+		if entry["success"] != false || !strings.Contains(entry["error"].(string), "after 1 attempts") {
+			t.Errorf("expected a single failed attempt, got: %v", entry)
+		}
+
+		status := mustDoCommand(t, svc, map[string]interface{}{"command": "integrations_status"})
+		slack := status["integrations"].([]interface{})[1].(map[string]interface{})
+		if slack["last_result"] != "failure" || slack["target"] != "https://hooks.slack.com/***" {
+			t.Errorf("unexpected slack status: %v", slack)
+		}
+	})
+
+	t.Run("checkout_alerts posts checked out and returned items", func(t *testing.T) {
+		recorder := &slackServer{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		oneScan := 1
+		delay := 60
+		start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+		svc, mockVision, clock := newTestKeeperWithClock(t, &Config{
+			PresenceDebounceScans: &oneScan,
+			CheckInDelaySeconds:   &delay,
+			CheckoutAlerts:        true,
+			SlackWebhookURL:       "https://hooks.slack.com/services/T000/B000/secret",
+		}, start)
+		svc.notifiers[0].(*slackNotifier).url = server.URL
+
+		apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+		visible := true
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			if !visible {
+				return []objectdetection.Detection{}, nil
+			}
+			return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+		}
+
+		ctx := context.Background()
+		svc.scanAndCompare(ctx)
+		visible = false
+		svc.scanAndCompare(ctx)
+		clock.Advance(61 * time.Second)
+		svc.scanAndCompare(ctx)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "force_check_in", "item_id": "apple-001"})
+
+		deadline := time.Now().Add(2 * time.Second)
+		for len(recorder.posted()) < 2 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		texts := recorder.posted()
+		if len(texts) != 2 {
+			t.Fatalf("expected 2 Slack posts, got: %v", texts)
+		}
+		if !strings.Contains(texts[0], "Item apple-001 (Honeycrisp Apple) checked out") {
+			t.Errorf("unexpected checked out message: %s", texts[0])
+		}
+		if !strings.Contains(texts[1], "Item apple-001 (Honeycrisp Apple) returned") {
+			t.Errorf("unexpected returned message: %s", texts[1])
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// snapshotVersion identifies the layout of snapshot dumps
+const snapshotVersion = 1
+
+// secretConfigFields are config keys whose values never appear in a snapshot
+var secretConfigFields = []string{"encryption_key", "signing_secret", "smtp_password", "command_secret", "redis_password", "slack_webhook_url", "notifiers"}
+
+// restorableSnapshot is the subset of a snapshot dump that restore_snapshot
+// loads back. Secrets and runtime state (alerts, present-set) are not restored.
+type restorableSnapshot struct {
+	Inventory []*InventoryItem `json:"inventory"`
+	History   []HistoryEvent   `json:"history"`
+}
+
+// redactedConfig returns the config as a response map with secrets masked
+func (s *inventoryKeeperKeeper) redactedConfig() (map[string]interface{}, error) {
+	raw, err := json.Marshal(s.config())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	for _, key := range secretConfigFields {
+		if _, set := settings[key]; set {
+			settings[key] = "[redacted]"
+		}
+	}
+	return settings, nil
+}
+
+// handleSnapshot returns a complete dump of inventory, history, alerts,
+// the present-set, and non-secret settings
+func (s *inventoryKeeperKeeper) handleSnapshot(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	settings, err := s.redactedConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.RLock()
+	keys := make([]itemKey, 0, len(s.inventory))
+	for key := range s.inventory {
+		keys = append(keys, key)
+	}
+	sortItemKeys(keys)
+	inventory := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		inventory = append(inventory, s.inventory[key].toMap(s.location))
+	}
+	history := make([]interface{}, 0, len(s.history))
+	for _, event := range s.history {
+		history = append(history, event.toMap(time.UTC))
+	}
+	s.inventoryMu.RUnlock()
+
+	s.alertsMu.Lock()
+	alerts := make([]interface{}, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		alerts = append(alerts, alert.toMap(time.UTC))
+	}
+	s.alertsMu.Unlock()
+
+	s.monitorMu.Lock()
+	presentKeys := make([]itemKey, 0, len(s.presence))
+	for key, entry := range s.presence {
+		if entry.Present {
+			presentKeys = append(presentKeys, key)
+		}
+	}
+	sortItemKeys(presentKeys)
+	present := make([]interface{}, 0, len(presentKeys))
+	for _, key := range presentKeys {
+		entry := s.presence[key]
+		present = append(present, map[string]interface{}{
+			"namespace":  key.Namespace,
+			"item_id":    entry.ItemID,
+			"item_name":  entry.ItemName,
+			"first_seen": formatTimestampIn(entry.FirstSeen, s.location),
+			"last_seen":  formatTimestampIn(entry.LastSeen, s.location),
+		})
+	}
+	s.monitorMu.Unlock()
+
+	return map[string]interface{}{
+		"version":   snapshotVersion,
+		"taken_at":  formatTimestampIn(s.now(), s.location),
+		"inventory": inventory,
+		"history":   history,
+		"alerts":    alerts,
+		"present":   present,
+		"config":    settings,
+	}, nil
+}
+
+// snapshotArg extracts and parses the "snapshot" argument, checking it is
+// within cfg's import limit and its inventory is well formed
+func snapshotArg(cmd map[string]interface{}, cfg *Config) (map[itemKey]*InventoryItem, []HistoryEvent, error) {
+	dump, ok := cmd["snapshot"].(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.New("snapshot is required and must be an object")
+	}
+	return parseSnapshotDump(dump, cfg)
+}
+
+// parseSnapshotDump parses the inventory and history of a snapshot dump,
+// checking it is within cfg's import limit and its inventory is well formed
+func parseSnapshotDump(dump map[string]interface{}, cfg *Config) (map[itemKey]*InventoryItem, []HistoryEvent, error) {
+	if err := cfg.checkImportArg(dump); err != nil {
+		return nil, nil, err
+	}
+
+	// Round-trip through JSON to get typed items and events
+	raw, err := json.Marshal(dump)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	var snap restorableSnapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return nil, nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	inventory := make(map[itemKey]*InventoryItem, len(snap.Inventory))
+	for _, item := range snap.Inventory {
+		if item == nil || item.ItemID == "" {
+			return nil, nil, errors.New("invalid snapshot: inventory item missing item_id")
+		}
+		// Items from snapshots taken before namespaces belong to the default
+		item.Namespace = normalizeNamespace(item.Namespace)
+		if err := validateNamespace(item.Namespace); err != nil {
+			return nil, nil, fmt.Errorf("invalid snapshot: item %s: %w", item.ItemID, err)
+		}
+		if _, dup := inventory[item.key()]; dup {
+			return nil, nil, fmt.Errorf("invalid snapshot: duplicate item %s in namespace %s", item.ItemID, item.Namespace)
+		}
+		if item.Quantity < 0 {
+			return nil, nil, fmt.Errorf("invalid snapshot: item %s has negative quantity %d", item.ItemID, item.Quantity)
+		}
+		inventory[item.key()] = item
+	}
+	return inventory, snap.History, nil
+}
+
+// handleRestoreSnapshot replaces inventory and history with the contents of
+// a snapshot dump. The undo stack is cleared since it refers to the old state.
+func (s *inventoryKeeperKeeper) handleRestoreSnapshot(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	inventory, history, err := snapshotArg(cmd, s.config())
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > maxHistoryEvents {
+		history = history[len(history)-maxHistoryEvents:]
+	}
+
+	if limit := s.config().maxInventoryItems(); limit > 0 && len(inventory) > limit {
+		return nil, fmt.Errorf("invalid snapshot: %d items exceeds max_inventory_items of %d", len(inventory), limit)
+	}
+
+	s.inventoryMu.Lock()
+	s.inventory = inventory
+	s.history = history
+	s.historySeq = max(s.historySeq, numberHistory(history))
+	s.undoStack = nil
+	s.markStorageDirtyLocked()
+	s.inventoryMu.Unlock()
+
+	s.logger.Infof("Restored snapshot with %d items and %d history events", len(inventory), len(history))
+	return map[string]interface{}{
+		"restored":        true,
+		"items_restored":  len(inventory),
+		"events_restored": len(history),
+	}, nil
+}
@@ -0,0 +1,99 @@
+package inventorykeeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	source, _ := newTestKeeper(t, &Config{
+		SigningSecret: "shelf-secret",
+		EncryptionKey: testEncryptionKey('k'),
+	})
+
+	mustDoCommand(t, source, map[string]interface{}{
+		"command":   "add_item",
+		"item_id":   "apple-001",
+		"item_name": "Honeycrisp Apple",
+		"quantity":  6.0,
+		"location":  "aisle-3",
+		"tags":      []interface{}{"fruit", "fresh"},
+	})
+	mustDoCommand(t, source, map[string]interface{}{
+		"command":   "add_item",
+		"item_id":   "flour-210",
+		"item_name": "Bread Flour",
+	})
+	mustDoCommand(t, source, map[string]interface{}{"command": "adjust_quantity", "item_id": "apple-001", "delta": -2.0})
+
+	snapshot := mustDoCommand(t, source, map[string]interface{}{"command": "snapshot"})
+
+	t.Run("secrets are redacted", func(t *testing.T) {
+		settings := snapshot["config"].(map[string]interface{})
+		for _, key := range []string{"encryption_key", "signing_secret"} {
+			if settings[key] != "[redacted]" {
+				t.Errorf("expected %s to be redacted, got: %v", key, settings[key])
+			}
+		}
+
+		// Every secret field is set here, so one added to Config without
+		// being filled in below fails rather than passing as absent
+		full := &inventoryKeeperKeeper{cfg: &Config{
+			EncryptionKey:   testEncryptionKey('k'),
+			SigningSecret:   "shelf-secret",
+			SMTPPassword:    "smtp-secret",
+			CommandSecret:   "command-secret",
+			RedisPassword:   "redis-secret",
+			SlackWebhookURL: "https://hooks.slack.com/services/T000/B000/secret",
+			Notifiers:       []NotifierConfig{{Type: "webhook", URL: "https://example.com/hook?token=secret"}},
+		}}
+		redacted, err := full.redactedConfig()
+		if err != nil {
+			t.Fatalf("failed to redact config: %v", err)
+		}
+		for _, key := range secretConfigFields {
+			if redacted[key] != "[redacted]" {
+				t.Errorf("expected %s to be redacted, got: %v", key, redacted[key])
+			}
+		}
+		if settings["camera_name"] != "test-camera" {
+			t.Errorf("expected non-secret settings to be kept, got camera_name: %v", settings["camera_name"])
+		}
+	})
+
+	t.Run("restore on a fresh keeper reproduces inventory and history", func(t *testing.T) {
+		target, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, target, map[string]interface{}{"command": "restore_snapshot", "snapshot": snapshot})
+		if result["items_restored"] != 2 || result["events_restored"] != 3 {
+			t.Errorf("expected 2 items and 3 events restored, got: %v", result)
+		}
+
+		sourceItems := mustDoCommand(t, source, map[string]interface{}{"command": "list_items"})
+		targetItems := mustDoCommand(t, target, map[string]interface{}{"command": "list_items"})
+		if !reflect.DeepEqual(sourceItems, targetItems) {
+			t.Errorf("restored inventory differs:\nsource: %v\ntarget: %v", sourceItems, targetItems)
+		}
+
+		sourceEvents := mustDoCommand(t, source, map[string]interface{}{"command": "get_history"})["events"].([]interface{})
+		targetEvents := mustDoCommand(t, target, map[string]interface{}{"command": "get_history"})["events"].([]interface{})
+		if len(sourceEvents) != len(targetEvents) {
+			t.Fatalf("expected %d events, got: %d", len(sourceEvents), len(targetEvents))
+		}
+		for i := range sourceEvents {
+			want, got := sourceEvents[i].(map[string]interface{}), targetEvents[i].(map[string]interface{})
+			for _, key := range []string{"type", "item_id", "timestamp"} {
+				if want[key] != got[key] {
+					t.Errorf("event %d %s: expected %v, got: %v", i, key, want[key], got[key])
+				}
+			}
+		}
+	})
+
+	t.Run("restore rejects a missing snapshot", func(t *testing.T) {
+		target, _ := newTestKeeper(t, nil)
+		if _, err := target.DoCommand(t.Context(), map[string]interface{}{"command": "restore_snapshot"}); err == nil {
+			t.Error("expected error for missing snapshot")
+		}
+	})
+}
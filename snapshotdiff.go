@@ -0,0 +1,134 @@
+package inventorykeeper
+
+import "context"
+
+// fieldChange is one item field that differs between a snapshot and now
+type fieldChange struct {
+	Key    itemKey
+	Before interface{}
+	After  interface{}
+}
+
+// toMap converts the change into a DoCommand-friendly response map
+func (c fieldChange) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"namespace": c.Key.Namespace,
+		"item_id":   c.Key.ItemID,
+		"before":    c.Before,
+		"after":     c.After,
+	}
+}
+
+// inventoryDiff lists how an inventory changed, per change type. An item
+// whose quantity, name, and location all changed appears in all three
+// buckets. Every bucket is sorted by namespace and item_id.
+type inventoryDiff struct {
+	Added           []*InventoryItem // In the current inventory only
+	Removed         []*InventoryItem // In the snapshot only
+	QuantityChanged []fieldChange    // Before and after quantities
+	Renamed         []fieldChange    // Before and after item names
+	Moved           []fieldChange    // Before and after locations
+}
+
+// diffInventories compares a snapshot's inventory (before) with the current
+// one (after)
+func diffInventories(before, after map[itemKey]*InventoryItem) inventoryDiff {
+	keys := make([]itemKey, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sortItemKeys(keys)
+
+	var diff inventoryDiff
+	for _, key := range keys {
+		old, wasThere := before[key]
+		cur, isThere := after[key]
+		switch {
+		case !wasThere:
+			diff.Added = append(diff.Added, cur)
+		case !isThere:
+			diff.Removed = append(diff.Removed, old)
+		default:
+			if old.Quantity != cur.Quantity {
+				diff.QuantityChanged = append(diff.QuantityChanged, fieldChange{key, old.Quantity, cur.Quantity})
+			}
+			if old.ItemName != cur.ItemName {
+				diff.Renamed = append(diff.Renamed, fieldChange{key, old.ItemName, cur.ItemName})
+			}
+			if old.Location != cur.Location {
+				diff.Moved = append(diff.Moved, fieldChange{key, old.Location, cur.Location})
+			}
+		}
+	}
+	return diff
+}
+
+// toMap converts the diff into a DoCommand-friendly response map
+func (d inventoryDiff) toMap() map[string]interface{} {
+	items := func(list []*InventoryItem) []interface{} {
+		out := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			out = append(out, map[string]interface{}{
+				"namespace": item.Namespace,
+				"item_id":   item.ItemID,
+				"item_name": item.ItemName,
+				"quantity":  item.Quantity,
+			})
+		}
+		return out
+	}
+	changes := func(list []fieldChange) []interface{} {
+		out := make([]interface{}, 0, len(list))
+		for _, change := range list {
+			out = append(out, change.toMap())
+		}
+		return out
+	}
+
+	quantityChanged := make([]interface{}, 0, len(d.QuantityChanged))
+	for _, change := range d.QuantityChanged {
+		entry := change.toMap()
+		entry["delta"] = change.After.(int) - change.Before.(int)
+		quantityChanged = append(quantityChanged, entry)
+	}
+
+	total := len(d.Added) + len(d.Removed) + len(d.QuantityChanged) + len(d.Renamed) + len(d.Moved)
+	return map[string]interface{}{
+		"added":            items(d.Added),
+		"removed":          items(d.Removed),
+		"quantity_changed": quantityChanged,
+		"renamed":          changes(d.Renamed),
+		"moved":            changes(d.Moved),
+		"changes":          total,
+		"identical":        total == 0,
+	}
+}
+
+// handleDiffSnapshot compares a snapshot dump's inventory with the current
+// inventory across all namespaces. Nothing is modified.
+func (s *inventoryKeeperKeeper) handleDiffSnapshot(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	before, _, err := snapshotArg(cmd, s.config())
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.RLock()
+	after := make(map[itemKey]*InventoryItem, len(s.inventory))
+	for key, item := range s.inventory {
+		after[key] = item.clone()
+	}
+	s.inventoryMu.RUnlock()
+
+	result := diffInventories(before, after).toMap()
+	if dump, ok := cmd["snapshot"].(map[string]interface{}); ok {
+		if takenAt, ok := dump["taken_at"].(string); ok {
+			result["snapshot_taken_at"] = takenAt
+		}
+	}
+	return result, nil
+}
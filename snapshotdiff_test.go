@@ -0,0 +1,97 @@
+package inventorykeeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffInventories(t *testing.T) {
+	item := func(id, name string, quantity int, location string) *InventoryItem {
+		return &InventoryItem{Namespace: defaultNamespace, ItemID: id, ItemName: name, Quantity: quantity, Location: location}
+	}
+	inventory := func(items ...*InventoryItem) map[itemKey]*InventoryItem {
+		m := make(map[itemKey]*InventoryItem)
+		for _, i := range items {
+			m[i.key()] = i
+		}
+		return m
+	}
+
+	before := inventory(
+		item("apple-001", "Honeycrisp Apple", 6, "aisle-3"),
+		item("banana-042", "Organic Banana", 2, "aisle-3"),
+		item("flour-210", "Flour", 1, "aisle-7"),
+	)
+	after := inventory(
+		item("apple-001", "Honeycrisp Apple", 4, "aisle-3"),
+		item("cheese-777", "Aged Cheddar", 1, "fridge"),
+		item("flour-210", "Bread Flour", 1, "aisle-8"),
+	)
+
+	diff := diffInventories(before, after)
+
+	if len(diff.Added) != 1 || diff.Added[0].ItemID != "cheese-777" {
+		t.Errorf("expected cheese-777 added, got: %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ItemID != "banana-042" {
+		t.Errorf("expected banana-042 removed, got: %v", diff.Removed)
+	}
+	want := []fieldChange{{keyFor(defaultNamespace, "apple-001"), 6, 4}}
+	if !reflect.DeepEqual(diff.QuantityChanged, want) {
+		t.Errorf("expected apple-001 quantity 6 -> 4, got: %v", diff.QuantityChanged)
+	}
+	want = []fieldChange{{keyFor(defaultNamespace, "flour-210"), "Flour", "Bread Flour"}}
+	if !reflect.DeepEqual(diff.Renamed, want) {
+		t.Errorf("expected flour-210 renamed, got: %v", diff.Renamed)
+	}
+	want = []fieldChange{{keyFor(defaultNamespace, "flour-210"), "aisle-7", "aisle-8"}}
+	if !reflect.DeepEqual(diff.Moved, want) {
+		t.Errorf("expected flour-210 moved, got: %v", diff.Moved)
+	}
+
+	if same := diffInventories(before, before).toMap(); same["identical"] != true || same["changes"] != 0 {
+		t.Errorf("expected no changes diffing an inventory with itself, got: %v", same)
+	}
+}
+
+func TestDiffSnapshot(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+	for _, id := range []string{"apple-001", "banana-042"} {
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": id, "item_name": id, "quantity": 3})
+	}
+	snapshot := mustDoCommand(t, svc, map[string]interface{}{"command": "snapshot"})
+
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "cheese-777", "item_name": "Aged Cheddar"})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "remove_item", "item_id": "banana-042"})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "apple-001", "delta": -1})
+
+	result := mustDoCommand(t, svc, map[string]interface{}{"command": "diff_snapshot", "snapshot": snapshot})
+
+	ids := func(bucket string) []interface{} {
+		var out []interface{}
+		for _, entry := range result[bucket].([]interface{}) {
+			out = append(out, entry.(map[string]interface{})["item_id"])
+		}
+		return out
+	}
+	if got := ids("added"); !reflect.DeepEqual(got, []interface{}{"cheese-777"}) {
+		t.Errorf("added: expected [cheese-777], got: %v", got)
+	}
+	if got := ids("removed"); !reflect.DeepEqual(got, []interface{}{"banana-042"}) {
+		t.Errorf("removed: expected [banana-042], got: %v", got)
+	}
+	changed := result["quantity_changed"].([]interface{})
+	if len(changed) != 1 {
+		t.Fatalf("expected one quantity change, got: %v", changed)
+	}
+	if entry := changed[0].(map[string]interface{}); entry["item_id"] != "apple-001" || entry["before"] != 3 || entry["after"] != 2 || entry["delta"] != -1 {
+		t.Errorf("unexpected quantity change: %v", entry)
+	}
+	if result["changes"] != 3 || result["snapshot_taken_at"] != snapshot["taken_at"] {
+		t.Errorf("unexpected summary: %v", result)
+	}
+
+	if _, err := svc.DoCommand(t.Context(), map[string]interface{}{"command": "diff_snapshot"}); err == nil {
+		t.Error("expected error without a snapshot")
+	}
+}
@@ -0,0 +1,99 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// staleItem is an inventory item the camera hasn't seen within the window
+type staleItem struct {
+	item     *InventoryItem
+	lastSeen time.Time // Zero when never seen since startup
+}
+
+// handleStaleItems lists a namespace's items the camera hasn't detected in
+// the not_seen_for duration, for directing physical counts. Last-seen times
+// are kept in memory, so until an item is scanned after startup it counts as
+// never seen. Never-seen items come first, then the longest unseen. Items
+// tagged exclude_tag (e.g. stock kept off camera) are skipped.
+func (s *inventoryKeeperKeeper) handleStaleItems(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := optionalStringArg(cmd, "not_seen_for")
+	if err != nil {
+		return nil, err
+	}
+	if raw == "" {
+		return nil, errors.New("not_seen_for is required, e.g. \"2h\"")
+	}
+	window, err := time.ParseDuration(raw)
+	if err != nil {
+		return nil, fmt.Errorf("not_seen_for must be a duration such as \"2h\": %w", err)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("not_seen_for must be positive, got: %s", raw)
+	}
+	excludeTag, err := optionalStringArg(cmd, "exclude_tag")
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	cutoff := now.Add(-window)
+
+	s.monitorMu.Lock()
+	lastSeen := make(map[itemKey]time.Time, len(s.lastSeen))
+	for key, seen := range s.lastSeen {
+		if key.Namespace == namespace {
+			lastSeen[key] = seen
+		}
+	}
+	s.monitorMu.Unlock()
+
+	var stale []staleItem
+	s.inventoryMu.RLock()
+	for key, item := range s.inventory {
+		if key.Namespace != namespace || (excludeTag != "" && hasTag(item, excludeTag)) {
+			continue
+		}
+		seen := lastSeen[key]
+		if seen.IsZero() || seen.Before(cutoff) {
+			stale = append(stale, staleItem{item: item.clone(), lastSeen: seen})
+		}
+	}
+	s.inventoryMu.RUnlock()
+
+	sort.Slice(stale, func(i, j int) bool {
+		if !stale[i].lastSeen.Equal(stale[j].lastSeen) {
+			return stale[i].lastSeen.Before(stale[j].lastSeen)
+		}
+		return stale[i].item.ItemID < stale[j].item.ItemID
+	})
+
+	items := make([]interface{}, 0, len(stale))
+	neverSeen := 0
+	for _, entry := range stale {
+		m := entry.item.toMap(s.location)
+		m["never_seen"] = entry.lastSeen.IsZero()
+		if entry.lastSeen.IsZero() {
+			neverSeen++
+		} else {
+			m["last_seen"] = formatTimestampIn(entry.lastSeen, s.location)
+			m["unseen_seconds"] = now.Sub(entry.lastSeen).Seconds()
+		}
+		items = append(items, m)
+	}
+
+	return map[string]interface{}{
+		"namespace":    namespace,
+		"not_seen_for": window.String(),
+		"items":        items,
+		"count":        len(items),
+		"never_seen":   neverSeen,
+	}, nil
+}
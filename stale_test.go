@@ -0,0 +1,78 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestStaleItems(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	// setup stocks three items, scans the apple once, then lets 3h pass
+	setup := func(t *testing.T) *inventoryKeeperKeeper {
+		svc, mockVision, clock := newTestKeeperWithClock(t, nil, start)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "banana-042", "item_name": "Banana"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "pallet-9", "item_name": "Pallet", "tags": []interface{}{"backstock"}})
+
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+		}
+		svc.scanAndCompare(ctx)
+		clock.Advance(3 * time.Hour)
+		return svc
+	}
+	staleIDs := func(result map[string]interface{}) []string {
+		var ids []string
+		for _, raw := range result["items"].([]interface{}) {
+			ids = append(ids, raw.(map[string]interface{})["item_id"].(string))
+		}
+		return ids
+	}
+
+	t.Run("item seen 3h ago is stale for 2h but not 4h", func(t *testing.T) {
+		svc := setup(t)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "stale_items", "not_seen_for": "2h", "exclude_tag": "backstock"})
+		ids := staleIDs(result)
+		if len(ids) != 2 || ids[0] != "banana-042" || ids[1] != "apple-001" {
+			t.Fatalf("expected never-seen banana then apple, got: %v", ids)
+		}
+		appleEntry := result["items"].([]interface{})[1].(map[string]interface{})
+		if appleEntry["last_seen"] != formatTimestamp(start) || appleEntry["unseen_seconds"] != (3*time.Hour).Seconds() {
+			t.Errorf("expected apple last seen at start, got: %v", appleEntry)
+		}
+
+		ids = staleIDs(mustDoCommand(t, svc, map[string]interface{}{"command": "stale_items", "not_seen_for": "4h", "exclude_tag": "backstock"}))
+		if len(ids) != 1 || ids[0] != "banana-042" {
+			t.Errorf("expected only banana for a 4h window, got: %v", ids)
+		}
+	})
+
+	t.Run("excluded tag is skipped only when asked", func(t *testing.T) {
+		svc := setup(t)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "stale_items", "not_seen_for": "4h"})
+		if result["count"] != 2 || result["never_seen"] != 2 {
+			t.Errorf("expected banana and pallet never seen, got: %v", result)
+		}
+	})
+
+	t.Run("not_seen_for must be a positive duration", func(t *testing.T) {
+		svc := setup(t)
+		for _, cmd := range []map[string]interface{}{
+			{"command": "stale_items"},
+			{"command": "stale_items", "not_seen_for": "soon"},
+			{"command": "stale_items", "not_seen_for": "-1h"},
+		} {
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package inventorykeeper
+
+import "time"
+
+// startupGrace holds theft detection off while the first scans build the
+// present-set, so an empty baseline isn't mistaken for removals
+type startupGrace struct {
+	endsAt time.Time // When detection begins (zero until the first scan)
+	over   bool      // The grace period has ended and been logged
+}
+
+// startupGrace returns the grace period after the first scan, zero when unset
+func (cfg *Config) startupGrace() time.Duration {
+	if cfg.StartupGraceSeconds == nil {
+		return 0
+	}
+	return time.Duration(*cfg.StartupGraceSeconds) * time.Second
+}
+
+// inStartupGrace reports whether a scan at now falls within the startup
+// grace period. The period starts with the first monitoring scan, since
+// that's when the present-set starts to fill.
+func (s *inventoryKeeperKeeper) inStartupGrace(now time.Time) bool {
+	grace := s.config().startupGrace()
+	if grace <= 0 {
+		return false
+	}
+
+	s.startupMu.Lock()
+	defer s.startupMu.Unlock()
+
+	if s.startup.over {
+		return false
+	}
+	if s.startup.endsAt.IsZero() {
+		s.startup.endsAt = now.Add(grace)
+		s.logger.Infof("Theft detection starts after a %v startup grace period, at %s", grace, formatTimestamp(s.startup.endsAt))
+	}
+	if now.Before(s.startup.endsAt) {
+		return true
+	}
+	s.startup.over = true
+	s.logger.Info("Startup grace period ended, theft detection active")
+	return false
+}
+
+// startupGraceStatus reports when theft detection begins while the startup
+// grace period is running, nil otherwise
+func (s *inventoryKeeperKeeper) startupGraceStatus() map[string]interface{} {
+	s.startupMu.Lock()
+	defer s.startupMu.Unlock()
+
+	if s.startup.over || s.startup.endsAt.IsZero() {
+		return nil
+	}
+	return map[string]interface{}{
+		"ends_at": formatTimestampIn(s.startup.endsAt, s.location),
+	}
+}
@@ -0,0 +1,90 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestStartupGrace(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	// setup returns a keeper whose shelf can be switched between stocked and empty
+	setup := func(t *testing.T, cfg *Config) (*inventoryKeeperKeeper, *fakeClock, func(stocked bool)) {
+		svc, mockVision, clock := newTestKeeperWithClock(t, cfg, start)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+
+		stocked := true
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			if !stocked {
+				return []objectdetection.Detection{}, nil
+			}
+			return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+		}
+		return svc, clock, func(s bool) { stocked = s }
+	}
+	theftAlerts := func(t *testing.T, svc *inventoryKeeperKeeper) int {
+		t.Helper()
+		count := 0
+		for _, alert := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{}) {
+			if alert.(map[string]interface{})["type"] == "theft" {
+				count++
+			}
+		}
+		return count
+	}
+	// cycle stocks the shelf then empties it, two scans each, advancing the
+	// clock a second per scan
+	cycle := func(svc *inventoryKeeperKeeper, clock *fakeClock, setStocked func(bool)) {
+		for _, stocked := range []bool{true, true, false, false} {
+			setStocked(stocked)
+			svc.scanAndCompare(ctx)
+			clock.Advance(time.Second)
+		}
+	}
+
+	t.Run("removals during the grace window raise no alert", func(t *testing.T) {
+		grace := 60
+		svc, clock, setStocked := setup(t, &Config{StartupGraceSeconds: &grace})
+
+		cycle(svc, clock, setStocked)
+		if n := theftAlerts(t, svc); n != 0 {
+			t.Errorf("expected no theft alerts during startup grace, got: %d", n)
+		}
+		status := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})
+		if grace, ok := status["startup_grace"].(map[string]interface{}); !ok || grace["ends_at"] != formatTimestamp(start.Add(time.Minute)) {
+			t.Errorf("expected get_status to report the grace end, got: %v", status["startup_grace"])
+		}
+
+		clock.Advance(time.Minute)
+		cycle(svc, clock, setStocked)
+		if n := theftAlerts(t, svc); n != 1 {
+			t.Errorf("expected a theft alert once grace elapsed, got: %d", n)
+		}
+		if _, ok := mustDoCommand(t, svc, map[string]interface{}{"command": "get_status"})["startup_grace"]; ok {
+			t.Error("expected startup_grace dropped from get_status after it ends")
+		}
+	})
+
+	t.Run("no grace by default", func(t *testing.T) {
+		svc, clock, setStocked := setup(t, nil)
+
+		cycle(svc, clock, setStocked)
+		if n := theftAlerts(t, svc); n != 1 {
+			t.Errorf("expected an immediate theft alert, got: %d", n)
+		}
+	})
+
+	t.Run("negative startup_grace_seconds is rejected", func(t *testing.T) {
+		grace := -1
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", StartupGraceSeconds: &grace}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected validation error for negative startup_grace_seconds")
+		}
+	})
+}
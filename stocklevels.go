@@ -0,0 +1,96 @@
+package inventorykeeper
+
+import (
+	"context"
+	"sort"
+)
+
+// Stock level statuses reported by get_stock_levels
+const (
+	stockNotScanned = "not_scanned" // No scan has run yet
+	stockNotSeen    = "not_seen"    // Not in the present-set
+	stockMatched    = "ok"          // Units seen match the recorded quantity
+	stockShort      = "short"       // Fewer units seen than recorded
+	stockSurplus    = "surplus"     // More units seen than recorded
+)
+
+// handleGetStockLevels reports every recorded item's quantity alongside the
+// units its labels stood for in the latest scan that saw it. Several labels
+// for one item_id count separately, and a label carrying a quantity counts
+// as that many units.
+func (s *inventoryKeeperKeeper) handleGetStockLevels(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	type seen struct{ labels, units int }
+	detected := make(map[string]seen)
+	s.monitorMu.Lock()
+	lastScan := s.lastScanAt
+	for key, entry := range s.presence {
+		if entry.Present && key.Namespace == namespace {
+			detected[key.ItemID] = seen{labels: entry.Labels, units: entry.Units}
+		}
+	}
+	s.monitorMu.Unlock()
+
+	s.inventoryMu.RLock()
+	items := make([]*InventoryItem, 0, len(s.inventory))
+	for key, item := range s.inventory {
+		if key.Namespace == namespace {
+			items = append(items, item.clone())
+		}
+	}
+	s.inventoryMu.RUnlock()
+	sort.Slice(items, func(i, j int) bool { return items[i].ItemID < items[j].ItemID })
+
+	levels := make([]interface{}, 0, len(items))
+	totalUnits, lowStock, mismatched := 0, 0, 0
+	for _, item := range items {
+		totalUnits += item.Quantity
+		level := map[string]interface{}{
+			"item_id":   item.ItemID,
+			"item_name": item.ItemName,
+			"quantity":  item.Quantity,
+			"low_stock": item.atReorderPoint(),
+		}
+		if item.atReorderPoint() {
+			lowStock++
+			level["reorder_threshold"] = item.ReorderThreshold
+		}
+
+		count, present := detected[item.ItemID]
+		switch {
+		case lastScan.IsZero():
+			level["status"] = stockNotScanned
+		case !present:
+			level["status"] = stockNotSeen
+		default:
+			level["labels"] = count.labels
+			level["detected_units"] = count.units
+			level["difference"] = item.Quantity - count.units
+			switch {
+			case count.units == item.Quantity:
+				level["status"] = stockMatched
+			case count.units < item.Quantity:
+				level["status"] = stockShort
+				mismatched++
+			default:
+				level["status"] = stockSurplus
+				mismatched++
+			}
+		}
+		levels = append(levels, level)
+	}
+
+	return map[string]interface{}{
+		"namespace":    namespace,
+		"items":        levels,
+		"count":        len(levels),
+		"total_units":  totalUnits,
+		"low_stock":    lowStock,
+		"mismatched":   mismatched,
+		"last_scan_at": formatTimestampIn(lastScan, s.location),
+	}, nil
+}
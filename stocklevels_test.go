@@ -0,0 +1,83 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestGetStockLevels(t *testing.T) {
+	ctx := context.Background()
+	svc, mockVision := newTestKeeper(t, nil)
+
+	// Two loose soup cans plus a case label standing for 12 more; one pear
+	// label for two recorded pears; no beans at all
+	soup, _ := json.Marshal(ItemQRData{ItemID: "soup-003", ItemName: "Tomato Soup"})
+	soupCase, _ := json.Marshal(ItemQRData{ItemID: "soup-003", ItemName: "Tomato Soup", Quantity: 12})
+	pear, _ := json.Marshal(ItemQRData{ItemID: "pear-002", ItemName: "Bosc Pear"})
+	mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{
+			testDetection(0, string(soup)),
+			testDetection(1, string(soup)),
+			testDetection(2, string(soupCase)),
+			testDetection(3, string(pear)),
+		}, nil
+	}
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "soup-003", "item_name": "Tomato Soup", "quantity": 14})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "pear-002", "item_name": "Bosc Pear", "quantity": 2})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "beans-010", "item_name": "Black Beans"})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "set_supplier_info", "item_id": "pear-002", "reorder_threshold": 2})
+
+	result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_stock_levels"})
+	if first := result["items"].([]interface{})[0].(map[string]interface{}); first["status"] != stockNotScanned {
+		t.Errorf("expected not_scanned before any scan, got: %v", first)
+	}
+
+	svc.scanAndCompare(ctx)
+	svc.scanAndCompare(ctx)
+
+	result = mustDoCommand(t, svc, map[string]interface{}{"command": "get_stock_levels"})
+	if result["count"] != 3 || result["total_units"] != 17 || result["low_stock"] != 1 || result["mismatched"] != 1 {
+		t.Fatalf("unexpected summary: %v", result)
+	}
+	items := result["items"].([]interface{})
+	want := []struct {
+		itemID string
+		status string
+	}{
+		{"beans-010", stockNotSeen},
+		{"pear-002", stockShort},
+		{"soup-003", stockMatched},
+	}
+	for i, w := range want {
+		level := items[i].(map[string]interface{})
+		if level["item_id"] != w.itemID || level["status"] != w.status {
+			t.Errorf("expected %s %s, got: %v", w.itemID, w.status, level)
+		}
+	}
+	if soupLevel := items[2].(map[string]interface{}); soupLevel["labels"] != 3 || soupLevel["detected_units"] != 14 {
+		t.Errorf("expected 3 labels standing for 14 units, got: %v", soupLevel)
+	}
+	if pearLevel := items[1].(map[string]interface{}); pearLevel["low_stock"] != true || pearLevel["difference"] != 1 {
+		t.Errorf("expected low pear stock one short, got: %v", pearLevel)
+	}
+}
+
+func TestGenerateQRQuantity(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+
+	result := mustDoCommand(t, svc, map[string]interface{}{"command": "generate_qr", "item_id": "soup-003", "item_name": "Tomato Soup", "quantity": 12})
+	var data ItemQRData
+	if err := json.Unmarshal([]byte(result["qr_data"].(string)), &data); err != nil {
+		t.Fatalf("expected JSON payload, got: %v", result["qr_data"])
+	}
+	if data.Quantity != 12 || result["quantity"] != 12 {
+		t.Errorf("expected a case label of 12, got payload %+v and response %v", data, result["quantity"])
+	}
+
+	if _, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "generate_qr", "item_id": "soup-003", "item_name": "Tomato Soup", "quantity": 0}); err == nil {
+		t.Error("expected error for quantity 0")
+	}
+}
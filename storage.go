@@ -0,0 +1,212 @@
+package inventorykeeper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// storageVersion identifies the layout of the storage_path file
+const storageVersion = 1
+
+// defaultStorageBackups is how many previous versions of the storage file
+// are kept when storage_backups is not configured
+const defaultStorageBackups = 3
+
+// storedInventory is the document written to storage_path
+type storedInventory struct {
+	Version   int              `json:"version"`
+	SavedAt   time.Time        `json:"saved_at"`
+	Inventory []*InventoryItem `json:"inventory"`
+	History   []HistoryEvent   `json:"history"`
+}
+
+// storageBackups returns how many rotated backups to keep, defaulting to 3
+func (cfg *Config) storageBackups() int {
+	if cfg.StorageBackups == nil {
+		return defaultStorageBackups
+	}
+	return *cfg.StorageBackups
+}
+
+// backupPath returns the path of the nth most recent backup (1 is newest)
+func backupPath(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// parseStoredInventory decodes and checks a storage file
+func parseStoredInventory(raw []byte) (*storedInventory, error) {
+	var stored storedInventory
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, err
+	}
+	if stored.Version != storageVersion {
+		return nil, fmt.Errorf("unsupported storage version %d", stored.Version)
+	}
+	for _, item := range stored.Inventory {
+		if item == nil || item.ItemID == "" {
+			return nil, errors.New("inventory item missing item_id")
+		}
+	}
+	return &stored, nil
+}
+
+// loadStoredInventory reads the storage file, falling back to the newest
+// readable backup if it is corrupt. A missing file means a fresh start.
+func loadStoredInventory(path string, backups int) (map[itemKey]*InventoryItem, []HistoryEvent, error) {
+	candidates := []string{path}
+	for n := 1; n <= backups; n++ {
+		candidates = append(candidates, backupPath(path, n))
+	}
+
+	var firstErr error
+	for _, candidate := range candidates {
+		// The main file can be missing if a save was interrupted mid-rotation
+		raw, err := os.ReadFile(candidate)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			var stored *storedInventory
+			if stored, err = parseStoredInventory(raw); err == nil {
+				inventory := make(map[itemKey]*InventoryItem, len(stored.Inventory))
+				for _, item := range stored.Inventory {
+					item.Namespace = normalizeNamespace(item.Namespace)
+					inventory[item.key()] = item
+				}
+				return inventory, stored.History, nil
+			}
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("failed to load storage_path %s: %w", candidate, err)
+		}
+	}
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	// Nothing saved yet
+	return make(map[itemKey]*InventoryItem), nil, nil
+}
+
+// writeFileSync writes data and flushes it to disk before returning
+func writeFileSync(name string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// markStorageDirtyLocked notes that inventory or history changed and must be
+// saved. Caller must hold inventoryMu for writing.
+func (s *inventoryKeeperKeeper) markStorageDirtyLocked() {
+	s.storeSync.dirty = true
+	s.replication.dirty = true
+	if s.config().StoragePath != "" {
+		s.storageDirty = true
+	}
+}
+
+// flushStorage writes changed items to the inventory store, then saves
+// inventory and history to storage_path if they changed. Failures are logged
+// and the state stays dirty so the next command retries.
+func (s *inventoryKeeperKeeper) flushStorage() {
+	cfg := s.config()
+
+	// storageMu orders saves, so an older state is never written over a newer one
+	s.storageMu.Lock()
+	defer s.storageMu.Unlock()
+
+	s.recordReplicationChanges()
+	s.syncStore()
+	if cfg.StoragePath == "" {
+		return
+	}
+	s.flushAlertState(cfg.StoragePath)
+
+	s.inventoryMu.Lock()
+	if !s.storageDirty {
+		s.inventoryMu.Unlock()
+		return
+	}
+	keys := make([]itemKey, 0, len(s.inventory))
+	for key := range s.inventory {
+		keys = append(keys, key)
+	}
+	sortItemKeys(keys)
+	stored := storedInventory{
+		Version:   storageVersion,
+		SavedAt:   s.now().UTC(),
+		Inventory: make([]*InventoryItem, 0, len(keys)),
+		History:   append([]HistoryEvent(nil), s.history...),
+	}
+	for _, key := range keys {
+		stored.Inventory = append(stored.Inventory, s.inventory[key].clone())
+	}
+	s.storageDirty = false
+	s.inventoryMu.Unlock()
+
+	if err := s.saveStoredInventory(cfg.StoragePath, cfg.storageBackups(), &stored); err != nil {
+		s.logger.Errorf("Failed to save inventory to %s, keeping the previous file: %v", cfg.StoragePath, err)
+		s.inventoryMu.Lock()
+		s.storageDirty = true
+		s.inventoryMu.Unlock()
+	}
+}
+
+// saveStoredInventory writes the document to a temporary file, re-reads and
+// parses it, then rotates backups and renames it into place. If verification
+// fails the previous file and backups are untouched.
+func (s *inventoryKeeperKeeper) saveStoredInventory(path string, backups int, stored *storedInventory) error {
+	raw, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := s.writeStorageFile(tmp, raw, 0o644); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+
+	written, err := os.ReadFile(tmp)
+	if err == nil {
+		var check *storedInventory
+		if check, err = parseStoredInventory(written); err == nil && len(check.Inventory) != len(stored.Inventory) {
+			err = fmt.Errorf("read back %d items, wrote %d", len(check.Inventory), len(stored.Inventory))
+		}
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("verification of %s failed: %w", tmp, err)
+	}
+
+	if backups > 0 {
+		// Shift path.(n-1) to path.n, dropping the oldest, then path to path.1
+		for n := backups - 1; n >= 1; n-- {
+			if err := os.Rename(backupPath(path, n), backupPath(path, n+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to rotate backups: %w", err)
+			}
+		}
+		if err := os.Rename(path, backupPath(path, 1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate backups: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
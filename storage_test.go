@@ -0,0 +1,106 @@
+package inventorykeeper
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	// savedItems returns how many items the storage file at path holds
+	savedItems := func(t *testing.T, path string) int {
+		t.Helper()
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		stored, err := parseStoredInventory(raw)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", path, err)
+		}
+		return len(stored.Inventory)
+	}
+	addItem := func(t *testing.T, svc *inventoryKeeperKeeper, n int) {
+		t.Helper()
+		id := "item-" + strconv.Itoa(n)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": id, "item_name": id})
+	}
+
+	t.Run("saves rotate a bounded set of backups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "inventory.json")
+		backups := 3
+		svc, _ := newTestKeeper(t, &Config{StoragePath: path, StorageBackups: &backups})
+
+		for n := 1; n <= 5; n++ {
+			addItem(t, svc, n)
+		}
+
+		if got := savedItems(t, path); got != 5 {
+			t.Errorf("expected 5 items in %s, got: %d", path, got)
+		}
+		for n := 1; n <= backups; n++ {
+			if got := savedItems(t, backupPath(path, n)); got != 5-n {
+				t.Errorf("expected backup .%d to hold %d items, got: %d", n, 5-n, got)
+			}
+		}
+		if _, err := os.Stat(backupPath(path, backups+1)); !os.IsNotExist(err) {
+			t.Errorf("expected no backup beyond .%d, got: %v", backups, err)
+		}
+	})
+
+	t.Run("failed verification keeps the previous file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "inventory.json")
+		svc, _ := newTestKeeper(t, &Config{StoragePath: path})
+		addItem(t, svc, 1)
+		addItem(t, svc, 2)
+
+		// Simulate a torn write: only half the document reaches disk
+		svc.writeStorageFile = func(name string, data []byte, perm os.FileMode) error {
+			return writeFileSync(name, data[:len(data)/2], perm)
+		}
+		addItem(t, svc, 3)
+
+		if got := savedItems(t, path); got != 2 {
+			t.Errorf("expected previous file with 2 items, got: %d", got)
+		}
+		if got := savedItems(t, backupPath(path, 1)); got != 1 {
+			t.Errorf("expected backups untouched, .1 has %d items", got)
+		}
+
+		// The change is saved by the next command once writes succeed
+		svc.writeStorageFile = writeFileSync
+		mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})
+		if got := savedItems(t, path); got != 3 {
+			t.Errorf("expected retried save with 3 items, got: %d", got)
+		}
+	})
+
+	t.Run("inventory is loaded at startup, falling back to a backup", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "inventory.json")
+		svc, _ := newTestKeeper(t, &Config{StoragePath: path})
+		addItem(t, svc, 1)
+		addItem(t, svc, 2)
+
+		reloaded, _ := newTestKeeper(t, &Config{StoragePath: path})
+		if count := mustDoCommand(t, reloaded, map[string]interface{}{"command": "list_items"})["total_count"]; count != 2 {
+			t.Errorf("expected 2 items loaded, got: %v", count)
+		}
+
+		if err := os.WriteFile(path, []byte(`{"version": 1, "inventory": [`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		recovered, _ := newTestKeeper(t, &Config{StoragePath: path})
+		if count := mustDoCommand(t, recovered, map[string]interface{}{"command": "list_items"})["total_count"]; count != 1 {
+			t.Errorf("expected 1 item from backup .1, got: %v", count)
+		}
+	})
+
+	t.Run("negative storage_backups is rejected", func(t *testing.T) {
+		negative := -1
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", StorageBackups: &negative}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for negative storage_backups")
+		}
+	})
+}
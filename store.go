@@ -0,0 +1,345 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Inventory store backends
+const (
+	storeBackendMemory = "memory" // In process only
+	storeBackendFile   = "file"   // In process, saved to storage_path
+	storeBackendRedis  = "redis"  // A Redis hash shared across keepers
+	storeBackendBolt   = "bolt"   // An embedded bbolt database at db_path
+)
+
+// validStoreBackends lists the accepted store_backend values
+var validStoreBackends = map[string]bool{
+	storeBackendMemory: true,
+	storeBackendFile:   true,
+	storeBackendRedis:  true,
+	storeBackendBolt:   true,
+}
+
+// storeSyncTimeout bounds one sync of changed items to the store
+const storeSyncTimeout = 10 * time.Second
+
+// InventoryStore holds inventory items by namespace and item id. Commands
+// work on the keeper's in-memory map under inventoryMu, which is the whole
+// store for the memory and file backends. With an external store, List
+// seeds the map at startup and the items each command changed are written
+// through Put and Delete after it; a shared store is also read back before
+// every command and scan, so each keeper sees the others' changes.
+type InventoryStore interface {
+	// Get returns the item stored under key, with found false if there is none
+	Get(ctx context.Context, key itemKey) (item *InventoryItem, found bool, err error)
+	// Put stores the item under its key, replacing any previous version
+	Put(ctx context.Context, item *InventoryItem) error
+	// Delete removes the item under key; deleting a missing item is not an error
+	Delete(ctx context.Context, key itemKey) error
+	// List returns every stored item, sorted by namespace and item id
+	List(ctx context.Context) ([]*InventoryItem, error)
+	// Close releases the store's connections
+	Close() error
+}
+
+// storeBackend returns the configured backend, defaulting to "bolt" when
+// db_path is set, "file" when storage_path is set, and "memory" otherwise
+func (cfg *Config) storeBackend() string {
+	if cfg.StoreBackend != "" {
+		return cfg.StoreBackend
+	}
+	if cfg.DBPath != "" {
+		return storeBackendBolt
+	}
+	if cfg.StoragePath != "" {
+		return storeBackendFile
+	}
+	return storeBackendMemory
+}
+
+// validateStoreBackend checks store_backend against the settings it needs
+func (cfg *Config) validateStoreBackend() error {
+	if cfg.StoreBackend != "" && !validStoreBackends[cfg.StoreBackend] {
+		return fmt.Errorf("store_backend must be %q, %q, %q, or %q, got: %q", storeBackendMemory, storeBackendFile, storeBackendRedis, storeBackendBolt, cfg.StoreBackend)
+	}
+	if cfg.DBPath != "" && cfg.storeBackend() != storeBackendBolt {
+		return fmt.Errorf("db_path is only used by store_backend %q, got store_backend %q", storeBackendBolt, cfg.StoreBackend)
+	}
+	switch cfg.storeBackend() {
+	case storeBackendFile:
+		if cfg.StoragePath == "" {
+			return fmt.Errorf("store_backend %q requires storage_path", storeBackendFile)
+		}
+	case storeBackendRedis:
+		if cfg.RedisAddress == "" {
+			return fmt.Errorf("store_backend %q requires redis_address", storeBackendRedis)
+		}
+		if cfg.RedisDB < 0 {
+			return fmt.Errorf("redis_db must be non-negative, got: %d", cfg.RedisDB)
+		}
+	case storeBackendBolt:
+		if cfg.DBPath == "" {
+			return fmt.Errorf("store_backend %q requires db_path", storeBackendBolt)
+		}
+	}
+	return nil
+}
+
+// storeLocation describes where an external store keeps items, for logs
+// and errors
+func (cfg *Config) storeLocation() string {
+	if cfg.storeBackend() == storeBackendRedis {
+		return "redis at " + cfg.RedisAddress
+	}
+	return cfg.DBPath
+}
+
+// storeShared reports whether other keepers may write to the configured
+// store, so it must be read back before each command and scan
+func (cfg *Config) storeShared() bool {
+	return cfg.storeBackend() == storeBackendRedis
+}
+
+// newInventoryStore builds the external store for the configured backend,
+// or returns nil for the memory and file backends: their items live only in
+// the keeper's own map, which flushStorage saves to storage_path along with
+// history.
+func newInventoryStore(cfg *Config) (InventoryStore, error) {
+	switch cfg.storeBackend() {
+	case storeBackendRedis:
+		return newRedisStore(newRESPClient(cfg.RedisAddress, cfg.RedisPassword, cfg.RedisDB), cfg.redisKey()), nil
+	case storeBackendBolt:
+		store, err := openBoltStore(cfg.DBPath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	return nil, nil
+}
+
+// memoryStore is an in-process InventoryStore, the reference the store
+// contract is tested against. Items are copied in and out so callers can't
+// mutate stored state.
+type memoryStore struct {
+	mu    sync.RWMutex
+	items map[itemKey]*InventoryItem
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{items: make(map[itemKey]*InventoryItem)}
+}
+
+// Get implements InventoryStore
+func (m *memoryStore) Get(ctx context.Context, key itemKey) (*InventoryItem, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, found := m.items[key]
+	return item.clone(), found, nil
+}
+
+// Put implements InventoryStore
+func (m *memoryStore) Put(ctx context.Context, item *InventoryItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[item.key()] = item.clone()
+	return nil
+}
+
+// Delete implements InventoryStore
+func (m *memoryStore) Delete(ctx context.Context, key itemKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+	return nil
+}
+
+// List implements InventoryStore
+func (m *memoryStore) List(ctx context.Context) ([]*InventoryItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]itemKey, 0, len(m.items))
+	for key := range m.items {
+		keys = append(keys, key)
+	}
+	sortItemKeys(keys)
+	items := make([]*InventoryItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, m.items[key].clone())
+	}
+	return items, nil
+}
+
+// Close implements InventoryStore
+func (m *memoryStore) Close() error { return nil }
+
+// storeSyncState remembers what the store last accepted for each item, so
+// only changed items are written
+type storeSyncState struct {
+	dirty  bool               // Inventory changed since the last sync
+	synced map[itemKey][]byte // Encoded item as last written
+}
+
+// seedStore records the startup inventory as already stored when it was
+// loaded from the store; otherwise the first sync writes all of it. Without
+// an external store there is nothing to track. Called before the keeper is
+// shared.
+func (s *inventoryKeeperKeeper) seedStore(fromStore bool) {
+	if s.store == nil {
+		return
+	}
+	s.storeSync.synced = make(map[itemKey][]byte, len(s.inventory))
+	if !fromStore {
+		s.storeSync.dirty = len(s.inventory) > 0
+		return
+	}
+	for key, item := range s.inventory {
+		if encoded, err := json.Marshal(item); err == nil {
+			s.storeSync.synced[key] = encoded
+		}
+	}
+}
+
+// syncStore writes items changed since the last sync to the store and
+// deletes removed ones. Failed writes are logged and retried on the next
+// sync. Caller must hold storageMu.
+func (s *inventoryKeeperKeeper) syncStore() {
+	if s.store == nil {
+		return
+	}
+
+	s.inventoryMu.Lock()
+	if !s.storeSync.dirty {
+		s.inventoryMu.Unlock()
+		return
+	}
+	puts := map[itemKey][]byte{}
+	var putItems []*InventoryItem
+	for key, item := range s.inventory {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			s.logger.Errorf("Failed to encode item %s for the store: %v", key.ItemID, err)
+			continue
+		}
+		if string(encoded) != string(s.storeSync.synced[key]) {
+			puts[key] = encoded
+			putItems = append(putItems, item.clone())
+		}
+	}
+	var deletes []itemKey
+	for key := range s.storeSync.synced {
+		if _, exists := s.inventory[key]; !exists {
+			deletes = append(deletes, key)
+		}
+	}
+	s.storeSync.dirty = false
+	s.inventoryMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeSyncTimeout)
+	defer cancel()
+
+	var failed error
+	done := map[itemKey][]byte{}
+	for _, item := range putItems {
+		if err := s.store.Put(ctx, item); err != nil {
+			failed = err
+			continue
+		}
+		done[item.key()] = puts[item.key()]
+	}
+	var deleted []itemKey
+	for _, key := range deletes {
+		if err := s.store.Delete(ctx, key); err != nil {
+			failed = err
+			continue
+		}
+		deleted = append(deleted, key)
+	}
+
+	s.inventoryMu.Lock()
+	for key, encoded := range done {
+		s.storeSync.synced[key] = encoded
+	}
+	for _, key := range deleted {
+		delete(s.storeSync.synced, key)
+	}
+	if failed != nil {
+		s.storeSync.dirty = true
+	}
+	s.inventoryMu.Unlock()
+
+	if failed != nil {
+		s.logger.Errorf("Failed to sync inventory to the %s store, will retry: %v", s.config().storeBackend(), failed)
+	}
+}
+
+// refreshFromStore reads a shared store back into the inventory, so adds,
+// updates, and removals by other keepers are seen before the next command
+// or scan. Local changes are written first; any still unwritten after a
+// failed sync are kept over the stored versions. Failures are logged and
+// the local copy is used until the next refresh.
+func (s *inventoryKeeperKeeper) refreshFromStore(ctx context.Context) {
+	if s.store == nil || !s.config().storeShared() {
+		return
+	}
+
+	s.storageMu.Lock()
+	defer s.storageMu.Unlock()
+	s.syncStore()
+
+	ctx, cancel := context.WithTimeout(ctx, storeSyncTimeout)
+	defer cancel()
+	items, err := s.store.List(ctx)
+	if err != nil {
+		s.logger.Warnf("Failed to refresh inventory from the %s store, using the local copy: %v", s.config().storeBackend(), err)
+		return
+	}
+	stored := make(map[itemKey]*InventoryItem, len(items))
+	for _, item := range items {
+		stored[item.key()] = item
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	// unwritten reports whether the local item differs from what this keeper
+	// last synced, so the store's version must not replace it
+	unwritten := func(key itemKey) bool {
+		synced, wasSynced := s.storeSync.synced[key]
+		local, exists := s.inventory[key]
+		if !exists {
+			return wasSynced
+		}
+		encoded, err := json.Marshal(local)
+		return err != nil || string(encoded) != string(synced)
+	}
+
+	changed := false
+	for key, item := range stored {
+		encoded, err := json.Marshal(item)
+		if err != nil || string(encoded) == string(s.storeSync.synced[key]) || unwritten(key) {
+			continue
+		}
+		s.inventory[key] = item
+		s.storeSync.synced[key] = encoded
+		changed = true
+	}
+	for key := range s.storeSync.synced {
+		if _, kept := stored[key]; kept || unwritten(key) {
+			continue
+		}
+		delete(s.inventory, key)
+		delete(s.storeSync.synced, key)
+		changed = true
+	}
+	if changed {
+		s.replication.dirty = true
+		if s.config().StoragePath != "" {
+			s.storageDirty = true
+		}
+	}
+}
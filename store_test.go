@@ -0,0 +1,279 @@
+package inventorykeeper
+
+import (
+	"bufio"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeRedis answers the hash commands redisStore sends from an in-memory map
+type fakeRedis struct {
+	hashes map[string]map[string]string
+	closed bool
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{hashes: map[string]map[string]string{}}
+}
+
+func (f *fakeRedis) Do(ctx context.Context, args ...string) (interface{}, error) {
+	hash := f.hashes[args[1]]
+	if hash == nil {
+		hash = map[string]string{}
+		f.hashes[args[1]] = hash
+	}
+	switch strings.ToUpper(args[0]) {
+	case "HSET":
+		hash[args[2]] = args[3]
+		return int64(1), nil
+	case "HGET":
+		value, ok := hash[args[2]]
+		if !ok {
+			return nil, nil
+		}
+		return value, nil
+	case "HDEL":
+		delete(hash, args[2])
+		return int64(1), nil
+	case "HGETALL":
+		var reply []interface{}
+		for field, value := range hash {
+			reply = append(reply, field, value)
+		}
+		return reply, nil
+	}
+	return nil, redisError("ERR unknown command " + args[0])
+}
+
+func (f *fakeRedis) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestInventoryStoreContract(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]func(t *testing.T) InventoryStore{
+		"memory": func(t *testing.T) InventoryStore { return newMemoryStore() },
+		"redis":  func(t *testing.T) InventoryStore { return newRedisStore(newFakeRedis(), defaultRedisKey) },
+		"bolt": func(t *testing.T) InventoryStore {
+			store, err := openBoltStore(filepath.Join(t.TempDir(), "inventory.db"))
+			if err != nil {
+				t.Fatalf("openBoltStore: %v", err)
+			}
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			defer store.Close()
+
+			apple := &InventoryItem{ItemID: "apple-001", ItemName: "Apple", Quantity: 3}
+			pear := &InventoryItem{ItemID: "pear-002", ItemName: "Pear", Namespace: "warehouse"}
+			for _, item := range []*InventoryItem{pear, apple} {
+				if err := store.Put(ctx, item); err != nil {
+					t.Fatalf("Put %s: %v", item.ItemID, err)
+				}
+			}
+
+			got, found, err := store.Get(ctx, apple.key())
+			if err != nil || !found {
+				t.Fatalf("Get apple: found=%v err=%v", found, err)
+			}
+			if got.ItemName != "Apple" || got.Quantity != 3 {
+				t.Errorf("Get apple = %+v", got)
+			}
+			if _, found, _ := store.Get(ctx, itemKey{ItemID: "missing"}); found {
+				t.Error("Get of a missing item should not be found")
+			}
+
+			items, err := store.List(ctx)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(items) != 2 || items[0].ItemID != "apple-001" || items[1].ItemID != "pear-002" {
+				t.Errorf("List should return apple then pear, got %v", items)
+			}
+
+			if err := store.Delete(ctx, apple.key()); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if err := store.Delete(ctx, apple.key()); err != nil {
+				t.Errorf("Deleting a missing item should succeed, got: %v", err)
+			}
+			if _, found, _ := store.Get(ctx, apple.key()); found {
+				t.Error("apple should be gone after Delete")
+			}
+		})
+	}
+}
+
+func TestReadRESPReply(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("+OK\r\n:42\r\n$5\r\nhello\r\n$-1\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n-ERR nope\r\n"))
+
+	expect := []interface{}{"OK", int64(42), "hello", nil}
+	for _, want := range expect {
+		got, err := readRESPReply(r)
+		if err != nil || got != want {
+			t.Fatalf("readRESPReply = %v, %v; want %v", got, err, want)
+		}
+	}
+	arr, err := readRESPReply(r)
+	if elems, ok := arr.([]interface{}); err != nil || !ok || len(elems) != 2 || elems[0] != "a" || elems[1] != "b" {
+		t.Errorf("array reply = %v, %v", arr, err)
+	}
+	if _, err := readRESPReply(r); err == nil || !strings.Contains(err.Error(), "ERR nope") {
+		t.Errorf("error reply should surface as an error, got: %v", err)
+	}
+}
+
+// useRedisStore points a test keeper at a fake Redis as if it had been
+// configured with store_backend "redis"
+func useRedisStore(svc *inventoryKeeperKeeper, redis *fakeRedis) {
+	cfg := *svc.config()
+	cfg.StoreBackend, cfg.RedisAddress = storeBackendRedis, "redis.test:6379"
+	svc.cfgMu.Lock()
+	svc.cfg = &cfg
+	svc.cfgMu.Unlock()
+
+	svc.store = newRedisStore(redis, defaultRedisKey)
+	svc.seedStore(true)
+}
+
+func TestStoreSyncsAfterCommands(t *testing.T) {
+	svc, _ := newTestKeeper(t, nil)
+	redis := newFakeRedis()
+	useRedisStore(svc, redis)
+
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+	mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "pear-002", "item_name": "Pear"})
+	if got := len(redis.hashes[defaultRedisKey]); got != 2 {
+		t.Fatalf("store should hold 2 items after adds, got %d", got)
+	}
+
+	mustDoCommand(t, svc, map[string]interface{}{"command": "remove_item", "item_id": "apple-001"})
+	items, err := svc.store.List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(items) != 1 || items[0].ItemID != "pear-002" {
+		t.Errorf("store should hold only pear after remove, got %v", items)
+	}
+}
+
+func TestSharedStore(t *testing.T) {
+	redis := newFakeRedis()
+	first, _ := newTestKeeper(t, nil)
+	second, _ := newTestKeeper(t, nil)
+	useRedisStore(first, redis)
+	useRedisStore(second, redis)
+
+	t.Run("each keeper sees the other's adds, updates, and removals", func(t *testing.T) {
+		mustDoCommand(t, first, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+		item := mustDoCommand(t, second, map[string]interface{}{"command": "get_item", "item_id": "apple-001"})
+		if item["item_name"] != "Apple" {
+			t.Fatalf("expected the second keeper to see the added apple, got: %v", item)
+		}
+
+		mustDoCommand(t, second, map[string]interface{}{"command": "update_item", "item_id": "apple-001", "quantity": 7.0})
+		if item := mustDoCommand(t, first, map[string]interface{}{"command": "get_item", "item_id": "apple-001"}); item["quantity"] != 7 {
+			t.Errorf("expected the first keeper to see the update, got: %v", item)
+		}
+
+		mustDoCommand(t, first, map[string]interface{}{"command": "add_item", "item_id": "pear-002", "item_name": "Pear"})
+		mustDoCommand(t, first, map[string]interface{}{"command": "remove_item", "item_id": "apple-001"})
+		items := mustDoCommand(t, second, map[string]interface{}{"command": "list_items"})["items"].([]interface{})
+		if len(items) != 1 || items[0].(map[string]interface{})["item_id"] != "pear-002" {
+			t.Errorf("expected the second keeper to see only pear, got: %v", items)
+		}
+	})
+
+	t.Run("a change made outside a command is written before the refresh", func(t *testing.T) {
+		// As a scan would: changed in place, saved by the next command
+		first.inventoryMu.Lock()
+		first.inventory[keyFor("", "pear-002")].Location = "aisle-2"
+		first.markStorageDirtyLocked()
+		first.inventoryMu.Unlock()
+
+		if item := mustDoCommand(t, first, map[string]interface{}{"command": "get_item", "item_id": "pear-002"}); item["location"] != "aisle-2" {
+			t.Errorf("expected the local change to survive the refresh, got: %v", item)
+		}
+		if item := mustDoCommand(t, second, map[string]interface{}{"command": "get_item", "item_id": "pear-002"}); item["location"] != "aisle-2" {
+			t.Errorf("expected the local change to reach the other keeper, got: %v", item)
+		}
+	})
+}
+
+func TestBoltStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "inventory.db")
+
+	t.Run("inventory survives a restart", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{DBPath: dbPath})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Apple"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "update_item", "item_id": "apple-001", "quantity": 5.0})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "pear-002", "item_name": "Pear"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "delete_item", "item_id": "pear-002"})
+		svc.Close(ctx)
+
+		restarted, _ := newTestKeeper(t, &Config{DBPath: dbPath})
+		item := mustDoCommand(t, restarted, map[string]interface{}{"command": "get_item", "item_id": "apple-001"})
+		if item["quantity"] != 5 {
+			t.Errorf("expected apple with quantity 5 after restart, got: %v", item)
+		}
+		if _, err := restarted.DoCommand(ctx, map[string]interface{}{"command": "get_item", "item_id": "pear-002"}); err == nil {
+			t.Error("expected the deleted pear to stay deleted")
+		}
+		restarted.Close(ctx)
+	})
+
+	t.Run("a new database starts from storage_path", func(t *testing.T) {
+		storagePath := filepath.Join(dir, "inventory.json")
+		svc, _ := newTestKeeper(t, &Config{StoragePath: storagePath})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "fig-003", "item_name": "Fig"})
+		svc.Close(ctx)
+
+		migrated, _ := newTestKeeper(t, &Config{StoragePath: storagePath, DBPath: filepath.Join(dir, "migrated.db")})
+		mustDoCommand(t, migrated, map[string]interface{}{"command": "list_items"})
+		items, err := migrated.store.List(ctx)
+		if err != nil || len(items) != 1 || items[0].ItemID != "fig-003" {
+			t.Errorf("expected fig imported into the database, got %v (%v)", items, err)
+		}
+	})
+}
+
+func TestStoreBackendValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"default memory", Config{}, ""},
+		{"unknown backend", Config{StoreBackend: "mongo"}, "store_backend must be"},
+		{"file without path", Config{StoreBackend: "file"}, "requires storage_path"},
+		{"redis without address", Config{StoreBackend: "redis"}, "requires redis_address"},
+		{"redis negative db", Config{StoreBackend: "redis", RedisAddress: "localhost:6379", RedisDB: -1}, "redis_db must be non-negative"},
+		{"redis ok", Config{StoreBackend: "redis", RedisAddress: "localhost:6379"}, ""},
+		{"bolt without path", Config{StoreBackend: "bolt"}, "requires db_path"},
+		{"db_path picks bolt", Config{DBPath: "/data/inventory.db"}, ""},
+		{"db_path with another backend", Config{StoreBackend: "redis", RedisAddress: "localhost:6379", DBPath: "/data/inventory.db"}, "db_path is only used"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validateStoreBackend()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
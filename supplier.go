@@ -0,0 +1,221 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// applySupplierArgs sets the supplier and reorder fields given in cmd on the
+// item, leaving the rest alone. Returns the names of the fields set.
+func applySupplierArgs(cmd map[string]interface{}, item *InventoryItem) ([]string, error) {
+	var set []string
+	for _, field := range []struct {
+		name   string
+		target *string
+	}{
+		{"supplier", &item.Supplier},
+		{"supplier_sku", &item.SupplierSKU},
+	} {
+		raw, ok := cmd[field.name]
+		if !ok {
+			continue
+		}
+		value, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", field.name)
+		}
+		*field.target = value
+		set = append(set, field.name)
+	}
+
+	for _, field := range []struct {
+		name   string
+		target *int
+	}{
+		{"reorder_quantity", &item.ReorderQuantity},
+		{"reorder_threshold", &item.ReorderThreshold},
+		{"min_quantity", &item.MinQuantity},
+	} {
+		value, ok, err := intArg(cmd, field.name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if value < 1 {
+			return nil, fmt.Errorf("%s must be positive, got: %d", field.name, value)
+		}
+		*field.target = value
+		set = append(set, field.name)
+	}
+	return set, nil
+}
+
+// atReorderPoint reports whether the item has a reorder threshold and its
+// quantity is at or below it
+func (item *InventoryItem) atReorderPoint() bool {
+	return item.ReorderThreshold > 0 && item.Quantity <= item.ReorderThreshold
+}
+
+// recommendedReorder is how many units to order: the item's reorder_quantity,
+// or else enough to bring it back above its threshold
+func (item *InventoryItem) recommendedReorder() int {
+	if item.ReorderQuantity > 0 {
+		return item.ReorderQuantity
+	}
+	return item.ReorderThreshold - item.Quantity + 1
+}
+
+// reorderMap describes what to order for an item at its reorder point
+func (item *InventoryItem) reorderMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"namespace":            item.Namespace,
+		"item_id":              item.ItemID,
+		"item_name":            item.ItemName,
+		"quantity":             item.Quantity,
+		"reorder_threshold":    item.ReorderThreshold,
+		"recommended_quantity": item.recommendedReorder(),
+	}
+	if item.Supplier != "" {
+		m["supplier"] = item.Supplier
+	}
+	if item.SupplierSKU != "" {
+		m["supplier_sku"] = item.SupplierSKU
+	}
+	return m
+}
+
+// lowStockCopy returns a copy of the item if a quantity change from previous
+// just brought it to its reorder point, or nil otherwise
+func lowStockCopy(item *InventoryItem, previous int) *InventoryItem {
+	if !item.atReorderPoint() || previous <= item.ReorderThreshold {
+		return nil
+	}
+	return item.clone()
+}
+
+// alertLowStock raises a low_stock alert for an item that just reached its
+// reorder point. A nil item is ignored. Must be called without inventoryMu held.
+func (s *inventoryKeeperKeeper) alertLowStock(item *InventoryItem) {
+	if item == nil {
+		return
+	}
+	s.raiseAlert("low_stock", severityWarning, item.ItemID,
+		fmt.Sprintf("Item %s (%s) is down to %d, reorder %d", item.ItemID, item.ItemName, item.Quantity, item.recommendedReorder()),
+		item.reorderMap())
+}
+
+// handleSetSupplierInfo updates an item's supplier, SKU, reorder quantity,
+// reorder threshold, and minimum shelf quantity. Only the fields given change; strings can be
+// cleared with "".
+func (s *inventoryKeeperKeeper) handleSetSupplierInfo(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	key := keyFor(namespace, itemID)
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	item, exists := s.inventory[key]
+	if !exists {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	updated := item.clone()
+	set, err := applySupplierArgs(cmd, updated)
+	if err != nil {
+		return nil, err
+	}
+	if len(set) == 0 {
+		return nil, errors.New("at least one of supplier, supplier_sku, reorder_quantity, reorder_threshold, or min_quantity is required")
+	}
+
+	s.pushUndoLocked("set_supplier_info", key, item)
+	updated.UpdatedAt = s.now()
+	s.inventory[key] = updated
+
+	result := updated.toMap(s.location)
+	details := map[string]interface{}{}
+	for _, field := range set {
+		details[field] = result[field]
+	}
+	s.recordHistoryLocked("supplier_info_set", namespace, itemID, details)
+
+	s.logger.Infof("Updated supplier info for %s: %v", itemID, set)
+	return result, nil
+}
+
+// handleGetReorderList returns the namespace's items at or below their
+// reorder threshold, or seen on the shelf below their min_quantity, with what
+// to order, sorted by supplier then item_id. An optional supplier narrows
+// the list to one supplier.
+func (s *inventoryKeeperKeeper) handleGetReorderList(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	supplier, err := optionalStringArg(cmd, "supplier")
+	if err != nil {
+		return nil, err
+	}
+
+	s.monitorMu.Lock()
+	shelf, scanned := s.shelfCountsLocked(), !s.lastScanAt.IsZero()
+	s.monitorMu.Unlock()
+
+	s.inventoryMu.RLock()
+	var due []*InventoryItem
+	for _, item := range s.inventory {
+		if item.Namespace != namespace {
+			continue
+		}
+		if !item.atReorderPoint() && !(scanned && item.belowMinQuantity(shelf[item.key()])) {
+			continue
+		}
+		if supplier != "" && item.Supplier != supplier {
+			continue
+		}
+		due = append(due, item.clone())
+	}
+	s.inventoryMu.RUnlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].Supplier != due[j].Supplier {
+			return due[i].Supplier < due[j].Supplier
+		}
+		return due[i].ItemID < due[j].ItemID
+	})
+
+	items := make([]interface{}, 0, len(due))
+	total := 0
+	for _, item := range due {
+		entry := item.reorderMap()
+		recommended := item.recommendedReorder()
+		if item.MinQuantity > 0 && scanned {
+			count := shelf[item.key()]
+			entry["min_quantity"] = item.MinQuantity
+			entry["shelf_count"] = count
+			if !item.atReorderPoint() {
+				recommended = item.shelfReorder(count)
+				entry["recommended_quantity"] = recommended
+			}
+		}
+		items = append(items, entry)
+		total += recommended
+	}
+	return map[string]interface{}{
+		"namespace":   namespace,
+		"items":       items,
+		"count":       len(items),
+		"total_units": total,
+	}, nil
+}
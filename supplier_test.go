@@ -0,0 +1,96 @@
+package inventorykeeper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSupplierInfo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("low-stock item surfaces in the reorder list with supplier details", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "bolt-001", "item_name": "M6 Bolt", "quantity": 20,
+			"supplier": "Fastenal", "supplier_sku": "FA-1138", "reorder_quantity": 100, "reorder_threshold": 5,
+		})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "nut-001", "item_name": "M6 Nut", "quantity": 50})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_supplier_info", "item_id": "nut-001", "supplier": "Grainger", "reorder_threshold": 10})
+
+		if count := mustDoCommand(t, svc, map[string]interface{}{"command": "get_reorder_list"})["count"]; count != 0 {
+			t.Fatalf("expected nothing to reorder while stocked, got: %v", count)
+		}
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "bolt-001", "delta": -16})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_quantity", "item_id": "nut-001", "quantity": 7})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "get_reorder_list"})
+		items := result["items"].([]interface{})
+		if len(items) != 2 {
+			t.Fatalf("expected 2 items to reorder, got: %v", items)
+		}
+		bolt := items[0].(map[string]interface{})
+		if bolt["item_id"] != "bolt-001" || bolt["supplier"] != "Fastenal" || bolt["supplier_sku"] != "FA-1138" || bolt["quantity"] != 4 || bolt["recommended_quantity"] != 100 {
+			t.Errorf("unexpected bolt entry: %v", bolt)
+		}
+		nut := items[1].(map[string]interface{})
+		if nut["item_id"] != "nut-001" || nut["supplier"] != "Grainger" || nut["recommended_quantity"] != 4 {
+			t.Errorf("expected nut topped back above its threshold, got: %v", nut)
+		}
+		if result["total_units"] != 104 {
+			t.Errorf("expected 104 total units, got: %v", result["total_units"])
+		}
+
+		filtered := mustDoCommand(t, svc, map[string]interface{}{"command": "get_reorder_list", "supplier": "Grainger"})
+		if filtered["count"] != 1 {
+			t.Errorf("expected supplier filter to leave 1 item, got: %v", filtered["items"])
+		}
+	})
+
+	t.Run("reaching the reorder point raises one low_stock alert", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command": "add_item", "item_id": "bolt-001", "item_name": "M6 Bolt", "quantity": 10,
+			"supplier": "Fastenal", "supplier_sku": "FA-1138", "reorder_quantity": 100, "reorder_threshold": 5,
+		})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "bolt-001", "delta": -5})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "adjust_quantity", "item_id": "bolt-001", "delta": -1})
+
+		alerts := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{})
+		if len(alerts) != 1 {
+			t.Fatalf("expected one low_stock alert, got: %v", alerts)
+		}
+		alert := alerts[0].(map[string]interface{})
+		details := alert["details"].(map[string]interface{})
+		if alert["type"] != "low_stock" || details["supplier"] != "Fastenal" || details["supplier_sku"] != "FA-1138" || details["recommended_quantity"] != 100 {
+			t.Errorf("expected low_stock alert with supplier details, got: %v", alert)
+		}
+	})
+
+	t.Run("non-positive reorder_quantity is rejected", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "bolt-001", "item_name": "M6 Bolt"})
+		for _, cmd := range []map[string]interface{}{
+			{"command": "add_item", "item_id": "nut-001", "item_name": "M6 Nut", "reorder_quantity": 0},
+			{"command": "set_supplier_info", "item_id": "bolt-001", "reorder_quantity": -3},
+			{"command": "set_supplier_info", "item_id": "bolt-001"},
+			{"command": "set_supplier_info", "item_id": "bolt-001", "supplier": 7},
+		} {
+			if _, err := svc.DoCommand(ctx, cmd); err == nil {
+				t.Errorf("expected error for %v", cmd)
+			}
+		}
+	})
+
+	t.Run("set_supplier_info can be undone", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "bolt-001", "item_name": "M6 Bolt", "supplier": "Fastenal"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "set_supplier_info", "item_id": "bolt-001", "supplier": "Grainger"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "undo"})
+
+		item := mustDoCommand(t, svc, map[string]interface{}{"command": "list_items"})["items"].([]interface{})[0].(map[string]interface{})
+		if item["supplier"] != "Fastenal" {
+			t.Errorf("expected supplier restored to Fastenal, got: %v", item["supplier"])
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	dmencoder "github.com/makiuchi-d/gozxing/datamatrix/encoder"
+	zxingqr "github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/skip2/go-qrcode"
+)
+
+// Symbologies generate_qr can render. The ItemQRData payload is the same for both.
+const (
+	codeTypeQR         = "qr"
+	codeTypeDataMatrix = "datamatrix"
+)
+
+// defaultDataMatrixBorder is the quiet zone (in modules) around a Data Matrix
+// symbol; the spec minimum is one module
+const defaultDataMatrixBorder = 2
+
+// Image formats item codes can be rendered as
+const (
+	codeFormatPNG = "png"
+	codeFormatSVG = "svg"
+)
+
+// Rendered code side lengths in pixels: the default, and the bounds on a
+// requested size
+const (
+	defaultCodeSize = 256
+	minCodeSize     = 64
+	maxCodeSize     = 4096
+)
+
+// qrRecoveryLevels maps error_correction letters to QR recovery levels,
+// which restore roughly 7%, 15%, 25%, and 30% of a damaged code
+var qrRecoveryLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// codeRenderOptions controls how an item's payload is drawn
+type codeRenderOptions struct {
+	CodeType        string
+	Border          int
+	HasBorder       bool   // Border was given; otherwise each symbology's default is used
+	Size            int    // Image side length in pixels; 0 keeps the default sizing
+	Format          string // codeFormatPNG or codeFormatSVG; empty means PNG
+	ErrorCorrection string // QR recovery level letter; empty means M
+}
+
+// codeRenderArgs parses the optional code_type, border (quiet zone width in
+// modules), size (pixels), format, and error_correction shared by commands
+// that render item codes
+func codeRenderArgs(cmd map[string]interface{}) (codeRenderOptions, error) {
+	border, hasBorder, err := intArg(cmd, "border")
+	if err != nil {
+		return codeRenderOptions{}, err
+	}
+	if border < 0 {
+		return codeRenderOptions{}, fmt.Errorf("border must be non-negative, got: %d", border)
+	}
+	codeType, err := codeTypeArg(cmd)
+	if err != nil {
+		return codeRenderOptions{}, err
+	}
+
+	size, hasSize, err := intArg(cmd, "size")
+	if err != nil {
+		return codeRenderOptions{}, err
+	}
+	if hasSize && (size < minCodeSize || size > maxCodeSize) {
+		return codeRenderOptions{}, fmt.Errorf("size must be between %d and %d pixels, got: %d", minCodeSize, maxCodeSize, size)
+	}
+
+	format, err := optionalStringArg(cmd, "format")
+	if err != nil {
+		return codeRenderOptions{}, err
+	}
+	format = strings.ToLower(format)
+	switch format {
+	case "":
+		format = codeFormatPNG
+	case codeFormatPNG, codeFormatSVG:
+	default:
+		return codeRenderOptions{}, fmt.Errorf("format must be %q or %q, got: %q", codeFormatPNG, codeFormatSVG, format)
+	}
+
+	level, err := optionalStringArg(cmd, "error_correction")
+	if err != nil {
+		return codeRenderOptions{}, err
+	}
+	level = strings.ToUpper(level)
+	if level != "" {
+		if codeType != codeTypeQR {
+			return codeRenderOptions{}, fmt.Errorf("error_correction applies only to QR codes; %s uses a fixed level", codeType)
+		}
+		if _, ok := qrRecoveryLevels[level]; !ok {
+			return codeRenderOptions{}, fmt.Errorf("error_correction must be L, M, Q, or H, got: %q", level)
+		}
+	}
+
+	return codeRenderOptions{
+		CodeType:        codeType,
+		Border:          border,
+		HasBorder:       hasBorder,
+		Size:            size,
+		Format:          format,
+		ErrorCorrection: level,
+	}, nil
+}
+
+// imageFormat returns the format codes are rendered as
+func (opts codeRenderOptions) imageFormat() string {
+	if opts.Format == "" {
+		return codeFormatPNG
+	}
+	return opts.Format
+}
+
+// responseFormat describes rendered codes in command results
+func (opts codeRenderOptions) responseFormat() string {
+	return "base64-" + opts.imageFormat()
+}
+
+// recoveryLevel returns the QR recovery level, defaulting to M
+func (opts codeRenderOptions) recoveryLevel() string {
+	if opts.ErrorCorrection == "" {
+		return "M"
+	}
+	return opts.ErrorCorrection
+}
+
+// renderItemCode encodes the item payload (encrypted and signed as configured)
+// and renders it as a PNG or SVG. With a size, the image is that many pixels
+// square and modules stay whole pixels, any remainder widening the quiet
+// zone. Otherwise QR codes without a border are the standard 256x256 image,
+// and other codes size to their border. Returns the image, the payload, and
+// the image side length in pixels.
+func (s *inventoryKeeperKeeper) renderItemCode(data ItemQRData, opts codeRenderOptions) ([]byte, string, int, error) {
+	payload, err := s.encodeQRPayload(data)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	bitmap, defaultBorder, err := codeBitmap(payload, opts)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	border := defaultBorder
+	if opts.HasBorder {
+		border = opts.Border
+	}
+	modules := len(bitmap)
+	span := modules + 2*border
+
+	var moduleSize, side int
+	switch {
+	case opts.Size > 0:
+		if opts.Size < span {
+			return nil, "", 0, fmt.Errorf("size %d is too small for this code, which needs at least %d pixels", opts.Size, span)
+		}
+		moduleSize, side = opts.Size/span, opts.Size
+	case opts.CodeType != codeTypeDataMatrix && !opts.HasBorder:
+		moduleSize, side = max(1, defaultCodeSize/span), max(defaultCodeSize, span)
+	default:
+		// Modules are sized as if the default border filled the standard
+		// image, so a wider border produces a larger image
+		moduleSize = max(1, defaultCodeSize/(modules+2*defaultBorder))
+		side = span * moduleSize
+	}
+
+	if opts.imageFormat() == codeFormatSVG {
+		return codeSVG(bitmap, border, side), payload, side, nil
+	}
+	pngBytes, err := codePNG(bitmap, border, moduleSize, side)
+	return pngBytes, payload, side, err
+}
+
+// codeBitmap encodes content in the requested symbology. Returns its modules
+// (true is dark) without a quiet zone, and the symbology's default quiet zone
+// width in modules.
+func codeBitmap(content string, opts codeRenderOptions) ([][]bool, int, error) {
+	if opts.CodeType == codeTypeDataMatrix {
+		hints := map[gozxing.EncodeHintType]interface{}{
+			gozxing.EncodeHintType_DATA_MATRIX_SHAPE: dmencoder.SymbolShapeHint_FORCE_SQUARE,
+		}
+		// Zero dimensions render one pixel per module with no padding
+		symbol, err := datamatrix.NewDataMatrixWriter().Encode(content, gozxing.BarcodeFormat_DATA_MATRIX, 0, 0, hints)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to generate Data Matrix code: %w", err)
+		}
+		bitmap := make([][]bool, symbol.GetHeight())
+		for y := range bitmap {
+			bitmap[y] = make([]bool, symbol.GetWidth())
+			for x := range bitmap[y] {
+				bitmap[y][x] = symbol.Get(x, y)
+			}
+		}
+		return bitmap, defaultDataMatrixBorder, nil
+	}
+
+	q, err := qrcode.New(content, qrRecoveryLevels[opts.recoveryLevel()])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	q.DisableBorder = true
+	return q.Bitmap(), defaultQRBorder, nil
+}
+
+// codePNG draws a square bitmap with a quiet zone of border modules, each
+// moduleSize pixels, centered in a side x side PNG
+func codePNG(bitmap [][]bool, border, moduleSize, side int) ([]byte, error) {
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	inset := (side-(len(bitmap)+2*border)*moduleSize)/2 + border*moduleSize
+	offset := image.Pt(inset, inset)
+	for y, row := range bitmap {
+		for x, set := range row {
+			if !set {
+				continue
+			}
+			origin := offset.Add(image.Pt(x, y).Mul(moduleSize))
+			module := image.Rectangle{Min: origin, Max: origin.Add(image.Pt(moduleSize, moduleSize))}
+			draw.Draw(img, module, image.Black, image.Point{}, draw.Src)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode code image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// codeSVG draws a square bitmap with a quiet zone of border modules as an
+// SVG side pixels square. The viewBox is in modules, so the code scales to
+// any print size without blurring; each row's runs of dark modules are one
+// path segment.
+func codeSVG(bitmap [][]bool, border, side int) []byte {
+	span := len(bitmap) + 2*border
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, side, side, span, span)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, span, span)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			run := 1
+			for x+run < len(row) && row[x+run] {
+				run++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", x+border, y+border, run, run)
+			x += run - 1
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.Bytes()
+}
+
+// codeTypeArg returns the command's optional code_type, defaulting to QR
+func codeTypeArg(cmd map[string]interface{}) (string, error) {
+	codeType, err := optionalStringArg(cmd, "code_type")
+	if err != nil {
+		return "", err
+	}
+	switch codeType {
+	case "":
+		return codeTypeQR, nil
+	case codeTypeQR, codeTypeDataMatrix:
+		return codeType, nil
+	default:
+		return "", fmt.Errorf("code_type must be %q or %q, got: %q", codeTypeQR, codeTypeDataMatrix, codeType)
+	}
+}
+
+// decodeCodeImage reads the text of a single QR or Data Matrix code from an
+// image, returning which symbology it found
+func decodeCodeImage(img image.Image) (string, string, error) {
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to binarize image: %w", err)
+	}
+
+	qrResult, qrErr := zxingqr.NewQRCodeReader().Decode(bmp, nil)
+	if qrErr == nil {
+		return qrResult.GetText(), codeTypeQR, nil
+	}
+	dmResult, dmErr := datamatrix.NewDataMatrixReader().Decode(bmp, nil)
+	if dmErr == nil {
+		return dmResult.GetText(), codeTypeDataMatrix, nil
+	}
+	return "", "", fmt.Errorf("failed to decode QR or Data Matrix code: %w", qrErr)
+}
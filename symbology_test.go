@@ -0,0 +1,144 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDataMatrix(t *testing.T) {
+	t.Run("round-trips item fields", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+			"code_type": "datamatrix",
+		})
+		if result["code_type"] != codeTypeDataMatrix {
+			t.Errorf("expected code_type datamatrix, got: %v", result["code_type"])
+		}
+
+		img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(result["qr_code"].(string))))
+		if err != nil {
+			t.Fatalf("qr_code is not a PNG: %v", err)
+		}
+		if result["size"] != img.Bounds().Dx() {
+			t.Errorf("expected size %d to match image width, got: %v", img.Bounds().Dx(), result["size"])
+		}
+
+		content, codeType, err := decodeCodeImage(img)
+		if err != nil {
+			t.Fatalf("failed to decode Data Matrix image: %v", err)
+		}
+		if codeType != codeTypeDataMatrix {
+			t.Errorf("expected image recognized as datamatrix, got: %s", codeType)
+		}
+		data, err := svc.decodeQRPayload(content)
+		if err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if data.ItemID != "apple-001" || data.ItemName != "Honeycrisp Apple" {
+			t.Errorf("expected apple-001/Honeycrisp Apple, got: %s/%s", data.ItemID, data.ItemName)
+		}
+	})
+
+	t.Run("defaults to QR", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+		})
+		img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(result["qr_code"].(string))))
+		if err != nil {
+			t.Fatalf("qr_code is not a PNG: %v", err)
+		}
+		if _, codeType, err := decodeCodeImage(img); err != nil || codeType != codeTypeQR {
+			t.Errorf("expected image recognized as qr, got: %s (err %v)", codeType, err)
+		}
+	})
+
+	t.Run("invalid code_type returns error", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "apple-001",
+			"item_name": "Honeycrisp Apple",
+			"code_type": "aztec",
+		})
+		if err == nil {
+			t.Error("expected error for unsupported code_type")
+		}
+	})
+}
+
+func TestGenerateQRSizeAndFormat(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestKeeper(t, nil)
+	base := map[string]interface{}{"command": "generate_qr", "item_id": "apple-001", "item_name": "Honeycrisp Apple"}
+	with := func(extra map[string]interface{}) map[string]interface{} {
+		cmd := make(map[string]interface{}, len(base)+len(extra))
+		for k, v := range base {
+			cmd[k] = v
+		}
+		for k, v := range extra {
+			cmd[k] = v
+		}
+		return cmd
+	}
+
+	t.Run("PNG is the requested size and still scans", func(t *testing.T) {
+		for _, level := range []string{"L", "M", "Q", "h"} {
+			result := mustDoCommand(t, svc, with(map[string]interface{}{"size": 1000, "error_correction": level}))
+			if result["error_correction"] != strings.ToUpper(level) {
+				t.Errorf("expected error_correction %s, got: %v", strings.ToUpper(level), result["error_correction"])
+			}
+			img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(result["qr_code"].(string))))
+			if err != nil {
+				t.Fatalf("qr_code is not a PNG: %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != 1000 || b.Dy() != 1000 || result["size"] != 1000 {
+				t.Errorf("%s: expected 1000x1000, got %dx%d (size %v)", level, b.Dx(), b.Dy(), result["size"])
+			}
+			if content, _, err := decodeCodeImage(img); err != nil || content != result["qr_data"] {
+				t.Errorf("%s: expected the payload to scan back, got %q (%v)", level, content, err)
+			}
+		}
+	})
+
+	t.Run("SVG scales its modules to the size", func(t *testing.T) {
+		result := mustDoCommand(t, svc, with(map[string]interface{}{"format": "svg", "size": 512}))
+		if result["format"] != "base64-svg" {
+			t.Errorf("expected base64-svg, got: %v", result["format"])
+		}
+		raw, err := base64.StdEncoding.DecodeString(result["qr_code"].(string))
+		if err != nil {
+			t.Fatalf("qr_code is not base64: %v", err)
+		}
+		svg := string(raw)
+		if !strings.HasPrefix(svg, "<svg ") || !strings.Contains(svg, `width="512" height="512"`) || !strings.HasSuffix(svg, "</svg>") {
+			t.Errorf("unexpected SVG: %.120s", svg)
+		}
+	})
+
+	t.Run("bad options return errors", func(t *testing.T) {
+		for _, extra := range []map[string]interface{}{
+			{"size": 16},
+			{"size": 10000},
+			{"size": 64, "border": 20},
+			{"format": "gif"},
+			{"error_correction": "X"},
+			{"error_correction": "H", "code_type": "datamatrix"},
+		} {
+			if _, err := svc.DoCommand(ctx, with(extra)); err == nil {
+				t.Errorf("expected error for %v", extra)
+			}
+		}
+	})
+}
@@ -0,0 +1,377 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Detector event types
+const (
+	detectorEventCheckIn      = "check_in"      // Items authorized for removal
+	detectorEventItemRemoved  = "item_removed"  // Item left the debounced present-set
+	detectorEventItemReturned = "item_returned" // Item pending as a theft came back into the present-set
+	detectorEventTick         = "tick"          // Time passed; pending removals may become thefts
+)
+
+// detectorEvent is a single input to the theft detector. Events are plain
+// data so they can be logged and replayed through a fresh detector. All
+// items in one event share a namespace; empty means the default.
+type detectorEvent struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace,omitempty"`
+	ItemIDs   []string  `json:"item_ids"`
+	Person    string    `json:"person,omitempty"` // Who checked in, or the person allowed in the namespace recognized at a removal
+}
+
+// theftFinding is a removal the detector considers unauthorized
+type theftFinding struct {
+	Namespace string
+	ItemID    string
+	Time      time.Time
+}
+
+// checkInAuthorization allows one removal of an item until it expires
+type checkInAuthorization struct {
+	Person  string
+	Expires time.Time
+}
+
+// theftDetector decides which removals are thefts. It holds no locks and
+// reads no clock, so the same logic runs live and against replayed events.
+// Namespaces are independent: a check-in in one never covers another.
+type theftDetector struct {
+	window      time.Duration                    // How long a check-in authorizes removal
+	grace       time.Duration                    // After any check-in, suppress all thefts in its namespace for this long
+	delay       time.Duration                    // How long after a removal a check-in can still cover it
+	authorized  map[itemKey]checkInAuthorization // Keyed by namespace and ItemID
+	lastCheckIn map[string]time.Time             // Most recent check-in of any item, per namespace
+	pending     map[itemKey]time.Time            // Unauthorized removals awaiting a late check-in, by removal time
+}
+
+func newTheftDetector(window, grace, delay time.Duration) *theftDetector {
+	return &theftDetector{
+		window:      window,
+		grace:       grace,
+		delay:       delay,
+		authorized:  make(map[itemKey]checkInAuthorization),
+		lastCheckIn: make(map[string]time.Time),
+		pending:     make(map[itemKey]time.Time),
+	}
+}
+
+// process applies one event and returns any thefts it reveals. A check-in
+// authorizes each listed item for the window; an authorized removal consumes
+// the authorization. Other removals are thefts unless they fall within the
+// grace period after the latest check-in of any item in the same namespace,
+// when someone is evidently handling the shelf. With a delay, such a removal
+// is held until the delay passes, and a check-in of the item or its return
+// to the shelf before then covers it instead.
+func (d *theftDetector) process(event detectorEvent) []theftFinding {
+	findings := d.expirePending(event.Time)
+	namespace := normalizeNamespace(event.Namespace)
+
+	switch event.Type {
+	case detectorEventCheckIn:
+		if event.Time.After(d.lastCheckIn[namespace]) {
+			d.lastCheckIn[namespace] = event.Time
+		}
+		for _, itemID := range event.ItemIDs {
+			key := keyFor(namespace, itemID)
+			if _, ok := d.pending[key]; ok {
+				// Checked in late, covering the removal already seen
+				delete(d.pending, key)
+				continue
+			}
+			d.authorized[key] = checkInAuthorization{
+				Person:  event.Person,
+				Expires: event.Time.Add(d.window),
+			}
+		}
+
+	case detectorEventItemRemoved:
+		for _, itemID := range event.ItemIDs {
+			key := keyFor(namespace, itemID)
+			auth, ok := d.authorized[key]
+			delete(d.authorized, key)
+			if ok && !event.Time.After(auth.Expires) {
+				continue
+			}
+			if event.Person != "" {
+				// Someone allowed to take from this namespace was seen taking it
+				continue
+			}
+			if d.inGrace(namespace, event.Time) {
+				continue
+			}
+			if d.delay > 0 {
+				d.pending[key] = event.Time
+				continue
+			}
+			findings = append(findings, theftFinding{Namespace: namespace, ItemID: itemID, Time: event.Time})
+		}
+
+	case detectorEventItemReturned:
+		for _, itemID := range event.ItemIDs {
+			// Put back before the delay ran out, so nothing was taken
+			delete(d.pending, keyFor(namespace, itemID))
+		}
+	}
+
+	return findings
+}
+
+// expirePending turns removals whose delay has passed by now into thefts,
+// sorted by namespace and item id
+func (d *theftDetector) expirePending(now time.Time) []theftFinding {
+	return d.takePending(func(removedAt time.Time) bool { return now.Sub(removedAt) >= d.delay })
+}
+
+// flushPending turns every pending removal into a theft, as at the end of
+// a replayed log where no later check-in or return can cover them
+func (d *theftDetector) flushPending() []theftFinding {
+	return d.takePending(func(time.Time) bool { return true })
+}
+
+// pendingDue reports whether any pending removal's delay has passed by now
+func (d *theftDetector) pendingDue(now time.Time) bool {
+	for _, removedAt := range d.pending {
+		if now.Sub(removedAt) >= d.delay {
+			return true
+		}
+	}
+	return false
+}
+
+// takePending removes the pending removals selected by due and returns them
+// as thefts, sorted by namespace and item id
+func (d *theftDetector) takePending(due func(removedAt time.Time) bool) []theftFinding {
+	var keys []itemKey
+	for key, removedAt := range d.pending {
+		if due(removedAt) {
+			keys = append(keys, key)
+		}
+	}
+	sortItemKeys(keys)
+
+	var findings []theftFinding
+	for _, key := range keys {
+		findings = append(findings, theftFinding{Namespace: key.Namespace, ItemID: key.ItemID, Time: d.pending[key]})
+		delete(d.pending, key)
+	}
+	return findings
+}
+
+// inGrace reports whether t falls within the grace period after the
+// namespace's latest check-in
+func (d *theftDetector) inGrace(namespace string, t time.Time) bool {
+	last := d.lastCheckIn[namespace]
+	if d.grace <= 0 || last.IsZero() {
+		return false
+	}
+	return !t.After(last.Add(d.grace))
+}
+
+// feedTheftDetector runs an event through the live detector and raises an
+// alert for each theft it finds. The item's value at the time rides along
+// so shrinkage_report can estimate the loss later.
+func (s *inventoryKeeperKeeper) feedTheftDetector(event detectorEvent) {
+	s.theftMu.Lock()
+	s.appendEventLocked(event)
+	findings := s.theft.process(event)
+	s.theftMu.Unlock()
+
+	for _, finding := range findings {
+		details := map[string]interface{}{
+			"namespace":  finding.Namespace,
+			"removed_at": formatTimestampIn(finding.Time, s.location),
+		}
+		s.inventoryMu.RLock()
+		if item, exists := s.inventory[keyFor(finding.Namespace, finding.ItemID)]; exists && item.Value > 0 {
+			details["unit_value"] = item.Value
+		}
+		s.inventoryMu.RUnlock()
+		s.raiseAlert("theft", severityCritical, finding.ItemID, fmt.Sprintf("Item %s removed without check-in", finding.ItemID), details)
+	}
+}
+
+// tickTheftDetector lets removals held for theft_alert_delay_seconds become
+// alerts once it passes. Ticks are only fed, and logged, when a pending
+// removal is due. The monitor loop ticks after every attempt, so alerts
+// still fire while scans fail or monitoring is paused.
+func (s *inventoryKeeperKeeper) tickTheftDetector(now time.Time) {
+	s.theftMu.Lock()
+	due := s.theft.pendingDue(now)
+	s.theftMu.Unlock()
+
+	if due {
+		s.feedTheftDetector(detectorEvent{Type: detectorEventTick, Time: now})
+	}
+}
+
+// returnToTheftDetector tells the detector about items back in the
+// present-set, one event per namespace, so removals it holds for the delay
+// are dropped. Only items it holds are fed, and logged.
+func (s *inventoryKeeperKeeper) returnToTheftDetector(appeared []itemKey, now time.Time) {
+	s.theftMu.Lock()
+	var returned []itemKey
+	for _, key := range appeared {
+		if _, pending := s.theft.pending[key]; pending {
+			returned = append(returned, key)
+		}
+	}
+	s.theftMu.Unlock()
+
+	// appeared is sorted by namespace, so each namespace's items are adjacent
+	for start := 0; start < len(returned); {
+		namespace := returned[start].Namespace
+		var itemIDs []string
+		for ; start < len(returned) && returned[start].Namespace == namespace; start++ {
+			itemIDs = append(itemIDs, returned[start].ItemID)
+		}
+		s.feedTheftDetector(detectorEvent{
+			Type:      detectorEventItemReturned,
+			Time:      now,
+			Namespace: namespace,
+			ItemIDs:   itemIDs,
+		})
+	}
+}
+
+// checkIn authorizes removal of the given items in a namespace for the
+// check-in window. Items not in that namespace's inventory are returned as
+// unknown and are not authorized.
+func (s *inventoryKeeperKeeper) checkIn(namespace string, itemIDs []string, person string) (accepted, unknown []string) {
+	now := s.now()
+	accepted, unknown = []string{}, []string{}
+
+	s.inventoryMu.Lock()
+	for _, itemID := range itemIDs {
+		if _, exists := s.inventory[keyFor(namespace, itemID)]; exists {
+			accepted = append(accepted, itemID)
+		} else {
+			unknown = append(unknown, itemID)
+		}
+	}
+	if len(accepted) > 0 {
+		details := map[string]interface{}{"item_ids": toInterfaceSlice(accepted)}
+		if person != "" {
+			details["person"] = person
+		}
+		historyItemID := ""
+		if len(accepted) == 1 {
+			historyItemID = accepted[0]
+		}
+		s.recordHistoryLocked("check_in", namespace, historyItemID, details)
+	}
+	s.inventoryMu.Unlock()
+
+	if len(accepted) > 0 {
+		s.feedTheftDetector(detectorEvent{
+			Type:      detectorEventCheckIn,
+			Time:      now,
+			Namespace: namespace,
+			ItemIDs:   accepted,
+			Person:    person,
+		})
+	}
+	return accepted, unknown
+}
+
+// handleCheckIn authorizes removal of a single item
+func (s *inventoryKeeperKeeper) handleCheckIn(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemID, ok := cmd["item_id"].(string)
+	if !ok || itemID == "" {
+		return nil, errors.New("item_id is required and must be a string")
+	}
+	person, err := optionalStringArg(cmd, "person")
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+	confirmedBy, err := s.confirmRemovalByFace(ctx, "check_in", person)
+	if err != nil {
+		return nil, err
+	}
+	if confirmedBy.Name != "" {
+		person = confirmedBy.Name
+	}
+
+	accepted, _ := s.checkIn(namespace, []string{itemID}, person)
+	if len(accepted) == 0 {
+		return nil, fmt.Errorf("item %s not found", itemID)
+	}
+
+	result := map[string]interface{}{
+		"namespace":  namespace,
+		"item_id":    itemID,
+		"authorized": true,
+		"expires_at": formatTimestampIn(s.now().Add(s.config().checkInWindow()), s.location),
+	}
+	if confirmedBy.Name != "" {
+		result["authorized_by"] = confirmedBy.Name
+	}
+	return result, nil
+}
+
+// handleCheckInBatch authorizes removal of several items in one check-in,
+// reporting which ids were accepted and which are not in inventory
+func (s *inventoryKeeperKeeper) handleCheckInBatch(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	itemIDs, _, err := stringSliceArg(cmd, "item_ids")
+	if err != nil {
+		return nil, err
+	}
+	if len(itemIDs) == 0 {
+		return nil, errors.New("item_ids is required and must be a non-empty list of strings")
+	}
+	if err := s.config().checkBatchArg("item_ids", len(itemIDs)); err != nil {
+		return nil, err
+	}
+	person, err := optionalStringArg(cmd, "person")
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := namespaceArg(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop duplicates so each item is authorized once
+	seen := make(map[string]bool, len(itemIDs))
+	unique := make([]string, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if itemID == "" || seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+		unique = append(unique, itemID)
+	}
+	sort.Strings(unique)
+
+	confirmedBy, err := s.confirmRemovalByFace(ctx, "check_in_batch", person)
+	if err != nil {
+		return nil, err
+	}
+	if confirmedBy.Name != "" {
+		person = confirmedBy.Name
+	}
+
+	accepted, unknown := s.checkIn(namespace, unique, person)
+
+	result := map[string]interface{}{
+		"namespace":  namespace,
+		"accepted":   toInterfaceSlice(accepted),
+		"unknown":    toInterfaceSlice(unknown),
+		"expires_at": formatTimestampIn(s.now().Add(s.config().checkInWindow()), s.location),
+	}
+	if confirmedBy.Name != "" {
+		result["authorized_by"] = confirmedBy.Name
+	}
+	return result, nil
+}
@@ -0,0 +1,275 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestTheftDetector(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("removal inside the check-in window is authorized once", func(t *testing.T) {
+		d := newTheftDetector(time.Minute, 0, 0)
+		d.process(detectorEvent{Type: detectorEventCheckIn, Time: start, ItemIDs: []string{"apple-001"}})
+
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(30 * time.Second), ItemIDs: []string{"apple-001"}}); len(findings) != 0 {
+			t.Errorf("expected authorized removal, got: %v", findings)
+		}
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(40 * time.Second), ItemIDs: []string{"apple-001"}}); len(findings) != 1 {
+			t.Errorf("expected second removal to be a theft, got: %v", findings)
+		}
+	})
+
+	t.Run("removal after the window expires is a theft", func(t *testing.T) {
+		d := newTheftDetector(time.Minute, 0, 0)
+		d.process(detectorEvent{Type: detectorEventCheckIn, Time: start, ItemIDs: []string{"apple-001"}})
+
+		findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(2 * time.Minute), ItemIDs: []string{"apple-001"}})
+		if len(findings) != 1 || findings[0].ItemID != "apple-001" {
+			t.Errorf("expected theft of apple-001, got: %v", findings)
+		}
+	})
+
+	t.Run("removals shortly after any check-in are suppressed", func(t *testing.T) {
+		d := newTheftDetector(time.Minute, 10*time.Second, 0)
+		d.process(detectorEvent{Type: detectorEventCheckIn, Time: start, ItemIDs: []string{"banana-042"}})
+
+		// apple was never checked in, but someone is handling the shelf
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(5 * time.Second), ItemIDs: []string{"apple-001"}}); len(findings) != 0 {
+			t.Errorf("expected removal within grace to be suppressed, got: %v", findings)
+		}
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(30 * time.Second), ItemIDs: []string{"cheese-777"}}); len(findings) != 1 {
+			t.Errorf("expected removal after grace to be a theft, got: %v", findings)
+		}
+	})
+
+	t.Run("delayed removal is covered by a late check-in", func(t *testing.T) {
+		d := newTheftDetector(time.Minute, 0, 30*time.Second)
+
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start, ItemIDs: []string{"apple-001", "banana-042"}}); len(findings) != 0 {
+			t.Errorf("expected removals held for the delay, got: %v", findings)
+		}
+		d.process(detectorEvent{Type: detectorEventCheckIn, Time: start.Add(20 * time.Second), ItemIDs: []string{"apple-001"}})
+
+		if findings := d.process(detectorEvent{Type: detectorEventTick, Time: start.Add(25 * time.Second)}); len(findings) != 0 {
+			t.Errorf("expected nothing before the delay passes, got: %v", findings)
+		}
+		findings := d.process(detectorEvent{Type: detectorEventTick, Time: start.Add(30 * time.Second)})
+		if len(findings) != 1 || findings[0].ItemID != "banana-042" || !findings[0].Time.Equal(start) {
+			t.Errorf("expected theft of banana-042 at its removal time, got: %v", findings)
+		}
+
+		// The late check-in covered the removal, it isn't a fresh authorization
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start.Add(40 * time.Second), ItemIDs: []string{"apple-001"}}); len(findings) != 0 || len(d.pending) != 1 {
+			t.Errorf("expected second apple removal held as pending, got: %v (pending %v)", findings, d.pending)
+		}
+	})
+
+	t.Run("delayed removal is covered by the item's return", func(t *testing.T) {
+		d := newTheftDetector(time.Minute, 0, 30*time.Second)
+
+		d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start, ItemIDs: []string{"apple-001", "banana-042"}})
+		d.process(detectorEvent{Type: detectorEventItemReturned, Time: start.Add(10 * time.Second), ItemIDs: []string{"apple-001"}})
+
+		findings := d.process(detectorEvent{Type: detectorEventTick, Time: start.Add(30 * time.Second)})
+		if len(findings) != 1 || findings[0].ItemID != "banana-042" {
+			t.Errorf("expected only banana-042 to become a theft, got: %v", findings)
+		}
+	})
+
+	t.Run("zero grace never suppresses", func(t *testing.T) {
+		d := newTheftDetector(time.Minute, 0, 0)
+		d.process(detectorEvent{Type: detectorEventCheckIn, Time: start, ItemIDs: []string{"banana-042"}})
+
+		if findings := d.process(detectorEvent{Type: detectorEventItemRemoved, Time: start, ItemIDs: []string{"apple-001"}}); len(findings) != 1 {
+			t.Errorf("expected theft without grace, got: %v", findings)
+		}
+	})
+}
+
+func TestCheckInBatch(t *testing.T) {
+	ctx := context.Background()
+
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+	banana, _ := json.Marshal(ItemQRData{ItemID: "banana-042", ItemName: "Organic Banana"})
+	onShelf := func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{testDetection(0, string(apple)), testDetection(1, string(banana))}, nil
+	}
+	emptyShelf := func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{}, nil
+	}
+
+	// setup stocks the shelf with apple and banana and confirms both present
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, func()) {
+		svc, mockVision := newTestKeeper(t, nil)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "banana-042", "item_name": "Organic Banana"})
+
+		mockVision.DetectionsFromCameraFunc = onShelf
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+
+		// takeBoth removes both items in the same scans
+		takeBoth := func() {
+			mockVision.DetectionsFromCameraFunc = emptyShelf
+			svc.scanAndCompare(ctx)
+			svc.scanAndCompare(ctx)
+		}
+		return svc, takeBoth
+	}
+
+	theftCount := func(svc *inventoryKeeperKeeper) int {
+		count := 0
+		for _, raw := range mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["alerts"].([]interface{}) {
+			if raw.(map[string]interface{})["type"] == "theft" {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("batch check-in authorizes simultaneous removals", func(t *testing.T) {
+		svc, takeBoth := setup(t)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{
+			"command":  "check_in_batch",
+			"item_ids": []interface{}{"apple-001", "banana-042", "ghost-999"},
+			"person":   "alice",
+		})
+		if !reflect.DeepEqual(result["accepted"], []interface{}{"apple-001", "banana-042"}) {
+			t.Errorf("expected apple and banana accepted, got: %v", result["accepted"])
+		}
+		if !reflect.DeepEqual(result["unknown"], []interface{}{"ghost-999"}) {
+			t.Errorf("expected ghost-999 unknown, got: %v", result["unknown"])
+		}
+
+		takeBoth()
+		if n := theftCount(svc); n != 0 {
+			t.Errorf("expected no theft alerts after batch check-in, got: %d", n)
+		}
+
+		// One grouped history event covers the whole batch
+		history := mustDoCommand(t, svc, map[string]interface{}{"command": "get_history", "limit": 1.0})
+		event := history["events"].([]interface{})[0].(map[string]interface{})
+		if event["type"] != "check_in" {
+			t.Fatalf("expected check_in event, got: %v", event["type"])
+		}
+		details := event["details"].(map[string]interface{})
+		if len(details["item_ids"].([]interface{})) != 2 || details["person"] != "alice" {
+			t.Errorf("expected grouped check-in for alice, got: %v", details)
+		}
+	})
+
+	t.Run("removals without check-in raise theft alerts", func(t *testing.T) {
+		svc, takeBoth := setup(t)
+
+		takeBoth()
+		if n := theftCount(svc); n != 2 {
+			t.Errorf("expected 2 theft alerts, got: %d", n)
+		}
+	})
+
+	t.Run("empty item_ids returns error", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "check_in_batch", "item_ids": []interface{}{}}); err == nil {
+			t.Error("expected error for empty item_ids")
+		}
+	})
+}
+
+func TestTheftAlertDelay(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	apple, _ := json.Marshal(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"})
+
+	showApple := func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{testDetection(0, string(apple))}, nil
+	}
+
+	// setup confirms apple-001 present, then takes it off the shelf
+	setup := func(t *testing.T) (*inventoryKeeperKeeper, *inject.VisionService, *fakeClock) {
+		delay := 30
+		svc, mockVision, clock := newTestKeeperWithClock(t, &Config{TheftAlertDelaySeconds: &delay}, start)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+
+		mockVision.DetectionsFromCameraFunc = showApple
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			return []objectdetection.Detection{}, nil
+		}
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		return svc, mockVision, clock
+	}
+
+	alertCount := func(t *testing.T, svc *inventoryKeeperKeeper) interface{} {
+		return mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})["count"]
+	}
+
+	t.Run("alert fires once the delay passes", func(t *testing.T) {
+		svc, _, clock := setup(t)
+		if count := alertCount(t, svc); count != 0 {
+			t.Fatalf("expected no alert during the delay, got: %v", count)
+		}
+
+		clock.Advance(30 * time.Second)
+		svc.scanAndCompare(ctx)
+		alerts := mustDoCommand(t, svc, map[string]interface{}{"command": "get_alerts"})
+		if alerts["count"] != 1 {
+			t.Fatalf("expected a theft alert after the delay, got: %v", alerts["count"])
+		}
+		alert := alerts["alerts"].([]interface{})[0].(map[string]interface{})
+		if alert["type"] != "theft" || alert["item_id"] != "apple-001" {
+			t.Errorf("expected theft of apple-001, got: %v", alert)
+		}
+	})
+
+	t.Run("check-in during the delay prevents the alert", func(t *testing.T) {
+		svc, _, clock := setup(t)
+
+		clock.Advance(10 * time.Second)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "check_in", "item_id": "apple-001", "person": "alice"})
+		clock.Advance(30 * time.Second)
+		svc.scanAndCompare(ctx)
+
+		if count := alertCount(t, svc); count != 0 {
+			t.Errorf("expected no alert after a late check-in, got: %v", count)
+		}
+	})
+
+	t.Run("putting the item back during the delay prevents the alert", func(t *testing.T) {
+		svc, mockVision, clock := setup(t)
+
+		clock.Advance(10 * time.Second)
+		mockVision.DetectionsFromCameraFunc = showApple
+		svc.scanAndCompare(ctx)
+		svc.scanAndCompare(ctx)
+		clock.Advance(30 * time.Second)
+		svc.scanAndCompare(ctx)
+
+		if count := alertCount(t, svc); count != 0 {
+			t.Errorf("expected no alert once the item is back, got: %v", count)
+		}
+	})
+
+	t.Run("alert fires without a successful scan", func(t *testing.T) {
+		svc, _, clock := setup(t)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "pause_monitoring"})
+
+		clock.Advance(30 * time.Second)
+		svc.scanAndCompare(ctx)
+		svc.tickTheftDetector(clock.Now()) // As the monitor loop does after every attempt
+		if count := alertCount(t, svc); count != 1 {
+			t.Errorf("expected the theft alert while paused, got: %v", count)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errTimeout is returned when a command does not finish before its deadline
+var errTimeout = errors.New("TIMEOUT")
+
+// defaultCommandTimeout bounds camera- and sensor-bound commands when
+// command_timeout_seconds is not configured
+const defaultCommandTimeout = 30 * time.Second
+
+// commandHandler is the signature shared by DoCommand handlers
+type commandHandler func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+
+// withCommandTimeout runs a handler that talks to hardware, returning a
+// TIMEOUT error as soon as the caller's context is done or the configured
+// command timeout elapses. The handler runs on its own goroutine so a
+// dependency that ignores cancellation can't hold the caller; its eventual
+// result is discarded.
+func (s *inventoryKeeperKeeper) withCommandTimeout(ctx context.Context, name string, cmd map[string]interface{}, handler commandHandler) (map[string]interface{}, error) {
+	if timeout := s.config().commandTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result map[string]interface{}
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := handler(ctx, cmd)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.err
+	case <-ctx.Done():
+		s.logger.Warnf("Command %s timed out: %v", name, ctx.Err())
+		return nil, fmt.Errorf("%w: %s did not complete: %w", errTimeout, name, ctx.Err())
+	}
+}
@@ -0,0 +1,70 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// blockCamera makes the keeper's mock camera hang until the test ends,
+// ignoring its context like a stalled device would
+func blockCamera(t *testing.T, svc *inventoryKeeperKeeper) {
+	t.Helper()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	svc.camera.(*inject.Camera).ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+		<-release
+		return nil, camera.ImageMetadata{}, errors.New("released")
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	t.Run("blocking camera returns TIMEOUT after command_timeout_seconds", func(t *testing.T) {
+		timeout := 0.1
+		svc, _ := newTestKeeper(t, &Config{CommandTimeoutSeconds: &timeout})
+		blockCamera(t, svc)
+
+		start := time.Now()
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "get_image"})
+		elapsed := time.Since(start)
+
+		if !errors.Is(err, errTimeout) {
+			t.Fatalf("expected TIMEOUT error, got: %v", err)
+		}
+		if elapsed > time.Second {
+			t.Errorf("expected prompt return, took: %v", elapsed)
+		}
+	})
+
+	t.Run("caller deadline is honored without a configured timeout", func(t *testing.T) {
+		noTimeout := 0.0
+		svc, _ := newTestKeeper(t, &Config{CommandTimeoutSeconds: &noTimeout})
+		blockCamera(t, svc)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := svc.DoCommand(ctx, map[string]interface{}{"command": "get_image"})
+		if !errors.Is(err, errTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected TIMEOUT wrapping deadline exceeded, got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected prompt return, took: %v", elapsed)
+		}
+	})
+
+	t.Run("negative command_timeout_seconds returns error", func(t *testing.T) {
+		negative := -1.0
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", CommandTimeoutSeconds: &negative}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for negative command_timeout_seconds")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package inventorykeeper
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Default field length limits, in characters
+const (
+	defaultMaxItemNameLength = 128
+	defaultMaxItemIDLength   = 128
+)
+
+// compileFieldPattern compiles an allowlist pattern so it must match the
+// whole value. An empty pattern returns nil (no restriction).
+func compileFieldPattern(field, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid regular expression: %w", field, err)
+	}
+	return re, nil
+}
+
+// itemFieldRules holds the length limits and patterns for item fields
+type itemFieldRules struct {
+	maxNameLength int
+	maxIDLength   int
+	namePattern   *regexp.Regexp
+	idPattern     *regexp.Regexp
+}
+
+// newItemFieldRules builds the rules from config, assuming Validate has passed
+func newItemFieldRules(cfg *Config) (itemFieldRules, error) {
+	rules := itemFieldRules{
+		maxNameLength: defaultMaxItemNameLength,
+		maxIDLength:   defaultMaxItemIDLength,
+	}
+	if cfg.MaxItemNameLength != nil {
+		rules.maxNameLength = *cfg.MaxItemNameLength
+	}
+	if cfg.MaxItemIDLength != nil {
+		rules.maxIDLength = *cfg.MaxItemIDLength
+	}
+
+	var err error
+	if rules.namePattern, err = compileFieldPattern("item_name_pattern", cfg.ItemNamePattern); err != nil {
+		return rules, err
+	}
+	if rules.idPattern, err = compileFieldPattern("item_id_pattern", cfg.ItemIDPattern); err != nil {
+		return rules, err
+	}
+	return rules, nil
+}
+
+// checkField validates one value against a length limit and optional pattern
+func checkField(field, value string, maxLength int, pattern *regexp.Regexp, patternField string) error {
+	if n := utf8.RuneCountInString(value); n > maxLength {
+		return fmt.Errorf("%s is too long: %d characters exceeds max_%s_length %d", field, n, field, maxLength)
+	}
+	if pattern != nil && !pattern.MatchString(value) {
+		return fmt.Errorf("%s %q contains characters not allowed by %s", field, value, patternField)
+	}
+	return nil
+}
+
+// validateItemID checks an item_id against the configured rules
+func (r itemFieldRules) validateItemID(itemID string) error {
+	return checkField("item_id", itemID, r.maxIDLength, r.idPattern, "item_id_pattern")
+}
+
+// validateItemName checks an item_name against the configured rules
+func (r itemFieldRules) validateItemName(itemName string) error {
+	return checkField("item_name", itemName, r.maxNameLength, r.namePattern, "item_name_pattern")
+}
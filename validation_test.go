@@ -0,0 +1,71 @@
+package inventorykeeper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestItemFieldValidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("default limit rejects names over 128 characters", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		for _, command := range []string{"add_item", "generate_qr"} {
+			_, err := svc.DoCommand(ctx, map[string]interface{}{
+				"command":   command,
+				"item_id":   "apple-001",
+				"item_name": strings.Repeat("a", 129),
+			})
+			if err == nil || !strings.Contains(err.Error(), "item_name is too long") {
+				t.Errorf("%s: expected item_name length error, got: %v", command, err)
+			}
+		}
+
+		mustDoCommand(t, svc, map[string]interface{}{
+			"command":   "add_item",
+			"item_id":   "apple-001",
+			"item_name": strings.Repeat("a", 128),
+		})
+	})
+
+	t.Run("item_id has its own limit", func(t *testing.T) {
+		maxID := 8
+		svc, _ := newTestKeeper(t, &Config{MaxItemIDLength: &maxID})
+
+		_, err := svc.DoCommand(ctx, map[string]interface{}{
+			"command":   "add_item",
+			"item_id":   "apple-0001",
+			"item_name": "Honeycrisp Apple",
+		})
+		if err == nil || !strings.Contains(err.Error(), "max_item_id_length 8") {
+			t.Errorf("expected item_id length error, got: %v", err)
+		}
+	})
+
+	t.Run("configured pattern rejects disallowed characters", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{ItemNamePattern: `[A-Za-z0-9 -]+`})
+
+		_, err := svc.DoCommand(ctx, map[string]interface{}{
+			"command":   "generate_qr",
+			"item_id":   "apple-001",
+			"item_name": "Apple; DROP",
+		})
+		if err == nil || !strings.Contains(err.Error(), "item_name_pattern") {
+			t.Errorf("expected item_name_pattern error, got: %v", err)
+		}
+
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple"})
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "rename_item", "item_id": "apple-001", "item_name": "Apple!"}); err == nil {
+			t.Error("expected rename_item to enforce item_name_pattern")
+		}
+	})
+
+	t.Run("invalid pattern is rejected in Validate", func(t *testing.T) {
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", ItemIDPattern: "[a-z"}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for invalid item_id_pattern")
+		}
+	})
+}
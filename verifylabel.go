@@ -0,0 +1,127 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Outcomes of verify_label
+const (
+	labelMatch      = "match"      // Label agrees with the stored record
+	labelMismatch   = "mismatch"   // Label decodes to a known item but disagrees with it
+	labelUnknown    = "unknown"    // Label decodes to an item not in inventory
+	labelUnreadable = "unreadable" // A code is in view but isn't an item payload
+)
+
+// Signature states reported by verify_label
+const (
+	signatureValid      = "valid"
+	signatureInvalid    = "invalid"
+	signatureUnsigned   = "unsigned"
+	signatureNotChecked = "not_checked" // signing_secret isn't configured
+)
+
+// labelMismatchEntry is one field where the label and the record disagree
+type labelMismatchEntry struct {
+	Field  string
+	Label  string
+	Record string
+}
+
+func (m labelMismatchEntry) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"field":  m.Field,
+		"label":  m.Label,
+		"record": m.Record,
+	}
+}
+
+// labelSignature checks a decoded label's signature against signing_secret
+func (s *inventoryKeeperKeeper) labelSignature(data ItemQRData) string {
+	secret := s.config().SigningSecret
+	switch {
+	case secret == "":
+		return signatureNotChecked
+	case data.Sig == "":
+		return signatureUnsigned
+	case !verifyPayloadSignature(secret, data):
+		return signatureInvalid
+	default:
+		return signatureValid
+	}
+}
+
+// handleVerifyLabel scans the one label held up to the camera and checks it
+// against the inventory record for its item: the name (barcodes carry none),
+// and with signing_secret set, the signature. Labels don't encode a location,
+// so the record's location is returned for the auditor to compare.
+func (s *inventoryKeeperKeeper) handleVerifyLabel(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	detections, _, err := s.detectQRCodesWithSource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan label: %w", err)
+	}
+	if len(detections) == 0 {
+		return nil, errors.New("no label in view")
+	}
+	if len(detections) > 1 {
+		return nil, fmt.Errorf("%d labels in view; hold up one label at a time", len(detections))
+	}
+
+	content := detections[0].Label()
+	symbology := codeSymbology(content)
+	result := map[string]interface{}{
+		"content":   content,
+		"symbology": symbology,
+	}
+	data, err := s.decodeQRPayload(content)
+	if err != nil || data.ItemID == "" {
+		result["status"] = labelUnreadable
+		if err != nil {
+			result["reason"] = err.Error()
+		}
+		return result, nil
+	}
+
+	namespace := normalizeNamespace(data.Namespace)
+	signature := s.labelSignature(data)
+	result["namespace"] = namespace
+	result["item_id"] = data.ItemID
+	result["label_item_name"] = data.ItemName
+	result["signature"] = signature
+
+	s.inventoryMu.RLock()
+	item, exists := s.inventory[keyFor(namespace, data.ItemID)]
+	var record map[string]interface{}
+	var recordName string
+	if exists {
+		record = item.toMap(s.location)
+		recordName = item.ItemName
+	}
+	s.inventoryMu.RUnlock()
+	if !exists {
+		result["status"] = labelUnknown
+		return result, nil
+	}
+
+	var mismatches []labelMismatchEntry
+	if symbology == codeTypeQR && data.ItemName != recordName {
+		mismatches = append(mismatches, labelMismatchEntry{Field: "item_name", Label: data.ItemName, Record: recordName})
+	}
+	if signature == signatureInvalid || signature == signatureUnsigned {
+		mismatches = append(mismatches, labelMismatchEntry{Field: "sig", Label: signature, Record: signatureValid})
+	}
+
+	result["record"] = record
+	result["location"] = record["location"]
+	result["status"] = labelMatch
+	mismatchMaps := make([]interface{}, 0, len(mismatches))
+	for _, m := range mismatches {
+		mismatchMaps = append(mismatchMaps, m.toMap())
+	}
+	result["mismatches"] = mismatchMaps
+	if len(mismatches) > 0 {
+		result["status"] = labelMismatch
+	}
+	return result, nil
+}
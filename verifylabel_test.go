@@ -0,0 +1,96 @@
+package inventorykeeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestVerifyLabel(t *testing.T) {
+	ctx := context.Background()
+
+	// setup stocks apple-001 and shows the given label contents to the camera
+	setup := func(t *testing.T, cfg *Config, labels ...string) *inventoryKeeperKeeper {
+		svc, mockVision := newTestKeeper(t, cfg)
+		mustDoCommand(t, svc, map[string]interface{}{"command": "add_item", "item_id": "apple-001", "item_name": "Honeycrisp Apple", "location": "aisle-3"})
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			detections := make([]objectdetection.Detection, 0, len(labels))
+			for i, label := range labels {
+				detections = append(detections, testDetection(i, label))
+			}
+			return detections, nil
+		}
+		return svc
+	}
+	label := func(data ItemQRData) string {
+		raw, _ := json.Marshal(data)
+		return string(raw)
+	}
+
+	t.Run("matching label", func(t *testing.T) {
+		svc := setup(t, nil, label(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"}))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_label"})
+		if result["status"] != labelMatch || len(result["mismatches"].([]interface{})) != 0 {
+			t.Errorf("expected a match, got: %v", result)
+		}
+		if result["location"] != "aisle-3" || result["signature"] != signatureNotChecked {
+			t.Errorf("expected record location and unchecked signature, got: %v", result)
+		}
+	})
+
+	t.Run("label name differs from the record", func(t *testing.T) {
+		svc := setup(t, nil, label(ItemQRData{ItemID: "apple-001", ItemName: "Gala Apple"}))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_label"})
+		if result["status"] != labelMismatch {
+			t.Fatalf("expected a mismatch, got: %v", result)
+		}
+		mismatches := result["mismatches"].([]interface{})
+		if len(mismatches) != 1 {
+			t.Fatalf("expected one mismatch, got: %v", mismatches)
+		}
+		m := mismatches[0].(map[string]interface{})
+		if m["field"] != "item_name" || m["label"] != "Gala Apple" || m["record"] != "Honeycrisp Apple" {
+			t.Errorf("unexpected mismatch: %v", m)
+		}
+	})
+
+	t.Run("unknown item id", func(t *testing.T) {
+		svc := setup(t, nil, label(ItemQRData{ItemID: "pear-009", ItemName: "Pear"}))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_label"})
+		if result["status"] != labelUnknown || result["item_id"] != "pear-009" {
+			t.Errorf("expected unknown pear-009, got: %v", result)
+		}
+	})
+
+	t.Run("unsigned label fails with signing enabled", func(t *testing.T) {
+		svc := setup(t, &Config{SigningSecret: "shelf-secret"}, label(ItemQRData{ItemID: "apple-001", ItemName: "Honeycrisp Apple"}))
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_label"})
+		if result["status"] != labelMismatch || result["signature"] != signatureUnsigned {
+			t.Errorf("expected unsigned label mismatch, got: %v", result)
+		}
+	})
+
+	t.Run("unreadable label", func(t *testing.T) {
+		svc := setup(t, nil, "not an item")
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "verify_label"})
+		if result["status"] != labelUnreadable {
+			t.Errorf("expected unreadable, got: %v", result)
+		}
+	})
+
+	t.Run("needs exactly one label in view", func(t *testing.T) {
+		for _, labels := range [][]string{nil, {"a", "b"}} {
+			svc := setup(t, nil, labels...)
+			if _, err := svc.DoCommand(ctx, map[string]interface{}{"command": "verify_label"}); err == nil {
+				t.Errorf("expected error with %d labels in view", len(labels))
+			}
+		}
+	})
+}
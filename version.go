@@ -0,0 +1,75 @@
+package inventorykeeper
+
+import (
+	"context"
+	"runtime"
+)
+
+// Version is the module release, bumped with each published build
+const Version = "0.1.0"
+
+// Build details, set at link time by the Makefile:
+//
+//	-ldflags "-X inventorykeeper.buildCommit=<sha> -X inventorykeeper.buildDate=<RFC 3339>"
+var (
+	buildCommit string
+	buildDate   string
+)
+
+// buildValue returns an injected build detail, or "unknown" when the binary
+// was built without it
+func buildValue(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
+
+// enabledFeatures lists, in a fixed order, the optional features the config
+// turns on
+func enabledFeatures(cfg *Config) []string {
+	features := []string{}
+	for _, feature := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"storage", cfg.StoragePath != ""},
+		{"scale", cfg.ScaleSensor != ""},
+		{"fullness_vision", cfg.FullnessVisionService != ""},
+		{"face_recognition", cfg.FaceVisionService != ""},
+		{"face_removal_gate", cfg.RequireFaceForRemoval},
+		{"encryption", cfg.EncryptionKey != ""},
+		{"signing", cfg.SigningSecret != ""},
+		{"compress_payload", cfg.CompressPayload},
+		{"command_auth", cfg.CommandSecret != ""},
+		{"event_log", cfg.EventLogFile != ""},
+		{"scheduled_audit", cfg.AuditIntervalSeconds != nil && *cfg.AuditIntervalSeconds > 0},
+		{"expiry_alerts", cfg.ExpiryAlertWithin != ""},
+		{"local_decode_fallback", cfg.LocalDecodeFallback},
+		{"skip_duplicate_frames", cfg.SkipDuplicateFrames},
+	} {
+		if feature.enabled {
+			features = append(features, feature.name)
+		}
+	}
+	return features
+}
+
+// handleVersion reports the module version, build details, and which
+// optional features and integrations are enabled
+func (s *inventoryKeeperKeeper) handleVersion(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	integrations := []string{}
+	for _, n := range s.notifiers {
+		integrations = append(integrations, n.Name())
+	}
+
+	return map[string]interface{}{
+		"version":      Version,
+		"go_version":   runtime.Version(),
+		"commit":       buildValue(buildCommit),
+		"build_date":   buildValue(buildDate),
+		"platform":     runtime.GOOS + "/" + runtime.GOARCH,
+		"features":     toInterfaceSlice(enabledFeatures(s.config())),
+		"integrations": toInterfaceSlice(integrations),
+	}, nil
+}
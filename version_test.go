@@ -0,0 +1,56 @@
+package inventorykeeper
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	t.Run("reports the version and unknown build details", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, nil)
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "version"})
+		if result["version"] != Version {
+			t.Errorf("expected version %s, got: %v", Version, result["version"])
+		}
+		if result["go_version"] != runtime.Version() {
+			t.Errorf("expected go_version %s, got: %v", runtime.Version(), result["go_version"])
+		}
+		if result["commit"] != "unknown" || result["build_date"] != "unknown" {
+			t.Errorf("expected unknown build details in tests, got commit %v, build_date %v", result["commit"], result["build_date"])
+		}
+		if features := result["features"].([]interface{}); len(features) != 0 {
+			t.Errorf("expected no optional features, got: %v", features)
+		}
+	})
+
+	t.Run("reports injected build details", func(t *testing.T) {
+		buildCommit, buildDate = "abc1234", "2026-03-01T09:00:00Z"
+		t.Cleanup(func() { buildCommit, buildDate = "", "" })
+
+		svc, _ := newTestKeeper(t, nil)
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "version"})
+		if result["commit"] != "abc1234" || result["build_date"] != "2026-03-01T09:00:00Z" {
+			t.Errorf("expected injected build details, got commit %v, build_date %v", result["commit"], result["build_date"])
+		}
+	})
+
+	t.Run("lists enabled features and integrations", func(t *testing.T) {
+		svc, _ := newTestKeeper(t, &Config{
+			SigningSecret:   "shelf-secret",
+			CompressPayload: true,
+			SMTPHost:        "smtp.example.com",
+			SMTPPort:        587,
+			AlertEmailTo:    "ops@example.com",
+		})
+
+		result := mustDoCommand(t, svc, map[string]interface{}{"command": "version"})
+		if want := []interface{}{"signing", "compress_payload"}; !reflect.DeepEqual(result["features"], want) {
+			t.Errorf("expected features %v, got: %v", want, result["features"])
+		}
+		if want := []interface{}{"email"}; !reflect.DeepEqual(result["integrations"], want) {
+			t.Errorf("expected integrations %v, got: %v", want, result["integrations"])
+		}
+	})
+}
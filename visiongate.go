@@ -0,0 +1,79 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// errVisionBusy is returned when vision_busy_fail_fast is set and every
+// vision call slot is taken
+var errVisionBusy = errors.New("BUSY")
+
+// defaultMaxConcurrentVisionCalls bounds in-flight vision calls when
+// max_concurrent_vision_calls is not configured
+const defaultMaxConcurrentVisionCalls = 2
+
+// visionGate is a counting semaphore shared by the monitoring loop and
+// command handlers, so bursts of commands can't overload inference hardware
+type visionGate struct {
+	slots chan struct{}
+}
+
+func newVisionGate(size int) *visionGate {
+	return &visionGate{slots: make(chan struct{}, size)}
+}
+
+// acquire takes a slot, waiting until one frees up or ctx is done. With
+// failFast it returns errVisionBusy instead of waiting.
+func (g *visionGate) acquire(ctx context.Context, failFast bool) (release func(), err error) {
+	release = func() { <-g.slots }
+	select {
+	case g.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	if failFast {
+		return nil, fmt.Errorf("%w: all %d vision call slots are in use", errVisionBusy, cap(g.slots))
+	}
+
+	select {
+	case g.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// maxConcurrentVisionCalls returns the vision call limit, defaulting to 2
+func (cfg *Config) maxConcurrentVisionCalls() int {
+	if cfg.MaxConcurrentVisionCalls == nil {
+		return defaultMaxConcurrentVisionCalls
+	}
+	return *cfg.MaxConcurrentVisionCalls
+}
+
+// visionDetections runs the QR vision service on an image once a call slot
+// is free
+func (s *inventoryKeeperKeeper) visionDetections(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+	release, err := s.visionGate.acquire(ctx, s.config().VisionBusyFailFast)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return s.qrVision().Detections(ctx, img, nil)
+}
+
+// visionDetectionsFromCamera has the QR vision service capture and detect
+// from a camera once a call slot is free
+func (s *inventoryKeeperKeeper) visionDetectionsFromCamera(ctx context.Context, cameraName string) ([]objectdetection.Detection, error) {
+	release, err := s.visionGate.acquire(ctx, s.config().VisionBusyFailFast)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return s.qrVision().DetectionsFromCamera(ctx, cameraName, nil)
+}
@@ -0,0 +1,113 @@
+package inventorykeeper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestVisionConcurrencyLimit(t *testing.T) {
+	// blockVision makes every camera-backed detection wait for release,
+	// tracking how many run at once
+	blockVision := func(t *testing.T, cfg *Config) (*inventoryKeeperKeeper, chan struct{}, chan struct{}, *int32) {
+		svc, mockVision := newTestKeeper(t, cfg)
+		entered := make(chan struct{}, 2)
+		release := make(chan struct{})
+		var inFlight, maxInFlight int32
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			entered <- struct{}{}
+			<-release
+			return []objectdetection.Detection{}, nil
+		}
+		return svc, entered, release, &maxInFlight
+	}
+
+	t.Run("limit of 1 serializes a second call", func(t *testing.T) {
+		limit := 1
+		svc, entered, release, maxInFlight := blockVision(t, &Config{MaxConcurrentVisionCalls: &limit})
+
+		done := make(chan error, 2)
+		scan := func() {
+			_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "scan_qr"})
+			done <- err
+		}
+		go scan()
+		<-entered
+		go scan()
+
+		select {
+		case <-entered:
+			t.Fatal("expected second call to wait for the first")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		release <- struct{}{}
+		<-entered
+		release <- struct{}{}
+		for i := 0; i < 2; i++ {
+			if err := <-done; err != nil {
+				t.Errorf("expected both scans to succeed, got: %v", err)
+			}
+		}
+		if got := atomic.LoadInt32(maxInFlight); got != 1 {
+			t.Errorf("expected at most 1 call in flight, got: %d", got)
+		}
+	})
+
+	t.Run("fail fast returns BUSY", func(t *testing.T) {
+		limit := 1
+		svc, entered, release, _ := blockVision(t, &Config{MaxConcurrentVisionCalls: &limit, VisionBusyFailFast: true})
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "scan_qr"})
+			done <- err
+		}()
+		<-entered
+
+		_, err := svc.DoCommand(context.Background(), map[string]interface{}{"command": "scan_qr"})
+		if !errors.Is(err, errVisionBusy) {
+			t.Errorf("expected BUSY error, got: %v", err)
+		}
+
+		close(release)
+		if err := <-done; err != nil {
+			t.Errorf("expected first scan to succeed, got: %v", err)
+		}
+	})
+
+	t.Run("waiting respects the caller's context", func(t *testing.T) {
+		limit := 1
+		svc, entered, release, _ := blockVision(t, &Config{MaxConcurrentVisionCalls: &limit})
+		defer close(release)
+
+		go svc.DoCommand(context.Background(), map[string]interface{}{"command": "scan_qr"})
+		<-entered
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := svc.detectQRCodesOnce(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded while waiting, got: %v", err)
+		}
+	})
+
+	t.Run("zero limit is rejected", func(t *testing.T) {
+		zero := 0
+		cfg := &Config{CameraName: "cam", QRVisionService: "qr", MaxConcurrentVisionCalls: &zero}
+		if _, _, err := cfg.Validate(""); err == nil {
+			t.Error("expected error for max_concurrent_vision_calls 0")
+		}
+	})
+}
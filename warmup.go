@@ -0,0 +1,55 @@
+package inventorykeeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// cameraIdleWarmup is how long the camera can go without a frame being
+// taken before the next capture warms it up again
+const cameraIdleWarmup = time.Minute
+
+// maxCameraWarmupFrames bounds camera_warmup_frames so a warm-up can't stall
+// a scan for long
+const maxCameraWarmupFrames = 30
+
+// warmUpCamera discards camera_warmup_frames frames when the camera is cold:
+// before its first capture, which includes the monitoring loop's first scan,
+// and after sitting idle for cameraIdleWarmup. Discarded frames aren't decoded.
+func (s *inventoryKeeperKeeper) warmUpCamera(ctx context.Context) error {
+	cfg := s.config()
+	frames := cfg.CameraWarmupFrames
+	if frames <= 0 {
+		return nil
+	}
+
+	now := s.now()
+	s.healthMu.Lock()
+	lastFrameAt := s.health.lastFrameAt
+	s.healthMu.Unlock()
+	if !lastFrameAt.IsZero() && now.Sub(lastFrameAt) < cameraIdleWarmup {
+		return nil
+	}
+
+	if lastFrameAt.IsZero() {
+		s.logger.Infof("Warming up camera %s: discarding %d frames", cfg.CameraName, frames)
+	} else {
+		s.logger.Infof("Warming up camera %s after %v idle: discarding %d frames", cfg.CameraName, now.Sub(lastFrameAt).Round(time.Second), frames)
+	}
+	for i := 0; i < frames; i++ {
+		if _, _, err := s.shelfCamera().Image(ctx, cfg.captureMimeType(), nil); err != nil {
+			return fmt.Errorf("failed to warm up camera %s (frame %d of %d): %w", cfg.CameraName, i+1, frames, err)
+		}
+	}
+	s.recordFrameTaken()
+	return nil
+}
+
+// recordFrameTaken notes that the camera just produced a frame, keeping it warm
+func (s *inventoryKeeperKeeper) recordFrameTaken() {
+	now := s.now()
+	s.healthMu.Lock()
+	s.health.lastFrameAt = now
+	s.healthMu.Unlock()
+}
@@ -0,0 +1,89 @@
+package inventorykeeper
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestCameraWarmup(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	var good bytes.Buffer
+	if err := png.Encode(&good, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatalf("failed to encode frame: %v", err)
+	}
+
+	// setup serves undecodable frames except every third, counting requests
+	setup := func(t *testing.T, frames int) (*inventoryKeeperKeeper, *fakeClock, *int) {
+		svc, _, clock := newTestKeeperWithClock(t, &Config{CameraWarmupFrames: frames}, start)
+		requests := 0
+		svc.camera.(*inject.Camera).ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+			requests++
+			if requests%3 != 0 {
+				return []byte("overexposed"), camera.ImageMetadata{MimeType: utils.MimeTypePNG}, nil
+			}
+			return good.Bytes(), camera.ImageMetadata{MimeType: utils.MimeTypePNG}, nil
+		}
+		return svc, clock, &requests
+	}
+
+	t.Run("two warm-up frames are discarded before the first capture", func(t *testing.T) {
+		svc, _, requests := setup(t, 2)
+
+		img, err := svc.captureFrame(ctx)
+		if err != nil {
+			t.Fatalf("expected the third frame to decode, got: %v", err)
+		}
+		if *requests != 3 || img.Bounds().Dx() != 8 {
+			t.Errorf("expected 3 frames requested and the last decoded, got %d requests", *requests)
+		}
+	})
+
+	t.Run("a warm camera isn't warmed again until idle", func(t *testing.T) {
+		svc, clock, requests := setup(t, 2)
+		if _, err := svc.captureFrame(ctx); err != nil {
+			t.Fatalf("captureFrame: %v", err)
+		}
+
+		// Next capture is immediate, so the single frame requested is an undecodable one
+		clock.Advance(time.Second)
+		if _, err := svc.captureFrame(ctx); err == nil || *requests != 4 {
+			t.Errorf("expected one frame without warm-up, got %d requests (err %v)", *requests, err)
+		}
+
+		*requests = 0
+		clock.Advance(2 * cameraIdleWarmup)
+		if _, err := svc.captureFrame(ctx); err != nil || *requests != 3 {
+			t.Errorf("expected a warm-up after idling, got %d requests (err %v)", *requests, err)
+		}
+	})
+
+	t.Run("vision captures are warmed up too", func(t *testing.T) {
+		svc, _, requests := setup(t, 2)
+		mockVision := svc.qrVisionService.(*inject.VisionService)
+		mockVision.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+			if *requests != 2 {
+				t.Errorf("expected 2 warm-up frames before detection, got %d", *requests)
+			}
+			return nil, nil
+		}
+		mustDoCommand(t, svc, map[string]interface{}{"command": "scan_qr"})
+	})
+
+	t.Run("no warm-up by default", func(t *testing.T) {
+		svc, _, requests := setup(t, 0)
+		if _, err := svc.captureFrame(ctx); err == nil || *requests != 1 {
+			t.Errorf("expected the first frame used as is, got %d requests (err %v)", *requests, err)
+		}
+	})
+}